@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/provider/file"
+	"github.com/traefik/traefik/v3/pkg/server/history"
+)
+
+// runDiff prints the differences between the dynamic configurations defined by the given files or directories,
+// as the values which are removed (-), added (+), or changed (-/+), by path.
+func runDiff(w io.Writer, oldPath, newPath string) error {
+	oldConfiguration, err := loadDynamicConfiguration(oldPath)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", oldPath, err)
+	}
+
+	newConfiguration, err := loadDynamicConfiguration(newPath)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", newPath, err)
+	}
+
+	changes, err := history.Diff(oldConfiguration, newConfiguration)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		_, err = fmt.Fprintln(w, "No differences.")
+		return err
+	}
+
+	for _, change := range changes {
+		if change.Old != nil {
+			if _, err := fmt.Fprintf(w, "- %s: %s\n", change.Path, change.Old); err != nil {
+				return err
+			}
+		}
+
+		if change.New != nil {
+			if _, err := fmt.Fprintf(w, "+ %s: %s\n", change.Path, change.New); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadDynamicConfiguration loads the dynamic configuration from the given file or directory,
+// the same way as the file provider.
+func loadDynamicConfiguration(path string) (*dynamic.Configuration, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &file.Provider{}
+	if info.IsDir() {
+		provider.Directory = path
+	} else {
+		provider.Filename = path
+	}
+
+	return provider.BuildConfiguration()
+}
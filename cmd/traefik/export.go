@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v3/cmd"
+	"github.com/traefik/traefik/v3/pkg/config/export"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+)
+
+func newExportCmd(traefikConfiguration *cmd.TraefikCmdConfiguration, loaders []cli.ResourceLoader) (*cli.Command, error) {
+	exportCmd := &cli.Command{
+		Name:        "export",
+		Description: `Exports the dynamic configuration of the file provider in the format of another provider: traefik export FORMAT.`,
+	}
+
+	descriptions := map[string]string{
+		export.FormatYAML:       "Exports the dynamic configuration of the file provider as a YAML file.",
+		export.FormatTOML:       "Exports the dynamic configuration of the file provider as a TOML file.",
+		export.FormatLabels:     "Exports the dynamic configuration of the file provider as Docker labels, one key=value per line.",
+		export.FormatKV:         "Exports the dynamic configuration of the file provider as KV pairs, one key=value per line.",
+		export.FormatKubernetes: "Exports the dynamic configuration of the file provider as Kubernetes CRD manifests.",
+	}
+
+	for _, format := range export.Formats {
+		err := exportCmd.AddCommand(&cli.Command{
+			Name:          format,
+			Description:   descriptions[format],
+			Configuration: traefikConfiguration,
+			Resources:     loaders,
+			Run: func(_ []string) error {
+				return runExport(os.Stdout, &traefikConfiguration.Configuration, format)
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return exportCmd, nil
+}
+
+// runExport writes the dynamic configuration of the file provider in the given format.
+func runExport(w io.Writer, staticConfiguration *static.Configuration, format string) error {
+	// Only the exported configuration is written to the output.
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	if staticConfiguration.Providers == nil || staticConfiguration.Providers.File == nil {
+		return errors.New("the file provider is not configured")
+	}
+
+	fileConfiguration, err := staticConfiguration.Providers.File.BuildConfiguration()
+	if err != nil {
+		return fmt.Errorf("loading file provider configuration: %w", err)
+	}
+
+	return export.Encode(w, fileConfiguration, format)
+}
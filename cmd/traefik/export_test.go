@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/cmd"
+	"github.com/traefik/traefik/v3/pkg/config/export"
+	"github.com/traefik/traefik/v3/pkg/provider/file"
+)
+
+func TestRunExport(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dynamic.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(`
+http:
+  routers:
+    foo:
+      rule: Host(`+"`foo.localhost`"+`)
+      service: foo
+  services:
+    foo:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1:8000
+`), 0o600))
+
+	staticConfiguration := cmd.NewTraefikConfiguration().Configuration
+	staticConfiguration.Providers.File = &file.Provider{Filename: filename}
+
+	var output bytes.Buffer
+	require.NoError(t, runExport(&output, &staticConfiguration, export.FormatLabels))
+
+	assert.Contains(t, output.String(), "traefik.http.routers.foo.rule=Host(`foo.localhost`)\n")
+	assert.Contains(t, output.String(), "traefik.http.services.foo.loadbalancer.server.url=http://127.0.0.1:8000\n")
+}
+
+func TestRunExport_noFileProvider(t *testing.T) {
+	staticConfiguration := cmd.NewTraefikConfiguration().Configuration
+
+	err := runExport(&bytes.Buffer{}, &staticConfiguration, export.FormatYAML)
+	require.Error(t, err)
+}
@@ -28,7 +28,9 @@ import (
 	tcli "github.com/traefik/traefik/v3/pkg/cli"
 	"github.com/traefik/traefik/v3/pkg/collector"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/export"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/secret"
 	"github.com/traefik/traefik/v3/pkg/config/static"
 	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
@@ -37,6 +39,7 @@ import (
 	otypes "github.com/traefik/traefik/v3/pkg/observability/types"
 	"github.com/traefik/traefik/v3/pkg/provider/acme"
 	"github.com/traefik/traefik/v3/pkg/provider/aggregator"
+	"github.com/traefik/traefik/v3/pkg/provider/privateca"
 	"github.com/traefik/traefik/v3/pkg/provider/tailscale"
 	"github.com/traefik/traefik/v3/pkg/provider/traefik"
 	"github.com/traefik/traefik/v3/pkg/proxy"
@@ -44,6 +47,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/redactor"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server"
+	"github.com/traefik/traefik/v3/pkg/server/history"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	"github.com/traefik/traefik/v3/pkg/server/service"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -80,6 +84,30 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	validateCmd, err := newValidateCmd(tConfig, loaders)
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(validateCmd)
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	exportCmd, err := newExportCmd(tConfig, loaders)
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(exportCmd)
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cli.Execute(cmdTraefik)
 	if err != nil {
 		log.Error().Err(err).Msg("Command error")
@@ -202,6 +230,10 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	tsProviders := initTailscaleProviders(staticConfiguration, providerAggregator)
 
+	// Private CA
+
+	privateCAProviders := initPrivateCAProviders(staticConfiguration, providerAggregator)
+
 	// Observability
 
 	metricRegistries := registerMetricClients(staticConfiguration.Metrics)
@@ -213,6 +245,11 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	}
 	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
+	for _, p := range acmeProviders {
+		p.SetMetricsRegistry(metricsRegistry)
+	}
+	tlsManager.SetHandshakesCounter(metricsRegistry.TLSHandshakesCounter())
+
 	accessLog := setupAccessLog(ctx, staticConfiguration.AccessLog)
 	tracer, tracerCloser := setupTracing(ctx, staticConfiguration.Tracing)
 	observabilityMgr := middleware.NewObservabilityMgr(*staticConfiguration, metricsRegistry, semConvMetricRegistry, accessLog, tracer, tracerCloser)
@@ -303,7 +340,14 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	dialerManager := tcp.NewDialerManager(spiffeX509Source)
 	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, observabilityMgr, transportManager, proxyBuilder, acmeHTTPHandler)
+	var configurationHistory *history.History
+	if staticConfiguration.ConfigurationHistory != nil {
+		configurationHistory = history.New(staticConfiguration.ConfigurationHistory.MaxEntries)
+	}
+
+	configurationSnapshot := &export.Snapshot{}
+
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, observabilityMgr, transportManager, proxyBuilder, acmeHTTPHandler, tlsManager, configurationHistory, configurationSnapshot)
 
 	// Router factory
 
@@ -321,6 +365,32 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		"internal",
 	)
 
+	// Configuration export, recorded before the secret references are resolved.
+	watcher.AddTransformer(configurationSnapshot.Transform)
+
+	// Secret references, resolved only for the trusted providers.
+	if secretReferences := staticConfiguration.SecretReferences; secretReferences != nil {
+		resolvers := secret.NewResolvers(secretReferences.EnvPrefixes, secretReferences.FilePrefixes)
+		watcher.AddTransformer(resolvers.Transformer(secretReferences.Providers))
+	}
+
+	// Configuration history
+	if configurationHistory != nil {
+		watcher.SetHistory(configurationHistory)
+
+		if maxDisabledRouters := staticConfiguration.ConfigurationHistory.MaxDisabledRouters; maxDisabledRouters > 0 {
+			// The plugins are not instantiated when checking the routers.
+			var guardPluginBuilder middleware.PluginsBuilder = pluginBuilder
+			if pluginBuilder != nil {
+				guardPluginBuilder = &dryRunPluginsBuilder{builder: pluginBuilder}
+			}
+
+			watcher.SetRoutersGuard(maxDisabledRouters, func(conf dynamic.Configuration) (*runtime.Configuration, error) {
+				return buildRuntimeConfiguration(staticConfiguration, guardPluginBuilder, conf)
+			})
+		}
+	}
+
 	// TLS
 	watcher.AddListener(func(conf dynamic.Configuration) {
 		ctx := context.Background()
@@ -378,6 +448,12 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		watcher.AddListener(p.HandleConfigUpdate)
 	}
 
+	// Private CA
+	for _, p := range privateCAProviders {
+		resolverNames[p.ResolverName] = struct{}{}
+		watcher.AddListener(p.HandleConfigUpdate)
+	}
+
 	// Certificate resolver logs
 	watcher.AddListener(func(config dynamic.Configuration) {
 		for rtName, rt := range config.HTTP.Routers {
@@ -456,18 +532,31 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 	localStores := map[string]*acme.LocalStore{}
 
 	var resolvers []*acme.Provider
-	for name, resolver := range c.CertificatesResolvers {
+	// The resolvers are sorted by name, as the first on-demand issuer handling a domain issues its certificate.
+	for _, name := range slices.Sorted(maps.Keys(c.CertificatesResolvers)) {
+		resolver := c.CertificatesResolvers[name]
 		if resolver.ACME == nil {
 			continue
 		}
 
-		if localStores[resolver.ACME.Storage] == nil {
-			localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage, routinesPool)
+		var store acme.Store
+		if resolver.ACME.SharedStorage != nil {
+			sharedStore, err := resolver.ACME.SharedStorage.NewStore(context.Background())
+			if err != nil {
+				log.Error().Err(err).Str("resolver", name).Msg("Unable to create the ACME shared storage, the resolver is skipped from the resolvers list")
+				continue
+			}
+			store = sharedStore
+		} else {
+			if localStores[resolver.ACME.Storage] == nil {
+				localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage, routinesPool)
+			}
+			store = localStores[resolver.ACME.Storage]
 		}
 
 		p := &acme.Provider{
 			Configuration:         resolver.ACME,
-			Store:                 localStores[resolver.ACME.Storage],
+			Store:                 store,
 			ResolverName:          name,
 			HTTPChallengeProvider: httpChallengeProvider,
 			TLSChallengeProvider:  tlsChallengeProvider,
@@ -480,6 +569,10 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 
 		p.SetTLSManager(tlsManager)
 
+		if resolver.ACME.OnDemand != nil {
+			tlsManager.AddOnDemandIssuer(p)
+		}
+
 		p.SetConfigListenerChan(make(chan dynamic.Configuration))
 
 		resolvers = append(resolvers, p)
@@ -509,6 +602,37 @@ func initTailscaleProviders(cfg *static.Configuration, providerAggregator *aggre
 	return providers
 }
 
+// initPrivateCAProviders creates and registers privateca.Provider instances corresponding to the configured private CA certificate resolvers.
+func initPrivateCAProviders(cfg *static.Configuration, providerAggregator *aggregator.ProviderAggregator) []*privateca.Provider {
+	localStores := map[string]*privateca.LocalStore{}
+
+	var providers []*privateca.Provider
+	for name, resolver := range cfg.CertificatesResolvers {
+		if resolver.PrivateCA == nil {
+			continue
+		}
+
+		if localStores[resolver.PrivateCA.Storage] == nil {
+			localStores[resolver.PrivateCA.Storage] = privateca.NewLocalStore(resolver.PrivateCA.Storage)
+		}
+
+		caProvider := &privateca.Provider{
+			Configuration: resolver.PrivateCA,
+			ResolverName:  name,
+			Store:         localStores[resolver.PrivateCA.Storage],
+		}
+
+		if err := providerAggregator.AddProvider(caProvider); err != nil {
+			log.Error().Err(err).Str(logs.ProviderName, name).Msg("Unable to create private CA provider")
+			continue
+		}
+
+		providers = append(providers, caProvider)
+	}
+
+	return providers
+}
+
 func registerMetricClients(metricsConfig *otypes.Metrics) []metrics.Registry {
 	if metricsConfig == nil {
 		return nil
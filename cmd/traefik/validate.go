@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v3/cmd"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/plugins"
+	"github.com/traefik/traefik/v3/pkg/provider/traefik"
+	"github.com/traefik/traefik/v3/pkg/proxy/httputil"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/server"
+	"github.com/traefik/traefik/v3/pkg/server/middleware"
+	"github.com/traefik/traefik/v3/pkg/server/service"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+)
+
+// validationReport holds the elements of the runtime configuration which are not enabled,
+// and the TLS certificates which cannot be loaded.
+type validationReport struct {
+	*runtime.Configuration
+
+	Certificates map[string]string `json:"certificates,omitempty"`
+}
+
+func (r validationReport) isValid() bool {
+	if len(r.Certificates) > 0 {
+		return false
+	}
+
+	for _, status := range r.statuses() {
+		if status == runtime.StatusDisabled {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r validationReport) isEmpty() bool {
+	return len(r.Certificates) == 0 && len(r.statuses()) == 0
+}
+
+func (r validationReport) statuses() []string {
+	var statuses []string
+	for _, rt := range r.Routers {
+		statuses = append(statuses, rt.Status)
+	}
+	for _, svc := range r.Services {
+		statuses = append(statuses, svc.Status)
+	}
+	for _, mw := range r.Middlewares {
+		statuses = append(statuses, mw.Status)
+	}
+	for _, rt := range r.TCPRouters {
+		statuses = append(statuses, rt.Status)
+	}
+	for _, svc := range r.TCPServices {
+		statuses = append(statuses, svc.Status)
+	}
+	for _, mw := range r.TCPMiddlewares {
+		statuses = append(statuses, mw.Status)
+	}
+	for _, rt := range r.UDPRouters {
+		statuses = append(statuses, rt.Status)
+	}
+	for _, svc := range r.UDPServices {
+		statuses = append(statuses, svc.Status)
+	}
+	return statuses
+}
+
+func newValidateCmd(traefikConfiguration *cmd.TraefikCmdConfiguration, loaders []cli.ResourceLoader) (*cli.Command, error) {
+	validateCmd := &cli.Command{
+		Name:          "validate",
+		Description:   `Validates the static configuration, and the dynamic configuration of the file provider, without starting Traefik.`,
+		Configuration: traefikConfiguration,
+		Resources:     loaders,
+		Run: func(_ []string) error {
+			valid, err := runValidate(os.Stdout, &traefikConfiguration.Configuration)
+			if err != nil {
+				fmt.Printf("Invalid configuration: %s\n", err)
+				os.Exit(1)
+			}
+
+			if !valid {
+				os.Exit(1)
+			}
+
+			return nil
+		},
+	}
+
+	err := validateCmd.AddCommand(&cli.Command{
+		Name:        "diff",
+		Description: `Prints the differences between two dynamic configurations, each one being a file or a directory: traefik validate diff OLD NEW.`,
+		AllowArg:    true,
+		Run: func(args []string) error {
+			if len(args) != 2 {
+				return errors.New("two dynamic configurations are expected")
+			}
+
+			return runDiff(os.Stdout, args[0], args[1])
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return validateCmd, nil
+}
+
+// runValidate validates the given static configuration and the dynamic configuration it defines,
+// prints the validation report, and returns whether the configuration is valid.
+func runValidate(w io.Writer, staticConfiguration *static.Configuration) (bool, error) {
+	// The errors are reported in the validation report.
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	staticConfiguration.SetEffectiveConfiguration()
+	if err := staticConfiguration.ValidateConfiguration(); err != nil {
+		return false, err
+	}
+
+	configurations := dynamic.Configurations{}
+
+	internalConfiguration, err := provideInternalConfiguration(staticConfiguration)
+	if err != nil {
+		return false, err
+	}
+	configurations["internal"] = internalConfiguration
+
+	if staticConfiguration.Providers != nil && staticConfiguration.Providers.File != nil {
+		fileConfiguration, err := staticConfiguration.Providers.File.BuildConfiguration()
+		if err != nil {
+			return false, fmt.Errorf("loading file provider configuration: %w", err)
+		}
+		configurations["file"] = fileConfiguration
+	}
+
+	report, err := validateDynamicConfiguration(staticConfiguration, configurations)
+	if err != nil {
+		return false, err
+	}
+
+	if report.isEmpty() {
+		_, err = fmt.Fprintln(w, "Configuration is valid.")
+		return true, err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return false, err
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+	return report.isValid(), err
+}
+
+func provideInternalConfiguration(staticConfiguration *static.Configuration) (*dynamic.Configuration, error) {
+	configurationChan := make(chan dynamic.Message, 1)
+	if err := traefik.New(*staticConfiguration).Provide(configurationChan, nil); err != nil {
+		return nil, err
+	}
+
+	return (<-configurationChan).Configuration, nil
+}
+
+// validateDynamicConfiguration builds the routers, services and middlewares of the given configurations,
+// the same way as when they are applied, and returns the elements in error.
+func validateDynamicConfiguration(staticConfiguration *static.Configuration, configurations dynamic.Configurations) (validationReport, error) {
+	conf := server.MergeConfigurations(configurations, getDefaultsEntrypoints(staticConfiguration))
+
+	report := validationReport{Configuration: &runtime.Configuration{}}
+
+	for _, cert := range conf.TLS.Certificates {
+		if _, err := cert.Certificate.GetCertificate(); err != nil {
+			if report.Certificates == nil {
+				report.Certificates = make(map[string]string)
+			}
+			report.Certificates[cert.Certificate.GetTruncatedCertificateName()] = err.Error()
+		}
+	}
+
+	pluginBuilder, err := createPluginBuilder(staticConfiguration)
+	if err != nil {
+		return validationReport{}, fmt.Errorf("plugins: %w", err)
+	}
+
+	rtConf, err := buildRuntimeConfiguration(staticConfiguration, pluginBuilder, conf)
+	if err != nil {
+		return validationReport{}, err
+	}
+
+	report.Routers = notEnabled(rtConf.Routers, func(rt *runtime.RouterInfo) string { return rt.Status })
+	report.Services = notEnabled(rtConf.Services, func(svc *runtime.ServiceInfo) string { return svc.Status })
+	report.Middlewares = notEnabled(rtConf.Middlewares, func(mw *runtime.MiddlewareInfo) string { return mw.Status })
+	report.TCPRouters = notEnabled(rtConf.TCPRouters, func(rt *runtime.TCPRouterInfo) string { return rt.Status })
+	report.TCPServices = notEnabled(rtConf.TCPServices, func(svc *runtime.TCPServiceInfo) string { return svc.Status })
+	report.TCPMiddlewares = notEnabled(rtConf.TCPMiddlewares, func(mw *runtime.TCPMiddlewareInfo) string { return mw.Status })
+	report.UDPRouters = notEnabled(rtConf.UDPRouters, func(rt *runtime.UDPRouterInfo) string { return rt.Status })
+	report.UDPServices = notEnabled(rtConf.UDPServices, func(svc *runtime.UDPServiceInfo) string { return svc.Status })
+
+	return report, nil
+}
+
+// buildRuntimeConfiguration builds the routers, services and middlewares of the given merged configuration,
+// the same way as when it is applied but without serving them, and returns the resulting runtime configuration.
+// The errors are reported in the runtime configuration, and are not logged.
+func buildRuntimeConfiguration(staticConfiguration *static.Configuration, pluginBuilder middleware.PluginsBuilder, conf dynamic.Configuration) (*runtime.Configuration, error) {
+	// A disabled logger is not stored in the context, in favor of the default one.
+	logger := zerolog.New(io.Discard).Level(zerolog.PanicLevel)
+
+	ctx, cancel := context.WithCancel(logger.WithContext(context.Background()))
+	defer cancel()
+
+	// The health checks are stopped as soon as the routers are built.
+	routinesPool := safe.NewPool(ctx)
+	defer routinesPool.Stop()
+
+	tlsManager := traefiktls.NewManager(nil)
+	tlsManager.UpdateConfigs(ctx, conf.TLS.Stores, conf.TLS.Options, conf.TLS.Certificates)
+
+	transportManager := service.NewTransportManager(nil)
+	transportManager.Update(conf.HTTP.ServersTransports)
+
+	proxyBuilder := httputil.NewProxyBuilder(transportManager, nil)
+	proxyBuilder.Update(conf.HTTP.ServersTransports)
+
+	dialerManager := tcp.NewDialerManager(nil)
+	dialerManager.Update(conf.TCP.ServersTransports)
+
+	// The ACME HTTP challenge handler is only checked for existence.
+	var acmeHTTPHandler http.Handler
+	for _, resolver := range staticConfiguration.CertificatesResolvers {
+		if resolver.ACME != nil && resolver.ACME.HTTPChallenge != nil {
+			acmeHTTPHandler = http.NotFoundHandler()
+			break
+		}
+	}
+
+	// The observability is not set up, as no request is served.
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, nil, transportManager, proxyBuilder, acmeHTTPHandler, tlsManager, nil, nil)
+
+	routerFactory, err := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, nil, pluginBuilder, dialerManager)
+	if err != nil {
+		return nil, fmt.Errorf("creating router factory: %w", err)
+	}
+	routerFactory.DisableLogs()
+
+	rtConf := runtime.NewConfig(conf)
+	routerFactory.CreateRouters(rtConf)
+
+	return rtConf, nil
+}
+
+// dryRunPluginsBuilder builds the plugin middlewares without instantiating them,
+// so that checking a configuration has no side effect, and does not depend on the plugins.
+type dryRunPluginsBuilder struct {
+	builder *plugins.Builder
+}
+
+// Build checks the configuration of the plugin, and returns a constructor of a pass-through handler.
+func (b *dryRunPluginsBuilder) Build(pName string, config map[string]any, middlewareName string) (plugins.Constructor, error) {
+	if _, err := b.builder.Build(pName, config, middlewareName); err != nil {
+		return nil, err
+	}
+
+	return func(_ context.Context, next http.Handler) (http.Handler, error) {
+		return next, nil
+	}, nil
+}
+
+// notEnabled returns the elements whose status is not enabled.
+func notEnabled[V any](elements map[string]V, status func(V) string) map[string]V {
+	var result map[string]V
+	for name, element := range elements {
+		if status(element) == runtime.StatusEnabled {
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]V)
+		}
+		result[name] = element
+	}
+
+	return result
+}
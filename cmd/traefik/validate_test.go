@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/cmd"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/provider/file"
+)
+
+func TestRunValidate(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		dynamic       string
+		expectedValid bool
+		expectedErrs  map[string]string
+	}{
+		{
+			desc: "valid configuration",
+			dynamic: `
+http:
+  routers:
+    foo:
+      rule: Host(` + "`foo.localhost`" + `)
+      service: foo
+  services:
+    foo:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1:8000
+`,
+			expectedValid: true,
+		},
+		{
+			desc: "invalid rule and missing service",
+			dynamic: `
+http:
+  routers:
+    foo:
+      rule: Host(` + "`foo.localhost`" + `
+      service: foo
+    bar:
+      rule: Host(` + "`bar.localhost`" + `)
+      service: bar
+  services:
+    foo:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1:8000
+`,
+			expectedErrs: map[string]string{
+				"foo@file": "error while parsing rule",
+				"bar@file": `the service "bar@file" does not exist`,
+			},
+		},
+		{
+			desc: "middlewares cycle",
+			dynamic: `
+http:
+  routers:
+    foo:
+      rule: Host(` + "`foo.localhost`" + `)
+      service: foo
+      middlewares: [chain1]
+  middlewares:
+    chain1:
+      chain:
+        middlewares: [chain2]
+    chain2:
+      chain:
+        middlewares: [chain1]
+  services:
+    foo:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1:8000
+`,
+			expectedErrs: map[string]string{
+				"foo@file": "recursion detected",
+			},
+		},
+		{
+			desc: "router on the api service",
+			dynamic: `
+http:
+  routers:
+    dashboard:
+      rule: Host(` + "`traefik.localhost`" + `)
+      service: api@internal
+`,
+			expectedValid: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "dynamic.yml")
+			require.NoError(t, os.WriteFile(filename, []byte(test.dynamic), 0o644))
+
+			staticConfiguration := cmd.NewTraefikConfiguration().Configuration
+			staticConfiguration.EntryPoints["web"] = &static.EntryPoint{Address: ":80"}
+			staticConfiguration.API = &static.API{BasePath: "/"}
+			staticConfiguration.Providers.File = &file.Provider{Filename: filename}
+
+			var output bytes.Buffer
+			valid, err := runValidate(&output, &staticConfiguration)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedValid, valid, output.String())
+
+			if test.expectedValid {
+				assert.Equal(t, "Configuration is valid.\n", output.String())
+				return
+			}
+
+			var report struct {
+				Routers map[string]struct {
+					Err    []string `json:"error"`
+					Status string   `json:"status"`
+				} `json:"routers"`
+			}
+			require.NoError(t, json.Unmarshal(output.Bytes(), &report))
+
+			require.Len(t, report.Routers, len(test.expectedErrs))
+			for name, expectedErr := range test.expectedErrs {
+				require.Contains(t, report.Routers, name)
+				assert.Equal(t, "disabled", report.Routers[name].Status)
+				require.Len(t, report.Routers[name].Err, 1)
+				assert.Contains(t, report.Routers[name].Err[0], expectedErr)
+			}
+		})
+	}
+}
+
+func TestRunValidate_certificates(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dynamic.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(`
+tls:
+  certificates:
+    - certFile: foo
+      keyFile: bar
+`), 0o644))
+
+	staticConfiguration := cmd.NewTraefikConfiguration().Configuration
+	staticConfiguration.EntryPoints["web"] = &static.EntryPoint{Address: ":80"}
+	staticConfiguration.Providers.File = &file.Provider{Filename: filename}
+
+	var output bytes.Buffer
+	valid, err := runValidate(&output, &staticConfiguration)
+	require.NoError(t, err)
+
+	assert.False(t, valid)
+	assert.Contains(t, output.String(), `"certificates"`)
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+
+	oldFilename := filepath.Join(dir, "old.yml")
+	require.NoError(t, os.WriteFile(oldFilename, []byte(`
+http:
+  routers:
+    foo:
+      rule: Host(`+"`foo.localhost`"+`)
+      service: foo
+    bar:
+      rule: Host(`+"`bar.localhost`"+`)
+      service: bar
+`), 0o644))
+
+	newFilename := filepath.Join(dir, "new.toml")
+	require.NoError(t, os.WriteFile(newFilename, []byte(`
+[http.routers.foo]
+  rule = "Host(`+"`foo.localhost`"+`)"
+  service = "foo"
+  middlewares = ["baz"]
+[http.routers.bar]
+  rule = "Host(`+"`bar.example.com`"+`)"
+  service = "bar"
+`), 0o644))
+
+	var output bytes.Buffer
+	require.NoError(t, runDiff(&output, oldFilename, newFilename))
+
+	expected := "- http.routers.bar.rule: \"Host(`bar.localhost`)\"\n" +
+		"+ http.routers.bar.rule: \"Host(`bar.example.com`)\"\n" +
+		"+ http.routers.foo.middlewares[0]: \"baz\"\n"
+	assert.Equal(t, expected, output.String())
+
+	output.Reset()
+	require.NoError(t, runDiff(&output, oldFilename, oldFilename))
+	assert.Equal(t, "No differences.\n", output.String())
+}
@@ -9,8 +9,12 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/export"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/redactor"
+	"github.com/traefik/traefik/v3/pkg/server/history"
+	"github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/version"
 )
 
@@ -58,12 +62,26 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	// tlsManager is the source of the TLS information exposed by the API, if any.
+	tlsManager *tls.Manager
+
+	// configurationHistory is the history of the applied dynamic configurations, if enabled.
+	configurationHistory *history.History
+
+	// configurationSnapshot holds the last configurations of the providers, to export them.
+	configurationSnapshot *export.Snapshot
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, tlsManager *tls.Manager, configurationHistory *history.History, configurationSnapshot *export.Snapshot) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.tlsManager = tlsManager
+		handler.configurationHistory = configurationHistory
+		handler.configurationSnapshot = configurationSnapshot
+
+		return handler.createRouter()
 	}
 }
 
@@ -98,6 +116,8 @@ func (h Handler) createRouter() *mux.Router {
 
 	apiRouter.Methods(http.MethodGet).Path("/api/support-dump").HandlerFunc(h.getSupportDump)
 
+	apiRouter.Methods(http.MethodGet).Path("/api/export").HandlerFunc(h.getExport)
+
 	apiRouter.Methods(http.MethodGet).Path("/api/entrypoints").HandlerFunc(h.getEntryPoints)
 	apiRouter.Methods(http.MethodGet).Path("/api/entrypoints/{entryPointID}").HandlerFunc(h.getEntryPoint)
 
@@ -120,6 +140,18 @@ func (h Handler) createRouter() *mux.Router {
 	apiRouter.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	apiRouter.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
+	apiRouter.Methods(http.MethodGet).Path("/api/tls/certificates").HandlerFunc(h.getCertificates)
+	apiRouter.Methods(http.MethodGet).Path("/api/tls/options").HandlerFunc(h.getTLSOptionsList)
+	apiRouter.Methods(http.MethodGet).Path("/api/tls/options/{tlsOptionsID}").HandlerFunc(h.getTLSOptions)
+	apiRouter.Methods(http.MethodGet).Path("/api/tls/ech").HandlerFunc(h.getECHConfigLists)
+	apiRouter.Methods(http.MethodGet).Path("/api/tls/ech/{tlsOptionsID}").HandlerFunc(h.getECHConfigList)
+
+	apiRouter.Methods(http.MethodGet).Path("/api/git").HandlerFunc(h.getGitStatus)
+
+	apiRouter.Methods(http.MethodGet).Path("/api/history").HandlerFunc(h.getHistory)
+	apiRouter.Methods(http.MethodGet).Path("/api/history/diff").HandlerFunc(h.getHistoryDiff)
+	apiRouter.Methods(http.MethodPost).Path("/api/history/{version}/rollback").HandlerFunc(h.rollbackHistory)
+
 	version.Handler{}.Append(apiRouter)
 
 	return router
@@ -142,9 +174,17 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 		}
 	}
 
+	// The credentials, which may have been resolved from secret references, are not exposed.
+	middlewares, err := redactor.Redact(h.runtimeConfiguration.Middlewares)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	result := RunTimeRepresentation{
 		Routers:        h.runtimeConfiguration.Routers,
-		Middlewares:    h.runtimeConfiguration.Middlewares,
+		Middlewares:    middlewares,
 		Services:       siRepr,
 		TCPRouters:     h.runtimeConfiguration.TCPRouters,
 		TCPMiddlewares: h.runtimeConfiguration.TCPMiddlewares,
@@ -155,7 +195,7 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 
 	rw.Header().Set("Content-Type", "application/json")
 
-	err := json.NewEncoder(rw).Encode(result)
+	err = json.NewEncoder(rw).Encode(result)
 	if err != nil {
 		log.Ctx(request.Context()).Error().Err(err).Send()
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/export"
+	"github.com/traefik/traefik/v3/pkg/redactor"
+	"github.com/traefik/traefik/v3/pkg/server/history"
+)
+
+var exportContentTypes = map[string]string{
+	export.FormatYAML:       "application/yaml",
+	export.FormatTOML:       "application/toml",
+	export.FormatLabels:     "text/plain; charset=utf-8",
+	export.FormatKV:         "text/plain; charset=utf-8",
+	export.FormatKubernetes: "application/yaml",
+}
+
+// getExport returns the dynamic configuration of a provider, or of all the providers, in the requested format.
+func (h Handler) getExport(rw http.ResponseWriter, request *http.Request) {
+	if h.configurationSnapshot == nil {
+		writeError(rw, "configuration export is not available", http.StatusNotFound)
+		return
+	}
+
+	format := request.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatYAML
+	}
+
+	if !slices.Contains(export.Formats, format) {
+		writeError(rw, fmt.Sprintf("invalid format %q, must be one of %s", format, strings.Join(export.Formats, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	conf, err := export.Merge(h.configurationSnapshot.Configurations(), request.URL.Query().Get("provider"))
+	if err != nil {
+		if errors.Is(err, export.ErrUnknownProvider) {
+			writeError(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		writeError(rw, err.Error(), http.StatusConflict)
+		return
+	}
+
+	// The credentials are not exposed, like in the other endpoints.
+	redacted, err := redactor.Redact(conf)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The redacted fields are reported, as the exported configuration cannot be used as is.
+	changes, err := history.Diff(conf, redacted)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	notes := make([]string, 0, len(changes))
+	for _, change := range changes {
+		notes = append(notes, change.Path+" redacted")
+	}
+
+	var buf bytes.Buffer
+	if err := export.Encode(&buf, redacted, format, notes...); err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", exportContentTypes[format])
+	_, _ = rw.Write(buf.Bytes())
+}
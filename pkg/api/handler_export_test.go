@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/export"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+)
+
+func TestHandler_Export(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		disabled    bool
+		path        string
+		statusCode  int
+		contentType string
+		expected    string
+	}{
+		{
+			desc:       "export not available",
+			disabled:   true,
+			path:       "/api/export",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:        "all providers as YAML",
+			path:        "/api/export",
+			statusCode:  http.StatusOK,
+			contentType: "application/yaml",
+			expected: `# http.middlewares.auth.basicAuth.users[0] redacted
+
+http:
+  routers:
+    foo:
+      middlewares:
+        - auth
+      service: bar
+      rule: Host(` + "`foo.localhost`" + `)
+  services:
+    bar:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1:8080
+        passHostHeader: true
+  middlewares:
+    auth:
+      basicAuth:
+        users:
+          - xxxx
+tcp: {}
+udp: {}
+tls: {}
+`,
+		},
+		{
+			desc:        "single provider as labels",
+			path:        "/api/export?format=labels&provider=docker",
+			statusCode:  http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			expected: `traefik.http.services.bar.loadbalancer.passhostheader=true
+traefik.http.services.bar.loadbalancer.server.preservepath=false
+traefik.http.services.bar.loadbalancer.server.url=http://127.0.0.1:8080
+`,
+		},
+		{
+			desc:       "unknown provider",
+			path:       "/api/export?provider=consul",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "invalid format",
+			path:       "/api/export?format=json",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}}, &runtime.Configuration{})
+			if !test.disabled {
+				handler.configurationSnapshot = newTestSnapshot()
+			}
+
+			server := httptest.NewServer(handler.createRouter())
+			t.Cleanup(server.Close)
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.statusCode, resp.StatusCode)
+
+			if test.expected == "" {
+				return
+			}
+
+			assert.Equal(t, test.contentType, resp.Header.Get("Content-Type"))
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(contents))
+		})
+	}
+}
+
+func newTestSnapshot() *export.Snapshot {
+	snapshot := &export.Snapshot{}
+	snapshot.Transform(context.Background(), dynamic.Configurations{
+		"file": {
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"foo": {Rule: "Host(`foo.localhost`)", Service: "bar@docker", Middlewares: []string{"auth"}},
+				},
+				Middlewares: map[string]*dynamic.Middleware{
+					"auth": {BasicAuth: &dynamic.BasicAuth{Users: []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}}},
+				},
+			},
+		},
+		"docker": {
+			HTTP: &dynamic.HTTPConfiguration{
+				Services: map[string]*dynamic.Service{
+					"bar": {LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers:        []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+						PassHostHeader: pointer(true),
+					}},
+				},
+			},
+		},
+		"internal": {
+			HTTP: &dynamic.HTTPConfiguration{
+				Services: map[string]*dynamic.Service{"api": {}},
+			},
+		},
+	})
+
+	return snapshot
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (h Handler) getGitStatus(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	if h.staticConfig.Providers == nil || h.staticConfig.Providers.Git == nil {
+		writeError(rw, "git provider is not enabled", http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(rw).Encode(h.staticConfig.Providers.Git.Status())
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
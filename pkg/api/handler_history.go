@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/server/history"
+)
+
+type historyDiffRepresentation struct {
+	From    int              `json:"from"`
+	To      int              `json:"to"`
+	Changes []history.Change `json:"changes"`
+}
+
+func (h Handler) getHistory(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	if h.configurationHistory == nil {
+		writeError(rw, "configuration history is not enabled", http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(rw).Encode(h.configurationHistory.Entries())
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getHistoryDiff returns the changes between the from and to versions.
+// The to version defaults to the latest applied one, and the from version to the applied one preceding the to version.
+func (h Handler) getHistoryDiff(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	if h.configurationHistory == nil {
+		writeError(rw, "configuration history is not enabled", http.StatusNotFound)
+		return
+	}
+
+	to := h.configurationHistory.Latest()
+	if raw := request.URL.Query().Get("to"); raw != "" {
+		var err error
+		if to, err = strconv.Atoi(raw); err != nil {
+			writeError(rw, fmt.Sprintf("invalid to version: %s", raw), http.StatusBadRequest)
+			return
+		}
+	}
+
+	from := h.configurationHistory.Previous(to)
+	if raw := request.URL.Query().Get("from"); raw != "" {
+		var err error
+		if from, err = strconv.Atoi(raw); err != nil {
+			writeError(rw, fmt.Sprintf("invalid from version: %s", raw), http.StatusBadRequest)
+			return
+		}
+	}
+
+	changes, err := h.configurationHistory.Diff(from, to)
+	if err != nil {
+		if errors.Is(err, history.ErrVersionNotFound) {
+			writeError(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if changes == nil {
+		changes = []history.Change{}
+	}
+
+	err = json.NewEncoder(rw).Encode(historyDiffRepresentation{From: from, To: to, Changes: changes})
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) rollbackHistory(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	if h.configurationHistory == nil {
+		writeError(rw, "configuration history is not enabled", http.StatusNotFound)
+		return
+	}
+
+	version, err := strconv.Atoi(mux.Vars(request)["version"])
+	if err != nil {
+		writeError(rw, fmt.Sprintf("invalid version: %s", mux.Vars(request)["version"]), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.configurationHistory.Rollback(version); err != nil {
+		if errors.Is(err, history.ErrVersionNotFound) {
+			writeError(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		writeError(rw, err.Error(), http.StatusConflict)
+		return
+	}
+
+	rw.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/server/history"
+)
+
+func TestHandler_History(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		disabled   bool
+		method     string
+		path       string
+		statusCode int
+		expected   string
+	}{
+		{
+			desc:       "history not enabled",
+			disabled:   true,
+			method:     http.MethodGet,
+			path:       "/api/history",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "diff with the previous version",
+			method:     http.MethodGet,
+			path:       "/api/history/diff",
+			statusCode: http.StatusOK,
+			expected:   `{"from":1,"to":2,"changes":[{"path":"http.routers.foo.rule","old":"Host(` + "`foo.localhost`" + `)","new":"Host(` + "`bar.localhost`" + `)"}]}`,
+		},
+		{
+			desc:       "diff between versions",
+			method:     http.MethodGet,
+			path:       "/api/history/diff?from=2&to=2",
+			statusCode: http.StatusOK,
+			expected:   `{"from":2,"to":2,"changes":[]}`,
+		},
+		{
+			desc:       "diff with unknown version",
+			method:     http.MethodGet,
+			path:       "/api/history/diff?from=3",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "diff with invalid version",
+			method:     http.MethodGet,
+			path:       "/api/history/diff?from=foo",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc:       "rollback",
+			method:     http.MethodPost,
+			path:       "/api/history/1/rollback",
+			statusCode: http.StatusAccepted,
+		},
+		{
+			desc:       "rollback to unknown version",
+			method:     http.MethodPost,
+			path:       "/api/history/3/rollback",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}}, &runtime.Configuration{})
+			if !test.disabled {
+				handler.configurationHistory = newTestHistory()
+			}
+
+			server := httptest.NewServer(handler.createRouter())
+			t.Cleanup(server.Close)
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.statusCode, resp.StatusCode)
+
+			if test.expected == "" {
+				return
+			}
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, test.expected, string(contents))
+		})
+	}
+}
+
+func TestHandler_History_entries(t *testing.T) {
+	handler := New(static.Configuration{API: &static.API{}}, &runtime.Configuration{})
+	handler.configurationHistory = newTestHistory()
+
+	server := httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/history")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var entries []history.Entry
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[0].Version)
+	assert.Equal(t, []string{"file"}, entries[0].Providers)
+	assert.Equal(t, []string{"file"}, entries[0].ChangedProviders)
+	assert.False(t, entries[0].Date.IsZero())
+	assert.Equal(t, 1, entries[1].Version)
+}
+
+func newTestHistory() *history.History {
+	h := history.New(10)
+	for _, rule := range []string{"Host(`foo.localhost`)", "Host(`bar.localhost`)"} {
+		conf := &dynamic.Configuration{HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{"foo": {Rule: rule, Service: "foo"}},
+		}}
+		h.Add(dynamic.Configurations{"file": conf}, *conf, 0)
+	}
+
+	return h
+}
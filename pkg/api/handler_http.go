@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/redactor"
 	"github.com/traefik/traefik/v3/pkg/tls"
 )
 
@@ -62,13 +63,19 @@ type middlewareRepresentation struct {
 	Type     string `json:"type,omitempty"`
 }
 
-func newMiddlewareRepresentation(name string, mi *runtime.MiddlewareInfo) middlewareRepresentation {
+func newMiddlewareRepresentation(name string, mi *runtime.MiddlewareInfo) (middlewareRepresentation, error) {
+	// The credentials, which may have been resolved from secret references, are not exposed.
+	redacted, err := redactor.Redact(mi)
+	if err != nil {
+		return middlewareRepresentation{}, fmt.Errorf("redacting middleware %s: %w", name, err)
+	}
+
 	return middlewareRepresentation{
-		MiddlewareInfo: mi,
+		MiddlewareInfo: redacted,
 		Name:           name,
 		Provider:       getProviderName(name),
 		Type:           strings.ToLower(extractType(mi.Middleware)),
-	}
+	}, nil
 }
 
 func (h Handler) getRouters(rw http.ResponseWriter, request *http.Request) {
@@ -192,9 +199,18 @@ func (h Handler) getMiddlewares(rw http.ResponseWriter, request *http.Request) {
 	criterion := newSearchCriterion(query)
 
 	for name, mi := range h.runtimeConfiguration.Middlewares {
-		if keepMiddleware(name, mi, criterion) {
-			results = append(results, newMiddlewareRepresentation(name, mi))
+		if !keepMiddleware(name, mi, criterion) {
+			continue
+		}
+
+		result, err := newMiddlewareRepresentation(name, mi)
+		if err != nil {
+			log.Ctx(request.Context()).Error().Err(err).Send()
+			writeError(rw, err.Error(), http.StatusInternalServerError)
+			return
 		}
+
+		results = append(results, result)
 	}
 
 	sortMiddlewares(query, results)
@@ -233,7 +249,12 @@ func (h Handler) getMiddleware(rw http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	result := newMiddlewareRepresentation(middlewareID, middleware)
+	result, err := newMiddlewareRepresentation(middlewareID, middleware)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	err = json.NewEncoder(rw).Encode(result)
 	if err != nil {
@@ -83,6 +83,30 @@ func TestHandler_SupportDump(t *testing.T) {
 				assert.Contains(t, string(files["runtime-config.json"]), `"test-service"`)
 			},
 		},
+		{
+			desc:       "with credentials",
+			path:       "/api/support-dump",
+			confStatic: static.Configuration{API: &static.API{}, Global: &static.Global{}},
+			confDyn: runtime.Configuration{
+				Middlewares: map[string]*runtime.MiddlewareInfo{
+					"auth@file": {
+						Middleware: &dynamic.Middleware{
+							BasicAuth: &dynamic.BasicAuth{Users: []string{"admin:secret"}},
+						},
+						Status: runtime.StatusEnabled,
+					},
+				},
+			},
+			validate: func(t *testing.T, files map[string][]byte) {
+				t.Helper()
+
+				require.Contains(t, files, "runtime-config.json")
+
+				// Verify runtime config does not contain the credentials
+				assert.Contains(t, string(files["runtime-config.json"]), `"users":["xxxx"]`)
+				assert.NotContains(t, string(files["runtime-config.json"]), "secret")
+			},
+		},
 	}
 
 	for _, test := range testCases {
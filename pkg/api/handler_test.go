@@ -207,7 +207,7 @@ func TestHandler_GetMiddleware(t *testing.T) {
 				MiddlewareInfo: &runtime.MiddlewareInfo{
 					Middleware: &dynamic.Middleware{
 						BasicAuth: &dynamic.BasicAuth{
-							Users: []string{"admin:admin"},
+							Users: []string{"xxxx"},
 						},
 					},
 				},
@@ -0,0 +1,440 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls"
+)
+
+type echConfigListRepresentation struct {
+	Name        string   `json:"name,omitempty"`
+	PublicNames []string `json:"publicNames,omitempty"`
+	// ConfigList is the base64 encoded ECHConfigList, as expected by the ech parameter of the DNS HTTPS records.
+	ConfigList string `json:"configList,omitempty"`
+}
+
+func newECHConfigListRepresentation(name string, configList tls.ECHConfigList) echConfigListRepresentation {
+	return echConfigListRepresentation{
+		Name:        name,
+		PublicNames: configList.PublicNames,
+		ConfigList:  base64.StdEncoding.EncodeToString(configList.ConfigList),
+	}
+}
+
+func (h Handler) getECHConfigLists(rw http.ResponseWriter, request *http.Request) {
+	var configLists map[string]tls.ECHConfigList
+	if h.tlsManager != nil {
+		configLists = h.tlsManager.GetECHConfigLists()
+	}
+
+	results := make([]echConfigListRepresentation, 0, len(configLists))
+	for name, configList := range configLists {
+		results = append(results, newECHConfigListRepresentation(name, configList))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getECHConfigList(rw http.ResponseWriter, request *http.Request) {
+	scapedTLSOptionsID := mux.Vars(request)["tlsOptionsID"]
+
+	tlsOptionsID, err := url.PathUnescape(scapedTLSOptionsID)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("unable to decode tlsOptionsID %q: %s", scapedTLSOptionsID, err), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	var configLists map[string]tls.ECHConfigList
+	if h.tlsManager != nil {
+		configLists = h.tlsManager.GetECHConfigLists()
+	}
+
+	configList, ok := configLists[tlsOptionsID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("ECH configuration not found: %s", tlsOptionsID), http.StatusNotFound)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(newECHConfigListRepresentation(tlsOptionsID, configList))
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type ocspStapleRepresentation struct {
+	Status     string     `json:"status,omitempty"`
+	ThisUpdate *time.Time `json:"thisUpdate,omitempty"`
+	NextUpdate *time.Time `json:"nextUpdate,omitempty"`
+}
+
+type certificateRepresentation struct {
+	Store string `json:"store,omitempty"`
+	// Source is the provider of the certificate, or default/generated for the store default certificates.
+	Source string `json:"source,omitempty"`
+	// Resolver is the certificate resolver which obtained the certificate, if any.
+	Resolver     string                    `json:"resolver,omitempty"`
+	Default      bool                      `json:"default,omitempty"`
+	Subject      string                    `json:"subject,omitempty"`
+	SANs         []string                  `json:"sans,omitempty"`
+	Issuer       string                    `json:"issuer,omitempty"`
+	SerialNumber string                    `json:"serialNumber,omitempty"`
+	NotBefore    time.Time                 `json:"notBefore"`
+	NotAfter     time.Time                 `json:"notAfter"`
+	OCSP         *ocspStapleRepresentation `json:"ocsp,omitempty"`
+	Routers      []string                  `json:"routers,omitempty"`
+	TCPRouters   []string                  `json:"tcpRouters,omitempty"`
+	EntryPoints  []string                  `json:"entryPoints,omitempty"`
+}
+
+func newCertificateRepresentation(cert tls.CertificateInfo, usages []tlsUsage) certificateRepresentation {
+	repr := certificateRepresentation{
+		Store:        cert.Store,
+		Source:       cert.Source,
+		Default:      cert.Default,
+		Subject:      cert.Leaf.Subject.String(),
+		SANs:         slices.Clone(cert.Leaf.DNSNames),
+		Issuer:       cert.Leaf.Issuer.String(),
+		SerialNumber: cert.Leaf.SerialNumber.String(),
+		NotBefore:    cert.Leaf.NotBefore.UTC(),
+		NotAfter:     cert.Leaf.NotAfter.UTC(),
+	}
+
+	// The certificates of the resolvers are provided by the <resolver>.<type> providers (e.g. myresolver.acme).
+	if resolver, _, ok := strings.Cut(cert.Source, "."); ok {
+		repr.Resolver = resolver
+	}
+
+	for _, ip := range cert.Leaf.IPAddresses {
+		repr.SANs = append(repr.SANs, ip.String())
+	}
+
+	if cert.OCSP != nil {
+		repr.OCSP = &ocspStapleRepresentation{Status: cert.OCSP.Status}
+		if !cert.OCSP.ThisUpdate.IsZero() {
+			thisUpdate := cert.OCSP.ThisUpdate.UTC()
+			repr.OCSP.ThisUpdate = &thisUpdate
+		}
+		if !cert.OCSP.NextUpdate.IsZero() {
+			nextUpdate := cert.OCSP.NextUpdate.UTC()
+			repr.OCSP.NextUpdate = &nextUpdate
+		}
+	}
+
+	repr.Routers, repr.TCPRouters, repr.EntryPoints = mergeTLSUsages(usages)
+
+	return repr
+}
+
+type tlsOptionsRepresentation struct {
+	tls.Options
+
+	Name        string   `json:"name,omitempty"`
+	Provider    string   `json:"provider,omitempty"`
+	Routers     []string `json:"routers,omitempty"`
+	TCPRouters  []string `json:"tcpRouters,omitempty"`
+	EntryPoints []string `json:"entryPoints,omitempty"`
+}
+
+func newTLSOptionsRepresentation(name string, options tls.Options, usages []tlsUsage) tlsOptionsRepresentation {
+	// The ECH private keys, and the session ticket keys, must not be exposed.
+	if options.ECH != nil {
+		options.ECH = &tls.ECH{GracePeriod: options.ECH.GracePeriod}
+	}
+	if options.SessionTickets != nil {
+		sessionTickets := *options.SessionTickets
+		sessionTickets.Keys = nil
+		options.SessionTickets = &sessionTickets
+	}
+
+	repr := tlsOptionsRepresentation{
+		Options: options,
+		Name:    name,
+	}
+
+	if strings.Contains(name, "@") {
+		repr.Provider = getProviderName(name)
+	}
+
+	var optionsUsages []tlsUsage
+	for _, usage := range usages {
+		if usage.options == name {
+			optionsUsages = append(optionsUsages, usage)
+		}
+	}
+
+	repr.Routers, repr.TCPRouters, repr.EntryPoints = mergeTLSUsages(optionsUsages)
+
+	return repr
+}
+
+// tlsUsage describes a router terminating TLS.
+type tlsUsage struct {
+	router  string
+	tcp     bool
+	options string
+	domains []string
+
+	entryPoints []string
+}
+
+// getTLSUsages returns the enabled routers terminating TLS.
+func (h Handler) getTLSUsages() []tlsUsage {
+	var usages []tlsUsage
+
+	for name, rt := range h.runtimeConfiguration.Routers {
+		if rt.TLS == nil || rt.Status == runtime.StatusDisabled {
+			continue
+		}
+
+		// Domains which cannot be parsed are ignored, as the router would have been disabled.
+		domains, _ := httpmuxer.ParseDomains(rt.Rule)
+		for _, domain := range rt.TLS.Domains {
+			domains = append(domains, domain.ToStrArray()...)
+		}
+
+		usages = append(usages, tlsUsage{
+			router:      name,
+			options:     qualifyTLSOptionsName(name, rt.TLS.Options),
+			domains:     domains,
+			entryPoints: rt.EntryPoints,
+		})
+	}
+
+	for name, rt := range h.runtimeConfiguration.TCPRouters {
+		if rt.TLS == nil || rt.TLS.Passthrough || rt.Status == runtime.StatusDisabled {
+			continue
+		}
+
+		domains, _ := tcpmuxer.ParseHostSNI(rt.Rule)
+		for _, domain := range rt.TLS.Domains {
+			domains = append(domains, domain.ToStrArray()...)
+		}
+
+		usages = append(usages, tlsUsage{
+			router:      name,
+			tcp:         true,
+			options:     qualifyTLSOptionsName(name, rt.TLS.Options),
+			domains:     slices.DeleteFunc(domains, func(domain string) bool { return domain == "*" }),
+			entryPoints: rt.EntryPoints,
+		})
+	}
+
+	return usages
+}
+
+// qualifyTLSOptionsName returns the name of the TLS options used by the router,
+// qualified with the router provider as done when building the routers.
+func qualifyTLSOptionsName(routerName, options string) string {
+	if options == "" || options == tls.DefaultTLSConfigName {
+		return tls.DefaultTLSConfigName
+	}
+
+	if strings.Contains(options, "@") || !strings.Contains(routerName, "@") {
+		return options
+	}
+
+	return options + "@" + getProviderName(routerName)
+}
+
+// certificateUsages returns the routers serving the certificate.
+// Only the certificates of the default store are served,
+// and the default certificate is served for the domains not matching any other certificate.
+func certificateUsages(cert tls.CertificateInfo, certs []tls.CertificateInfo, usages []tlsUsage) []tlsUsage {
+	if cert.Store != tls.DefaultTLSStoreName {
+		return nil
+	}
+
+	var result []tlsUsage
+	for _, usage := range usages {
+		if slices.ContainsFunc(usage.domains, func(domain string) bool {
+			return servesCertificate(cert, certs, domain)
+		}) || (cert.Default && len(usage.domains) == 0) {
+			result = append(result, usage)
+		}
+	}
+
+	return result
+}
+
+func servesCertificate(cert tls.CertificateInfo, certs []tls.CertificateInfo, domain string) bool {
+	if cert.MatchDomain(domain) {
+		return true
+	}
+
+	return cert.Default && !slices.ContainsFunc(certs, func(c tls.CertificateInfo) bool {
+		return c.Store == cert.Store && c.MatchDomain(domain)
+	})
+}
+
+// mergeTLSUsages returns the sorted HTTP routers, TCP routers, and entry points of the usages.
+func mergeTLSUsages(usages []tlsUsage) ([]string, []string, []string) {
+	var routers, tcpRouters, entryPoints []string
+	for _, usage := range usages {
+		if usage.tcp {
+			tcpRouters = append(tcpRouters, usage.router)
+		} else {
+			routers = append(routers, usage.router)
+		}
+
+		for _, entryPoint := range usage.entryPoints {
+			if !slices.Contains(entryPoints, entryPoint) {
+				entryPoints = append(entryPoints, entryPoint)
+			}
+		}
+	}
+
+	slices.Sort(routers)
+	slices.Sort(tcpRouters)
+	slices.Sort(entryPoints)
+
+	return routers, tcpRouters, entryPoints
+}
+
+func (h Handler) getCertificates(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	query := request.URL.Query()
+
+	var expiresBefore time.Time
+	if expiresWithin := query.Get("expiresWithin"); expiresWithin != "" {
+		window, err := time.ParseDuration(expiresWithin)
+		if err != nil {
+			writeError(rw, fmt.Sprintf("invalid expiresWithin parameter %q: %s", expiresWithin, err), http.StatusBadRequest)
+			return
+		}
+
+		expiresBefore = time.Now().Add(window)
+	}
+
+	var certs []tls.CertificateInfo
+	if h.tlsManager != nil {
+		certs = h.tlsManager.GetCertificates()
+	}
+
+	usages := h.getTLSUsages()
+
+	results := make([]certificateRepresentation, 0, len(certs))
+	for _, cert := range certs {
+		if store := query.Get("store"); store != "" && cert.Store != store {
+			continue
+		}
+
+		if !expiresBefore.IsZero() && !cert.Leaf.NotAfter.Before(expiresBefore) {
+			continue
+		}
+
+		results = append(results, newCertificateRepresentation(cert, certificateUsages(cert, certs, usages)))
+	}
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getTLSOptionsList(rw http.ResponseWriter, request *http.Request) {
+	var options map[string]tls.Options
+	if h.tlsManager != nil {
+		options = h.tlsManager.GetOptions()
+	}
+
+	usages := h.getTLSUsages()
+
+	results := make([]tlsOptionsRepresentation, 0, len(options))
+	for name, opts := range options {
+		results = append(results, newTLSOptionsRepresentation(name, opts, usages))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getTLSOptions(rw http.ResponseWriter, request *http.Request) {
+	scapedTLSOptionsID := mux.Vars(request)["tlsOptionsID"]
+
+	tlsOptionsID, err := url.PathUnescape(scapedTLSOptionsID)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("unable to decode tlsOptionsID %q: %s", scapedTLSOptionsID, err), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	var options map[string]tls.Options
+	if h.tlsManager != nil {
+		options = h.tlsManager.GetOptions()
+	}
+
+	opts, ok := options[tlsOptionsID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("TLS options not found: %s", tlsOptionsID), http.StatusNotFound)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(newTLSOptionsRepresentation(tlsOptionsID, opts, h.getTLSUsages()))
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
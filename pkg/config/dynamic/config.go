@@ -25,6 +25,47 @@ type Configuration struct {
 	TCP  *TCPConfiguration  `json:"tcp,omitempty" toml:"tcp,omitempty" yaml:"tcp,omitempty" export:"true"`
 	UDP  *UDPConfiguration  `json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty" export:"true"`
 	TLS  *TLSConfiguration  `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+
+	// Namespaces are set by the Kubernetes providers, and cannot be defined by the configuration itself.
+	Namespaces *Namespaces `json:"-" toml:"-" yaml:"-" label:"-" file:"-" kv:"-"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Namespaces holds the Kubernetes namespaces of the elements of a configuration, by element name.
+// They identify the namespace owning an element, which cannot be told from its name,
+// as both a namespace and the name of a Kubernetes resource can contain dashes.
+type Namespaces struct {
+	Routers        map[string]string
+	Middlewares    map[string]string
+	Services       map[string]string
+	TCPRouters     map[string]string
+	TCPMiddlewares map[string]string
+	TCPServices    map[string]string
+	UDPRouters     map[string]string
+	UDPServices    map[string]string
+
+	TLSOptions           map[string]string
+	ServersTransports    map[string]string
+	TCPServersTransports map[string]string
+}
+
+// NewNamespaces creates an empty Namespaces.
+func NewNamespaces() *Namespaces {
+	return &Namespaces{
+		Routers:        make(map[string]string),
+		Middlewares:    make(map[string]string),
+		Services:       make(map[string]string),
+		TCPRouters:     make(map[string]string),
+		TCPMiddlewares: make(map[string]string),
+		TCPServices:    make(map[string]string),
+		UDPRouters:     make(map[string]string),
+		UDPServices:    make(map[string]string),
+
+		TLSOptions:           make(map[string]string),
+		ServersTransports:    make(map[string]string),
+		TCPServersTransports: make(map[string]string),
+	}
 }
 
 // +k8s:deepcopy-gen=true
@@ -67,6 +67,7 @@ type Service struct {
 	Weighted            *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
 	Mirroring           *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
 	Failover            *Failover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
+	Labels              map[string]string    `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
 }
 
 // Merge merges another Service into this one.
@@ -95,6 +96,7 @@ type Router struct {
 	Priority                    int                                `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
 	TLS                         *RouterTLSConfig                   `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Observability               *RouterObservabilityConfig         `json:"observability,omitempty" toml:"observability,omitempty" yaml:"observability,omitempty" export:"true"`
+	Labels                      map[string]string                  `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
 	DefaultRule                 bool                               `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
 	DeniedEncodedPathCharacters *RouterDeniedEncodedPathCharacters `json:"-" toml:"-" yaml:"-" label:"-" file:"-" kv:"-"`
 }
@@ -374,6 +376,9 @@ type ServersLoadBalancer struct {
 	ResponseForwarding *ResponseForwarding       `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string                    `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
 
+	// WebSocket defines the limits applied to the WebSocket connections proxied to the servers.
+	WebSocket *WebSocket `json:"webSocket,omitempty" toml:"webSocket,omitempty" yaml:"webSocket,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+
 	// NginxUpstreamHashBy enables the customization of the hashing key.
 	// It can be set to a specific text value, a NGINX variable or a combination of both.
 	NginxUpstreamHashBy string `json:"nginxUpstreamHashBy,omitempty" toml:"-" yaml:"-" label:"-" file:"-" kv:"-" export:"true"`
@@ -428,6 +433,19 @@ func (l *ServersLoadBalancer) mergeable(loadBalancer *ServersLoadBalancer) bool
 
 // +k8s:deepcopy-gen=true
 
+// WebSocket holds the WebSocket connections configuration.
+type WebSocket struct {
+	// IdleTimeout defines the maximum duration a WebSocket connection can stay idle,
+	// i.e. without any frame exchanged, including the ping and pong control frames.
+	// Zero means no timeout.
+	IdleTimeout ptypes.Duration `json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	// MaxDuration defines the maximum duration of a WebSocket connection.
+	// Zero means no limit.
+	MaxDuration ptypes.Duration `json:"maxDuration,omitempty" toml:"maxDuration,omitempty" yaml:"maxDuration,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds the response forwarding configuration.
 type ResponseForwarding struct {
 	// FlushInterval defines the interval, in milliseconds, in between flushes to the client while copying the response body.
@@ -512,11 +530,21 @@ type ServersTransport struct {
 	CipherSuites        []string                `description:"Defines the cipher suites to use when contacting backend servers." json:"cipherSuites,omitempty" toml:"cipherSuites,omitempty" yaml:"cipherSuites,omitempty" export:"true"`
 	MinVersion          string                  `description:"Defines the minimum TLS version to use when contacting backend servers." json:"minVersion,omitempty" toml:"minVersion,omitempty" yaml:"minVersion,omitempty" export:"true"`
 	MaxVersion          string                  `description:"Defines the maximum TLS version to use when contacting backend servers." json:"maxVersion,omitempty" toml:"maxVersion,omitempty" yaml:"maxVersion,omitempty" export:"true"`
+	CurvePreferences    []string                `description:"Defines the preferred elliptic curves and key exchange groups, including the hybrid post-quantum X25519MLKEM768, to use when contacting backend servers." json:"curvePreferences,omitempty" toml:"curvePreferences,omitempty" yaml:"curvePreferences,omitempty" export:"true"`
 	MaxIdleConnsPerHost int                     `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used. If negative, disables connection reuse." json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
 	ForwardingTimeouts  *ForwardingTimeouts     `description:"Defines the timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
 	DisableHTTP2        bool                    `description:"Disables HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
 	PeerCertURI         string                  `description:"Defines the URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
 	Spiffe              *Spiffe                 `description:"Defines the SPIFFE configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	HTTP3               *ServersTransportHTTP3  `description:"Enables HTTP/3 for connections with backend servers using the https scheme." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServersTransportHTTP3 holds the HTTP/3 configuration for the communication with the backend servers.
+type ServersTransportHTTP3 struct {
+	// DisableFallback disables the fallback to HTTP/1.1 and HTTP/2 over TCP when a server cannot be reached with QUIC.
+	DisableFallback bool `description:"Disables the fallback to HTTP/1.1 and HTTP/2 over TCP when a server cannot be reached with QUIC." json:"disableFallback,omitempty" toml:"disableFallback,omitempty" yaml:"disableFallback,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
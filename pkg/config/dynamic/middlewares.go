@@ -44,12 +44,15 @@ type Middleware struct {
 	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
 	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
+	TLSClientCertAuth *TLSClientCertAuth `json:"tlsClientCertAuth,omitempty" toml:"tlsClientCertAuth,omitempty" yaml:"tlsClientCertAuth,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	GrpcWeb           *GrpcWeb           `json:"grpcWeb,omitempty" toml:"grpcWeb,omitempty" yaml:"grpcWeb,omitempty" export:"true"`
 
 	Plugin map[string]PluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty" export:"true"`
 
+	Labels map[string]string `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
+
 	// Gateway API filter middlewares.
 	RequestHeaderModifier  *HeaderModifier  `json:"requestHeaderModifier,omitempty" toml:"-" yaml:"-" label:"-" file:"-" kv:"-" export:"true"`
 	ResponseHeaderModifier *HeaderModifier  `json:"responseHeaderModifier,omitempty" toml:"-" yaml:"-" label:"-" file:"-" kv:"-" export:"true"`
@@ -577,6 +580,17 @@ type PassTLSClientCert struct {
 
 // +k8s:deepcopy-gen=true
 
+// TLSClientCertAuth holds the TLS client certificate authorization middleware configuration.
+// This middleware authorizes the requests based on the attributes of the verified client TLS certificate.
+// More info: https://doc.traefik.io/traefik/v3.7/middlewares/http/tlsclientcertauth/
+type TLSClientCertAuth struct {
+	// Rules defines the rules the client certificate is matched against.
+	// The request is authorized when the client certificate matches at least one of the rules.
+	Rules []TLSClientCertRule `json:"rules,omitempty" toml:"rules,omitempty" yaml:"rules,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // AuthTLSPassCertificateToUpstream holds the pass TLS client cert middleware for ingress-nginx provider.
 type AuthTLSPassCertificateToUpstream struct {
 	ClientAuthType string                `json:"clientAuthType,omitempty" toml:"clientAuthType,omitempty" yaml:"clientAuthType,omitempty" export:"true"`
@@ -862,6 +876,49 @@ type TLSClientCertificateSubjectDNInfo struct {
 
 // +k8s:deepcopy-gen=true
 
+// TLSClientCertRule holds a rule matching the attributes of a client TLS certificate.
+// The certificate matches the rule when it matches all the attributes defined by the rule,
+// and it matches an attribute when one of its values matches one of the given patterns.
+// The patterns support the * wildcard, which matches any sequence of characters except /.
+type TLSClientCertRule struct {
+	// Subject defines the patterns matching the distinguished name of the subject.
+	Subject *TLSClientCertDNRule `json:"subject,omitempty" toml:"subject,omitempty" yaml:"subject,omitempty" export:"true"`
+	// Issuer defines the patterns matching the distinguished name of the issuer.
+	Issuer *TLSClientCertDNRule `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty" export:"true"`
+	// DNSNames defines the patterns matching the DNS names of the Subject Alternative Name.
+	DNSNames []string `json:"dnsNames,omitempty" toml:"dnsNames,omitempty" yaml:"dnsNames,omitempty" export:"true"`
+	// URIs defines the patterns matching the URIs of the Subject Alternative Name, like SPIFFE IDs.
+	URIs []string `json:"uris,omitempty" toml:"uris,omitempty" yaml:"uris,omitempty" export:"true"`
+	// EmailAddresses defines the patterns matching the email addresses of the Subject Alternative Name.
+	EmailAddresses []string `json:"emailAddresses,omitempty" toml:"emailAddresses,omitempty" yaml:"emailAddresses,omitempty" export:"true"`
+	// SerialNumbers defines the allowed serial numbers of the certificate, in decimal notation.
+	SerialNumbers []string `json:"serialNumbers,omitempty" toml:"serialNumbers,omitempty" yaml:"serialNumbers,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TLSClientCertDNRule holds the patterns matching the components of a client TLS certificate distinguished name.
+type TLSClientCertDNRule struct {
+	// Country defines the patterns matching the country.
+	Country []string `json:"country,omitempty" toml:"country,omitempty" yaml:"country,omitempty" export:"true"`
+	// Province defines the patterns matching the province.
+	Province []string `json:"province,omitempty" toml:"province,omitempty" yaml:"province,omitempty" export:"true"`
+	// Locality defines the patterns matching the locality.
+	Locality []string `json:"locality,omitempty" toml:"locality,omitempty" yaml:"locality,omitempty" export:"true"`
+	// Organization defines the patterns matching the organization.
+	Organization []string `json:"organization,omitempty" toml:"organization,omitempty" yaml:"organization,omitempty" export:"true"`
+	// OrganizationalUnit defines the patterns matching the organizational unit.
+	OrganizationalUnit []string `json:"organizationalUnit,omitempty" toml:"organizationalUnit,omitempty" yaml:"organizationalUnit,omitempty" export:"true"`
+	// CommonName defines the patterns matching the common name.
+	CommonName []string `json:"commonName,omitempty" toml:"commonName,omitempty" yaml:"commonName,omitempty" export:"true"`
+	// SerialNumber defines the patterns matching the serial number.
+	SerialNumber []string `json:"serialNumber,omitempty" toml:"serialNumber,omitempty" yaml:"serialNumber,omitempty" export:"true"`
+	// DomainComponent defines the patterns matching the domain component.
+	DomainComponent []string `json:"domainComponent,omitempty" toml:"domainComponent,omitempty" yaml:"domainComponent,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Users holds a list of users.
 type Users []string
 
@@ -9,6 +9,9 @@ import (
 	"github.com/traefik/traefik/v3/pkg/types"
 )
 
+// TCPMirroringDefaultMaxBufferSize is the TCPMirroring.MaxBufferSize option default value.
+const TCPMirroringDefaultMaxBufferSize int64 = 1024 * 1024
+
 // +k8s:deepcopy-gen=true
 
 // TCPConfiguration contains all the TCP configuration parameters.
@@ -33,6 +36,8 @@ type TCPModel struct {
 type TCPService struct {
 	LoadBalancer *TCPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *TCPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
+	Mirroring    *TCPMirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
+	Labels       map[string]string       `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
 }
 
 // Merge merges another TCPService into this one.
@@ -69,6 +74,30 @@ func (w *TCPWRRService) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// TCPMirroring holds the TCP mirroring configuration.
+type TCPMirroring struct {
+	Service       string             `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	MaxBufferSize *int64             `json:"maxBufferSize,omitempty" toml:"maxBufferSize,omitempty" yaml:"maxBufferSize,omitempty" export:"true"`
+	Mirrors       []TCPMirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty" export:"true"`
+	HealthCheck   *HealthCheck       `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// SetDefaults Default values for a TCPMirroring.
+func (m *TCPMirroring) SetDefaults() {
+	defaultMaxBufferSize := TCPMirroringDefaultMaxBufferSize
+	m.MaxBufferSize = &defaultMaxBufferSize
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMirrorService holds the TCP mirror configuration.
+type TCPMirrorService struct {
+	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	Percent int    `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPRouter holds the router configuration.
 type TCPRouter struct {
 	EntryPoints []string `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
@@ -79,6 +108,7 @@ type TCPRouter struct {
 	RuleSyntax string              `json:"ruleSyntax,omitempty" toml:"ruleSyntax,omitempty" yaml:"ruleSyntax,omitempty" export:"true"`
 	Priority   int                 `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
 	TLS        *RouterTCPTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Labels     map[string]string   `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -168,6 +198,9 @@ type ProxyProtocol struct {
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=2
 	Version int `description:"Defines the PROXY Protocol version to use." json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	// TLVs defines the TLVs to send with a PROXY Protocol v2 header.
+	// +kubebuilder:validation:items:Enum=authority;alpn;ssl
+	TLVs []string `description:"Defines the TLVs to send with a PROXY Protocol v2 header (authority, alpn, ssl)." json:"tlvs,omitempty" toml:"tlvs,omitempty" yaml:"tlvs,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a ProxyProtocol.
@@ -207,6 +240,7 @@ type TLSClientConfig struct {
 	InsecureSkipVerify bool                    `description:"Disables SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
 	RootCAs            []types.FileOrContent   `description:"Defines a list of CA certificates used to validate server certificates." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
 	Certificates       traefiktls.Certificates `description:"Defines a list of client certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
+	CurvePreferences   []string                `description:"Defines the preferred elliptic curves and key exchange groups, including the hybrid post-quantum X25519MLKEM768, to use when contacting backend servers." json:"curvePreferences,omitempty" toml:"curvePreferences,omitempty" yaml:"curvePreferences,omitempty" export:"true"`
 	PeerCertURI        string                  `description:"Defines the URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
 	Spiffe             *Spiffe                 `description:"Defines the SPIFFE TLS configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
@@ -6,8 +6,10 @@ package dynamic
 type TCPMiddleware struct {
 	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
 	// Deprecated: please use IPAllowList instead.
-	IPWhiteList *TCPIPWhiteList `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
-	IPAllowList *TCPIPAllowList `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	IPWhiteList       *TCPIPWhiteList       `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	IPAllowList       *TCPIPAllowList       `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	TLSClientCertAuth *TCPTLSClientCertAuth `json:"tlsClientCertAuth,omitempty" toml:"tlsClientCertAuth,omitempty" yaml:"tlsClientCertAuth,omitempty" export:"true"`
+	Labels            map[string]string     `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -42,3 +44,14 @@ type TCPIPAllowList struct {
 	// SourceRange defines the allowed IPs (or ranges of allowed IPs by using CIDR notation).
 	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// TCPTLSClientCertAuth holds the TCP TLS client certificate authorization middleware configuration.
+// This middleware authorizes the connections based on the attributes of the verified client TLS certificate.
+// More info: https://doc.traefik.io/traefik/v3.7/middlewares/tcp/tlsclientcertauth/
+type TCPTLSClientCertAuth struct {
+	// Rules defines the rules the client certificate is matched against.
+	// The connection is authorized when the client certificate matches at least one of the rules.
+	Rules []TLSClientCertRule `json:"rules,omitempty" toml:"rules,omitempty" yaml:"rules,omitempty" export:"true"`
+}
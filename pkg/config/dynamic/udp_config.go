@@ -18,6 +18,7 @@ type UDPConfiguration struct {
 type UDPService struct {
 	LoadBalancer *UDPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *UDPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
+	Labels       map[string]string       `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
 }
 
 // Merge merges another UDPService into this one.
@@ -55,8 +56,9 @@ func (w *UDPWRRService) SetDefaults() {
 
 // UDPRouter defines the configuration for an UDP router.
 type UDPRouter struct {
-	EntryPoints []string `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
-	Service     string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	EntryPoints []string          `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	Service     string            `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Labels      map[string]string `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
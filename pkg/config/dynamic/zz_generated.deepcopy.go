@@ -244,6 +244,11 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(TLSConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = new(Namespaces)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1091,6 +1096,11 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(PassTLSClientCert)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLSClientCertAuth != nil {
+		in, out := &in.TLSClientCertAuth, &out.TLSClientCertAuth
+		*out = new(TLSClientCertAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(Retry)
@@ -1113,6 +1123,13 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.RequestHeaderModifier != nil {
 		in, out := &in.RequestHeaderModifier, &out.RequestHeaderModifier
 		*out = new(HeaderModifier)
@@ -1245,6 +1262,99 @@ func (in *Model) DeepCopy() *Model {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Namespaces) DeepCopyInto(out *Namespaces) {
+	*out = *in
+	if in.Routers != nil {
+		in, out := &in.Routers, &out.Routers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Middlewares != nil {
+		in, out := &in.Middlewares, &out.Middlewares
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TCPRouters != nil {
+		in, out := &in.TCPRouters, &out.TCPRouters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TCPMiddlewares != nil {
+		in, out := &in.TCPMiddlewares, &out.TCPMiddlewares
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TCPServices != nil {
+		in, out := &in.TCPServices, &out.TCPServices
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UDPRouters != nil {
+		in, out := &in.UDPRouters, &out.UDPRouters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UDPServices != nil {
+		in, out := &in.UDPServices, &out.UDPServices
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSOptions != nil {
+		in, out := &in.TLSOptions, &out.TLSOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServersTransports != nil {
+		in, out := &in.ServersTransports, &out.ServersTransports
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TCPServersTransports != nil {
+		in, out := &in.TCPServersTransports, &out.TCPServersTransports
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Namespaces.
+func (in *Namespaces) DeepCopy() *Namespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(Namespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PassTLSClientCert) DeepCopyInto(out *PassTLSClientCert) {
 	*out = *in
@@ -1285,6 +1395,11 @@ func (in *PassiveServerHealthCheck) DeepCopy() *PassiveServerHealthCheck {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
 	*out = *in
+	if in.TLVs != nil {
+		in, out := &in.TLVs, &out.TLVs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1562,6 +1677,13 @@ func (in *Router) DeepCopyInto(out *Router) {
 		*out = new(RouterObservabilityConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.DeniedEncodedPathCharacters != nil {
 		in, out := &in.DeniedEncodedPathCharacters, &out.DeniedEncodedPathCharacters
 		*out = new(RouterDeniedEncodedPathCharacters)
@@ -1762,6 +1884,11 @@ func (in *ServersLoadBalancer) DeepCopyInto(out *ServersLoadBalancer) {
 		*out = new(ResponseForwarding)
 		**out = **in
 	}
+	if in.WebSocket != nil {
+		in, out := &in.WebSocket, &out.WebSocket
+		*out = new(WebSocket)
+		**out = **in
+	}
 	return
 }
 
@@ -1793,6 +1920,11 @@ func (in *ServersTransport) DeepCopyInto(out *ServersTransport) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CurvePreferences != nil {
+		in, out := &in.CurvePreferences, &out.CurvePreferences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ForwardingTimeouts != nil {
 		in, out := &in.ForwardingTimeouts, &out.ForwardingTimeouts
 		*out = new(ForwardingTimeouts)
@@ -1803,6 +1935,11 @@ func (in *ServersTransport) DeepCopyInto(out *ServersTransport) {
 		*out = new(Spiffe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP3 != nil {
+		in, out := &in.HTTP3, &out.HTTP3
+		*out = new(ServersTransportHTTP3)
+		**out = **in
+	}
 	return
 }
 
@@ -1816,6 +1953,22 @@ func (in *ServersTransport) DeepCopy() *ServersTransport {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServersTransportHTTP3) DeepCopyInto(out *ServersTransportHTTP3) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServersTransportHTTP3.
+func (in *ServersTransportHTTP3) DeepCopy() *ServersTransportHTTP3 {
+	if in == nil {
+		return nil
+	}
+	out := new(ServersTransportHTTP3)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -1849,6 +2002,13 @@ func (in *Service) DeepCopyInto(out *Service) {
 		*out = new(Failover)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -2160,6 +2320,18 @@ func (in *TCPMiddleware) DeepCopyInto(out *TCPMiddleware) {
 		*out = new(TCPIPAllowList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLSClientCertAuth != nil {
+		in, out := &in.TLSClientCertAuth, &out.TLSClientCertAuth
+		*out = new(TCPTLSClientCertAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -2173,6 +2345,53 @@ func (in *TCPMiddleware) DeepCopy() *TCPMiddleware {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPMirrorService) DeepCopyInto(out *TCPMirrorService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPMirrorService.
+func (in *TCPMirrorService) DeepCopy() *TCPMirrorService {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPMirrorService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPMirroring) DeepCopyInto(out *TCPMirroring) {
+	*out = *in
+	if in.MaxBufferSize != nil {
+		in, out := &in.MaxBufferSize, &out.MaxBufferSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]TCPMirrorService, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPMirroring.
+func (in *TCPMirroring) DeepCopy() *TCPMirroring {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPMirroring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPModel) DeepCopyInto(out *TCPModel) {
 	*out = *in
@@ -2207,6 +2426,13 @@ func (in *TCPRouter) DeepCopyInto(out *TCPRouter) {
 		*out = new(RouterTCPTLSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -2268,7 +2494,7 @@ func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 	if in.ProxyProtocol != nil {
 		in, out := &in.ProxyProtocol, &out.ProxyProtocol
 		*out = new(ProxyProtocol)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.TerminationDelay != nil {
 		in, out := &in.TerminationDelay, &out.TerminationDelay
@@ -2299,7 +2525,7 @@ func (in *TCPServersTransport) DeepCopyInto(out *TCPServersTransport) {
 	if in.ProxyProtocol != nil {
 		in, out := &in.ProxyProtocol, &out.ProxyProtocol
 		*out = new(ProxyProtocol)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
@@ -2332,6 +2558,18 @@ func (in *TCPService) DeepCopyInto(out *TCPService) {
 		*out = new(TCPWeightedRoundRobin)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(TCPMirroring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -2345,6 +2583,29 @@ func (in *TCPService) DeepCopy() *TCPService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPTLSClientCertAuth) DeepCopyInto(out *TCPTLSClientCertAuth) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]TLSClientCertRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPTLSClientCertAuth.
+func (in *TCPTLSClientCertAuth) DeepCopy() *TCPTLSClientCertAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPTLSClientCertAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPWRRService) DeepCopyInto(out *TCPWRRService) {
 	*out = *in
@@ -2394,6 +2655,131 @@ func (in *TCPWeightedRoundRobin) DeepCopy() *TCPWeightedRoundRobin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSClientCertAuth) DeepCopyInto(out *TLSClientCertAuth) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]TLSClientCertRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSClientCertAuth.
+func (in *TLSClientCertAuth) DeepCopy() *TLSClientCertAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSClientCertAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSClientCertDNRule) DeepCopyInto(out *TLSClientCertDNRule) {
+	*out = *in
+	if in.Country != nil {
+		in, out := &in.Country, &out.Country
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Province != nil {
+		in, out := &in.Province, &out.Province
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Locality != nil {
+		in, out := &in.Locality, &out.Locality
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Organization != nil {
+		in, out := &in.Organization, &out.Organization
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrganizationalUnit != nil {
+		in, out := &in.OrganizationalUnit, &out.OrganizationalUnit
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CommonName != nil {
+		in, out := &in.CommonName, &out.CommonName
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SerialNumber != nil {
+		in, out := &in.SerialNumber, &out.SerialNumber
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DomainComponent != nil {
+		in, out := &in.DomainComponent, &out.DomainComponent
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSClientCertDNRule.
+func (in *TLSClientCertDNRule) DeepCopy() *TLSClientCertDNRule {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSClientCertDNRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSClientCertRule) DeepCopyInto(out *TLSClientCertRule) {
+	*out = *in
+	if in.Subject != nil {
+		in, out := &in.Subject, &out.Subject
+		*out = new(TLSClientCertDNRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Issuer != nil {
+		in, out := &in.Issuer, &out.Issuer
+		*out = new(TLSClientCertDNRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.URIs != nil {
+		in, out := &in.URIs, &out.URIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmailAddresses != nil {
+		in, out := &in.EmailAddresses, &out.EmailAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SerialNumbers != nil {
+		in, out := &in.SerialNumbers, &out.SerialNumbers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSClientCertRule.
+func (in *TLSClientCertRule) DeepCopy() *TLSClientCertRule {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSClientCertRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSClientCertificateInfo) DeepCopyInto(out *TLSClientCertificateInfo) {
 	*out = *in
@@ -2465,6 +2851,11 @@ func (in *TLSClientConfig) DeepCopyInto(out *TLSClientConfig) {
 		*out = make(tls.Certificates, len(*in))
 		copy(*out, *in)
 	}
+	if in.CurvePreferences != nil {
+		in, out := &in.CurvePreferences, &out.CurvePreferences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Spiffe != nil {
 		in, out := &in.Spiffe, &out.Spiffe
 		*out = new(Spiffe)
@@ -2578,6 +2969,13 @@ func (in *UDPRouter) DeepCopyInto(out *UDPRouter) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -2641,6 +3039,13 @@ func (in *UDPService) DeepCopyInto(out *UDPService) {
 		*out = new(UDPWeightedRoundRobin)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -2787,6 +3192,22 @@ func (in *WRRService) DeepCopy() *WRRService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebSocket) DeepCopyInto(out *WebSocket) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebSocket.
+func (in *WebSocket) DeepCopy() *WebSocket {
+	if in == nil {
+		return nil
+	}
+	out := new(WebSocket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WeightedRoundRobin) DeepCopyInto(out *WeightedRoundRobin) {
 	*out = *in
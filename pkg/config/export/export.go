@@ -0,0 +1,216 @@
+// Package export renders a dynamic configuration in the formats read by the providers,
+// so that a configuration can be moved from a provider to another.
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/traefik/paerser/parser"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/kv"
+	"github.com/traefik/traefik/v3/pkg/config/label"
+	"gopkg.in/yaml.v3"
+)
+
+// Export formats.
+const (
+	// FormatYAML is the YAML format of the file provider.
+	FormatYAML = "yaml"
+	// FormatTOML is the TOML format of the file provider.
+	FormatTOML = "toml"
+	// FormatLabels is the Docker labels format, one key=value per line as read by docker --label-file.
+	FormatLabels = "labels"
+	// FormatKV is the format of the KV providers, one key=value per line.
+	FormatKV = "kv"
+	// FormatKubernetes is the format of the Kubernetes CRD provider, as a multi-document YAML manifest.
+	FormatKubernetes = "kubernetes"
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{FormatYAML, FormatTOML, FormatLabels, FormatKV, FormatKubernetes}
+
+// Encode writes the given configuration in the given format.
+// The given notes, and the parts of the configuration which cannot be expressed in the format,
+// are reported as comments at the beginning of the output.
+func Encode(w io.Writer, conf *dynamic.Configuration, format string, notes ...string) error {
+	if conf == nil {
+		conf = &dynamic.Configuration{}
+	}
+
+	switch format {
+	case FormatYAML:
+		writeNotes(w, notes)
+
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		return encoder.Encode(conf)
+
+	case FormatTOML:
+		writeNotes(w, notes)
+
+		return toml.NewEncoder(w).Encode(conf)
+
+	case FormatLabels:
+		labelsConf, labelsNotes := toLabelsConfiguration(conf)
+
+		labels, err := encodeLabels(labelsConf)
+		if err != nil {
+			return err
+		}
+
+		return writePairs(w, labels, slices.Concat(notes, labelsNotes))
+
+	case FormatKV:
+		pairs, err := kv.Encode(conf, parser.DefaultRootName)
+		if err != nil {
+			return err
+		}
+
+		return writePairs(w, pairs, notes)
+
+	case FormatKubernetes:
+		objects, kubernetesNotes := toKubernetesObjects(conf)
+		return writeManifests(w, objects, slices.Concat(notes, kubernetesNotes))
+
+	default:
+		return fmt.Errorf("unsupported export format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// encodeLabels converts a configuration to labels which are decoded back to the same configuration,
+// contrary to label.EncodeConfiguration which keeps the field names and the raw durations.
+func encodeLabels(conf *dynamic.Configuration) (map[string]string, error) {
+	node, err := label.EncodeToNode(conf, parser.DefaultRootName, label.EncoderOpts{TagName: parser.TagLabel, AllowSliceAsStruct: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.EncodeNode(node), nil
+}
+
+// toLabelsConfiguration returns a copy of the configuration restricted to what can be defined with labels.
+func toLabelsConfiguration(conf *dynamic.Configuration) (*dynamic.Configuration, []string) {
+	var notes []string
+
+	labelsConf := conf.DeepCopy()
+
+	if labelsConf.TLS != nil {
+		if len(labelsConf.TLS.Certificates) > 0 {
+			notes = append(notes, "TLS certificates cannot be defined with labels")
+		}
+		if len(labelsConf.TLS.Options) > 0 {
+			notes = append(notes, "TLS options cannot be defined with labels")
+		}
+
+		var stores []string
+		for name := range labelsConf.TLS.Stores {
+			if name != "default" {
+				stores = append(stores, name)
+			}
+		}
+		slices.Sort(stores)
+
+		for _, name := range stores {
+			notes = append(notes, fmt.Sprintf("TLS store %q cannot be defined with labels, only the default one can", name))
+			delete(labelsConf.TLS.Stores, name)
+		}
+
+		labelsConf.TLS.Certificates = nil
+		labelsConf.TLS.Options = nil
+	}
+
+	// With labels, a service has a single server, and the TCP and UDP servers addresses are the ones of the container.
+	if labelsConf.HTTP != nil {
+		for _, name := range sortedKeys(labelsConf.HTTP.Services) {
+			lb := labelsConf.HTTP.Services[name].LoadBalancer
+			if lb == nil || len(lb.Servers) <= 1 {
+				continue
+			}
+
+			notes = append(notes, fmt.Sprintf("service %q has %d servers, only the first one is exported", name, len(lb.Servers)))
+			lb.Servers = lb.Servers[:1]
+		}
+	}
+
+	if labelsConf.TCP != nil {
+		for _, name := range sortedKeys(labelsConf.TCP.Services) {
+			lb := labelsConf.TCP.Services[name].LoadBalancer
+			if lb == nil || len(lb.Servers) == 0 {
+				continue
+			}
+
+			notes = append(notes, fmt.Sprintf("TCP service %q: the server address is the one of the container, only the port of %s is exported", name, lb.Servers[0].Address))
+			lb.Servers = []dynamic.TCPServer{{Port: addressPort(lb.Servers[0].Address), TLS: lb.Servers[0].TLS}}
+		}
+	}
+
+	if labelsConf.UDP != nil {
+		for _, name := range sortedKeys(labelsConf.UDP.Services) {
+			lb := labelsConf.UDP.Services[name].LoadBalancer
+			if lb == nil || len(lb.Servers) == 0 {
+				continue
+			}
+
+			notes = append(notes, fmt.Sprintf("UDP service %q: the server address is the one of the container, only the port of %s is exported", name, lb.Servers[0].Address))
+			lb.Servers = []dynamic.UDPServer{{Port: addressPort(lb.Servers[0].Address)}}
+		}
+	}
+
+	return labelsConf, notes
+}
+
+// writePairs writes the given notes as comments, then the pairs, one key=value per line, sorted by key.
+func writePairs(w io.Writer, pairs map[string]string, notes []string) error {
+	buf := bufio.NewWriter(w)
+
+	writeNotes(buf, notes)
+
+	for _, key := range sortedKeys(pairs) {
+		if strings.ContainsAny(pairs[key], "\r\n") {
+			return fmt.Errorf("the value of %s spans several lines and cannot be exported in this format", key)
+		}
+
+		if _, err := fmt.Fprintf(buf, "%s=%s\n", key, pairs[key]); err != nil {
+			return err
+		}
+	}
+
+	return buf.Flush()
+}
+
+func writeNotes(w io.Writer, notes []string) {
+	for _, note := range notes {
+		_, _ = fmt.Fprintf(w, "# %s\n", note)
+	}
+
+	if len(notes) > 0 {
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// addressPort returns the port of the given host:port address.
+func addressPort(address string) string {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return ""
+	}
+
+	return port
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
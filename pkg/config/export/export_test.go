@@ -0,0 +1,265 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/file"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/kv"
+	"github.com/traefik/traefik/v3/pkg/config/label"
+	"github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestEncode_fileProvider(t *testing.T) {
+	conf := sampleConfiguration()
+
+	for _, format := range []string{FormatYAML, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, conf, format))
+
+			filename := filepath.Join(t.TempDir(), "dynamic."+format)
+			require.NoError(t, os.WriteFile(filename, buf.Bytes(), 0o600))
+
+			decoded := &dynamic.Configuration{}
+			require.NoError(t, file.Decode(filename, decoded))
+
+			assert.Equal(t, conf.HTTP.Routers, decoded.HTTP.Routers)
+			assert.Equal(t, conf.HTTP.Middlewares, decoded.HTTP.Middlewares)
+			assert.Equal(t, conf.HTTP.Services["whoami"].LoadBalancer.Servers, decoded.HTTP.Services["whoami"].LoadBalancer.Servers)
+			assert.Equal(t, conf.TCP.Routers, decoded.TCP.Routers)
+			assert.Equal(t, conf.TLS.Options["modern"].MinVersion, decoded.TLS.Options["modern"].MinVersion)
+		})
+	}
+}
+
+func TestEncode_labels(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, sampleConfiguration(), FormatLabels))
+
+	output := buf.String()
+
+	assert.Contains(t, output, "# TLS options cannot be defined with labels\n")
+	assert.Contains(t, output, "# service \"whoami\" has 2 servers, only the first one is exported\n")
+	assert.Contains(t, output, "traefik.http.middlewares.ratelimit.ratelimit.period=1m0s\n")
+	assert.Contains(t, output, "traefik.tcp.services.postgres.loadbalancer.server.port=5432\n")
+
+	labels := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		labels[key] = value
+	}
+
+	decoded, err := label.DecodeConfiguration(labels)
+	require.NoError(t, err)
+
+	conf := sampleConfiguration()
+	assert.Equal(t, conf.HTTP.Routers, decoded.HTTP.Routers)
+	assert.Equal(t, conf.HTTP.Middlewares, decoded.HTTP.Middlewares)
+	assert.Equal(t, conf.HTTP.Services["whoami"].LoadBalancer.Servers[:1], decoded.HTTP.Services["whoami"].LoadBalancer.Servers)
+}
+
+func TestEncodeLabels(t *testing.T) {
+	conf := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"whoami": {
+					EntryPoints: []string{"web", "websecure"},
+					Service:     "whoami",
+					Rule:        "Host(`whoami.example.com`)",
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{
+				"breaker": {
+					CircuitBreaker: &dynamic.CircuitBreaker{
+						Expression:       "NetworkErrorRatio() > 0.5",
+						CheckPeriod:      ptypes.Duration(10 * time.Second),
+						FallbackDuration: ptypes.Duration(time.Minute),
+						RecoveryDuration: ptypes.Duration(1500 * time.Millisecond),
+					},
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"whoami": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://10.0.0.1:8080"}},
+					},
+				},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"postgres": {
+					EntryPoints: []string{"postgres"},
+					Service:     "postgres",
+					Rule:        "HostSNI(`*`)",
+				},
+			},
+		},
+	}
+
+	labels, err := encodeLabels(conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1m0s", labels["traefik.http.middlewares.breaker.circuitbreaker.fallbackduration"])
+
+	decoded, err := label.DecodeConfiguration(labels)
+	require.NoError(t, err)
+
+	assert.Equal(t, conf.HTTP.Routers, decoded.HTTP.Routers)
+	assert.Equal(t, conf.HTTP.Middlewares, decoded.HTTP.Middlewares)
+	assert.Equal(t, conf.HTTP.Services["whoami"].LoadBalancer.Servers, decoded.HTTP.Services["whoami"].LoadBalancer.Servers)
+	assert.Equal(t, conf.TCP.Routers, decoded.TCP.Routers)
+}
+
+func TestEncode_kv(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, sampleConfiguration(), FormatKV))
+
+	var pairs []*store.KVPair
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		pairs = append(pairs, &store.KVPair{Key: key, Value: []byte(value)})
+	}
+
+	decoded := &dynamic.Configuration{}
+	require.NoError(t, kv.Decode(pairs, decoded, "traefik"))
+
+	conf := sampleConfiguration()
+	assert.Equal(t, conf.HTTP.Routers, decoded.HTTP.Routers)
+	assert.Equal(t, conf.HTTP.Middlewares, decoded.HTTP.Middlewares)
+	assert.Equal(t, conf.HTTP.Services["whoami"].LoadBalancer.Servers, decoded.HTTP.Services["whoami"].LoadBalancer.Servers)
+	assert.Equal(t, conf.TCP.Routers, decoded.TCP.Routers)
+}
+
+func TestEncode_kubernetes(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, sampleConfiguration(), FormatKubernetes))
+
+	expected := `# TLS option "modern" is not exported: json: unknown field "caFiles"
+# service "whoami": the Kubernetes Service "whoami" must select the servers http://10.0.0.1:8080, http://10.0.0.2:8080
+# TCP service "postgres": the Kubernetes Service "postgres" must select the servers 10.0.0.3:5432
+
+apiVersion: traefik.io/v1alpha1
+kind: Middleware
+metadata:
+  name: ratelimit
+spec:
+  rateLimit:
+    average: 100
+    burst: 50
+    period: 1m0s
+---
+apiVersion: traefik.io/v1alpha1
+kind: IngressRoute
+metadata:
+  name: whoami
+spec:
+  entryPoints:
+  - websecure
+  routes:
+  - kind: Rule
+    match: Host(` + "`whoami.example.com`" + `)
+    middlewares:
+    - name: ratelimit
+    - name: auth@docker
+    services:
+    - name: whoami
+      port: 8080
+  tls:
+    certResolver: letsencrypt
+---
+apiVersion: traefik.io/v1alpha1
+kind: IngressRouteTCP
+metadata:
+  name: postgres
+spec:
+  entryPoints:
+  - postgres
+  routes:
+  - match: HostSNI(` + "`*`" + `)
+    services:
+    - name: postgres
+      port: 5432
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestEncode_unsupportedFormat(t *testing.T) {
+	err := Encode(&bytes.Buffer{}, sampleConfiguration(), "json")
+	require.Error(t, err)
+}
+
+func sampleConfiguration() *dynamic.Configuration {
+	return &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"whoami": {
+					EntryPoints: []string{"websecure"},
+					Middlewares: []string{"ratelimit", "auth@docker"},
+					Service:     "whoami",
+					Rule:        "Host(`whoami.example.com`)",
+					TLS:         &dynamic.RouterTLSConfig{CertResolver: "letsencrypt"},
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{
+				"ratelimit": {
+					RateLimit: &dynamic.RateLimit{
+						Average: 100,
+						Period:  ptypes.Duration(time.Minute),
+						Burst:   50,
+					},
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"whoami": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{
+							{URL: "http://10.0.0.1:8080"},
+							{URL: "http://10.0.0.2:8080"},
+						},
+					},
+				},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"postgres": {
+					EntryPoints: []string{"postgres"},
+					Service:     "postgres",
+					Rule:        "HostSNI(`*`)",
+				},
+			},
+			Services: map[string]*dynamic.TCPService{
+				"postgres": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "10.0.0.3:5432"}},
+					},
+				},
+			},
+		},
+		TLS: &dynamic.TLSConfiguration{
+			Options: map[string]tls.Options{
+				"modern": {
+					MinVersion: "VersionTLS13",
+					ClientAuth: tls.ClientAuth{CAFiles: []types.FileOrContent{"ca.pem"}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,640 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefikv1alpha1 "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// toKubernetesObjects converts the configuration to Kubernetes CRD objects.
+// The load-balanced services are referenced as Kubernetes Services of the same name,
+// which have to be created to select the servers.
+func toKubernetesObjects(conf *dynamic.Configuration) ([]any, []string) {
+	c := &kubernetesConverter{
+		conf:  conf,
+		noted: make(map[string]struct{}),
+	}
+
+	var objects []any
+
+	if conf.HTTP != nil {
+		objects = append(objects, c.middlewares()...)
+	}
+
+	if conf.TCP != nil {
+		objects = append(objects, c.tcpMiddlewares()...)
+	}
+
+	if conf.TLS != nil {
+		objects = append(objects, c.tlsOptions()...)
+
+		if len(conf.TLS.Certificates) > 0 {
+			c.note("TLS certificates are not exported, they have to be stored in Kubernetes Secrets")
+		}
+		if len(conf.TLS.Stores) > 0 {
+			c.note("TLS stores are not exported, they have to reference Kubernetes Secrets")
+		}
+	}
+
+	if conf.HTTP != nil {
+		objects = append(objects, c.traefikServices()...)
+		objects = append(objects, c.ingressRoutes()...)
+
+		if len(conf.HTTP.ServersTransports) > 0 {
+			c.note("servers transports are not exported, they have to reference Kubernetes Secrets")
+		}
+	}
+
+	if conf.TCP != nil {
+		objects = append(objects, c.ingressRouteTCPs()...)
+
+		if len(conf.TCP.ServersTransports) > 0 {
+			c.note("TCP servers transports are not exported, they have to reference Kubernetes Secrets")
+		}
+	}
+
+	if conf.UDP != nil {
+		objects = append(objects, c.ingressRouteUDPs()...)
+	}
+
+	return objects, c.notes
+}
+
+type kubernetesConverter struct {
+	conf  *dynamic.Configuration
+	notes []string
+	// noted holds the notes already added, as a service may be referenced by several routers.
+	noted map[string]struct{}
+}
+
+func (c *kubernetesConverter) note(format string, args ...any) {
+	note := fmt.Sprintf(format, args...)
+	if _, ok := c.noted[note]; ok {
+		return
+	}
+
+	c.noted[note] = struct{}{}
+	c.notes = append(c.notes, note)
+}
+
+func (c *kubernetesConverter) middlewares() []any {
+	var objects []any
+
+	for _, name := range sortedKeys(c.conf.HTTP.Middlewares) {
+		middleware := c.conf.HTTP.Middlewares[name].DeepCopy()
+
+		var spec traefikv1alpha1.MiddlewareSpec
+
+		// The references to other elements are objects in the CRD.
+		if middleware.Chain != nil {
+			spec.Chain = &traefikv1alpha1.Chain{Middlewares: middlewareRefs(middleware.Chain.Middlewares)}
+			middleware.Chain = nil
+		}
+
+		if middleware.Errors != nil {
+			service, ok := c.service(middleware.Errors.Service)
+			if !ok {
+				c.note("middleware %q is not exported: unknown service %q", name, middleware.Errors.Service)
+				continue
+			}
+
+			spec.Errors = &traefikv1alpha1.ErrorPage{
+				Status:         middleware.Errors.Status,
+				StatusRewrites: middleware.Errors.StatusRewrites,
+				Service:        traefikv1alpha1.Service{LoadBalancerSpec: service},
+				Query:          middleware.Errors.Query,
+			}
+			middleware.Errors = nil
+		}
+
+		if err := convert(middleware, &spec); err != nil {
+			c.note("middleware %q is not exported: %v", name, err)
+			continue
+		}
+
+		objects = append(objects, &traefikv1alpha1.Middleware{
+			TypeMeta:   typeMeta("Middleware"),
+			ObjectMeta: metav1.ObjectMeta{Name: objectName(name)},
+			Spec:       spec,
+		})
+	}
+
+	return objects
+}
+
+func (c *kubernetesConverter) tcpMiddlewares() []any {
+	var objects []any
+
+	for _, name := range sortedKeys(c.conf.TCP.Middlewares) {
+		var spec traefikv1alpha1.MiddlewareTCPSpec
+		if err := convert(c.conf.TCP.Middlewares[name], &spec); err != nil {
+			c.note("TCP middleware %q is not exported: %v", name, err)
+			continue
+		}
+
+		objects = append(objects, &traefikv1alpha1.MiddlewareTCP{
+			TypeMeta:   typeMeta("MiddlewareTCP"),
+			ObjectMeta: metav1.ObjectMeta{Name: objectName(name)},
+			Spec:       spec,
+		})
+	}
+
+	return objects
+}
+
+func (c *kubernetesConverter) tlsOptions() []any {
+	var objects []any
+
+	for _, name := range sortedKeys(c.conf.TLS.Options) {
+		var spec traefikv1alpha1.TLSOptionSpec
+		if err := convert(c.conf.TLS.Options[name], &spec); err != nil {
+			c.note("TLS option %q is not exported: %v", name, err)
+			continue
+		}
+
+		objects = append(objects, &traefikv1alpha1.TLSOption{
+			TypeMeta:   typeMeta("TLSOption"),
+			ObjectMeta: metav1.ObjectMeta{Name: objectName(name)},
+			Spec:       spec,
+		})
+	}
+
+	return objects
+}
+
+// traefikServices converts the services which are not load-balancers of servers.
+func (c *kubernetesConverter) traefikServices() []any {
+	var objects []any
+
+	for _, name := range sortedKeys(c.conf.HTTP.Services) {
+		service := c.conf.HTTP.Services[name]
+
+		var spec traefikv1alpha1.TraefikServiceSpec
+
+		switch {
+		case service.LoadBalancer != nil:
+			continue
+
+		case service.Weighted != nil:
+			spec.Weighted = &traefikv1alpha1.WeightedRoundRobin{Sticky: service.Weighted.Sticky}
+			for _, wrr := range service.Weighted.Services {
+				lb, ok := c.service(wrr.Name)
+				if !ok {
+					continue
+				}
+
+				lb.Weight = wrr.Weight
+				spec.Weighted.Services = append(spec.Weighted.Services, traefikv1alpha1.Service{LoadBalancerSpec: lb})
+			}
+
+		case service.HighestRandomWeight != nil:
+			spec.HighestRandomWeight = &traefikv1alpha1.HighestRandomWeight{}
+			for _, hrw := range service.HighestRandomWeight.Services {
+				lb, ok := c.service(hrw.Name)
+				if !ok {
+					continue
+				}
+
+				lb.Weight = hrw.Weight
+				spec.HighestRandomWeight.Services = append(spec.HighestRandomWeight.Services, traefikv1alpha1.Service{LoadBalancerSpec: lb})
+			}
+
+		case service.Mirroring != nil:
+			lb, ok := c.service(service.Mirroring.Service)
+			if !ok {
+				continue
+			}
+
+			spec.Mirroring = &traefikv1alpha1.Mirroring{
+				LoadBalancerSpec: lb,
+				MirrorBody:       service.Mirroring.MirrorBody,
+				MaxBodySize:      service.Mirroring.MaxBodySize,
+			}
+			for _, mirror := range service.Mirroring.Mirrors {
+				lb, ok := c.service(mirror.Name)
+				if !ok {
+					continue
+				}
+
+				spec.Mirroring.Mirrors = append(spec.Mirroring.Mirrors, traefikv1alpha1.MirrorService{LoadBalancerSpec: lb, Percent: mirror.Percent})
+			}
+
+		case service.Failover != nil:
+			main, ok := c.service(service.Failover.Service)
+			if !ok {
+				continue
+			}
+
+			fallback, ok := c.service(service.Failover.Fallback)
+			if !ok {
+				continue
+			}
+
+			spec.Failover = &traefikv1alpha1.Failover{Service: main, Fallback: fallback}
+			if service.Failover.Errors != nil {
+				spec.Failover.Errors = traefikv1alpha1.FailoverError{
+					Status:              service.Failover.Errors.Status,
+					MaxRequestBodyBytes: service.Failover.Errors.MaxRequestBodyBytes,
+				}
+			}
+
+		default:
+			continue
+		}
+
+		objects = append(objects, &traefikv1alpha1.TraefikService{
+			TypeMeta:   typeMeta("TraefikService"),
+			ObjectMeta: metav1.ObjectMeta{Name: objectName(name)},
+			Spec:       spec,
+		})
+	}
+
+	return objects
+}
+
+func (c *kubernetesConverter) ingressRoutes() []any {
+	var objects []any
+
+	for _, name := range sortedKeys(c.conf.HTTP.Routers) {
+		router := c.conf.HTTP.Routers[name]
+
+		route := traefikv1alpha1.Route{
+			Match:         router.Rule,
+			Kind:          "Rule",
+			Priority:      router.Priority,
+			Syntax:        router.RuleSyntax,
+			Middlewares:   middlewareRefs(router.Middlewares),
+			Observability: router.Observability,
+		}
+
+		if router.Service != "" {
+			service, ok := c.service(router.Service)
+			if !ok {
+				c.note("router %q is not exported: unknown service %q", name, router.Service)
+				continue
+			}
+
+			route.Services = []traefikv1alpha1.Service{{LoadBalancerSpec: service}}
+		}
+
+		spec := traefikv1alpha1.IngressRouteSpec{
+			EntryPoints: router.EntryPoints,
+			Routes:      []traefikv1alpha1.Route{route},
+		}
+
+		for _, parent := range router.ParentRefs {
+			spec.ParentRefs = append(spec.ParentRefs, traefikv1alpha1.IngressRouteRef{Name: objectName(parent)})
+		}
+
+		if router.TLS != nil {
+			spec.TLS = &traefikv1alpha1.TLS{
+				CertResolver: router.TLS.CertResolver,
+				Domains:      router.TLS.Domains,
+			}
+
+			if router.TLS.Options != "" {
+				spec.TLS.Options = &traefikv1alpha1.TLSOptionRef{Name: reference(router.TLS.Options)}
+			}
+		}
+
+		objects = append(objects, &traefikv1alpha1.IngressRoute{
+			TypeMeta:   typeMeta("IngressRoute"),
+			ObjectMeta: metav1.ObjectMeta{Name: objectName(name)},
+			Spec:       spec,
+		})
+	}
+
+	return objects
+}
+
+func (c *kubernetesConverter) ingressRouteTCPs() []any {
+	var objects []any
+
+	for _, name := range sortedKeys(c.conf.TCP.Routers) {
+		router := c.conf.TCP.Routers[name]
+
+		services, ok := c.tcpServices(router.Service, nil)
+		if !ok {
+			c.note("TCP router %q is not exported: its service %q cannot be expressed with Kubernetes Services", name, router.Service)
+			continue
+		}
+
+		route := traefikv1alpha1.RouteTCP{
+			Match:    router.Rule,
+			Priority: router.Priority,
+			Syntax:   router.RuleSyntax,
+			Services: services,
+		}
+
+		for _, middleware := range router.Middlewares {
+			route.Middlewares = append(route.Middlewares, traefikv1alpha1.ObjectReference{Name: reference(middleware)})
+		}
+
+		spec := traefikv1alpha1.IngressRouteTCPSpec{
+			EntryPoints: router.EntryPoints,
+			Routes:      []traefikv1alpha1.RouteTCP{route},
+		}
+
+		if router.TLS != nil {
+			spec.TLS = &traefikv1alpha1.TLSTCP{
+				Passthrough:  router.TLS.Passthrough,
+				CertResolver: router.TLS.CertResolver,
+				Domains:      router.TLS.Domains,
+			}
+
+			if router.TLS.Options != "" {
+				spec.TLS.Options = &traefikv1alpha1.ObjectReference{Name: reference(router.TLS.Options)}
+			}
+		}
+
+		objects = append(objects, &traefikv1alpha1.IngressRouteTCP{
+			TypeMeta:   typeMeta("IngressRouteTCP"),
+			ObjectMeta: metav1.ObjectMeta{Name: objectName(name)},
+			Spec:       spec,
+		})
+	}
+
+	return objects
+}
+
+func (c *kubernetesConverter) ingressRouteUDPs() []any {
+	var objects []any
+
+	for _, name := range sortedKeys(c.conf.UDP.Routers) {
+		router := c.conf.UDP.Routers[name]
+
+		services, ok := c.udpServices(router.Service, nil)
+		if !ok {
+			c.note("UDP router %q is not exported: its service %q cannot be expressed with Kubernetes Services", name, router.Service)
+			continue
+		}
+
+		objects = append(objects, &traefikv1alpha1.IngressRouteUDP{
+			TypeMeta:   typeMeta("IngressRouteUDP"),
+			ObjectMeta: metav1.ObjectMeta{Name: objectName(name)},
+			Spec: traefikv1alpha1.IngressRouteUDPSpec{
+				EntryPoints: router.EntryPoints,
+				Routes:      []traefikv1alpha1.RouteUDP{{Services: services}},
+			},
+		})
+	}
+
+	return objects
+}
+
+// service returns the reference to the given HTTP service:
+// a Kubernetes Service for a load-balancer of servers, a TraefikService otherwise.
+func (c *kubernetesConverter) service(name string) (traefikv1alpha1.LoadBalancerSpec, bool) {
+	if strings.Contains(name, "@") {
+		return traefikv1alpha1.LoadBalancerSpec{Name: name, Kind: "TraefikService"}, true
+	}
+
+	service, ok := c.conf.HTTP.Services[name]
+	if !ok {
+		c.note("service %q is not defined in the exported configuration", name)
+		return traefikv1alpha1.LoadBalancerSpec{}, false
+	}
+
+	if service.LoadBalancer == nil {
+		return traefikv1alpha1.LoadBalancerSpec{Name: objectName(name), Kind: "TraefikService"}, true
+	}
+
+	lb := service.LoadBalancer
+
+	spec := traefikv1alpha1.LoadBalancerSpec{
+		Name:           objectName(name),
+		Middlewares:    middlewareRefs(service.Middlewares),
+		Sticky:         lb.Sticky,
+		Strategy:       lb.Strategy,
+		PassHostHeader: lb.PassHostHeader,
+	}
+
+	if lb.ServersTransport != "" {
+		spec.ServersTransport = reference(lb.ServersTransport)
+	}
+
+	var urls []string
+	for _, server := range lb.Servers {
+		urls = append(urls, server.URL)
+	}
+
+	if len(lb.Servers) > 0 {
+		if u, err := url.Parse(lb.Servers[0].URL); err == nil {
+			spec.Port = urlPort(u)
+			if u.Scheme != "http" {
+				spec.Scheme = u.Scheme
+			}
+		}
+	}
+
+	c.note("service %q: the Kubernetes Service %q must select the servers %s", name, spec.Name, strings.Join(urls, ", "))
+
+	return spec, true
+}
+
+// tcpServices returns the Kubernetes Services of the given TCP service.
+// A weighted service is expanded into its weighted load-balancers.
+func (c *kubernetesConverter) tcpServices(name string, weight *int) ([]traefikv1alpha1.ServiceTCP, bool) {
+	service, ok := c.conf.TCP.Services[name]
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case service.LoadBalancer != nil:
+		lb := service.LoadBalancer
+
+		spec := traefikv1alpha1.ServiceTCP{
+			Name:             objectName(name),
+			Weight:           weight,
+			TerminationDelay: lb.TerminationDelay, //nolint:staticcheck // The deprecated option is still exported.
+			ProxyProtocol:    lb.ProxyProtocol,
+		}
+
+		if lb.ServersTransport != "" {
+			spec.ServersTransport = reference(lb.ServersTransport)
+		}
+
+		var addresses []string
+		for _, server := range lb.Servers {
+			addresses = append(addresses, server.Address)
+		}
+
+		if len(lb.Servers) > 0 {
+			spec.Port = intstr.Parse(addressPort(lb.Servers[0].Address))
+			spec.TLS = lb.Servers[0].TLS
+		}
+
+		c.note("TCP service %q: the Kubernetes Service %q must select the servers %s", name, spec.Name, strings.Join(addresses, ", "))
+
+		return []traefikv1alpha1.ServiceTCP{spec}, true
+
+	case service.Weighted != nil && weight == nil:
+		var services []traefikv1alpha1.ServiceTCP
+		for _, wrr := range service.Weighted.Services {
+			s, ok := c.tcpServices(wrr.Name, wrr.Weight)
+			if !ok {
+				return nil, false
+			}
+
+			services = append(services, s...)
+		}
+
+		return services, true
+
+	default:
+		return nil, false
+	}
+}
+
+// udpServices returns the Kubernetes Services of the given UDP service.
+// A weighted service is expanded into its weighted load-balancers.
+func (c *kubernetesConverter) udpServices(name string, weight *int) ([]traefikv1alpha1.ServiceUDP, bool) {
+	service, ok := c.conf.UDP.Services[name]
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case service.LoadBalancer != nil:
+		spec := traefikv1alpha1.ServiceUDP{
+			Name:   objectName(name),
+			Weight: weight,
+		}
+
+		var addresses []string
+		for _, server := range service.LoadBalancer.Servers {
+			addresses = append(addresses, server.Address)
+		}
+
+		if len(service.LoadBalancer.Servers) > 0 {
+			spec.Port = intstr.Parse(addressPort(service.LoadBalancer.Servers[0].Address))
+		}
+
+		c.note("UDP service %q: the Kubernetes Service %q must select the servers %s", name, spec.Name, strings.Join(addresses, ", "))
+
+		return []traefikv1alpha1.ServiceUDP{spec}, true
+
+	case service.Weighted != nil && weight == nil:
+		var services []traefikv1alpha1.ServiceUDP
+		for _, wrr := range service.Weighted.Services {
+			s, ok := c.udpServices(wrr.Name, wrr.Weight)
+			if !ok {
+				return nil, false
+			}
+
+			services = append(services, s...)
+		}
+
+		return services, true
+
+	default:
+		return nil, false
+	}
+}
+
+// convert converts the given dynamic configuration element to its CRD counterpart through their JSON representations.
+// It fails if a field of the element has no counterpart in the CRD.
+func convert(element, spec any) error {
+	data, err := json.Marshal(element)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(spec)
+}
+
+func middlewareRefs(names []string) []traefikv1alpha1.MiddlewareRef {
+	var refs []traefikv1alpha1.MiddlewareRef
+	for _, name := range names {
+		refs = append(refs, traefikv1alpha1.MiddlewareRef{Name: reference(name)})
+	}
+
+	return refs
+}
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{APIVersion: traefikv1alpha1.SchemeGroupVersion.String(), Kind: kind}
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// objectName returns a valid Kubernetes object name for the given element name.
+func objectName(name string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+}
+
+// reference returns the name of the object referenced by the given name.
+// A reference to an element of another provider (name@provider) is kept as is.
+func reference(name string) string {
+	if strings.Contains(name, "@") {
+		return name
+	}
+
+	return objectName(name)
+}
+
+// urlPort returns the port of the given URL, or the default port of its scheme.
+func urlPort(u *url.URL) intstr.IntOrString {
+	if port := u.Port(); port != "" {
+		return intstr.Parse(port)
+	}
+
+	if u.Scheme == "https" {
+		return intstr.FromInt32(443)
+	}
+
+	return intstr.FromInt32(80)
+}
+
+// writeManifests writes the given notes as comments, then the objects as a multi-document YAML manifest.
+func writeManifests(w io.Writer, objects []any, notes []string) error {
+	writeNotes(w, notes)
+
+	for i, object := range objects {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+
+		// The zero creation timestamp is not omitted by the JSON encoding of the object metadata.
+		var manifest map[string]any
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return err
+		}
+
+		if metadata, ok := manifest["metadata"].(map[string]any); ok {
+			delete(metadata, "creationTimestamp")
+		}
+
+		data, err = yaml.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// internalProvider is the provider of the configuration built by Traefik itself (API, dashboard, ping, ...),
+// which is never exported.
+const internalProvider = "internal"
+
+// ErrUnknownProvider is returned by Merge when there is no configuration for the given provider.
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// Snapshot keeps the last configurations received from the providers, so that they can be exported on demand.
+type Snapshot struct {
+	mu             sync.RWMutex
+	configurations dynamic.Configurations
+}
+
+// Transform records the given configurations.
+// It is meant to be registered as a configuration transformer,
+// before the transformers which resolve the secret references, so that the exported configurations keep the references.
+func (s *Snapshot) Transform(_ context.Context, configurations dynamic.Configurations) dynamic.Configurations {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.configurations = configurations.DeepCopy()
+
+	return configurations
+}
+
+// Configurations returns a copy of the last recorded configurations.
+func (s *Snapshot) Configurations() dynamic.Configurations {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.configurations.DeepCopy()
+}
+
+// Merge returns the configuration of the given provider,
+// or, if the provider name is empty, the merged configurations of all the providers, but the internal one.
+// Contrary to the configuration applied by Traefik, the element names are not qualified with their provider name,
+// and the references to elements of the merged providers are unqualified,
+// so that the merged configuration can be loaded by a single provider.
+func Merge(configurations dynamic.Configurations, providerName string) (*dynamic.Configuration, error) {
+	if providerName != "" {
+		conf, ok := configurations[providerName]
+		if !ok || conf == nil {
+			return nil, fmt.Errorf("%w %q", ErrUnknownProvider, providerName)
+		}
+
+		return conf.DeepCopy(), nil
+	}
+
+	merged := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{},
+		TCP:  &dynamic.TCPConfiguration{},
+		UDP:  &dynamic.UDPConfiguration{},
+		TLS:  &dynamic.TLSConfiguration{},
+	}
+
+	// The provider which defines each element, by section and element name.
+	owners := make(map[string]string)
+	providers := make(map[string]struct{})
+
+	for _, name := range sortedKeys(configurations) {
+		if name == internalProvider || configurations[name] == nil {
+			continue
+		}
+
+		conf := configurations[name].DeepCopy()
+
+		providers[name] = struct{}{}
+
+		sections := []struct {
+			name     string
+			dst, src any
+		}{
+			{"http", merged.HTTP, conf.HTTP},
+			{"tcp", merged.TCP, conf.TCP},
+			{"udp", merged.UDP, conf.UDP},
+			{"tls", merged.TLS, conf.TLS},
+		}
+
+		for _, section := range sections {
+			if err := mergeSection(owners, name, section.name, section.dst, section.src); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, ref := range references(merged) {
+		name, provider, ok := strings.Cut(*ref, "@")
+		if !ok {
+			continue
+		}
+
+		if _, ok := providers[provider]; ok {
+			*ref = name
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeSection merges the maps and slices of the src section into the ones of the dst section.
+func mergeSection(owners map[string]string, providerName, sectionName string, dst, src any) error {
+	srcValue := reflect.ValueOf(src)
+	if srcValue.IsNil() {
+		return nil
+	}
+
+	srcValue = srcValue.Elem()
+	dstValue := reflect.ValueOf(dst).Elem()
+
+	for i := range srcValue.NumField() {
+		kind := sectionName + "." + strings.ToLower(srcValue.Type().Field(i).Name)
+		srcField := srcValue.Field(i)
+		dstField := dstValue.Field(i)
+
+		switch srcField.Kind() {
+		case reflect.Map:
+			if srcField.Len() == 0 {
+				continue
+			}
+
+			if dstField.IsNil() {
+				dstField.Set(reflect.MakeMap(srcField.Type()))
+			}
+
+			for _, key := range srcField.MapKeys() {
+				id := kind + "/" + key.String()
+				if owner, ok := owners[id]; ok {
+					return fmt.Errorf("%s %q is defined by both the %s and %s providers, export them separately", kind, key.String(), owner, providerName)
+				}
+
+				owners[id] = providerName
+				dstField.SetMapIndex(key, srcField.MapIndex(key))
+			}
+
+		case reflect.Slice:
+			dstField.Set(reflect.AppendSlice(dstField, srcField))
+		}
+	}
+
+	return nil
+}
+
+// references returns pointers to all the references to other elements of the configuration.
+func references(conf *dynamic.Configuration) []*string {
+	var refs []*string
+
+	addAll := func(names []string) {
+		for i := range names {
+			refs = append(refs, &names[i])
+		}
+	}
+
+	for _, router := range conf.HTTP.Routers {
+		refs = append(refs, &router.Service)
+		addAll(router.Middlewares)
+		addAll(router.ParentRefs)
+
+		if router.TLS != nil {
+			refs = append(refs, &router.TLS.Options)
+		}
+	}
+
+	for _, service := range conf.HTTP.Services {
+		addAll(service.Middlewares)
+
+		if service.LoadBalancer != nil {
+			refs = append(refs, &service.LoadBalancer.ServersTransport)
+		}
+
+		if service.Weighted != nil {
+			for i := range service.Weighted.Services {
+				refs = append(refs, &service.Weighted.Services[i].Name)
+			}
+		}
+
+		if service.HighestRandomWeight != nil {
+			for i := range service.HighestRandomWeight.Services {
+				refs = append(refs, &service.HighestRandomWeight.Services[i].Name)
+			}
+		}
+
+		if service.Mirroring != nil {
+			refs = append(refs, &service.Mirroring.Service)
+			for i := range service.Mirroring.Mirrors {
+				refs = append(refs, &service.Mirroring.Mirrors[i].Name)
+			}
+		}
+
+		if service.Failover != nil {
+			refs = append(refs, &service.Failover.Service, &service.Failover.Fallback)
+		}
+	}
+
+	for _, middleware := range conf.HTTP.Middlewares {
+		if middleware.Chain != nil {
+			addAll(middleware.Chain.Middlewares)
+		}
+
+		if middleware.Errors != nil {
+			refs = append(refs, &middleware.Errors.Service)
+		}
+	}
+
+	for _, router := range conf.TCP.Routers {
+		refs = append(refs, &router.Service)
+		addAll(router.Middlewares)
+
+		if router.TLS != nil {
+			refs = append(refs, &router.TLS.Options)
+		}
+	}
+
+	for _, service := range conf.TCP.Services {
+		if service.LoadBalancer != nil {
+			refs = append(refs, &service.LoadBalancer.ServersTransport)
+		}
+
+		if service.Weighted != nil {
+			for i := range service.Weighted.Services {
+				refs = append(refs, &service.Weighted.Services[i].Name)
+			}
+		}
+
+		if service.Mirroring != nil {
+			refs = append(refs, &service.Mirroring.Service)
+			for i := range service.Mirroring.Mirrors {
+				refs = append(refs, &service.Mirroring.Mirrors[i].Name)
+			}
+		}
+	}
+
+	for _, router := range conf.UDP.Routers {
+		refs = append(refs, &router.Service)
+	}
+
+	for _, service := range conf.UDP.Services {
+		if service.Weighted != nil {
+			for i := range service.Weighted.Services {
+				refs = append(refs, &service.Weighted.Services[i].Name)
+			}
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tls"
+)
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		configurations dynamic.Configurations
+		providerName   string
+		expected       *dynamic.Configuration
+		expErr         bool
+	}{
+		{
+			desc: "single provider",
+			configurations: dynamic.Configurations{
+				"file": {
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"foo": {Service: "bar@docker"},
+						},
+					},
+				},
+				"docker": {
+					HTTP: &dynamic.HTTPConfiguration{
+						Services: map[string]*dynamic.Service{"bar": {}},
+					},
+				},
+			},
+			providerName: "file",
+			expected: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo": {Service: "bar@docker"},
+					},
+				},
+			},
+		},
+		{
+			desc: "unknown provider",
+			configurations: dynamic.Configurations{
+				"file": {},
+			},
+			providerName: "docker",
+			expErr:       true,
+		},
+		{
+			desc: "all providers",
+			configurations: dynamic.Configurations{
+				"file": {
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"foo": {
+								Service:     "bar@docker",
+								Middlewares: []string{"auth", "dashboard@internal"},
+								TLS:         &dynamic.RouterTLSConfig{Options: "modern@docker"},
+							},
+						},
+						Middlewares: map[string]*dynamic.Middleware{
+							"auth": {Chain: &dynamic.Chain{Middlewares: []string{"ratelimit@docker"}}},
+						},
+					},
+				},
+				"docker": {
+					HTTP: &dynamic.HTTPConfiguration{
+						Services: map[string]*dynamic.Service{
+							"bar": {Weighted: &dynamic.WeightedRoundRobin{Services: []dynamic.WRRService{{Name: "baz"}}}},
+						},
+						Middlewares: map[string]*dynamic.Middleware{
+							"ratelimit": {RateLimit: &dynamic.RateLimit{Average: 100}},
+						},
+					},
+					TLS: &dynamic.TLSConfiguration{
+						Options: map[string]tls.Options{"modern": {MinVersion: "VersionTLS13"}},
+					},
+				},
+				"internal": {
+					HTTP: &dynamic.HTTPConfiguration{
+						Middlewares: map[string]*dynamic.Middleware{"dashboard": {}},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo": {
+							Service:     "bar",
+							Middlewares: []string{"auth", "dashboard@internal"},
+							TLS:         &dynamic.RouterTLSConfig{Options: "modern"},
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{
+						"auth":      {Chain: &dynamic.Chain{Middlewares: []string{"ratelimit"}}},
+						"ratelimit": {RateLimit: &dynamic.RateLimit{Average: 100}},
+					},
+					Services: map[string]*dynamic.Service{
+						"bar": {Weighted: &dynamic.WeightedRoundRobin{Services: []dynamic.WRRService{{Name: "baz"}}}},
+					},
+				},
+				TCP: &dynamic.TCPConfiguration{},
+				UDP: &dynamic.UDPConfiguration{},
+				TLS: &dynamic.TLSConfiguration{
+					Options: map[string]tls.Options{"modern": {MinVersion: "VersionTLS13"}},
+				},
+			},
+		},
+		{
+			desc: "conflicting names",
+			configurations: dynamic.Configurations{
+				"file": {
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{"foo": {Service: "bar"}},
+					},
+				},
+				"docker": {
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{"foo": {Service: "baz"}},
+					},
+				},
+			},
+			expErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf, err := Merge(test.configurations, test.providerName)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, conf)
+		})
+	}
+}
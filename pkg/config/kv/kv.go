@@ -6,6 +6,7 @@ import (
 
 	"github.com/kvtools/valkeyrie/store"
 	"github.com/traefik/paerser/parser"
+	"github.com/traefik/traefik/v3/pkg/config/label"
 )
 
 // Decode decodes the given KV pairs into the given element.
@@ -34,6 +35,21 @@ func Decode(pairs []*store.KVPair, element any, rootName string) error {
 	return parser.Fill(element, node, parser.FillerOpts{AllowSliceAsStruct: false})
 }
 
+// Encode encodes the given element into KV pairs, keyed by their path under the given root name.
+// The pairs can be decoded back with Decode.
+func Encode(element any, rootName string) (map[string]string, error) {
+	if element == nil {
+		return nil, nil
+	}
+
+	node, err := label.EncodeToNode(element, rootName, label.EncoderOpts{TagName: "kv", IndexSlices: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodeNode(node), nil
+}
+
 func getRootFieldNames(rootName string, element any) []string {
 	if element == nil {
 		return nil
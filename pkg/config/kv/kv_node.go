@@ -2,8 +2,10 @@ package kv
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kvtools/valkeyrie/store"
@@ -126,3 +128,52 @@ func filterPairs(pairs []*store.KVPair, filters []string) []*store.KVPair {
 
 	return sortedPairs
 }
+
+// EncodeNode converts a tree of nodes to KV pairs.
+func EncodeNode(node *parser.Node) map[string]string {
+	pairs := make(map[string]string)
+	encodeNode(pairs, node.Name, node)
+	return pairs
+}
+
+func encodeNode(pairs map[string]string, root string, node *parser.Node) {
+	for _, child := range node.Children {
+		if child.Disabled || len(child.Name) == 0 {
+			continue
+		}
+
+		childName := root + "/" + strings.Trim(child.Name, "[]")
+
+		if child.RawValue != nil {
+			encodeRawValue(pairs, childName, child.RawValue)
+			continue
+		}
+
+		if len(child.Children) > 0 {
+			encodeNode(pairs, childName, child)
+		} else {
+			pairs[childName] = child.Value
+		}
+	}
+}
+
+func encodeRawValue(pairs map[string]string, root string, rawValue any) {
+	if rawValue == nil {
+		return
+	}
+
+	value := reflect.ValueOf(rawValue)
+
+	switch value.Kind() {
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			encodeRawValue(pairs, root+"/"+fmt.Sprint(key.Interface()), value.MapIndex(key).Interface())
+		}
+	case reflect.Slice:
+		for i := range value.Len() {
+			encodeRawValue(pairs, root+"/"+strconv.Itoa(i), value.Index(i).Interface())
+		}
+	default:
+		pairs[root] = fmt.Sprint(rawValue)
+	}
+}
@@ -2,9 +2,12 @@ package kv
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 )
 
 func TestDecode(t *testing.T) {
@@ -100,6 +103,97 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestEncode(t *testing.T) {
+	element := &sample{
+		FieldA: "bar",
+		FieldB: 1,
+		FieldC: true,
+		FieldD: []string{"one", "two"},
+		FieldE: &struct {
+			Name string
+		}{},
+		FieldF: map[string]string{
+			"Test1": "A",
+			"Test2": "B",
+		},
+		FieldG: []sub{
+			{Name: "A"},
+			{Name: "B"},
+		},
+	}
+
+	pairs, err := Encode(element, "traefik")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"traefik/fielda":        "bar",
+		"traefik/fieldb":        "1",
+		"traefik/fieldc":        "true",
+		"traefik/fieldd/0":      "one",
+		"traefik/fieldd/1":      "two",
+		"traefik/fielde":        "true",
+		"traefik/fieldf/Test1":  "A",
+		"traefik/fieldf/Test2":  "B",
+		"traefik/fieldg/0/name": "A",
+		"traefik/fieldg/1/name": "B",
+	}
+	assert.Equal(t, expected, pairs)
+
+	decoded := &sample{}
+	err = Decode(mapToPairs(pairs), decoded, "traefik")
+	require.NoError(t, err)
+
+	assert.Equal(t, element, decoded)
+}
+
+func TestEncode_configuration(t *testing.T) {
+	conf := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"Router0": {
+					EntryPoints: []string{"web", "websecure"},
+					Middlewares: []string{"ratelimit"},
+					Service:     "Service0",
+					Rule:        "Host(`example.com`)",
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{
+				"ratelimit": {
+					RateLimit: &dynamic.RateLimit{
+						Average: 100,
+						Period:  ptypes.Duration(time.Minute),
+					},
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"Service0": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{
+							{URL: "http://10.0.0.1:80"},
+							{URL: "http://10.0.0.2:80"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pairs, err := Encode(conf, "traefik")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1m0s", pairs["traefik/http/middlewares/ratelimit/ratelimit/period"])
+	assert.Equal(t, "websecure", pairs["traefik/http/routers/Router0/entrypoints/1"])
+	assert.Equal(t, "http://10.0.0.2:80", pairs["traefik/http/services/Service0/loadbalancer/servers/1/url"])
+
+	decoded := &dynamic.Configuration{}
+	err = Decode(mapToPairs(pairs), decoded, "traefik")
+	require.NoError(t, err)
+
+	assert.Equal(t, conf.HTTP.Routers, decoded.HTTP.Routers)
+	assert.Equal(t, conf.HTTP.Middlewares, decoded.HTTP.Middlewares)
+	assert.Equal(t, conf.HTTP.Services["Service0"].LoadBalancer.Servers, decoded.HTTP.Services["Service0"].LoadBalancer.Servers)
+}
+
 type sample struct {
 	FieldA string
 	FieldB int
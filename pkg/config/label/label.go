@@ -2,10 +2,26 @@
 package label
 
 import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/traefik/paerser/parser"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 )
 
+// EncoderOpts holds the options of the configuration encoding.
+type EncoderOpts struct {
+	// TagName is the name of the struct tag used to skip fields.
+	TagName string
+	// AllowSliceAsStruct encodes a slice of a single struct under the name of its label-slice-as-struct tag.
+	AllowSliceAsStruct bool
+	// IndexSlices encodes each value of a slice of simple types in its own indexed node, instead of a comma-separated value.
+	IndexSlices bool
+}
+
 // DecodeConfiguration converts the labels to a configuration.
 func DecodeConfiguration(labels map[string]string) (*dynamic.Configuration, error) {
 	conf := &dynamic.Configuration{
@@ -29,6 +45,117 @@ func EncodeConfiguration(conf *dynamic.Configuration) (map[string]string, error)
 	return parser.Encode(conf, parser.DefaultRootName)
 }
 
+// EncodeToNode converts an element to a tree of nodes.
+// Contrary to parser.EncodeToNode, the field names are lower-cased,
+// and the durations are encoded in a form which is decoded back to the same value.
+func EncodeToNode(element any, rootName string, opts EncoderOpts) (*parser.Node, error) {
+	node, err := parser.EncodeToNode(element, rootName, parser.EncoderToNodeOpts{
+		OmitEmpty:          true,
+		TagName:            opts.TagName,
+		AllowSliceAsStruct: opts.AllowSliceAsStruct,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	normalizeNode(node, reflect.ValueOf(element), opts)
+
+	return node, nil
+}
+
+var durationType = reflect.TypeOf(ptypes.Duration(0))
+
+// normalizeNode walks the node along with the value it has been encoded from.
+func normalizeNode(node *parser.Node, value reflect.Value, opts EncoderOpts) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() == durationType {
+		node.Value = time.Duration(value.Int()).String()
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, child := range node.Children {
+			field := value.FieldByName(child.FieldName)
+			if !field.IsValid() {
+				continue
+			}
+
+			// The name of a label-slice-as-struct node is already the expected one.
+			if child.Name == child.FieldName {
+				child.Name = strings.ToLower(child.Name)
+			}
+
+			normalizeNode(child, field, opts)
+		}
+
+	case reflect.Map:
+		if node.RawValue != nil {
+			return
+		}
+
+		for _, child := range node.Children {
+			key := reflect.ValueOf(child.Name).Convert(value.Type().Key())
+			normalizeNode(child, value.MapIndex(key), opts)
+		}
+
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return
+		}
+
+		if len(node.Children) == 0 {
+			if opts.IndexSlices && node.Value != "" {
+				indexSlice(node, value)
+			}
+			return
+		}
+
+		if !strings.HasPrefix(node.Children[0].Name, "[") {
+			// label-slice-as-struct.
+			normalizeNode(node, value.Index(0), opts)
+			return
+		}
+
+		for i, child := range node.Children {
+			normalizeNode(child, value.Index(i), opts)
+		}
+	}
+}
+
+// indexSlice moves the values of a slice of simple types to indexed child nodes.
+func indexSlice(node *parser.Node, value reflect.Value) {
+	node.Value = ""
+
+	for i := range value.Len() {
+		elem := value.Index(i)
+
+		var v string
+		switch elem.Kind() {
+		case reflect.String:
+			v = elem.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v = strconv.FormatInt(elem.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v = strconv.FormatUint(elem.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			v = strconv.FormatFloat(elem.Float(), 'f', -1, 64)
+		case reflect.Bool:
+			v = strconv.FormatBool(elem.Bool())
+		default:
+			continue
+		}
+
+		node.Children = append(node.Children, &parser.Node{Name: "[" + strconv.Itoa(i) + "]", Value: v})
+	}
+}
+
 // Decode converts the labels to an element.
 // labels -> [ node -> node + metadata (type) ] -> element (node).
 func Decode(labels map[string]string, element any, filters ...string) error {
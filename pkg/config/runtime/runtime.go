@@ -33,6 +33,7 @@ type Configuration struct {
 	TCPServices    map[string]*TCPServiceInfo    `json:"tcpServices,omitempty"`
 	UDPRouters     map[string]*UDPRouterInfo     `json:"udpRouters,omitempty"`
 	UDPServices    map[string]*UDPServiceInfo    `json:"udpServices,omitempty"`
+	Namespaces     *dynamic.Namespaces           `json:"-"`
 }
 
 // NewConfig returns a Configuration initialized with the given conf. It never returns nil.
@@ -41,7 +42,7 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		return &Configuration{}
 	}
 
-	runtimeConfig := &Configuration{}
+	runtimeConfig := &Configuration{Namespaces: conf.Namespaces}
 
 	if conf.HTTP != nil {
 		routers := conf.HTTP.Routers
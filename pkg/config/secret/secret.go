@@ -0,0 +1,293 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+)
+
+const tagLoggable = "loggable"
+
+// ErrNotReference is returned by a Resolver when the given reference is not one it handles.
+// The value is then kept as is.
+var ErrNotReference = errors.New("not a secret reference")
+
+// ErrNotAllowed is returned by a Resolver when the given reference is outside of its allowed prefixes.
+var ErrNotAllowed = errors.New("secret reference not allowed")
+
+// Resolver resolves the secret references of a scheme.
+type Resolver interface {
+	// Resolve returns the secret of the given reference, without its scheme.
+	Resolve(ctx context.Context, reference string) (string, error)
+}
+
+// Resolvers holds the secret resolvers by scheme.
+// A value of the form <scheme>:<reference> is resolved by the resolver of the scheme.
+type Resolvers map[string]Resolver
+
+// NewResolvers returns the resolvers of the env and file schemes,
+// restricted to the environment variables and the files matching the given prefixes.
+func NewResolvers(envPrefixes, filePrefixes []string) Resolvers {
+	return Resolvers{
+		"env":  EnvResolver{Prefixes: envPrefixes},
+		"file": FileResolver{Prefixes: filePrefixes},
+	}
+}
+
+// Transformer returns a configuration transformer resolving the secret references of the configurations of the given providers.
+// It is meant to be registered with the configuration watcher, so that the references are resolved again on each change.
+// The configurations of the other providers are not trusted to read the environment and the files of Traefik:
+// their references are kept as is.
+func (r Resolvers) Transformer(providers []string) func(context.Context, dynamic.Configurations) dynamic.Configurations {
+	return func(ctx context.Context, configurations dynamic.Configurations) dynamic.Configurations {
+		for _, providerName := range providers {
+			logger := log.Ctx(ctx).With().Str(logs.ProviderName, providerName).Logger()
+			r.ResolveConfiguration(logger.WithContext(ctx), configurations[providerName])
+		}
+
+		return configurations
+	}
+}
+
+// ResolveConfiguration resolves, in place, the secret references of the fields with a loggable=false struct tag.
+// An object (middleware, servers transport, certificate, ...) whose references cannot be resolved is removed from the configuration.
+func (r Resolvers) ResolveConfiguration(ctx context.Context, configuration *dynamic.Configuration) {
+	if configuration == nil {
+		return
+	}
+
+	sections := []any{configuration.HTTP, configuration.TCP, configuration.UDP, configuration.TLS}
+	for _, section := range sections {
+		val := reflect.ValueOf(section)
+		if val.IsNil() {
+			continue
+		}
+
+		val = val.Elem()
+		for i := range val.NumField() {
+			r.resolveObjects(ctx, val.Type().Field(i).Name, val.Field(i))
+		}
+	}
+}
+
+// resolveObjects resolves the references of each object of the given map or slice.
+func (r Resolvers) resolveObjects(ctx context.Context, kind string, objects reflect.Value) {
+	logger := log.Ctx(ctx)
+
+	switch objects.Kind() {
+	case reflect.Map:
+		for _, key := range objects.MapKeys() {
+			object := objects.MapIndex(key)
+
+			// A map value cannot be set, it must be resolved through a pointer.
+			objectPtr := reflect.New(object.Type())
+			objectPtr.Elem().Set(object)
+
+			if err := r.resolve(ctx, objectPtr); err != nil {
+				logger.Error().Err(err).Msgf("Unable to resolve the secrets of %s %q, skipping it", kind, key)
+				objects.SetMapIndex(key, reflect.Value{})
+				continue
+			}
+
+			objects.SetMapIndex(key, objectPtr.Elem())
+		}
+	case reflect.Slice:
+		resolved := reflect.MakeSlice(objects.Type(), 0, objects.Len())
+		for i := range objects.Len() {
+			if err := r.resolve(ctx, objects.Index(i)); err != nil {
+				logger.Error().Err(err).Msgf("Unable to resolve the secrets of %s #%d, skipping it", kind, i)
+				continue
+			}
+
+			resolved = reflect.Append(resolved, objects.Index(i))
+		}
+
+		objects.Set(resolved)
+	}
+}
+
+func (r Resolvers) resolve(ctx context.Context, field reflect.Value) error {
+	switch field.Kind() {
+	case reflect.Ptr:
+		if !field.IsNil() {
+			return r.resolve(ctx, field.Elem())
+		}
+	case reflect.Struct:
+		for i := range field.NumField() {
+			stField := field.Type().Field(i)
+			if !stField.IsExported() {
+				continue
+			}
+
+			fld := field.Field(i)
+			if stField.Tag.Get(tagLoggable) == "false" {
+				if err := r.resolveValue(ctx, fld); err != nil {
+					return fmt.Errorf("field %s: %w", stField.Name, err)
+				}
+				continue
+			}
+
+			if err := r.resolve(ctx, fld); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range field.MapKeys() {
+			val := field.MapIndex(key)
+
+			// A map value cannot be set, it must be resolved through a pointer.
+			valPtr := reflect.New(val.Type())
+			valPtr.Elem().Set(val)
+
+			if err := r.resolve(ctx, valPtr); err != nil {
+				return err
+			}
+
+			field.SetMapIndex(key, valPtr.Elem())
+		}
+	case reflect.Slice:
+		for i := range field.Len() {
+			if err := r.resolve(ctx, field.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveValue resolves the given string, or slice of strings, field.
+func (r Resolvers) resolveValue(ctx context.Context, field reflect.Value) error {
+	switch field.Kind() {
+	case reflect.String:
+		value, err := r.Resolve(ctx, field.String())
+		if err != nil {
+			return err
+		}
+
+		field.SetString(value)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+
+		for i := range field.Len() {
+			value, err := r.Resolve(ctx, field.Index(i).String())
+			if err != nil {
+				return err
+			}
+
+			field.Index(i).SetString(value)
+		}
+	}
+
+	return nil
+}
+
+// Resolve returns the secret referenced by the given value, or the value itself when it is not a reference.
+func (r Resolvers) Resolve(ctx context.Context, value string) (string, error) {
+	scheme, reference, ok := strings.Cut(value, ":")
+	if !ok {
+		return value, nil
+	}
+
+	resolver, ok := r[scheme]
+	if !ok {
+		return value, nil
+	}
+
+	secret, err := resolver.Resolve(ctx, reference)
+	if errors.Is(err, ErrNotReference) {
+		return value, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolving %s reference %q: %w", scheme, reference, err)
+	}
+
+	return secret, nil
+}
+
+var envNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvResolver resolves the env:NAME references with the value of the environment variable NAME.
+type EnvResolver struct {
+	// Prefixes are the prefixes of the names of the environment variables which can be referenced.
+	Prefixes []string
+}
+
+// Resolve returns the value of the given environment variable.
+func (e EnvResolver) Resolve(_ context.Context, name string) (string, error) {
+	// Values like the users of the BasicAuth middleware (env:$apr1$...) are not references.
+	if !envNameRegexp.MatchString(name) {
+		return "", ErrNotReference
+	}
+
+	if !slices.ContainsFunc(e.Prefixes, func(prefix string) bool { return strings.HasPrefix(name, prefix) }) {
+		return "", ErrNotAllowed
+	}
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", errors.New("environment variable is not set")
+	}
+
+	return value, nil
+}
+
+// FileResolver resolves the file:/path references with the content of the file at the given absolute path.
+// A trailing newline is removed from the content.
+type FileResolver struct {
+	// Prefixes are the directories of the files which can be referenced.
+	Prefixes []string
+}
+
+// Resolve returns the content of the given file.
+func (f FileResolver) Resolve(_ context.Context, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", ErrNotReference
+	}
+
+	// The symbolic links are followed first, so that a link cannot lead outside of the allowed directories.
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+
+	if !slices.ContainsFunc(f.Prefixes, func(prefix string) bool { return inDirectory(realPath, prefix) }) {
+		return "", ErrNotAllowed
+	}
+
+	content, err := os.ReadFile(realPath)
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSuffix(string(content), "\n")
+	value = strings.TrimSuffix(value, "\r")
+
+	return value, nil
+}
+
+// inDirectory reports whether the given path is in the given directory, or in one of its subdirectories.
+func inDirectory(path, dir string) bool {
+	if realDir, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = realDir
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -158,8 +158,9 @@ type ForwardedHeaders struct {
 
 // ProxyProtocol contains Proxy-Protocol configuration.
 type ProxyProtocol struct {
-	Insecure   bool     `description:"Trust all." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	TrustedIPs []string `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Insecure   bool              `description:"Trust all." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	TrustedIPs []string          `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	TLVHeaders map[string]string `description:"Defines the request headers to populate with the PROXY protocol v2 TLVs, indexed by TLV name." json:"tlvHeaders,omitempty" toml:"tlvHeaders,omitempty" yaml:"tlvHeaders,omitempty" export:"true"`
 }
 
 // EntryPoints holds the HTTP entry point list.
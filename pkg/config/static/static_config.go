@@ -3,6 +3,7 @@ package static
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -16,9 +17,11 @@ import (
 	"github.com/traefik/traefik/v3/pkg/ping"
 	acmeprovider "github.com/traefik/traefik/v3/pkg/provider/acme"
 	"github.com/traefik/traefik/v3/pkg/provider/consulcatalog"
+	"github.com/traefik/traefik/v3/pkg/provider/dns"
 	"github.com/traefik/traefik/v3/pkg/provider/docker"
 	"github.com/traefik/traefik/v3/pkg/provider/ecs"
 	"github.com/traefik/traefik/v3/pkg/provider/file"
+	"github.com/traefik/traefik/v3/pkg/provider/git"
 	"github.com/traefik/traefik/v3/pkg/provider/http"
 	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd"
 	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/gateway"
@@ -30,6 +33,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/provider/kv/redis"
 	"github.com/traefik/traefik/v3/pkg/provider/kv/zk"
 	"github.com/traefik/traefik/v3/pkg/provider/nomad"
+	"github.com/traefik/traefik/v3/pkg/provider/privateca"
 	"github.com/traefik/traefik/v3/pkg/provider/rest"
 	"github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -68,6 +72,10 @@ type Configuration struct {
 	TCPServersTransport *TCPServersTransport `description:"TCP servers default transport." json:"tcpServersTransport,omitempty" toml:"tcpServersTransport,omitempty" yaml:"tcpServersTransport,omitempty" export:"true"`
 	EntryPoints         EntryPoints          `description:"Entry points definition." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
 	Providers           *Providers           `description:"Providers configuration." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
+	Tenants             map[string]*Tenant   `description:"Tenants isolating the configurations of the providers bound to them." json:"tenants,omitempty" toml:"tenants,omitempty" yaml:"tenants,omitempty" export:"true"`
+
+	ConfigurationHistory *ConfigurationHistory `description:"Keeps the history of the applied dynamic configurations." json:"configurationHistory,omitempty" toml:"configurationHistory,omitempty" yaml:"configurationHistory,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	SecretReferences     *SecretReferences     `description:"Enables the resolution of the secret references of the dynamic configuration of trusted providers." json:"secretReferences,omitempty" toml:"secretReferences,omitempty" yaml:"secretReferences,omitempty" export:"true"`
 
 	API     *API            `description:"Enable api/dashboard." json:"api,omitempty" toml:"api,omitempty" yaml:"api,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Metrics *otypes.Metrics `description:"Enable a metrics exporter." json:"metrics,omitempty" toml:"metrics,omitempty" yaml:"metrics,omitempty" export:"true"`
@@ -102,6 +110,36 @@ func (c *Core) SetDefaults() {
 	c.DefaultRuleSyntax = "v3"
 }
 
+// ConfigurationHistory configures the history of the applied dynamic configurations.
+type ConfigurationHistory struct {
+	MaxEntries         int     `description:"Maximum number of applied configurations kept in the history." json:"maxEntries,omitempty" toml:"maxEntries,omitempty" yaml:"maxEntries,omitempty" export:"true"`
+	MaxDisabledRouters float64 `description:"Maximum fraction (between 0 and 1) of the enabled routers that a new configuration can disable, the configuration being rejected otherwise. The protection is disabled when set to 0." json:"maxDisabledRouters,omitempty" toml:"maxDisabledRouters,omitempty" yaml:"maxDisabledRouters,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ConfigurationHistory) SetDefaults() {
+	c.MaxEntries = 10
+}
+
+// SecretReferences configures the resolution of the env: and file: secret references of the dynamic configuration.
+// Nothing is resolved but for the listed providers, and within the listed prefixes.
+type SecretReferences struct {
+	Providers    []string `description:"Defines the trusted providers whose dynamic configuration can reference secrets." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
+	EnvPrefixes  []string `description:"Defines the prefixes of the names of the environment variables which can be referenced." json:"envPrefixes,omitempty" toml:"envPrefixes,omitempty" yaml:"envPrefixes,omitempty" export:"true"`
+	FilePrefixes []string `description:"Defines the directories of the files which can be referenced." json:"filePrefixes,omitempty" toml:"filePrefixes,omitempty" yaml:"filePrefixes,omitempty" export:"true"`
+}
+
+// Tenant restricts the routers of the providers bound to it.
+type Tenant struct {
+	Providers        []string `description:"Defines the providers bound to the tenant. A namespace of the Kubernetes CRD provider is bound with the kubernetescrd/namespace syntax." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
+	Hosts            []string `description:"Defines the hostnames the routers of the tenant are allowed to match. A *. prefix allows the subdomains of a domain." json:"hosts,omitempty" toml:"hosts,omitempty" yaml:"hosts,omitempty" export:"true"`
+	PathPrefixes     []string `description:"Defines the path prefixes the routers of the tenant are allowed to match." json:"pathPrefixes,omitempty" toml:"pathPrefixes,omitempty" yaml:"pathPrefixes,omitempty" export:"true"`
+	EntryPoints      []string `description:"Defines the entry points the routers of the tenant are allowed to use." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	AllowedTenants   []string `description:"Defines the other tenants whose services and middlewares the routers of the tenant are allowed to reference." json:"allowedTenants,omitempty" toml:"allowedTenants,omitempty" yaml:"allowedTenants,omitempty" export:"true"`
+	AllowedProviders []string `description:"Defines the restricted providers, such as internal, whose services and middlewares the routers of the tenant are allowed to reference." json:"allowedProviders,omitempty" toml:"allowedProviders,omitempty" yaml:"allowedProviders,omitempty" export:"true"`
+	CertResolvers    []string `description:"Defines the certificate resolvers the routers of the tenant are allowed to use." json:"certResolvers,omitempty" toml:"certResolvers,omitempty" yaml:"certResolvers,omitempty" export:"true"`
+}
+
 // SpiffeClientConfig defines the SPIFFE client configuration.
 type SpiffeClientConfig struct {
 	WorkloadAPIAddr string `description:"Defines the workload API address." json:"workloadAPIAddr,omitempty" toml:"workloadAPIAddr,omitempty" yaml:"workloadAPIAddr,omitempty"`
@@ -111,6 +149,7 @@ type SpiffeClientConfig struct {
 type CertificateResolver struct {
 	ACME      *acmeprovider.Configuration `description:"Enables ACME (Let's Encrypt) automatic SSL." json:"acme,omitempty" toml:"acme,omitempty" yaml:"acme,omitempty" export:"true"`
 	Tailscale *struct{}                   `description:"Enables Tailscale certificate resolution." json:"tailscale,omitempty" toml:"tailscale,omitempty" yaml:"tailscale,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	PrivateCA *privateca.Configuration    `description:"Enables the certificate resolution with a private CA managed by Traefik." json:"privateCA,omitempty" toml:"privateCA,omitempty" yaml:"privateCA,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // Global holds the global configuration.
@@ -256,6 +295,8 @@ type Providers struct {
 	ZooKeeper              *zk.Provider                   `description:"Enables ZooKeeper provider." json:"zooKeeper,omitempty" toml:"zooKeeper,omitempty" yaml:"zooKeeper,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Redis                  *redis.Provider                `description:"Enables Redis provider." json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTP                   *http.Provider                 `description:"Enables HTTP provider." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Git                    *git.Provider                  `description:"Enables Git provider." json:"git,omitempty" toml:"git,omitempty" yaml:"git,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	DNS                    *dns.Provider                  `description:"Enables DNS provider." json:"dns,omitempty" toml:"dns,omitempty" yaml:"dns,omitempty" export:"true"`
 
 	Plugin map[string]PluginConf `description:"Plugins configuration." json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
 }
@@ -278,7 +319,8 @@ func (c *Configuration) SetEffectiveConfiguration() {
 	if (c.API != nil && c.API.Insecure) ||
 		(c.Ping != nil && !c.Ping.ManualRouting && c.Ping.EntryPoint == DefaultInternalEntryPointName) ||
 		(c.Metrics != nil && c.Metrics.Prometheus != nil && !c.Metrics.Prometheus.ManualRouting && c.Metrics.Prometheus.EntryPoint == DefaultInternalEntryPointName) ||
-		(c.Providers != nil && c.Providers.Rest != nil && c.Providers.Rest.Insecure) {
+		(c.Providers != nil && c.Providers.Rest != nil && c.Providers.Rest.Insecure) ||
+		(c.Providers != nil && c.Providers.Git != nil && c.Providers.Git.Webhook != nil && c.Providers.Git.Webhook.Insecure) {
 		if _, ok := c.EntryPoints[DefaultInternalEntryPointName]; !ok {
 			ep := &EntryPoint{Address: ":8080"}
 			ep.SetDefaults()
@@ -338,6 +380,11 @@ func (c *Configuration) SetEffectiveConfiguration() {
 		c.Providers.KubernetesIngress.DefaultRuleSyntax = c.Core.DefaultRuleSyntax
 	}
 
+	// Defines the default rule syntax used to validate the routers managed through the REST provider.
+	if c.Core != nil && c.Providers.Rest != nil {
+		c.Providers.Rest.DefaultRuleSyntax = c.Core.DefaultRuleSyntax
+	}
+
 	for _, resolver := range c.CertificatesResolvers {
 		if resolver.ACME == nil {
 			continue
@@ -413,6 +460,12 @@ func (c *Configuration) ValidateConfiguration() error {
 		if len(resolver.ACME.Storage) == 0 {
 			return fmt.Errorf("unable to initialize certificates resolver %q with no storage location for the certificates", name)
 		}
+
+		if resolver.ACME.SharedStorage != nil {
+			if err := resolver.ACME.SharedStorage.Validate(); err != nil {
+				return fmt.Errorf("unable to initialize certificates resolver %q shared storage: %w", name, err)
+			}
+		}
 	}
 
 	if c.Core != nil {
@@ -426,6 +479,62 @@ func (c *Configuration) ValidateConfiguration() error {
 		}
 	}
 
+	if c.ConfigurationHistory != nil {
+		if c.ConfigurationHistory.MaxEntries <= 0 {
+			return errors.New("the configuration history must keep at least one entry")
+		}
+
+		if c.ConfigurationHistory.MaxDisabledRouters < 0 || c.ConfigurationHistory.MaxDisabledRouters > 1 {
+			return fmt.Errorf("invalid configuration history maxDisabledRouters %v: must be between 0 and 1", c.ConfigurationHistory.MaxDisabledRouters)
+		}
+	}
+
+	boundProviders := make(map[string]string)
+	for name, tenant := range c.Tenants {
+		if tenant == nil || len(tenant.Providers) == 0 {
+			return fmt.Errorf("tenant %q must be bound to at least one provider", name)
+		}
+
+		for _, pvd := range tenant.Providers {
+			// Only the Kubernetes CRD provider records the namespaces of the routers, middlewares and services.
+			if pvdName, _, ok := strings.Cut(pvd, "/"); ok && pvdName != "kubernetescrd" {
+				return fmt.Errorf("tenant %q cannot be bound to a namespace of the provider %q", name, pvdName)
+			}
+
+			if other, ok := boundProviders[pvd]; ok && other != name {
+				return fmt.Errorf("provider %q cannot be bound to both tenants %q and %q", pvd, other, name)
+			}
+			boundProviders[pvd] = name
+		}
+
+		for _, allowed := range tenant.AllowedTenants {
+			if _, ok := c.Tenants[allowed]; !ok {
+				return fmt.Errorf("tenant %q allows the unknown tenant %q", name, allowed)
+			}
+		}
+	}
+
+	if c.SecretReferences != nil {
+		if len(c.SecretReferences.Providers) == 0 {
+			return errors.New("the secret references must be enabled for at least one provider")
+		}
+
+		// The configuration of a tenant is not trusted, it cannot read the secrets of Traefik.
+		for _, pvd := range c.SecretReferences.Providers {
+			for bound, tenant := range boundProviders {
+				if bound == pvd || strings.HasPrefix(bound, pvd+"/") {
+					return fmt.Errorf("the secret references cannot be enabled for the provider %q bound to the tenant %q", pvd, tenant)
+				}
+			}
+		}
+
+		for _, prefix := range c.SecretReferences.FilePrefixes {
+			if !filepath.IsAbs(prefix) {
+				return fmt.Errorf("the secret references file prefix %q must be an absolute path", prefix)
+			}
+		}
+	}
+
 	if c.Providers != nil && c.Providers.KubernetesIngressNGINX != nil {
 		if c.Providers.KubernetesIngressNGINX.WatchNamespace != "" && c.Providers.KubernetesIngressNGINX.WatchNamespaceSelector != "" {
 			return errors.New("watchNamespace and watchNamespaceSelector options are mutually exclusive")
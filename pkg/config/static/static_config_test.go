@@ -378,3 +378,132 @@ func TestValidateConfiguration_BasePath(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfiguration_Tenants(t *testing.T) {
+	tests := []struct {
+		desc      string
+		tenants   map[string]*Tenant
+		expectErr bool
+	}{
+		{
+			desc: "valid tenants",
+			tenants: map[string]*Tenant{
+				"team-a": {Providers: []string{"kubernetescrd/team-a"}, AllowedTenants: []string{"team-b"}},
+				"team-b": {Providers: []string{"kubernetescrd/team-b", "consul"}},
+			},
+			expectErr: false,
+		},
+		{
+			desc: "tenant without provider",
+			tenants: map[string]*Tenant{
+				"team-a": {Hosts: []string{"a.example.com"}},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "provider bound to two tenants",
+			tenants: map[string]*Tenant{
+				"team-a": {Providers: []string{"consul"}},
+				"team-b": {Providers: []string{"consul"}},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "unknown allowed tenant",
+			tenants: map[string]*Tenant{
+				"team-a": {Providers: []string{"consul"}, AllowedTenants: []string{"team-b"}},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "namespace of a provider not recording the namespaces",
+			tenants: map[string]*Tenant{
+				"team-a": {Providers: []string{"kubernetesgateway/team-a"}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Configuration{
+				Tenants: test.tenants,
+			}
+
+			err := cfg.ValidateConfiguration()
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateConfiguration_SecretReferences(t *testing.T) {
+	tests := []struct {
+		desc             string
+		secretReferences *SecretReferences
+		tenants          map[string]*Tenant
+		expectErr        bool
+	}{
+		{
+			desc: "valid secret references",
+			secretReferences: &SecretReferences{
+				Providers:    []string{"file"},
+				EnvPrefixes:  []string{"TRAEFIK_SECRET_"},
+				FilePrefixes: []string{"/run/secrets/traefik"},
+			},
+			tenants: map[string]*Tenant{
+				"team-a": {Providers: []string{"kubernetescrd/team-a"}},
+			},
+			expectErr: false,
+		},
+		{
+			desc:             "no provider",
+			secretReferences: &SecretReferences{EnvPrefixes: []string{"TRAEFIK_SECRET_"}},
+			expectErr:        true,
+		},
+		{
+			desc:             "relative file prefix",
+			secretReferences: &SecretReferences{Providers: []string{"file"}, FilePrefixes: []string{"secrets"}},
+			expectErr:        true,
+		},
+		{
+			desc:             "provider bound to a tenant",
+			secretReferences: &SecretReferences{Providers: []string{"consul"}},
+			tenants: map[string]*Tenant{
+				"team-a": {Providers: []string{"consul"}},
+			},
+			expectErr: true,
+		},
+		{
+			desc:             "provider with a namespace bound to a tenant",
+			secretReferences: &SecretReferences{Providers: []string{"kubernetescrd"}},
+			tenants: map[string]*Tenant{
+				"team-a": {Providers: []string{"kubernetescrd/team-a"}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Configuration{
+				SecretReferences: test.secretReferences,
+				Tenants:          test.tenants,
+			}
+
+			err := cfg.ValidateConfiguration()
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package accesslog
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/udp"
+)
+
+// Connection close reasons.
+const (
+	closeReasonClient = "client closed"
+	closeReasonServer = "server closed"
+	closeReasonDone   = "closed"
+)
+
+// NewTCPHandler returns a tcp.Handler writing an access log entry for each connection handled by next,
+// once the connection is closed.
+func (h *Handler) NewTCPHandler(entryPointName, routerName, serviceName string, next tcp.Handler) tcp.Handler {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		now := time.Now().UTC()
+
+		core := CoreLogData{
+			StartUTC:            now,
+			StartLocal:          now.Local(),
+			logs.EntryPointName: entryPointName,
+			RouterName:          routerName,
+			ServiceName:         serviceName,
+			RequestProtocol:     "TCP",
+			RequestCount:        nextRequestCount(),
+		}
+
+		core[ClientAddr] = conn.RemoteAddr().String()
+		core[ClientHost], core[ClientPort] = silentSplitHostPort(conn.RemoteAddr().String())
+
+		lConn := &logConn{WriteCloser: conn}
+
+		next.ServeTCP(lConn)
+
+		core[Duration] = time.Now().UTC().Sub(now)
+		core[RequestContentSize] = lConn.bytesRead.Load()
+		core[DownstreamContentSize] = lConn.bytesWritten.Load()
+
+		lConn.mu.Lock()
+		if lConn.serviceAddr != "" {
+			core[ServiceAddr] = lConn.serviceAddr
+		}
+		core[CloseReason] = lConn.closeReason
+		lConn.mu.Unlock()
+
+		if core[CloseReason] == "" {
+			core[CloseReason] = closeReasonDone
+		}
+
+		addTLSFields(core, conn)
+
+		h.logConnection(&LogData{Core: core})
+	})
+}
+
+// NewUDPHandler returns a udp.Handler writing an access log entry for each session handled by next,
+// once the session is closed.
+func (h *Handler) NewUDPHandler(entryPointName, routerName, serviceName string, next udp.Handler) udp.Handler {
+	return udp.HandlerFunc(func(conn *udp.Conn) {
+		now := time.Now().UTC()
+
+		core := CoreLogData{
+			StartUTC:            now,
+			StartLocal:          now.Local(),
+			logs.EntryPointName: entryPointName,
+			RouterName:          routerName,
+			ServiceName:         serviceName,
+			RequestProtocol:     "UDP",
+			RequestCount:        nextRequestCount(),
+		}
+
+		core[ClientAddr] = conn.RemoteAddr().String()
+		core[ClientHost], core[ClientPort] = silentSplitHostPort(conn.RemoteAddr().String())
+
+		next.ServeUDP(conn)
+
+		core[Duration] = time.Now().UTC().Sub(now)
+		core[RequestContentSize] = conn.BytesRead()
+		core[DownstreamContentSize] = conn.BytesWritten()
+
+		if serviceAddr := conn.ServiceAddr(); serviceAddr != "" {
+			core[ServiceAddr] = serviceAddr
+		}
+
+		core[CloseReason] = conn.CloseReason()
+		if core[CloseReason] == "" {
+			core[CloseReason] = closeReasonDone
+		}
+
+		h.logConnection(&LogData{Core: core})
+	})
+}
+
+func (h *Handler) logConnection(logDataTable *LogData) {
+	if h.config.BufferingSize > 0 {
+		h.logHandlerChan <- handlerParams{
+			ctx:          context.Background(),
+			logDataTable: logDataTable,
+			connection:   true,
+		}
+		return
+	}
+
+	h.logTheConnection(context.Background(), logDataTable)
+}
+
+// logTheConnection logs a TCP or UDP connection.
+// As connections have neither a status code nor retries, only the minDuration filter applies to them.
+func (h *Handler) logTheConnection(ctx context.Context, logDataTable *LogData) {
+	duration, _ := logDataTable.Core[Duration].(time.Duration)
+	if !h.keepAccessLog(0, 0, duration) {
+		return
+	}
+
+	fields := logrus.Fields{}
+
+	for k, v := range logDataTable.Core {
+		if h.config.Fields.Keep(strings.ToLower(k)) {
+			fields[k] = v
+		}
+	}
+
+	h.writeEntry(ctx, fields)
+}
+
+// addTLSFields adds the TLS information of the given connection, if any, to the log data.
+func addTLSFields(core CoreLogData, conn tcp.WriteCloser) {
+	switch c := conn.(type) {
+	case *tls.Conn:
+		state := c.ConnectionState()
+		if !state.HandshakeComplete {
+			return
+		}
+
+		core[TLSVersion] = traefiktls.GetVersion(&state)
+		core[TLSCipher] = traefiktls.GetCipherName(&state)
+		core[TLSCurve] = traefiktls.GetCurveName(&state)
+		if state.ServerName != "" {
+			core[TLSServerName] = state.ServerName
+		}
+		if len(state.PeerCertificates) > 0 && state.PeerCertificates[0] != nil {
+			core[TLSClientSubject] = state.PeerCertificates[0].Subject.String()
+		}
+
+	case interface{ ServerName() string }:
+		// TLS passthrough connections, for which the server name is read from the ClientHello.
+		if serverName := c.ServerName(); serverName != "" {
+			core[TLSServerName] = serverName
+		}
+	}
+}
+
+// logConn wraps a connection to keep track of the data needed by the access logs.
+type logConn struct {
+	tcp.WriteCloser
+
+	bytesRead    atomic.Int64
+	bytesWritten atomic.Int64
+
+	mu          sync.Mutex
+	serviceAddr string
+	closeReason string
+}
+
+func (c *logConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	c.bytesRead.Add(int64(n))
+
+	if errors.Is(err, io.EOF) {
+		c.SetCloseReason(closeReasonClient)
+	} else if err != nil {
+		c.SetCloseReason(err.Error())
+	}
+
+	return n, err
+}
+
+func (c *logConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	c.bytesWritten.Add(int64(n))
+
+	if err != nil {
+		c.SetCloseReason(err.Error())
+	}
+
+	return n, err
+}
+
+// CloseWrite is called when the server has ended its side of the connection.
+func (c *logConn) CloseWrite() error {
+	c.SetCloseReason(closeReasonServer)
+
+	return c.WriteCloser.CloseWrite()
+}
+
+// NetConn returns the wrapped connection.
+func (c *logConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// SetServiceAddr records the address of the server the connection is forwarded to.
+func (c *logConn) SetServiceAddr(addr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.serviceAddr = addr
+}
+
+// SetCloseReason records why the connection is ending.
+// Only the first reason is kept, as the following ones are consequences of it.
+func (c *logConn) SetCloseReason(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeReason == "" {
+		c.closeReason = reason
+	}
+}
@@ -0,0 +1,243 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	otypes "github.com/traefik/traefik/v3/pkg/observability/types"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/udp"
+)
+
+func TestTCPHandler(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	logger, err := NewHandler(t.Context(), &otypes.AccessLog{FilePath: logFilePath, Format: JSONFormat})
+	require.NoError(t, err)
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		conn.(interface{ SetServiceAddr(addr string) }).SetServiceAddr("10.0.0.1:6379")
+
+		buf := make([]byte, 4)
+		_, err := io.ReadFull(conn, buf)
+		assert.NoError(t, err)
+
+		_, err = conn.Write([]byte("pong!"))
+		assert.NoError(t, err)
+
+		_, err = conn.Read(buf)
+		assert.ErrorIs(t, err, io.EOF)
+
+		_ = conn.Close()
+	})
+
+	handler := logger.NewTCPHandler("redis", "router@file", "service@file", next)
+
+	serverConn, clientConn := tcpConnPair(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeTCP(serverConn)
+	}()
+
+	_, err = clientConn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(clientConn, buf)
+	require.NoError(t, err)
+
+	require.NoError(t, clientConn.CloseWrite())
+
+	<-done
+	require.NoError(t, logger.Close())
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	jsonData := make(map[string]any)
+	require.NoError(t, json.Unmarshal(logData, &jsonData))
+
+	assert.Equal(t, "redis", jsonData[logs.EntryPointName])
+	assert.Equal(t, "router@file", jsonData[RouterName])
+	assert.Equal(t, "service@file", jsonData[ServiceName])
+	assert.Equal(t, "10.0.0.1:6379", jsonData[ServiceAddr])
+	assert.Equal(t, "TCP", jsonData[RequestProtocol])
+	assert.Equal(t, "127.0.0.1", jsonData[ClientHost])
+	assert.InDelta(t, 4, jsonData[RequestContentSize], delta)
+	assert.InDelta(t, 5, jsonData[DownstreamContentSize], delta)
+	assert.Equal(t, closeReasonClient, jsonData[CloseReason])
+	assert.NotEmpty(t, jsonData[Duration])
+}
+
+func TestTCPHandler_Filters(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	config := &otypes.AccessLog{
+		FilePath: logFilePath,
+		Format:   JSONFormat,
+		Filters:  &otypes.AccessLogFilters{StatusCodes: []string{"200"}},
+	}
+	logger, err := NewHandler(t.Context(), config)
+	require.NoError(t, err)
+
+	handler := logger.NewTCPHandler("redis", "router@file", "service@file", tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_ = conn.Close()
+	}))
+
+	serverConn, _ := tcpConnPair(t)
+	handler.ServeTCP(serverConn)
+
+	require.NoError(t, logger.Close())
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.Empty(t, logData)
+}
+
+func TestUDPHandler(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		backend             func(t *testing.T) string
+		expectedResponse    bool
+		expectedCloseReason string
+	}{
+		{
+			desc:                "idle timeout",
+			backend:             udpEchoServer,
+			expectedResponse:    true,
+			expectedCloseReason: "idle timeout",
+		},
+		{
+			desc: "backend error",
+			backend: func(t *testing.T) string {
+				t.Helper()
+
+				// Nothing listens on the address, the datagrams are refused.
+				conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+				require.NoError(t, err)
+				require.NoError(t, conn.Close())
+
+				return conn.LocalAddr().String()
+			},
+			expectedCloseReason: "backend error: ",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+			logger, err := NewHandler(t.Context(), &otypes.AccessLog{FilePath: logFilePath, Format: JSONFormat})
+			require.NoError(t, err)
+
+			backendAddr := test.backend(t)
+
+			proxy, err := udp.NewProxy(backendAddr)
+			require.NoError(t, err)
+
+			handler := logger.NewUDPHandler("dns", "router@file", "service@file", proxy)
+
+			listener, err := udp.Listen(net.ListenConfig{}, "udp", "127.0.0.1:0", 200*time.Millisecond)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				handler.ServeUDP(conn)
+			}()
+
+			clientConn, err := net.Dial("udp", listener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = clientConn.Close() })
+
+			_, err = clientConn.Write([]byte("ping"))
+			require.NoError(t, err)
+
+			if test.expectedResponse {
+				buf := make([]byte, 4)
+				_, err = clientConn.Read(buf)
+				require.NoError(t, err)
+				assert.Equal(t, "ping", string(buf))
+			}
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timeout waiting for the end of the session")
+			}
+
+			require.NoError(t, logger.Close())
+
+			logData, err := os.ReadFile(logFilePath)
+			require.NoError(t, err)
+
+			jsonData := make(map[string]any)
+			require.NoError(t, json.Unmarshal(logData, &jsonData))
+
+			assert.Equal(t, "UDP", jsonData[RequestProtocol])
+			assert.Equal(t, backendAddr, jsonData[ServiceAddr])
+			assert.InDelta(t, 4, jsonData[RequestContentSize], delta)
+			assert.Contains(t, jsonData[CloseReason], test.expectedCloseReason)
+		})
+	}
+}
+
+// udpEchoServer starts a UDP server sending back the datagrams it receives, and returns its address.
+func udpEchoServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func tcpConnPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	serverConn, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverConn.Close() })
+
+	return serverConn.(*net.TCPConn), clientConn.(*net.TCPConn)
+}
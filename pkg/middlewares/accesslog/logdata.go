@@ -75,8 +75,26 @@ const (
 	TLSVersion = "TLSVersion"
 	// TLSCipher is the cipher used in the request.
 	TLSCipher = "TLSCipher"
+	// TLSCurve is the key exchange group (elliptic curve or hybrid post-quantum) negotiated for the request.
+	TLSCurve = "TLSCurve"
 	// TLSClientSubject is the string representation of the TLS client certificate's Subject.
 	TLSClientSubject = "TLSClientSubject"
+	// TLSServerName is the server name (SNI) requested by the client during the TLS handshake.
+	TLSServerName = "TLSServerName"
+
+	// CloseReason is the map key used for the reason why a TCP, UDP, or WebSocket connection was closed.
+	CloseReason = "CloseReason"
+
+	// WebSocketCloseCode is the map key used for the status code of the first close frame of a WebSocket connection.
+	WebSocketCloseCode = "WebSocketCloseCode"
+	// WebSocketClientMessages is the map key used for the number of WebSocket messages sent by the client.
+	WebSocketClientMessages = "WebSocketClientMessages"
+	// WebSocketServerMessages is the map key used for the number of WebSocket messages sent by the server.
+	WebSocketServerMessages = "WebSocketServerMessages"
+	// WebSocketClientBytes is the map key used for the number of bytes sent by the client over a WebSocket connection.
+	WebSocketClientBytes = "WebSocketClientBytes"
+	// WebSocketServerBytes is the map key used for the number of bytes sent by the server over a WebSocket connection.
+	WebSocketServerBytes = "WebSocketServerBytes"
 
 	// Deprecated: TraceID is the consistent identifier for tracking requests across services, including upstream ones managed by Traefik, shown as a 32-hex digit string.
 	TraceID = "TraceId"
@@ -130,7 +148,15 @@ func init() {
 	allCoreKeys[RetryAttempts] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
+	allCoreKeys[TLSCurve] = struct{}{}
 	allCoreKeys[TLSClientSubject] = struct{}{}
+	allCoreKeys[TLSServerName] = struct{}{}
+	allCoreKeys[CloseReason] = struct{}{}
+	allCoreKeys[WebSocketCloseCode] = struct{}{}
+	allCoreKeys[WebSocketClientMessages] = struct{}{}
+	allCoreKeys[WebSocketServerMessages] = struct{}{}
+	allCoreKeys[WebSocketClientBytes] = struct{}{}
+	allCoreKeys[WebSocketServerBytes] = struct{}{}
 	allCoreKeys[OTelTraceID] = struct{}{}
 	allCoreKeys[OTelSpanID] = struct{}{}
 }
@@ -61,6 +61,8 @@ func (n noopCloser) Close() error {
 type handlerParams struct {
 	ctx          context.Context
 	logDataTable *LogData
+	// connection is true when logDataTable describes a TCP or UDP connection instead of an HTTP round trip.
+	connection bool
 }
 
 // Handler will write each request and its response to the access log.
@@ -161,6 +163,11 @@ func NewHandler(ctx context.Context, config *otypes.AccessLog) (*Handler, error)
 	if config.BufferingSize > 0 {
 		logHandler.wg.Go(func() {
 			for handlerParams := range logHandler.logHandlerChan {
+				if handlerParams.connection {
+					logHandler.logTheConnection(handlerParams.ctx, handlerParams.logDataTable)
+					continue
+				}
+
 				logHandler.logTheRoundTrip(handlerParams.ctx, handlerParams.logDataTable)
 			}
 		})
@@ -239,6 +246,7 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		core[RequestScheme] = "https"
 		core[TLSVersion] = traefiktls.GetVersion(req.TLS)
 		core[TLSCipher] = traefiktls.GetCipherName(req.TLS)
+		core[TLSCurve] = traefiktls.GetCurveName(req.TLS)
 		if len(req.TLS.PeerCertificates) > 0 && req.TLS.PeerCertificates[0] != nil {
 			core[TLSClientSubject] = req.TLS.PeerCertificates[0].Subject.String()
 		}
@@ -361,6 +369,11 @@ func (h *Handler) logTheRoundTrip(ctx context.Context, logDataTable *LogData) {
 	h.redactHeaders(logDataTable.OriginResponse, fields, "origin_")
 	h.redactHeaders(logDataTable.DownstreamResponse.headers, fields, "downstream_")
 
+	h.writeEntry(ctx, fields)
+}
+
+// writeEntry writes the given fields as a new access log entry.
+func (h *Handler) writeEntry(ctx context.Context, fields logrus.Fields) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
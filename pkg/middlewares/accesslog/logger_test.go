@@ -664,6 +664,7 @@ func TestLoggerJSON(t *testing.T) {
 				TLSClientSubject:          assertString("CN=foobar"),
 				TLSVersion:                assertString("1.3"),
 				TLSCipher:                 assertString("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"),
+				TLSCurve:                  assertString("X25519MLKEM768"),
 				"time":                    assertNotEmpty(),
 				StartLocal:                assertNotEmpty(),
 				StartUTC:                  assertNotEmpty(),
@@ -1183,6 +1184,7 @@ func doLoggingTLSOpt(t *testing.T, config *otypes.AccessLog, enableTLS, tracing
 		req.TLS = &tls.ConnectionState{
 			Version:     tls.VersionTLS13,
 			CipherSuite: tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			CurveID:     tls.X25519MLKEM768,
 			PeerCertificates: []*x509.Certificate{{
 				Subject: pkix.Name{CommonName: "foobar"},
 			}},
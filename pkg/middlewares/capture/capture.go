@@ -190,7 +190,14 @@ func (crw *captureResponseWriter) Flush() {
 
 func (crw *captureResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	if h, ok := crw.rw.(http.Hijacker); ok {
-		return h.Hijack()
+		conn, brw, err := h.Hijack()
+		if err == nil && crw.status == 0 {
+			// The connection is hijacked to switch protocols (e.g. WebSocket),
+			// the status is written directly on the connection.
+			crw.status = http.StatusSwitchingProtocols
+		}
+
+		return conn, brw, err
 	}
 
 	return nil, nil, fmt.Errorf("not a hijacker: %T", crw.rw)
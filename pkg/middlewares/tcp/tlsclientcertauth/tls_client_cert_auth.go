@@ -0,0 +1,69 @@
+package tlsclientcertauth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tlsclientcertauth"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const (
+	typeName = "TLSClientCertAuthTCP"
+)
+
+// tlsClientCertAuth is a middleware that authorizes the connections based on the attributes of the client certificate.
+type tlsClientCertAuth struct {
+	next    tcp.Handler
+	checker *tlsclientcertauth.Checker
+	name    string
+}
+
+// New builds a new TCP TLSClientCertAuth middleware given a list of rules.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPTLSClientCertAuth, name string) (tcp.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	checker, err := tlsclientcertauth.NewChecker(config.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("creating TLS client certificate checker: %w", err)
+	}
+
+	return &tlsClientCertAuth{
+		next:    next,
+		checker: checker,
+		name:    name,
+	}, nil
+}
+
+func (a *tlsClientCertAuth) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), a.name, typeName)
+
+	addr := conn.RemoteAddr().String()
+
+	tlsConn, ok := tcp.GetTLSConn(conn)
+	if !ok {
+		logger.Error().Msgf("Connection from %s rejected: TLS is not terminated by the router", addr)
+		conn.Close()
+		return
+	}
+
+	// The client certificate is only known once the handshake is completed.
+	if err := tlsConn.Handshake(); err != nil {
+		logger.Debug().Err(err).Msgf("TLS handshake with %s failed", addr)
+		conn.Close()
+		return
+	}
+
+	state := tlsConn.ConnectionState()
+	if err := a.checker.IsAuthorizedConnection(&state); err != nil {
+		logger.Error().Err(err).Msgf("Connection from %s rejected", addr)
+		conn.Close()
+		return
+	}
+
+	logger.Debug().Msgf("Connection from %s accepted", addr)
+
+	a.next.ServeTCP(conn)
+}
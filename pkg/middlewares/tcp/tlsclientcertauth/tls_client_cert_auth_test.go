@@ -0,0 +1,205 @@
+package tlsclientcertauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPTLSClientCertAuth
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			expectedError: true,
+		},
+		{
+			desc: "empty rule",
+			config: dynamic.TCPTLSClientCertAuth{
+				Rules: []dynamic.TLSClientCertRule{{}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid rule",
+			config: dynamic.TCPTLSClientCertAuth{
+				Rules: []dynamic.TLSClientCertRule{{DNSNames: []string{"*.example.org"}}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+			handler, err := New(t.Context(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, handler)
+			}
+		})
+	}
+}
+
+func TestTLSClientCertAuth_ServeTCP(t *testing.T) {
+	caCert, caKey := generateCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	serverCert, serverKey := generateCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "server"},
+		DNSNames:    []string{"server.example.org"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, caCert, caKey)
+
+	clientCert, clientKey := generateCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "client"},
+		DNSNames:    []string{"client.example.org"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, caKey)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientCAs:    roots,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}
+
+	testCases := []struct {
+		desc       string
+		dnsName    string
+		withTLS    bool
+		clientCert bool
+		expected   string
+	}{
+		{
+			desc:       "authorized client certificate",
+			dnsName:    "client.example.org",
+			withTLS:    true,
+			clientCert: true,
+			expected:   "OK",
+		},
+		{
+			desc:       "non matching client certificate",
+			dnsName:    "other.example.org",
+			withTLS:    true,
+			clientCert: true,
+		},
+		{
+			desc:    "no client certificate",
+			dnsName: "client.example.org",
+			withTLS: true,
+		},
+		{
+			desc:    "TLS not terminated",
+			dnsName: "client.example.org",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				_, err := conn.Write([]byte("OK"))
+				require.NoError(t, err)
+
+				err = conn.Close()
+				require.NoError(t, err)
+			})
+
+			config := dynamic.TCPTLSClientCertAuth{
+				Rules: []dynamic.TLSClientCertRule{{DNSNames: []string{test.dnsName}}},
+			}
+			handler, err := New(t.Context(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			server, client := net.Pipe()
+
+			go func() {
+				var conn tcp.WriteCloser = &writeCloser{server}
+				if test.withTLS {
+					conn = tls.Server(server, serverConfig)
+				}
+				handler.ServeTCP(conn)
+			}()
+
+			if !test.withTLS {
+				read, err := io.ReadAll(client)
+				require.NoError(t, err)
+				assert.Empty(t, read)
+				return
+			}
+
+			clientConfig := &tls.Config{
+				RootCAs:    roots,
+				ServerName: "server.example.org",
+			}
+			if test.clientCert {
+				clientConfig.Certificates = []tls.Certificate{{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}}
+			}
+
+			tlsClient := tls.Client(client, clientConfig)
+			require.NoError(t, tlsClient.Handshake())
+
+			read, _ := io.ReadAll(tlsClient)
+			assert.Equal(t, test.expected, string(read))
+		})
+	}
+}
+
+type writeCloser struct {
+	net.Conn
+}
+
+func (c *writeCloser) CloseWrite() error {
+	return c.Close()
+}
+
+func generateCertificate(t *testing.T, template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
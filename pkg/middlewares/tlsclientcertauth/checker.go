@@ -0,0 +1,176 @@
+package tlsclientcertauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// oidDomainComponent is the OID of the domain component attribute (RFC 2247).
+var oidDomainComponent = asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}
+
+// Checker allows to check that a client certificate matches at least one of a set of rules.
+type Checker struct {
+	rules []dynamic.TLSClientCertRule
+}
+
+// NewChecker builds a new Checker given a list of rules.
+func NewChecker(rules []dynamic.TLSClientCertRule) (*Checker, error) {
+	if len(rules) == 0 {
+		return nil, errors.New("no rules provided")
+	}
+
+	for i, rule := range rules {
+		if err := validateRule(rule); err != nil {
+			return nil, fmt.Errorf("invalid rule %d: %w", i, err)
+		}
+	}
+
+	return &Checker{rules: rules}, nil
+}
+
+// IsAuthorized checks that the given client certificate matches at least one of the rules.
+func (c *Checker) IsAuthorized(cert *x509.Certificate) error {
+	if cert == nil {
+		return errors.New("no client certificate")
+	}
+
+	for _, rule := range c.rules {
+		if matchRule(rule, cert) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("client certificate %q (serial %s) does not match any rule", cert.Subject.CommonName, cert.SerialNumber)
+}
+
+// IsAuthorizedConnection checks that the given TLS connection presented a verified client certificate,
+// and that this certificate matches at least one of the rules.
+// The certificates which have not been verified against the client CAs of the TLS options are rejected,
+// as their attributes cannot be trusted.
+func (c *Checker) IsAuthorizedConnection(state *tls.ConnectionState) error {
+	if state == nil {
+		return errors.New("not a TLS connection")
+	}
+
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return errors.New("no verified client certificate")
+	}
+
+	return c.IsAuthorized(state.VerifiedChains[0][0])
+}
+
+func validateRule(rule dynamic.TLSClientCertRule) error {
+	var patterns [][]string
+	patterns = append(patterns, dnPatterns(rule.Subject)...)
+	patterns = append(patterns, dnPatterns(rule.Issuer)...)
+	patterns = append(patterns, rule.DNSNames, rule.URIs, rule.EmailAddresses, rule.SerialNumbers)
+
+	var count int
+	for _, values := range patterns {
+		for _, pattern := range values {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+
+		count += len(values)
+	}
+
+	// A rule without any attribute would match all the certificates.
+	if count == 0 {
+		return errors.New("the rule does not define any attribute")
+	}
+
+	return nil
+}
+
+func dnPatterns(rule *dynamic.TLSClientCertDNRule) [][]string {
+	if rule == nil {
+		return nil
+	}
+
+	return [][]string{
+		rule.Country,
+		rule.Province,
+		rule.Locality,
+		rule.Organization,
+		rule.OrganizationalUnit,
+		rule.CommonName,
+		rule.SerialNumber,
+		rule.DomainComponent,
+	}
+}
+
+// matchRule returns whether the given certificate matches all the attributes defined by the given rule.
+func matchRule(rule dynamic.TLSClientCertRule, cert *x509.Certificate) bool {
+	if !matchDN(rule.Subject, &cert.Subject) || !matchDN(rule.Issuer, &cert.Issuer) {
+		return false
+	}
+
+	var uris []string
+	for _, uri := range cert.URIs {
+		uris = append(uris, uri.String())
+	}
+
+	return matchAny(rule.DNSNames, cert.DNSNames, strings.ToLower) &&
+		matchAny(rule.URIs, uris, nil) &&
+		matchAny(rule.EmailAddresses, cert.EmailAddresses, strings.ToLower) &&
+		matchAny(rule.SerialNumbers, []string{cert.SerialNumber.String()}, nil)
+}
+
+func matchDN(rule *dynamic.TLSClientCertDNRule, name *pkix.Name) bool {
+	if rule == nil {
+		return true
+	}
+
+	var domainComponents []string
+	for _, attr := range name.Names {
+		if !attr.Type.Equal(oidDomainComponent) {
+			continue
+		}
+
+		if value, ok := attr.Value.(string); ok {
+			domainComponents = append(domainComponents, value)
+		}
+	}
+
+	return matchAny(rule.Country, name.Country, nil) &&
+		matchAny(rule.Province, name.Province, nil) &&
+		matchAny(rule.Locality, name.Locality, nil) &&
+		matchAny(rule.Organization, name.Organization, nil) &&
+		matchAny(rule.OrganizationalUnit, name.OrganizationalUnit, nil) &&
+		matchAny(rule.CommonName, []string{name.CommonName}, nil) &&
+		matchAny(rule.SerialNumber, []string{name.SerialNumber}, nil) &&
+		matchAny(rule.DomainComponent, domainComponents, nil)
+}
+
+// matchAny returns whether one of the values matches one of the patterns.
+// An attribute without patterns is not constrained, and always matches.
+// The normalize function, if any, is applied to the patterns and values before matching them.
+func matchAny(patterns, values []string, normalize func(string) string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if normalize != nil {
+				pattern, value = normalize(pattern), normalize(value)
+			}
+
+			if matched, _ := path.Match(pattern, value); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
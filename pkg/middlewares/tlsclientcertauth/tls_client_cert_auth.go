@@ -0,0 +1,58 @@
+package tlsclientcertauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+)
+
+const typeName = "TLSClientCertAuth"
+
+// tlsClientCertAuth is a middleware that authorizes the requests based on the attributes of the client certificate.
+type tlsClientCertAuth struct {
+	next    http.Handler
+	checker *Checker
+	name    string
+}
+
+// New builds a new TLSClientCertAuth middleware given a list of rules.
+func New(ctx context.Context, next http.Handler, config dynamic.TLSClientCertAuth, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	checker, err := NewChecker(config.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("creating TLS client certificate checker: %w", err)
+	}
+
+	return &tlsClientCertAuth{
+		next:    next,
+		checker: checker,
+		name:    name,
+	}, nil
+}
+
+func (a *tlsClientCertAuth) GetTracingInformation() (string, string) {
+	return a.name, typeName
+}
+
+func (a *tlsClientCertAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), a.name, typeName)
+
+	if err := a.checker.IsAuthorizedConnection(req.TLS); err != nil {
+		logger.Debug().Err(err).Msg("Rejecting request")
+		observability.SetStatusErrorf(req.Context(), "Rejecting request: %v", err)
+
+		rw.WriteHeader(http.StatusForbidden)
+		if _, err := rw.Write([]byte(http.StatusText(http.StatusForbidden))); err != nil {
+			log.Ctx(req.Context()).Error().Err(err).Send()
+		}
+		return
+	}
+
+	a.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,266 @@
+package tlsclientcertauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNewChecker(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rules         []dynamic.TLSClientCertRule
+		expectedError bool
+	}{
+		{
+			desc:          "no rules",
+			expectedError: true,
+		},
+		{
+			desc:          "empty rule",
+			rules:         []dynamic.TLSClientCertRule{{}},
+			expectedError: true,
+		},
+		{
+			desc: "empty subject rule",
+			rules: []dynamic.TLSClientCertRule{{
+				Subject: &dynamic.TLSClientCertDNRule{},
+			}},
+			expectedError: true,
+		},
+		{
+			desc: "invalid pattern",
+			rules: []dynamic.TLSClientCertRule{{
+				DNSNames: []string{"[foo"},
+			}},
+			expectedError: true,
+		},
+		{
+			desc: "valid rules",
+			rules: []dynamic.TLSClientCertRule{
+				{DNSNames: []string{"*.example.org"}},
+				{Issuer: &dynamic.TLSClientCertDNRule{CommonName: []string{"Root CA"}}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := NewChecker(test.rules)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, checker)
+		})
+	}
+}
+
+func TestChecker_IsAuthorized(t *testing.T) {
+	cert := testCertificate(t)
+
+	testCases := []struct {
+		desc     string
+		rules    []dynamic.TLSClientCertRule
+		expected bool
+	}{
+		{
+			desc: "matching subject common name",
+			rules: []dynamic.TLSClientCertRule{{
+				Subject: &dynamic.TLSClientCertDNRule{CommonName: []string{"client"}},
+			}},
+			expected: true,
+		},
+		{
+			desc: "matching subject with several components",
+			rules: []dynamic.TLSClientCertRule{{
+				Subject: &dynamic.TLSClientCertDNRule{
+					Organization:       []string{"Other", "Example"},
+					OrganizationalUnit: []string{"Billing"},
+					DomainComponent:    []string{"example"},
+				},
+			}},
+			expected: true,
+		},
+		{
+			desc: "non matching subject component",
+			rules: []dynamic.TLSClientCertRule{{
+				Subject: &dynamic.TLSClientCertDNRule{
+					CommonName:   []string{"client"},
+					Organization: []string{"Other"},
+				},
+			}},
+		},
+		{
+			desc: "matching issuer",
+			rules: []dynamic.TLSClientCertRule{{
+				Issuer: &dynamic.TLSClientCertDNRule{CommonName: []string{"Example * CA"}},
+			}},
+			expected: true,
+		},
+		{
+			desc: "matching DNS name case-insensitively",
+			rules: []dynamic.TLSClientCertRule{{
+				DNSNames: []string{"*.EXAMPLE.org"},
+			}},
+			expected: true,
+		},
+		{
+			desc: "matching SPIFFE ID",
+			rules: []dynamic.TLSClientCertRule{{
+				URIs: []string{"spiffe://example.org/ns/*/sa/billing"},
+			}},
+			expected: true,
+		},
+		{
+			desc: "wildcard does not match path separator",
+			rules: []dynamic.TLSClientCertRule{{
+				URIs: []string{"spiffe://example.org/*"},
+			}},
+		},
+		{
+			desc: "matching email address",
+			rules: []dynamic.TLSClientCertRule{{
+				EmailAddresses: []string{"billing@example.org"},
+			}},
+			expected: true,
+		},
+		{
+			desc: "matching serial number",
+			rules: []dynamic.TLSClientCertRule{{
+				SerialNumbers: []string{"42"},
+			}},
+			expected: true,
+		},
+		{
+			desc: "non matching serial number",
+			rules: []dynamic.TLSClientCertRule{{
+				SerialNumbers: []string{"43"},
+			}},
+		},
+		{
+			desc: "second rule matching",
+			rules: []dynamic.TLSClientCertRule{
+				{DNSNames: []string{"other.example.org"}},
+				{SerialNumbers: []string{"42"}},
+			},
+			expected: true,
+		},
+		{
+			desc: "rule with one non matching attribute",
+			rules: []dynamic.TLSClientCertRule{{
+				DNSNames:      []string{"client.example.org"},
+				SerialNumbers: []string{"43"},
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := NewChecker(test.rules)
+			require.NoError(t, err)
+
+			err = checker.IsAuthorized(cert)
+			if test.expected {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestTLSClientCertAuth_ServeHTTP(t *testing.T) {
+	cert := testCertificate(t)
+
+	testCases := []struct {
+		desc           string
+		state          *tls.ConnectionState
+		expectedStatus int
+	}{
+		{
+			desc:           "no TLS",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc: "unverified client certificate",
+			state: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc: "authorized client certificate",
+			state: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+				VerifiedChains:   [][]*x509.Certificate{{cert}},
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			config := dynamic.TLSClientCertAuth{
+				Rules: []dynamic.TLSClientCertRule{{
+					URIs: []string{"spiffe://example.org/ns/prod/sa/billing"},
+				}},
+			}
+			handler, err := New(t.Context(), next, config, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "https://example.org", nil)
+			req.TLS = test.state
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func testCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	spiffeID, err := url.Parse("spiffe://example.org/ns/prod/sa/billing")
+	require.NoError(t, err)
+
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject: pkix.Name{
+			CommonName:         "client",
+			Organization:       []string{"Example"},
+			OrganizationalUnit: []string{"Billing"},
+			Names: []pkix.AttributeTypeAndValue{
+				{Type: asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}, Value: "example"},
+			},
+		},
+		Issuer: pkix.Name{
+			CommonName: "Example Intermediate CA",
+		},
+		DNSNames:       []string{"client.example.org"},
+		EmailAddresses: []string{"billing@example.org"},
+		URIs:           []*url.URL{spiffeID},
+	}
+}
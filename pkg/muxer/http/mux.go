@@ -170,6 +170,16 @@ func withRoutingPath(req *http.Request) (*http.Request, error) {
 
 // ParseDomains extract domains from rule.
 func ParseDomains(rule string) ([]string, error) {
+	tree, err := ParseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.ParseMatchers([]string{"Host"}), nil
+}
+
+// ParseRule parses the given rule, of any syntax, into a tree of matchers.
+func ParseRule(rule string) (*rules.Tree, error) {
 	var matchers []string
 	for matcher := range httpFuncs {
 		matchers = append(matchers, matcher)
@@ -193,7 +203,7 @@ func ParseDomains(rule string) ([]string, error) {
 		return nil, fmt.Errorf("error while parsing rule %s", rule)
 	}
 
-	return buildTree().ParseMatchers([]string{"Host"}), nil
+	return buildTree(), nil
 }
 
 // routes implements sort.Interface.
@@ -10,13 +10,15 @@ import (
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/tcp"
 )
 
 var tcpFuncs = map[string]func(*matchersTree, ...string) error{
-	"ALPN":          expect1Parameter(alpn),
-	"ClientIP":      expect1Parameter(clientIP),
-	"HostSNI":       expect1Parameter(hostSNI),
-	"HostSNIRegexp": expect1Parameter(hostSNIRegexp),
+	"ALPN":             expect1Parameter(alpn),
+	"ClientIP":         expect1Parameter(clientIP),
+	"HostSNI":          expect1Parameter(hostSNI),
+	"HostSNIRegexp":    expect1Parameter(hostSNIRegexp),
+	"ProxyProtocolTLV": expect2Parameters(proxyProtocolTLV),
 }
 
 func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
@@ -29,6 +31,16 @@ func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTre
 	}
 }
 
+func expect2Parameters(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
+	return func(route *matchersTree, s ...string) error {
+		if len(s) != 2 {
+			return fmt.Errorf("unexpected number of parameters; got %d, expected 2", len(s))
+		}
+
+		return fn(route, s...)
+	}
+}
+
 // alpn checks if any of the connection ALPN protocols matches one of the matcher protocols.
 func alpn(tree *matchersTree, protos ...string) error {
 	proto := protos[0]
@@ -127,3 +139,19 @@ func isASCII(s string) bool {
 
 	return true
 }
+
+// proxyProtocolTLV checks if the PROXY protocol v2 header of the connection holds the given TLV with the given value.
+func proxyProtocolTLV(tree *matchersTree, params ...string) error {
+	name, value := params[0], params[1]
+
+	if err := tcp.ValidateProxyProtocolTLVName(name); err != nil {
+		return fmt.Errorf("invalid TLV name for ProxyProtocolTLV matcher: %w", err)
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		tlvValue, ok := tcp.LookupProxyProtocolTLV(meta.tlvs, name)
+		return ok && tlvValue == value
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package tcp
 import (
 	"testing"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -382,3 +383,58 @@ func Test_ALPN(t *testing.T) {
 		})
 	}
 }
+
+func Test_ProxyProtocolTLV(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[string]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid ProxyProtocolTLV matcher (unknown TLV)",
+			rule:     "ProxyProtocolTLV(`foo`, `bar`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid ProxyProtocolTLV matcher (too few parameters)",
+			rule:     "ProxyProtocolTLV(`aws.vpce_id`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid ProxyProtocolTLV matcher",
+			rule: "ProxyProtocolTLV(`authority`, `foo.example.com`)",
+			expected: map[string]bool{
+				"foo.example.com": true,
+				"bar.example.com": false,
+				"":                false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for authority, match := range test.expected {
+				var meta ConnData
+				if authority != "" {
+					meta.tlvs = []proxyproto.TLV{{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte(authority)}}
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, authority)
+			}
+		})
+	}
+}
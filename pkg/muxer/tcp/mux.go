@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/rules"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -18,6 +19,8 @@ type ConnData struct {
 	serverName string
 	remoteIP   string
 	alpnProtos []string
+	// tlvs are the TLVs received in the PROXY protocol v2 header, if any.
+	tlvs []proxyproto.TLV
 }
 
 // NewConnData builds a connData struct from the given parameters.
@@ -36,6 +39,7 @@ func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string) (
 		serverName: types.CanonicalDomain(serverName),
 		remoteIP:   remoteIP,
 		alpnProtos: alpnProtos,
+		tlvs:       tcp.ProxyProtocolTLVs(conn),
 	}, nil
 }
 
@@ -181,6 +185,16 @@ func (m *Muxer) HasRoutes() bool {
 // ParseHostSNI extracts the HostSNIs declared in a rule.
 // This is a first naive implementation used in TCP routing.
 func ParseHostSNI(rule string) ([]string, error) {
+	tree, err := ParseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.ParseMatchers([]string{"HostSNI"}), nil
+}
+
+// ParseRule parses the given rule, of any syntax, into a tree of matchers.
+func ParseRule(rule string) (*rules.Tree, error) {
 	var matchers []string
 	for matcher := range tcpFuncs {
 		matchers = append(matchers, matcher)
@@ -204,7 +218,7 @@ func ParseHostSNI(rule string) ([]string, error) {
 		return nil, fmt.Errorf("error while parsing rule %s", rule)
 	}
 
-	return buildTree().ParseMatchers([]string{"HostSNI"}), nil
+	return buildTree(), nil
 }
 
 // routes implements sort.Interface.
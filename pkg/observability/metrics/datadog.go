@@ -33,6 +33,10 @@ const (
 	ddOpenConnsName               = "open.connections"
 
 	ddTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	ddTLSHandshakesName             = "tls.handshakes.total"
+
+	ddACMECertsNotAfterTimestampName = "acme.certs.notAfterTimestamp"
+	ddACMECertsRenewalFailuresName   = "acme.certs.renewal.failures.total"
 
 	ddEntryPointReqsName        = "entrypoint.request.total"
 	ddEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -53,6 +57,11 @@ const (
 	ddServiceServerUpName     = "service.server.up"
 	ddServiceReqsBytesName    = "service.requests.bytes.total"
 	ddServiceRespsBytesName   = "service.responses.bytes.total"
+
+	ddServiceWebSocketOpenConnsName = "service.websocket.connections.open"
+	ddServiceWebSocketMessagesName  = "service.websocket.messages.total"
+	ddServiceWebSocketBytesName     = "service.websocket.bytes.total"
+	ddServiceWebSocketClosesName    = "service.websocket.closes.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -71,10 +80,13 @@ func RegisterDatadog(ctx context.Context, config *otypes.Datadog) Registry {
 	initDatadogClient(ctx, config, datadogLogger)
 
 	registry := &standardRegistry{
-		configReloadsCounter:           datadogClient.NewCounter(ddConfigReloadsName, 1.0),
-		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
-		openConnectionsGauge:           datadogClient.NewGauge(ddOpenConnsName),
-		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		configReloadsCounter:            datadogClient.NewCounter(ddConfigReloadsName, 1.0),
+		lastConfigReloadSuccessGauge:    datadogClient.NewGauge(ddLastConfigReloadSuccessName),
+		openConnectionsGauge:            datadogClient.NewGauge(ddOpenConnsName),
+		tlsCertsNotAfterTimestampGauge:  datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		tlsHandshakesCounter:            datadogClient.NewCounter(ddTLSHandshakesName, 1.0),
+		acmeCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddACMECertsNotAfterTimestampName),
+		acmeCertsRenewalFailuresCounter: datadogClient.NewCounter(ddACMECertsRenewalFailuresName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -104,6 +116,10 @@ func RegisterDatadog(ctx context.Context, config *otypes.Datadog) Registry {
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServiceServerUpName)
 		registry.serviceReqsBytesCounter = datadogClient.NewCounter(ddServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = datadogClient.NewCounter(ddServiceRespsBytesName, 1.0)
+		registry.serviceWebSocketOpenConnsGauge = datadogClient.NewGauge(ddServiceWebSocketOpenConnsName)
+		registry.serviceWebSocketMessagesCounter = datadogClient.NewCounter(ddServiceWebSocketMessagesName, 1.0)
+		registry.serviceWebSocketBytesCounter = datadogClient.NewCounter(ddServiceWebSocketBytesName, 1.0)
+		registry.serviceWebSocketClosesCounter = datadogClient.NewCounter(ddServiceWebSocketClosesName, 1.0)
 	}
 
 	return registry
@@ -30,6 +30,10 @@ const (
 	influxDBOpenConnsName               = "traefik.open.connections"
 
 	influxDBTLSCertsNotAfterTimestampName = "traefik.tls.certs.notAfterTimestamp"
+	influxDBTLSHandshakesName             = "traefik.tls.handshakes.total"
+
+	influxDBACMECertsNotAfterTimestampName = "traefik.acme.certs.notAfterTimestamp"
+	influxDBACMECertsRenewalFailuresName   = "traefik.acme.certs.renewal.failures.total"
 
 	influxDBEntryPointReqsName        = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqsTLSName     = "traefik.entrypoint.requests.tls.total"
@@ -50,6 +54,11 @@ const (
 	influxDBServiceServerUpName     = "traefik.service.server.up"
 	influxDBServiceReqsBytesName    = "traefik.service.requests.bytes.total"
 	influxDBServiceRespsBytesName   = "traefik.service.responses.bytes.total"
+
+	influxDBServiceWebSocketOpenConnsName = "traefik.service.websocket.connections.open"
+	influxDBServiceWebSocketMessagesName  = "traefik.service.websocket.messages.total"
+	influxDBServiceWebSocketBytesName     = "traefik.service.websocket.bytes.total"
+	influxDBServiceWebSocketClosesName    = "traefik.service.websocket.closes.total"
 )
 
 // RegisterInfluxDB2 creates metrics exporter for InfluxDB2.
@@ -80,10 +89,13 @@ func RegisterInfluxDB2(ctx context.Context, config *otypes.InfluxDB2) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:           influxDB2Store.NewCounter(influxDBConfigReloadsName),
-		lastConfigReloadSuccessGauge:   influxDB2Store.NewGauge(influxDBLastConfigReloadSuccessName),
-		openConnectionsGauge:           influxDB2Store.NewGauge(influxDBOpenConnsName),
-		tlsCertsNotAfterTimestampGauge: influxDB2Store.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		configReloadsCounter:            influxDB2Store.NewCounter(influxDBConfigReloadsName),
+		lastConfigReloadSuccessGauge:    influxDB2Store.NewGauge(influxDBLastConfigReloadSuccessName),
+		openConnectionsGauge:            influxDB2Store.NewGauge(influxDBOpenConnsName),
+		tlsCertsNotAfterTimestampGauge:  influxDB2Store.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		tlsHandshakesCounter:            influxDB2Store.NewCounter(influxDBTLSHandshakesName),
+		acmeCertsNotAfterTimestampGauge: influxDB2Store.NewGauge(influxDBACMECertsNotAfterTimestampName),
+		acmeCertsRenewalFailuresCounter: influxDB2Store.NewCounter(influxDBACMECertsRenewalFailuresName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -113,6 +125,10 @@ func RegisterInfluxDB2(ctx context.Context, config *otypes.InfluxDB2) Registry {
 		registry.serviceServerUpGauge = influxDB2Store.NewGauge(influxDBServiceServerUpName)
 		registry.serviceReqsBytesCounter = influxDB2Store.NewCounter(influxDBServiceReqsBytesName)
 		registry.serviceRespsBytesCounter = influxDB2Store.NewCounter(influxDBServiceRespsBytesName)
+		registry.serviceWebSocketOpenConnsGauge = influxDB2Store.NewGauge(influxDBServiceWebSocketOpenConnsName)
+		registry.serviceWebSocketMessagesCounter = influxDB2Store.NewCounter(influxDBServiceWebSocketMessagesName)
+		registry.serviceWebSocketBytesCounter = influxDB2Store.NewCounter(influxDBServiceWebSocketBytesName)
+		registry.serviceWebSocketClosesCounter = influxDB2Store.NewCounter(influxDBServiceWebSocketClosesName)
 	}
 
 	return registry
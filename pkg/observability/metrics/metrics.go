@@ -28,6 +28,12 @@ type Registry interface {
 	// TLS
 
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
+	TLSHandshakesCounter() metrics.Counter
+
+	// ACME
+
+	ACMECertsNotAfterTimestampGauge() metrics.Gauge
+	ACMECertsRenewalFailuresCounter() metrics.Counter
 
 	// entry point metrics
 
@@ -54,6 +60,10 @@ type Registry interface {
 	ServiceServerUpGauge() metrics.Gauge
 	ServiceReqsBytesCounter() metrics.Counter
 	ServiceRespsBytesCounter() metrics.Counter
+	ServiceWebSocketOpenConnsGauge() metrics.Gauge
+	ServiceWebSocketMessagesCounter() metrics.Counter
+	ServiceWebSocketBytesCounter() metrics.Counter
+	ServiceWebSocketClosesCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -70,6 +80,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var openConnectionsGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var tlsHandshakesCounter []metrics.Counter
+	var acmeCertsNotAfterTimestampGauge []metrics.Gauge
+	var acmeCertsRenewalFailuresCounter []metrics.Counter
 	var entryPointReqsCounter []CounterWithHeaders
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
@@ -87,6 +100,10 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceServerUpGauge []metrics.Gauge
 	var serviceReqsBytesCounter []metrics.Counter
 	var serviceRespsBytesCounter []metrics.Counter
+	var serviceWebSocketOpenConnsGauge []metrics.Gauge
+	var serviceWebSocketMessagesCounter []metrics.Counter
+	var serviceWebSocketBytesCounter []metrics.Counter
+	var serviceWebSocketClosesCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -101,6 +118,15 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.TLSHandshakesCounter() != nil {
+			tlsHandshakesCounter = append(tlsHandshakesCounter, r.TLSHandshakesCounter())
+		}
+		if r.ACMECertsNotAfterTimestampGauge() != nil {
+			acmeCertsNotAfterTimestampGauge = append(acmeCertsNotAfterTimestampGauge, r.ACMECertsNotAfterTimestampGauge())
+		}
+		if r.ACMECertsRenewalFailuresCounter() != nil {
+			acmeCertsRenewalFailuresCounter = append(acmeCertsRenewalFailuresCounter, r.ACMECertsRenewalFailuresCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -152,61 +178,87 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRespsBytesCounter() != nil {
 			serviceRespsBytesCounter = append(serviceRespsBytesCounter, r.ServiceRespsBytesCounter())
 		}
+		if r.ServiceWebSocketOpenConnsGauge() != nil {
+			serviceWebSocketOpenConnsGauge = append(serviceWebSocketOpenConnsGauge, r.ServiceWebSocketOpenConnsGauge())
+		}
+		if r.ServiceWebSocketMessagesCounter() != nil {
+			serviceWebSocketMessagesCounter = append(serviceWebSocketMessagesCounter, r.ServiceWebSocketMessagesCounter())
+		}
+		if r.ServiceWebSocketBytesCounter() != nil {
+			serviceWebSocketBytesCounter = append(serviceWebSocketBytesCounter, r.ServiceWebSocketBytesCounter())
+		}
+		if r.ServiceWebSocketClosesCounter() != nil {
+			serviceWebSocketClosesCounter = append(serviceWebSocketClosesCounter, r.ServiceWebSocketClosesCounter())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		openConnectionsGauge:           multi.NewGauge(openConnectionsGauge...),
-		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
-		entryPointReqsCounter:          NewMultiCounterWithHeaders(entryPointReqsCounter...),
-		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
-		entryPointReqDurationHistogram: MultiHistogram(entryPointReqDurationHistogram),
-		entryPointReqsBytesCounter:     multi.NewCounter(entryPointReqsBytesCounter...),
-		entryPointRespsBytesCounter:    multi.NewCounter(entryPointRespsBytesCounter...),
-		routerReqsCounter:              NewMultiCounterWithHeaders(routerReqsCounter...),
-		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
-		routerReqDurationHistogram:     MultiHistogram(routerReqDurationHistogram),
-		routerReqsBytesCounter:         multi.NewCounter(routerReqsBytesCounter...),
-		routerRespsBytesCounter:        multi.NewCounter(routerRespsBytesCounter...),
-		serviceReqsCounter:             NewMultiCounterWithHeaders(serviceReqsCounter...),
-		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
-		serviceReqDurationHistogram:    MultiHistogram(serviceReqDurationHistogram),
-		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
-		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
-		serviceReqsBytesCounter:        multi.NewCounter(serviceReqsBytesCounter...),
-		serviceRespsBytesCounter:       multi.NewCounter(serviceRespsBytesCounter...),
+		epEnabled:                       len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0,
+		svcEnabled:                      len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
+		routerEnabled:                   len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0,
+		configReloadsCounter:            multi.NewCounter(configReloadsCounter...),
+		lastConfigReloadSuccessGauge:    multi.NewGauge(lastConfigReloadSuccessGauge...),
+		openConnectionsGauge:            multi.NewGauge(openConnectionsGauge...),
+		tlsCertsNotAfterTimestampGauge:  multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		tlsHandshakesCounter:            multi.NewCounter(tlsHandshakesCounter...),
+		acmeCertsNotAfterTimestampGauge: multi.NewGauge(acmeCertsNotAfterTimestampGauge...),
+		acmeCertsRenewalFailuresCounter: multi.NewCounter(acmeCertsRenewalFailuresCounter...),
+		entryPointReqsCounter:           NewMultiCounterWithHeaders(entryPointReqsCounter...),
+		entryPointReqsTLSCounter:        multi.NewCounter(entryPointReqsTLSCounter...),
+		entryPointReqDurationHistogram:  MultiHistogram(entryPointReqDurationHistogram),
+		entryPointReqsBytesCounter:      multi.NewCounter(entryPointReqsBytesCounter...),
+		entryPointRespsBytesCounter:     multi.NewCounter(entryPointRespsBytesCounter...),
+		routerReqsCounter:               NewMultiCounterWithHeaders(routerReqsCounter...),
+		routerReqsTLSCounter:            multi.NewCounter(routerReqsTLSCounter...),
+		routerReqDurationHistogram:      MultiHistogram(routerReqDurationHistogram),
+		routerReqsBytesCounter:          multi.NewCounter(routerReqsBytesCounter...),
+		routerRespsBytesCounter:         multi.NewCounter(routerRespsBytesCounter...),
+		serviceReqsCounter:              NewMultiCounterWithHeaders(serviceReqsCounter...),
+		serviceReqsTLSCounter:           multi.NewCounter(serviceReqsTLSCounter...),
+		serviceReqDurationHistogram:     MultiHistogram(serviceReqDurationHistogram),
+		serviceRetriesCounter:           multi.NewCounter(serviceRetriesCounter...),
+		serviceServerUpGauge:            multi.NewGauge(serviceServerUpGauge...),
+		serviceReqsBytesCounter:         multi.NewCounter(serviceReqsBytesCounter...),
+		serviceRespsBytesCounter:        multi.NewCounter(serviceRespsBytesCounter...),
+		serviceWebSocketOpenConnsGauge:  multi.NewGauge(serviceWebSocketOpenConnsGauge...),
+		serviceWebSocketMessagesCounter: multi.NewCounter(serviceWebSocketMessagesCounter...),
+		serviceWebSocketBytesCounter:    multi.NewCounter(serviceWebSocketBytesCounter...),
+		serviceWebSocketClosesCounter:   multi.NewCounter(serviceWebSocketClosesCounter...),
 	}
 }
 
 type standardRegistry struct {
-	epEnabled                      bool
-	routerEnabled                  bool
-	svcEnabled                     bool
-	configReloadsCounter           metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	openConnectionsGauge           metrics.Gauge
-	tlsCertsNotAfterTimestampGauge metrics.Gauge
-	entryPointReqsCounter          CounterWithHeaders
-	entryPointReqsTLSCounter       metrics.Counter
-	entryPointReqDurationHistogram ScalableHistogram
-	entryPointReqsBytesCounter     metrics.Counter
-	entryPointRespsBytesCounter    metrics.Counter
-	routerReqsCounter              CounterWithHeaders
-	routerReqsTLSCounter           metrics.Counter
-	routerReqDurationHistogram     ScalableHistogram
-	routerReqsBytesCounter         metrics.Counter
-	routerRespsBytesCounter        metrics.Counter
-	serviceReqsCounter             CounterWithHeaders
-	serviceReqsTLSCounter          metrics.Counter
-	serviceReqDurationHistogram    ScalableHistogram
-	serviceRetriesCounter          metrics.Counter
-	serviceServerUpGauge           metrics.Gauge
-	serviceReqsBytesCounter        metrics.Counter
-	serviceRespsBytesCounter       metrics.Counter
+	epEnabled                       bool
+	routerEnabled                   bool
+	svcEnabled                      bool
+	configReloadsCounter            metrics.Counter
+	lastConfigReloadSuccessGauge    metrics.Gauge
+	openConnectionsGauge            metrics.Gauge
+	tlsCertsNotAfterTimestampGauge  metrics.Gauge
+	tlsHandshakesCounter            metrics.Counter
+	acmeCertsNotAfterTimestampGauge metrics.Gauge
+	acmeCertsRenewalFailuresCounter metrics.Counter
+	entryPointReqsCounter           CounterWithHeaders
+	entryPointReqsTLSCounter        metrics.Counter
+	entryPointReqDurationHistogram  ScalableHistogram
+	entryPointReqsBytesCounter      metrics.Counter
+	entryPointRespsBytesCounter     metrics.Counter
+	routerReqsCounter               CounterWithHeaders
+	routerReqsTLSCounter            metrics.Counter
+	routerReqDurationHistogram      ScalableHistogram
+	routerReqsBytesCounter          metrics.Counter
+	routerRespsBytesCounter         metrics.Counter
+	serviceReqsCounter              CounterWithHeaders
+	serviceReqsTLSCounter           metrics.Counter
+	serviceReqDurationHistogram     ScalableHistogram
+	serviceRetriesCounter           metrics.Counter
+	serviceServerUpGauge            metrics.Gauge
+	serviceReqsBytesCounter         metrics.Counter
+	serviceRespsBytesCounter        metrics.Counter
+	serviceWebSocketOpenConnsGauge  metrics.Gauge
+	serviceWebSocketMessagesCounter metrics.Counter
+	serviceWebSocketBytesCounter    metrics.Counter
+	serviceWebSocketClosesCounter   metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -237,6 +289,18 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) TLSHandshakesCounter() metrics.Counter {
+	return r.tlsHandshakesCounter
+}
+
+func (r *standardRegistry) ACMECertsNotAfterTimestampGauge() metrics.Gauge {
+	return r.acmeCertsNotAfterTimestampGauge
+}
+
+func (r *standardRegistry) ACMECertsRenewalFailuresCounter() metrics.Counter {
+	return r.acmeCertsRenewalFailuresCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() CounterWithHeaders {
 	return r.entryPointReqsCounter
 }
@@ -305,6 +369,22 @@ func (r *standardRegistry) ServiceRespsBytesCounter() metrics.Counter {
 	return r.serviceRespsBytesCounter
 }
 
+func (r *standardRegistry) ServiceWebSocketOpenConnsGauge() metrics.Gauge {
+	return r.serviceWebSocketOpenConnsGauge
+}
+
+func (r *standardRegistry) ServiceWebSocketMessagesCounter() metrics.Counter {
+	return r.serviceWebSocketMessagesCounter
+}
+
+func (r *standardRegistry) ServiceWebSocketBytesCounter() metrics.Counter {
+	return r.serviceWebSocketBytesCounter
+}
+
+func (r *standardRegistry) ServiceWebSocketClosesCounter() metrics.Counter {
+	return r.serviceWebSocketClosesCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
@@ -119,13 +119,16 @@ func RegisterOpenTelemetry(ctx context.Context, config *otypes.OTLP) Registry {
 		metric.WithInstrumentationVersion(version.Version))
 
 	reg := &standardRegistry{
-		epEnabled:                      config.AddEntryPointsLabels,
-		routerEnabled:                  config.AddRoutersLabels,
-		svcEnabled:                     config.AddServicesLabels,
-		configReloadsCounter:           newOTLPCounterFrom(meter, configReloadsTotalName, "Config reloads"),
-		lastConfigReloadSuccessGauge:   newOTLPGaugeFrom(meter, configLastReloadSuccessName, "Last config reload success", "ms"),
-		openConnectionsGauge:           newOTLPGaugeFrom(meter, openConnectionsName, "How many open connections exist, by entryPoint and protocol", "1"),
-		tlsCertsNotAfterTimestampGauge: newOTLPGaugeFrom(meter, tlsCertsNotAfterTimestampName, "Certificate expiration timestamp", "s"),
+		epEnabled:                       config.AddEntryPointsLabels,
+		routerEnabled:                   config.AddRoutersLabels,
+		svcEnabled:                      config.AddServicesLabels,
+		configReloadsCounter:            newOTLPCounterFrom(meter, configReloadsTotalName, "Config reloads"),
+		lastConfigReloadSuccessGauge:    newOTLPGaugeFrom(meter, configLastReloadSuccessName, "Last config reload success", "ms"),
+		openConnectionsGauge:            newOTLPGaugeFrom(meter, openConnectionsName, "How many open connections exist, by entryPoint and protocol", "1"),
+		tlsCertsNotAfterTimestampGauge:  newOTLPGaugeFrom(meter, tlsCertsNotAfterTimestampName, "Certificate expiration timestamp", "s"),
+		tlsHandshakesCounter:            newOTLPCounterFrom(meter, tlsHandshakesTotalName, "How many TLS handshakes have been completed, by TLS options, handshake type (full or resumed) and key exchange group"),
+		acmeCertsNotAfterTimestampGauge: newOTLPGaugeFrom(meter, acmeCertsNotAfterTimestampName, "ACME certificate expiration timestamp", "s"),
+		acmeCertsRenewalFailuresCounter: newOTLPCounterFrom(meter, acmeCertsRenewalFailuresTotalName, "How many ACME certificate renewals have failed"),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -173,6 +176,15 @@ func RegisterOpenTelemetry(ctx context.Context, config *otypes.OTLP) Registry {
 			"The total size of requests in bytes received by a service, partitioned by status code, protocol, and method.")
 		reg.serviceRespsBytesCounter = newOTLPCounterFrom(meter, serviceRespsBytesTotalName,
 			"The total size of responses in bytes returned by a service, partitioned by status code, protocol, and method.")
+		reg.serviceWebSocketOpenConnsGauge = newOTLPGaugeFrom(meter, serviceWebSocketOpenConnsName,
+			"How many WebSocket connections are currently open on a service.",
+			"1")
+		reg.serviceWebSocketMessagesCounter = newOTLPCounterFrom(meter, serviceWebSocketMessagesTotalName,
+			"How many WebSocket messages have been proxied on a service, partitioned by direction.")
+		reg.serviceWebSocketBytesCounter = newOTLPCounterFrom(meter, serviceWebSocketBytesTotalName,
+			"The total size in bytes of the WebSocket data proxied on a service, partitioned by direction.")
+		reg.serviceWebSocketClosesCounter = newOTLPCounterFrom(meter, serviceWebSocketClosesTotalName,
+			"How many WebSocket connections have been closed on a service, partitioned by close code.")
 	}
 
 	return reg
@@ -29,6 +29,12 @@ const (
 	// TLS.
 	metricsTLSPrefix              = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestampName = metricsTLSPrefix + "certs_not_after"
+	tlsHandshakesTotalName        = metricsTLSPrefix + "handshakes_total"
+
+	// ACME.
+	metricsACMEPrefix                 = MetricNamePrefix + "acme_"
+	acmeCertsNotAfterTimestampName    = metricsACMEPrefix + "certs_not_after"
+	acmeCertsRenewalFailuresTotalName = metricsACMEPrefix + "certs_renewal_failures_total"
 
 	// entry point.
 	metricEntryPointPrefix        = MetricNamePrefix + "entrypoint_"
@@ -55,6 +61,11 @@ const (
 	serviceServerUpName        = metricServicePrefix + "server_up"
 	serviceReqsBytesTotalName  = metricServicePrefix + "requests_bytes_total"
 	serviceRespsBytesTotalName = metricServicePrefix + "responses_bytes_total"
+
+	serviceWebSocketOpenConnsName     = metricServicePrefix + "websocket_open_connections"
+	serviceWebSocketMessagesTotalName = metricServicePrefix + "websocket_messages_total"
+	serviceWebSocketBytesTotalName    = metricServicePrefix + "websocket_bytes_total"
+	serviceWebSocketClosesTotalName   = metricServicePrefix + "websocket_closes_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -122,6 +133,18 @@ func initStandardRegistry(config *otypes.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestampName,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	tlsHandshakes := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tlsHandshakesTotalName,
+		Help: "How many TLS handshakes have been completed, by TLS options, handshake type (full or resumed) and key exchange group",
+	}, []string{"options", "type", "group"})
+	acmeCertsNotAfterTimestamp := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: acmeCertsNotAfterTimestampName,
+		Help: "ACME certificate expiration timestamp",
+	}, []string{"resolver", "cn", "sans"})
+	acmeCertsRenewalFailures := newCounterFrom(stdprometheus.CounterOpts{
+		Name: acmeCertsRenewalFailuresTotalName,
+		Help: "How many ACME certificate renewals have failed",
+	}, []string{"resolver", "cn", "sans"})
 	openConnections := newGaugeFrom(stdprometheus.GaugeOpts{
 		Name: openConnectionsName,
 		Help: "How many open connections exist, by entryPoint and protocol",
@@ -131,17 +154,23 @@ func initStandardRegistry(config *otypes.Prometheus) Registry {
 		configReloads.cv,
 		lastConfigReloadSuccess.gv,
 		tlsCertsNotAfterTimestamp.gv,
+		tlsHandshakes.cv,
+		acmeCertsNotAfterTimestamp.gv,
+		acmeCertsRenewalFailures.cv,
 		openConnections.gv,
 	}
 
 	reg := &standardRegistry{
-		epEnabled:                      config.AddEntryPointsLabels,
-		routerEnabled:                  config.AddRoutersLabels,
-		svcEnabled:                     config.AddServicesLabels,
-		configReloadsCounter:           configReloads,
-		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
-		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimestamp,
-		openConnectionsGauge:           openConnections,
+		epEnabled:                       config.AddEntryPointsLabels,
+		routerEnabled:                   config.AddRoutersLabels,
+		svcEnabled:                      config.AddServicesLabels,
+		configReloadsCounter:            configReloads,
+		lastConfigReloadSuccessGauge:    lastConfigReloadSuccess,
+		tlsCertsNotAfterTimestampGauge:  tlsCertsNotAfterTimestamp,
+		tlsHandshakesCounter:            tlsHandshakes,
+		acmeCertsNotAfterTimestampGauge: acmeCertsNotAfterTimestamp,
+		acmeCertsRenewalFailuresCounter: acmeCertsRenewalFailures,
+		openConnectionsGauge:            openConnections,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -249,6 +278,22 @@ func initStandardRegistry(config *otypes.Prometheus) Registry {
 			Name: serviceRespsBytesTotalName,
 			Help: "The total size of responses in bytes returned by a service, partitioned by status code, protocol, and method.",
 		}, []string{"code", "method", "protocol", "service"})
+		serviceWebSocketOpenConns := newGaugeFrom(stdprometheus.GaugeOpts{
+			Name: serviceWebSocketOpenConnsName,
+			Help: "How many WebSocket connections are currently open on a service.",
+		}, []string{"service"})
+		serviceWebSocketMessagesTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: serviceWebSocketMessagesTotalName,
+			Help: "How many WebSocket messages have been proxied on a service, partitioned by direction.",
+		}, []string{"direction", "service"})
+		serviceWebSocketBytesTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: serviceWebSocketBytesTotalName,
+			Help: "The total size in bytes of the WebSocket data proxied on a service, partitioned by direction.",
+		}, []string{"direction", "service"})
+		serviceWebSocketClosesTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: serviceWebSocketClosesTotalName,
+			Help: "How many WebSocket connections have been closed on a service, partitioned by close code.",
+		}, []string{"code", "service"})
 
 		promState.vectors = append(promState.vectors,
 			serviceReqs.cv,
@@ -258,6 +303,10 @@ func initStandardRegistry(config *otypes.Prometheus) Registry {
 			serviceServerUp.gv,
 			serviceReqsBytesTotal.cv,
 			serviceRespsBytesTotal.cv,
+			serviceWebSocketOpenConns.gv,
+			serviceWebSocketMessagesTotal.cv,
+			serviceWebSocketBytesTotal.cv,
+			serviceWebSocketClosesTotal.cv,
 		)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -267,6 +316,10 @@ func initStandardRegistry(config *otypes.Prometheus) Registry {
 		reg.serviceServerUpGauge = serviceServerUp
 		reg.serviceReqsBytesCounter = serviceReqsBytesTotal
 		reg.serviceRespsBytesCounter = serviceRespsBytesTotal
+		reg.serviceWebSocketOpenConnsGauge = serviceWebSocketOpenConns
+		reg.serviceWebSocketMessagesCounter = serviceWebSocketMessagesTotal
+		reg.serviceWebSocketBytesCounter = serviceWebSocketBytesTotal
+		reg.serviceWebSocketClosesCounter = serviceWebSocketClosesTotal
 	}
 
 	return reg
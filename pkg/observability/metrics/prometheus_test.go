@@ -113,6 +113,19 @@ func TestPrometheus(t *testing.T) {
 		TLSCertsNotAfterTimestampGauge().
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
+	prometheusRegistry.
+		TLSHandshakesCounter().
+		With("options", "default", "type", "resumed", "group", "X25519MLKEM768").
+		Add(1)
+
+	prometheusRegistry.
+		ACMECertsNotAfterTimestampGauge().
+		With("resolver", "myresolver", "cn", "value", "sans", "value").
+		Set(float64(time.Now().Unix()))
+	prometheusRegistry.
+		ACMECertsRenewalFailuresCounter().
+		With("resolver", "myresolver", "cn", "value", "sans", "value").
+		Add(1)
 
 	prometheusRegistry.
 		EntryPointReqsCounter().
@@ -180,6 +193,22 @@ func TestPrometheus(t *testing.T) {
 		ServiceReqsBytesCounter().
 		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
+	prometheusRegistry.
+		ServiceWebSocketOpenConnsGauge().
+		With("service", "service1").
+		Add(1)
+	prometheusRegistry.
+		ServiceWebSocketMessagesCounter().
+		With("service", "service1", "direction", "client_to_server").
+		Add(1)
+	prometheusRegistry.
+		ServiceWebSocketBytesCounter().
+		With("service", "service1", "direction", "server_to_client").
+		Add(1)
+	prometheusRegistry.
+		ServiceWebSocketClosesCounter().
+		With("service", "service1", "code", "1000").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -215,6 +244,33 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestampName),
 		},
+		{
+			name: tlsHandshakesTotalName,
+			labels: map[string]string{
+				"options": "default",
+				"type":    "resumed",
+				"group":   "X25519MLKEM768",
+			},
+			assert: buildCounterAssert(t, tlsHandshakesTotalName, 1),
+		},
+		{
+			name: acmeCertsNotAfterTimestampName,
+			labels: map[string]string{
+				"resolver": "myresolver",
+				"cn":       "value",
+				"sans":     "value",
+			},
+			assert: buildTimestampAssert(t, acmeCertsNotAfterTimestampName),
+		},
+		{
+			name: acmeCertsRenewalFailuresTotalName,
+			labels: map[string]string{
+				"resolver": "myresolver",
+				"cn":       "value",
+				"sans":     "value",
+			},
+			assert: buildCounterAssert(t, acmeCertsRenewalFailuresTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
@@ -376,6 +432,37 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildCounterAssert(t, serviceRespsBytesTotalName, 1),
 		},
+		{
+			name: serviceWebSocketOpenConnsName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildGaugeAssert(t, serviceWebSocketOpenConnsName, 1),
+		},
+		{
+			name: serviceWebSocketMessagesTotalName,
+			labels: map[string]string{
+				"direction": "client_to_server",
+				"service":   "service1",
+			},
+			assert: buildCounterAssert(t, serviceWebSocketMessagesTotalName, 1),
+		},
+		{
+			name: serviceWebSocketBytesTotalName,
+			labels: map[string]string{
+				"direction": "server_to_client",
+				"service":   "service1",
+			},
+			assert: buildCounterAssert(t, serviceWebSocketBytesTotalName, 1),
+		},
+		{
+			name: serviceWebSocketClosesTotalName,
+			labels: map[string]string{
+				"code":    "1000",
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, serviceWebSocketClosesTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -22,6 +22,10 @@ const (
 	statsdOpenConnectionsName         = "open.connections"
 
 	statsdTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	statsdTLSHandshakesName             = "tls.handshakes.total"
+
+	statsdACMECertsNotAfterTimestampName = "acme.certs.notAfterTimestamp"
+	statsdACMECertsRenewalFailuresName   = "acme.certs.renewal.failures.total"
 
 	statsdEntryPointReqsName        = "entrypoint.request.total"
 	statsdEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -42,6 +46,11 @@ const (
 	statsdServiceServerUpName     = "service.server.up"
 	statsdServiceReqsBytesName    = "service.requests.bytes.total"
 	statsdServiceRespsBytesName   = "service.responses.bytes.total"
+
+	statsdServiceWebSocketOpenConnsName = "service.websocket.connections.open"
+	statsdServiceWebSocketMessagesName  = "service.websocket.messages.total"
+	statsdServiceWebSocketBytesName     = "service.websocket.bytes.total"
+	statsdServiceWebSocketClosesName    = "service.websocket.closes.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -58,10 +67,13 @@ func RegisterStatsd(ctx context.Context, config *otypes.Statsd) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:           statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
-		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
-		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
-		openConnectionsGauge:           statsdClient.NewGauge(statsdOpenConnectionsName),
+		configReloadsCounter:            statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
+		lastConfigReloadSuccessGauge:    statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
+		tlsCertsNotAfterTimestampGauge:  statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		tlsHandshakesCounter:            statsdClient.NewCounter(statsdTLSHandshakesName, 1.0),
+		acmeCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdACMECertsNotAfterTimestampName),
+		acmeCertsRenewalFailuresCounter: statsdClient.NewCounter(statsdACMECertsRenewalFailuresName, 1.0),
+		openConnectionsGauge:            statsdClient.NewGauge(statsdOpenConnectionsName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -91,6 +103,10 @@ func RegisterStatsd(ctx context.Context, config *otypes.Statsd) Registry {
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
 		registry.serviceReqsBytesCounter = statsdClient.NewCounter(statsdServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = statsdClient.NewCounter(statsdServiceRespsBytesName, 1.0)
+		registry.serviceWebSocketOpenConnsGauge = statsdClient.NewGauge(statsdServiceWebSocketOpenConnsName)
+		registry.serviceWebSocketMessagesCounter = statsdClient.NewCounter(statsdServiceWebSocketMessagesName, 1.0)
+		registry.serviceWebSocketBytesCounter = statsdClient.NewCounter(statsdServiceWebSocketBytesName, 1.0)
+		registry.serviceWebSocketClosesCounter = statsdClient.NewCounter(statsdServiceWebSocketClosesName, 1.0)
 	}
 
 	return registry
@@ -0,0 +1,497 @@
+package acme
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/types"
+	traefikversion "github.com/traefik/traefik/v3/pkg/version"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	kclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	secretAccountKey      = "account"
+	secretCertificatesKey = "certificates"
+)
+
+// serviceAccountNamespaceFile is the file holding the namespace of the Traefik pod, when running inside a cluster.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// KubernetesStorage holds the configuration of a storage in Kubernetes Secrets.
+type KubernetesStorage struct {
+	Endpoint         string              `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token            types.FileOrContent `description:"Kubernetes bearer token (not needed for in-cluster client). It accepts either a token value or a file path to the token." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty" loggable:"false"`
+	CertAuthFilePath string              `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	Namespace        string              `description:"Namespace of the Secrets and Leases storing the ACME data (defaults to the namespace of the Traefik pod)." json:"namespace,omitempty" toml:"namespace,omitempty" yaml:"namespace,omitempty" export:"true"`
+	Prefix           string              `description:"Prefix of the names of the Secrets and Leases storing the ACME data." json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (k *KubernetesStorage) SetDefaults() {
+	k.Prefix = "traefik-acme"
+}
+
+// NewStore creates a KubernetesStore with a client of the configured cluster.
+func (k *KubernetesStorage) NewStore(ctx context.Context) (*KubernetesStore, error) {
+	logger := log.Ctx(ctx)
+
+	var (
+		config *rest.Config
+		err    error
+	)
+	switch {
+	case os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "":
+		logger.Debug().Msg("Creating in-cluster Kubernetes client for the ACME storage")
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create in-cluster configuration: %w", err)
+		}
+
+		if k.Endpoint != "" {
+			config.Host = k.Endpoint
+		}
+	case os.Getenv("KUBECONFIG") != "":
+		logger.Debug().Msgf("Creating cluster-external Kubernetes client for the ACME storage from KUBECONFIG %s", os.Getenv("KUBECONFIG"))
+		config, err = clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		logger.Debug().Msg("Creating cluster-external Kubernetes client for the ACME storage")
+		config, err = k.externalClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config.UserAgent = fmt.Sprintf(
+		"%s/%s (%s/%s) acme/storage",
+		filepath.Base(os.Args[0]),
+		traefikversion.Version,
+		runtime.GOOS,
+		runtime.GOARCH,
+	)
+
+	clientset, err := kclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := k.Namespace
+	if namespace == "" {
+		namespace = "default"
+		if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil && len(data) > 0 {
+			namespace = strings.TrimSpace(string(data))
+		}
+	}
+
+	return NewKubernetesStore(clientset, namespace, k.Prefix)
+}
+
+func (k *KubernetesStorage) externalClusterConfig() (*rest.Config, error) {
+	if k.Endpoint == "" {
+		return nil, errors.New("endpoint missing for external cluster client")
+	}
+
+	token, err := k.Token.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read token: %w", err)
+	}
+
+	config := &rest.Config{
+		Host:        k.Endpoint,
+		BearerToken: string(token),
+	}
+
+	if k.CertAuthFilePath != "" {
+		caData, err := os.ReadFile(k.CertAuthFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", k.CertAuthFilePath, err)
+		}
+
+		config.TLSClientConfig = rest.TLSClientConfig{CAData: caData}
+	}
+
+	return config, nil
+}
+
+var _ SharedStore = (*KubernetesStore)(nil)
+
+// KubernetesStore is a SharedStore implementation for Kubernetes.
+// The data of each resolver is stored in a Secret, and the locks are Leases.
+type KubernetesStore struct {
+	client    kclientset.Interface
+	namespace string
+	prefix    string
+
+	// identity identifies the Traefik instance as the holder of the Leases.
+	identity string
+}
+
+// NewKubernetesStore initializes a new KubernetesStore storing the data in the given namespace,
+// in Secrets whose names start with the given prefix.
+func NewKubernetesStore(client kclientset.Interface, namespace, prefix string) (*KubernetesStore, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("getting hostname: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("generating identity: %w", err)
+	}
+
+	return &KubernetesStore{
+		client:    client,
+		namespace: namespace,
+		prefix:    prefix,
+		identity:  hostname + "_" + hex.EncodeToString(suffix),
+	}, nil
+}
+
+// GetAccount returns ACME Account.
+func (s *KubernetesStore) GetAccount(resolverName string) (*Account, error) {
+	var account *Account
+	if err := s.get(resolverName, secretAccountKey, &account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// SaveAccount stores ACME Account.
+func (s *KubernetesStore) SaveAccount(resolverName string, account *Account) error {
+	return s.put(resolverName, secretAccountKey, account)
+}
+
+// GetCertificates returns ACME Certificates list.
+func (s *KubernetesStore) GetCertificates(resolverName string) ([]*CertAndStore, error) {
+	var certificates []*CertAndStore
+	if err := s.get(resolverName, secretCertificatesKey, &certificates); err != nil {
+		return nil, err
+	}
+
+	return withoutEmptyCertificates(certificates), nil
+}
+
+// SaveCertificates stores ACME Certificates list.
+func (s *KubernetesStore) SaveCertificates(resolverName string, certificates []*CertAndStore) error {
+	return s.put(resolverName, secretCertificatesKey, certificates)
+}
+
+// Lock blocks until the Lease with the given name is acquired, and returns the function releasing it.
+// The Lease is renewed until it is released, and expires if the Traefik instance holding it becomes unresponsive.
+func (s *KubernetesStore) Lock(ctx context.Context, name string) (func(), error) {
+	hash := sha256.Sum256([]byte(name))
+	leaseName := s.prefix + "-lock-" + hex.EncodeToString(hash[:8])
+
+	for {
+		acquired, err := s.tryLock(ctx, leaseName)
+		if err != nil {
+			return nil, fmt.Errorf("acquiring lock %q: %w", name, err)
+		}
+
+		if acquired {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("acquiring lock %q: %w", name, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+
+	renewCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.renewLease(renewCtx, leaseName)
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+
+		if err := s.releaseLease(leaseName); err != nil {
+			log.Error().Str(logs.ProviderName, "acme").Err(err).Msgf("Unable to release lock %q", name)
+		}
+	}, nil
+}
+
+// WatchCertificates sends the certificates of the given resolver each time they are updated.
+func (s *KubernetesStore) WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error) {
+	secretName := s.secretName(resolverName)
+
+	watcher, err := s.client.CoreV1().Secrets(s.namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", secretName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("watching secret %s/%s: %w", s.namespace, secretName, err)
+	}
+
+	certificatesChan := make(chan []*CertAndStore)
+	go func() {
+		defer close(certificatesChan)
+		defer watcher.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+
+				if event.Type != watch.Added && event.Type != watch.Modified {
+					continue
+				}
+
+				secret, ok := event.Object.(*corev1.Secret)
+				if !ok || secret.Name != secretName {
+					continue
+				}
+
+				var certificates []*CertAndStore
+				if data := secret.Data[secretCertificatesKey]; len(data) > 0 {
+					if err := json.Unmarshal(data, &certificates); err != nil {
+						log.Error().Str(logs.ProviderName, "acme").Err(err).Msgf("Unable to decode the certificates stored in the secret %s/%s", s.namespace, secretName)
+						continue
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case certificatesChan <- withoutEmptyCertificates(certificates):
+				}
+			}
+		}
+	}()
+
+	return certificatesChan, nil
+}
+
+// secretName returns the name of the Secret storing the data of the given resolver.
+func (s *KubernetesStore) secretName(resolverName string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(resolverName), "-")
+	return strings.Trim(s.prefix+"-"+name, "-")
+}
+
+func (s *KubernetesStore) get(resolverName, key string, value any) error {
+	secretName := s.secretName(resolverName)
+
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if kerror.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting secret %s/%s: %w", s.namespace, secretName, err)
+	}
+
+	data := secret.Data[key]
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("decoding %q from secret %s/%s: %w", key, s.namespace, secretName, err)
+	}
+
+	return nil
+}
+
+func (s *KubernetesStore) put(resolverName, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding %q: %w", key, err)
+	}
+
+	ctx := context.Background()
+	secretName := s.secretName(resolverName)
+	secrets := s.client.CoreV1().Secrets(s.namespace)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+		if kerror.IsNotFound(err) {
+			_, err = secrets.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: s.namespace,
+					Labels:    map[string]string{"app.kubernetes.io/managed-by": "traefik"},
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: map[string][]byte{key: data},
+			}, metav1.CreateOptions{})
+			if kerror.IsAlreadyExists(err) {
+				// Another instance created the secret in the meantime, the update is retried.
+				return kerror.NewConflict(corev1.Resource("secrets"), secretName, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[key] = data
+
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("saving %q in secret %s/%s: %w", key, s.namespace, secretName, err)
+	}
+
+	return nil
+}
+
+// tryLock tries to acquire the given Lease, which can be acquired if it does not exist, or has expired.
+func (s *KubernetesStore) tryLock(ctx context.Context, leaseName string) (bool, error) {
+	leases := s.client.CoordinationV1().Leases(s.namespace)
+	now := metav1.NewMicroTime(time.Now())
+
+	lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+	if kerror.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      leaseName,
+				Namespace: s.namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "traefik"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.identity),
+				LeaseDurationSeconds: ptr.To(int32(lockTTL.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		if kerror.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !leaseExpired(lease) {
+		return false, nil
+	}
+
+	lease.Spec.HolderIdentity = ptr.To(s.identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(lockTTL.Seconds()))
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	if kerror.IsConflict(err) {
+		// Another instance acquired the lease in the meantime.
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// renewLease renews the given Lease until the given context is done.
+func (s *KubernetesStore) renewLease(ctx context.Context, leaseName string) {
+	ticker := time.NewTicker(lockTTL / 3)
+	defer ticker.Stop()
+
+	leases := s.client.CoordinationV1().Leases(s.namespace)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+
+				if ptr.Deref(lease.Spec.HolderIdentity, "") != s.identity {
+					return errors.New("lease is held by another instance")
+				}
+
+				lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now()))
+
+				_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+				return err
+			})
+			if err != nil && ctx.Err() == nil {
+				log.Error().Str(logs.ProviderName, "acme").Err(err).Msgf("Unable to renew lease %s/%s", s.namespace, leaseName)
+			}
+		}
+	}
+}
+
+// releaseLease deletes the given Lease, if it is still held by this instance.
+func (s *KubernetesStore) releaseLease(leaseName string) error {
+	ctx := context.Background()
+	leases := s.client.CoordinationV1().Leases(s.namespace)
+
+	lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+	if kerror.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != s.identity {
+		return nil
+	}
+
+	err = leases.Delete(ctx, leaseName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: ptr.To(lease.ResourceVersion)},
+	})
+	if kerror.IsNotFound(err) || kerror.IsConflict(err) {
+		return nil
+	}
+
+	return err
+}
+
+func leaseExpired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+
+	return lease.Spec.RenewTime.Add(duration).Before(time.Now())
+}
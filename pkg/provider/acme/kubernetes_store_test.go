@@ -0,0 +1,206 @@
+package acme
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/types"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestKubernetesStore_account(t *testing.T) {
+	client := kubefake.NewClientset()
+
+	s, err := NewKubernetesStore(client, "traefik", "traefik-acme")
+	require.NoError(t, err)
+
+	account, err := s.GetAccount("myresolver")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	expected := &Account{Email: "foo@example.com", KeyType: "RSA4096"}
+	err = s.SaveAccount("myresolver", expected)
+	require.NoError(t, err)
+
+	account, err = s.GetAccount("myresolver")
+	require.NoError(t, err)
+	assert.Equal(t, expected, account)
+
+	secret, err := client.CoreV1().Secrets("traefik").Get(t.Context(), "traefik-acme-myresolver", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, secret.Data, "account")
+	assert.Equal(t, "traefik", secret.Labels["app.kubernetes.io/managed-by"])
+}
+
+func TestKubernetesStore_certificates(t *testing.T) {
+	client := kubefake.NewClientset()
+
+	s, err := NewKubernetesStore(client, "traefik", "traefik-acme")
+	require.NoError(t, err)
+
+	err = s.SaveAccount("My_Resolver", &Account{Email: "foo@example.com"})
+	require.NoError(t, err)
+
+	expected := []*CertAndStore{{
+		Certificate: Certificate{
+			Domain:      types.Domain{Main: "example.com"},
+			Certificate: []byte("cert"),
+			Key:         []byte("key"),
+		},
+		Store: "default",
+	}}
+
+	err = s.SaveCertificates("My_Resolver", expected)
+	require.NoError(t, err)
+
+	certificates, err := s.GetCertificates("My_Resolver")
+	require.NoError(t, err)
+	assert.Equal(t, expected, certificates)
+
+	// Saving the certificates keeps the account.
+	secret, err := client.CoreV1().Secrets("traefik").Get(t.Context(), "traefik-acme-my-resolver", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, secret.Data, "account")
+	assert.Contains(t, secret.Data, "certificates")
+}
+
+func TestKubernetesStore_Lock(t *testing.T) {
+	client := kubefake.NewClientset()
+
+	s1, err := NewKubernetesStore(client, "traefik", "traefik-acme")
+	require.NoError(t, err)
+
+	s2, err := NewKubernetesStore(client, "traefik", "traefik-acme")
+	require.NoError(t, err)
+
+	unlock, err := s1.Lock(t.Context(), "myresolver/domains/example.com")
+	require.NoError(t, err)
+
+	leases, err := client.CoordinationV1().Leases("traefik").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, leases.Items, 1)
+	assert.Equal(t, s1.identity, ptr.Deref(leases.Items[0].Spec.HolderIdentity, ""))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = s2.Lock(ctx, "myresolver/domains/example.com")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+
+	leases, err = client.CoordinationV1().Leases("traefik").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, leases.Items)
+
+	unlock, err = s2.Lock(t.Context(), "myresolver/domains/example.com")
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestKubernetesStore_Lock_expired(t *testing.T) {
+	client := kubefake.NewClientset()
+
+	s, err := NewKubernetesStore(client, "traefik", "traefik-acme")
+	require.NoError(t, err)
+
+	unlock, err := s.Lock(t.Context(), "myresolver/domains/example.com")
+	require.NoError(t, err)
+
+	leases, err := client.CoordinationV1().Leases("traefik").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, leases.Items, 1)
+
+	// Simulates an instance which stopped renewing the lease.
+	lease := leases.Items[0].DeepCopy()
+	lease.Spec.HolderIdentity = ptr.To("crashed")
+	lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now().Add(-time.Hour)))
+	_, err = client.CoordinationV1().Leases("traefik").Update(t.Context(), lease, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	unlock()
+
+	// The lease held by another instance is not released.
+	lease, err = client.CoordinationV1().Leases("traefik").Get(t.Context(), lease.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "crashed", ptr.Deref(lease.Spec.HolderIdentity, ""))
+
+	unlock, err = s.Lock(t.Context(), "myresolver/domains/example.com")
+	require.NoError(t, err)
+	defer unlock()
+
+	lease, err = client.CoordinationV1().Leases("traefik").Get(t.Context(), lease.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, s.identity, ptr.Deref(lease.Spec.HolderIdentity, ""))
+}
+
+func TestKubernetesStore_WatchCertificates(t *testing.T) {
+	client := kubefake.NewClientset()
+
+	s, err := NewKubernetesStore(client, "traefik", "traefik-acme")
+	require.NoError(t, err)
+
+	certificatesChan, err := s.WatchCertificates(t.Context(), "myresolver")
+	require.NoError(t, err)
+
+	// The data of other resolvers is ignored.
+	err = s.SaveCertificates("other", []*CertAndStore{{Certificate: Certificate{Certificate: []byte("cert"), Key: []byte("key")}}})
+	require.NoError(t, err)
+
+	expected := []*CertAndStore{{
+		Certificate: Certificate{
+			Domain:      types.Domain{Main: "example.com"},
+			Certificate: []byte("cert"),
+			Key:         []byte("key"),
+		},
+		Store: "default",
+	}}
+
+	err = s.SaveCertificates("myresolver", expected)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, receiveCertificates(t, certificatesChan))
+}
+
+func TestLeaseExpired(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		spec     coordinationv1.LeaseSpec
+		expected bool
+	}{
+		{
+			desc:     "no renew time",
+			spec:     coordinationv1.LeaseSpec{LeaseDurationSeconds: ptr.To(int32(30))},
+			expected: true,
+		},
+		{
+			desc: "renewed recently",
+			spec: coordinationv1.LeaseSpec{
+				LeaseDurationSeconds: ptr.To(int32(30)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(time.Now().Add(-10 * time.Second))),
+			},
+		},
+		{
+			desc: "not renewed",
+			spec: coordinationv1.LeaseSpec{
+				LeaseDurationSeconds: ptr.To(int32(30)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(time.Now().Add(-time.Minute))),
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, leaseExpired(&coordinationv1.Lease{Spec: test.spec}))
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package acme
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+)
+
+var _ SharedStore = (*KVStore)(nil)
+
+// KVStore is a SharedStore implementation for KV stores.
+// The data of each resolver is stored as JSON under the "<rootKey>/acme/<resolverName>" key.
+type KVStore struct {
+	client  store.Store
+	rootKey string
+}
+
+// NewKVStore initializes a new KVStore storing the data under the given root key.
+func NewKVStore(client store.Store, rootKey string) *KVStore {
+	return &KVStore{client: client, rootKey: rootKey}
+}
+
+// GetAccount returns ACME Account.
+func (s *KVStore) GetAccount(resolverName string) (*Account, error) {
+	var account *Account
+	if err := s.get(s.key(resolverName, "account"), &account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// SaveAccount stores ACME Account.
+func (s *KVStore) SaveAccount(resolverName string, account *Account) error {
+	return s.put(s.key(resolverName, "account"), account)
+}
+
+// GetCertificates returns ACME Certificates list.
+func (s *KVStore) GetCertificates(resolverName string) ([]*CertAndStore, error) {
+	var certificates []*CertAndStore
+	if err := s.get(s.key(resolverName, "certificates"), &certificates); err != nil {
+		return nil, err
+	}
+
+	return withoutEmptyCertificates(certificates), nil
+}
+
+// SaveCertificates stores ACME Certificates list.
+func (s *KVStore) SaveCertificates(resolverName string, certificates []*CertAndStore) error {
+	return s.put(s.key(resolverName, "certificates"), certificates)
+}
+
+// Lock blocks until the lock with the given name is acquired, and returns the function releasing it.
+// The lock is kept alive by the KV store client until it is released,
+// and expires if the Traefik instance holding it becomes unresponsive.
+func (s *KVStore) Lock(ctx context.Context, name string) (func(), error) {
+	hash := sha256.Sum256([]byte(name))
+
+	stopRenew := make(chan struct{})
+	locker, err := s.client.NewLock(ctx, path.Join(s.rootKey, "acme", "locks", hex.EncodeToString(hash[:])), &store.LockOptions{
+		TTL:       lockTTL,
+		RenewLock: stopRenew,
+	})
+	if err != nil {
+		close(stopRenew)
+		return nil, fmt.Errorf("creating lock %q: %w", name, err)
+	}
+
+	if _, err = locker.Lock(ctx); err != nil {
+		close(stopRenew)
+		return nil, fmt.Errorf("acquiring lock %q: %w", name, err)
+	}
+
+	return func() {
+		if err := locker.Unlock(context.Background()); err != nil {
+			log.Error().Str(logs.ProviderName, "acme").Err(err).Msgf("Unable to release lock %q", name)
+		}
+		close(stopRenew)
+	}, nil
+}
+
+// WatchCertificates sends the certificates of the given resolver each time they are updated.
+func (s *KVStore) WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error) {
+	key := s.key(resolverName, "certificates")
+
+	// Some KV stores cannot watch a key which does not exist yet.
+	_, _, err := s.client.AtomicPut(ctx, key, []byte("null"), nil, nil)
+	if err != nil && !errors.Is(err, store.ErrKeyExists) && !errors.Is(err, store.ErrKeyModified) {
+		return nil, fmt.Errorf("initializing key %q: %w", key, err)
+	}
+
+	pairs, err := s.client.Watch(ctx, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("watching key %q: %w", key, err)
+	}
+
+	certificatesChan := make(chan []*CertAndStore)
+	go func() {
+		defer close(certificatesChan)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case pair, ok := <-pairs:
+				if !ok {
+					return
+				}
+				if pair == nil {
+					continue
+				}
+
+				var certificates []*CertAndStore
+				if err := json.Unmarshal(pair.Value, &certificates); err != nil {
+					log.Error().Str(logs.ProviderName, "acme").Err(err).Msgf("Unable to decode the certificates stored under %q", key)
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case certificatesChan <- withoutEmptyCertificates(certificates):
+				}
+			}
+		}
+	}()
+
+	return certificatesChan, nil
+}
+
+func (s *KVStore) key(resolverName, name string) string {
+	return path.Join(s.rootKey, "acme", resolverName, name)
+}
+
+func (s *KVStore) get(key string, value any) error {
+	pair, err := s.client.Get(context.Background(), key, nil)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting key %q: %w", key, err)
+	}
+
+	if len(pair.Value) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(pair.Value, value); err != nil {
+		return fmt.Errorf("decoding key %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *KVStore) put(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding key %q: %w", key, err)
+	}
+
+	if err := s.client.Put(context.Background(), key, data, nil); err != nil {
+		return fmt.Errorf("putting key %q: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestKVStore_account(t *testing.T) {
+	s := NewKVStore(newMemoryKVStore(), "traefik")
+
+	account, err := s.GetAccount("myresolver")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	expected := &Account{Email: "foo@example.com", KeyType: "RSA4096"}
+	err = s.SaveAccount("myresolver", expected)
+	require.NoError(t, err)
+
+	account, err = s.GetAccount("myresolver")
+	require.NoError(t, err)
+	assert.Equal(t, expected, account)
+
+	account, err = s.GetAccount("other")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+}
+
+func TestKVStore_certificates(t *testing.T) {
+	client := newMemoryKVStore()
+	s := NewKVStore(client, "traefik")
+
+	certificates, err := s.GetCertificates("myresolver")
+	require.NoError(t, err)
+	assert.Empty(t, certificates)
+
+	expected := []*CertAndStore{{
+		Certificate: Certificate{
+			Domain:      types.Domain{Main: "example.com"},
+			Certificate: []byte("cert"),
+			Key:         []byte("key"),
+		},
+		Store: "default",
+	}}
+
+	err = s.SaveCertificates("myresolver", append(expected, &CertAndStore{Certificate: Certificate{Domain: types.Domain{Main: "empty.com"}}}))
+	require.NoError(t, err)
+
+	_, err = client.Get(t.Context(), "traefik/acme/myresolver/certificates", nil)
+	require.NoError(t, err)
+
+	certificates, err = s.GetCertificates("myresolver")
+	require.NoError(t, err)
+	assert.Equal(t, expected, certificates)
+}
+
+func TestKVStore_Lock(t *testing.T) {
+	s := NewKVStore(newMemoryKVStore(), "traefik")
+
+	unlock, err := s.Lock(t.Context(), "myresolver/domains/example.com")
+	require.NoError(t, err)
+
+	// Another lock can be acquired.
+	unlockOther, err := s.Lock(t.Context(), "myresolver/domains/example.org")
+	require.NoError(t, err)
+	unlockOther()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = s.Lock(ctx, "myresolver/domains/example.com")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+
+	unlock, err = s.Lock(t.Context(), "myresolver/domains/example.com")
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestKVStore_WatchCertificates(t *testing.T) {
+	s := NewKVStore(newMemoryKVStore(), "traefik")
+
+	certificatesChan, err := s.WatchCertificates(t.Context(), "myresolver")
+	require.NoError(t, err)
+
+	// The initial value of the key is sent first.
+	assert.Empty(t, receiveCertificates(t, certificatesChan))
+
+	expected := []*CertAndStore{{
+		Certificate: Certificate{
+			Domain:      types.Domain{Main: "example.com"},
+			Certificate: []byte("cert"),
+			Key:         []byte("key"),
+		},
+		Store: "default",
+	}}
+
+	err = s.SaveCertificates("myresolver", expected)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, receiveCertificates(t, certificatesChan))
+}
+
+func receiveCertificates(t *testing.T, certificatesChan <-chan []*CertAndStore) []*CertAndStore {
+	t.Helper()
+
+	select {
+	case certificates := <-certificatesChan:
+		return certificates
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the certificates")
+		return nil
+	}
+}
+
+// memoryKVStore is an in-memory store.Store, supporting the operations used by the KVStore.
+type memoryKVStore struct {
+	mu       sync.Mutex
+	pairs    map[string]*store.KVPair
+	watchers map[string][]chan *store.KVPair
+	locks    map[string]chan struct{}
+}
+
+func newMemoryKVStore() *memoryKVStore {
+	return &memoryKVStore{
+		pairs:    map[string]*store.KVPair{},
+		watchers: map[string][]chan *store.KVPair{},
+		locks:    map[string]chan struct{}{},
+	}
+}
+
+func (m *memoryKVStore) Put(_ context.Context, key string, value []byte, _ *store.WriteOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.put(key, value)
+
+	return nil
+}
+
+func (m *memoryKVStore) Get(_ context.Context, key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pair, ok := m.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return pair, nil
+}
+
+func (m *memoryKVStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.pairs, key)
+
+	return nil
+}
+
+func (m *memoryKVStore) Exists(_ context.Context, key string, _ *store.ReadOptions) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.pairs[key]
+
+	return ok, nil
+}
+
+func (m *memoryKVStore) Watch(ctx context.Context, key string, _ *store.ReadOptions) (<-chan *store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pair, ok := m.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	pairs := make(chan *store.KVPair, 10)
+	pairs <- pair
+	m.watchers[key] = append(m.watchers[key], pairs)
+
+	return pairs, nil
+}
+
+func (m *memoryKVStore) WatchTree(context.Context, string, *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	return nil, errors.New("method WatchTree not supported")
+}
+
+func (m *memoryKVStore) NewLock(_ context.Context, key string, _ *store.LockOptions) (store.Locker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.locks[key]; !ok {
+		m.locks[key] = make(chan struct{}, 1)
+	}
+
+	return &memoryLocker{lock: m.locks[key]}, nil
+}
+
+func (m *memoryKVStore) List(context.Context, string, *store.ReadOptions) ([]*store.KVPair, error) {
+	return nil, errors.New("method List not supported")
+}
+
+func (m *memoryKVStore) DeleteTree(context.Context, string) error {
+	return errors.New("method DeleteTree not supported")
+}
+
+func (m *memoryKVStore) AtomicPut(_ context.Context, key string, value []byte, previous *store.KVPair, _ *store.WriteOptions) (bool, *store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.pairs[key]
+	if previous == nil && ok {
+		return false, nil, store.ErrKeyExists
+	}
+	if previous != nil && (!ok || current.LastIndex != previous.LastIndex) {
+		return false, nil, store.ErrKeyModified
+	}
+
+	return true, m.put(key, value), nil
+}
+
+func (m *memoryKVStore) AtomicDelete(context.Context, string, *store.KVPair) (bool, error) {
+	return false, errors.New("method AtomicDelete not supported")
+}
+
+func (m *memoryKVStore) Close() error {
+	return nil
+}
+
+func (m *memoryKVStore) put(key string, value []byte) *store.KVPair {
+	var index uint64
+	if current, ok := m.pairs[key]; ok {
+		index = current.LastIndex + 1
+	}
+
+	pair := &store.KVPair{Key: key, Value: value, LastIndex: index}
+	m.pairs[key] = pair
+
+	for _, watcher := range m.watchers[key] {
+		watcher <- pair
+	}
+
+	return pair
+}
+
+type memoryLocker struct {
+	lock chan struct{}
+}
+
+func (l *memoryLocker) Lock(ctx context.Context) (<-chan struct{}, error) {
+	select {
+	case l.lock <- struct{}{}:
+		return make(chan struct{}), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *memoryLocker) Unlock(context.Context) error {
+	<-l.lock
+	return nil
+}
@@ -0,0 +1,251 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/types"
+	"golang.org/x/time/rate"
+)
+
+// onDemandAskTimeout is the timeout of the requests to the ask endpoint.
+const onDemandAskTimeout = 10 * time.Second
+
+// OnDemand holds the on-demand certificate issuance configuration.
+type OnDemand struct {
+	Ask                    string          `description:"URL of the endpoint authorizing the issuance of a certificate, called with the domain as query parameter. A 200 status code allows the issuance." json:"ask,omitempty" toml:"ask,omitempty" yaml:"ask,omitempty"`
+	AllowedDomains         []string        `description:"Domains for which a certificate can be issued without calling the ask endpoint. Wildcards are allowed (e.g. *.example.com)." json:"allowedDomains,omitempty" toml:"allowedDomains,omitempty" yaml:"allowedDomains,omitempty"`
+	RateLimitPeriod        ptypes.Duration `description:"Minimum duration between two issuance attempts for the same domain." json:"rateLimitPeriod,omitempty" toml:"rateLimitPeriod,omitempty" yaml:"rateLimitPeriod,omitempty" export:"true"`
+	RefusedCacheDuration   ptypes.Duration `description:"Duration during which a domain refused by the ask endpoint is not checked again." json:"refusedCacheDuration,omitempty" toml:"refusedCacheDuration,omitempty" yaml:"refusedCacheDuration,omitempty" export:"true"`
+	MaxConcurrentIssuances int             `description:"Maximum number of on-demand certificate issuances in progress at the same time." json:"maxConcurrentIssuances,omitempty" toml:"maxConcurrentIssuances,omitempty" yaml:"maxConcurrentIssuances,omitempty" export:"true"`
+	MaxIssuances           int             `description:"Maximum number of on-demand certificate issuances started during the issuance period, for all the domains." json:"maxIssuances,omitempty" toml:"maxIssuances,omitempty" yaml:"maxIssuances,omitempty" export:"true"`
+	IssuancePeriod         ptypes.Duration `description:"Period during which at most maxIssuances on-demand certificate issuances are started." json:"issuancePeriod,omitempty" toml:"issuancePeriod,omitempty" yaml:"issuancePeriod,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *OnDemand) SetDefaults() {
+	o.RateLimitPeriod = ptypes.Duration(10 * time.Minute)
+	o.RefusedCacheDuration = ptypes.Duration(time.Hour)
+	o.MaxConcurrentIssuances = 10
+	o.MaxIssuances = 50
+	o.IssuancePeriod = ptypes.Duration(time.Hour)
+}
+
+// onDemand holds the runtime state of the on-demand certificate issuance.
+type onDemand struct {
+	config *OnDemand
+	client *http.Client
+
+	// ready is set once the provider is able to publish the issued certificates.
+	ready atomic.Bool
+
+	// attempts holds the domains for which an issuance has been attempted during the rate limit period.
+	attempts *cache.Cache
+	// refused holds the domains recently refused by the ask endpoint.
+	refused *cache.Cache
+
+	// issuances holds a token for each issuance in progress.
+	issuances chan struct{}
+	// limiter limits the rate of the issuances, for all the domains.
+	limiter *rate.Limiter
+}
+
+func newOnDemand(config *OnDemand) (*onDemand, error) {
+	if config.Ask == "" && len(config.AllowedDomains) == 0 {
+		return nil, errors.New("an ask endpoint or allowed domains must be defined")
+	}
+
+	if config.Ask != "" {
+		askURL, err := url.Parse(config.Ask)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ask endpoint URL: %w", err)
+		}
+
+		if askURL.Scheme != "http" && askURL.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported ask endpoint URL scheme %q", askURL.Scheme)
+		}
+	}
+
+	if config.MaxConcurrentIssuances <= 0 {
+		return nil, errors.New("the maximum number of concurrent issuances must be positive")
+	}
+
+	if config.MaxIssuances <= 0 || config.IssuancePeriod <= 0 {
+		return nil, errors.New("the maximum number of issuances and the issuance period must be positive")
+	}
+
+	return &onDemand{
+		config:    config,
+		client:    &http.Client{Timeout: onDemandAskTimeout},
+		attempts:  cache.New(time.Duration(config.RateLimitPeriod), time.Minute),
+		refused:   cache.New(time.Duration(config.RefusedCacheDuration), time.Minute),
+		issuances: make(chan struct{}, config.MaxConcurrentIssuances),
+		limiter:   rate.NewLimiter(rate.Every(time.Duration(config.IssuancePeriod)/time.Duration(config.MaxIssuances)), config.MaxIssuances),
+	}, nil
+}
+
+// handles returns whether a certificate may be issued for the given domain,
+// i.e. whether it is not known to be refused.
+func (o *onDemand) handles(domain string) bool {
+	if _, refused := o.refused.Get(domain); refused {
+		return false
+	}
+
+	if o.config.Ask != "" {
+		return true
+	}
+
+	for _, allowedDomain := range o.config.AllowedDomains {
+		if types.MatchDomain(domain, allowedDomain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reserve returns whether an issuance can be attempted for the given domain,
+// and if so, prevents other attempts during the rate limit period.
+// A successful reservation must be released once the issuance is over.
+func (o *onDemand) reserve(domain string) bool {
+	if _, refused := o.refused.Get(domain); refused {
+		return false
+	}
+
+	// Add fails when an attempt has already been made during the rate limit period.
+	if o.attempts.Add(domain, struct{}{}, cache.DefaultExpiration) != nil {
+		return false
+	}
+
+	select {
+	case o.issuances <- struct{}{}:
+	default:
+		// The domain is attempted again with the next handshakes.
+		o.attempts.Delete(domain)
+		return false
+	}
+
+	if !o.limiter.Allow() {
+		<-o.issuances
+		o.attempts.Delete(domain)
+		return false
+	}
+
+	return true
+}
+
+// release releases a reservation.
+func (o *onDemand) release() {
+	<-o.issuances
+}
+
+// allowed returns whether a certificate can be issued for the given domain,
+// and caches the refusals of the ask endpoint.
+func (o *onDemand) allowed(ctx context.Context, domain string) (bool, error) {
+	for _, allowedDomain := range o.config.AllowedDomains {
+		if types.MatchDomain(domain, allowedDomain) {
+			return true, nil
+		}
+	}
+
+	if o.config.Ask == "" {
+		o.refused.SetDefault(domain, struct{}{})
+		return false, nil
+	}
+
+	askURL, err := url.Parse(o.config.Ask)
+	if err != nil {
+		return false, fmt.Errorf("parsing ask endpoint URL: %w", err)
+	}
+
+	query := askURL.Query()
+	query.Set("domain", domain)
+	askURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, askURL.String(), http.NoBody)
+	if err != nil {
+		return false, fmt.Errorf("creating ask request: %w", err)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("calling ask endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode >= http.StatusInternalServerError:
+		// The endpoint failure does not mean that the domain is refused.
+		return false, fmt.Errorf("ask endpoint responded with status code %d", resp.StatusCode)
+	default:
+		o.refused.SetDefault(domain, struct{}{})
+		return false, nil
+	}
+}
+
+// IssueOnDemand starts the issuance of a certificate for the given domain in the given store,
+// if it is allowed by the on-demand configuration.
+// It returns whether the domain is handled by the provider, even if the issuance is rate limited.
+func (p *Provider) IssueOnDemand(storeName, domain string) bool {
+	if p.onDemand == nil || !p.onDemand.ready.Load() {
+		return false
+	}
+
+	logger := log.With().Str(logs.ProviderName, p.ResolverName+resolverSuffix).Str("domain", domain).Logger()
+
+	if !p.onDemand.handles(domain) {
+		logger.Debug().Msg("On-demand certificate issuance refused")
+		return false
+	}
+
+	if !p.onDemand.reserve(domain) {
+		logger.Debug().Msg("On-demand certificate issuance rate limited")
+		return true
+	}
+
+	ctx := logger.WithContext(context.Background())
+
+	safe.Go(func() {
+		defer p.onDemand.release()
+
+		allowed, err := p.onDemand.allowed(ctx, domain)
+		if err != nil {
+			logger.Error().Err(err).Msg("Unable to check whether an on-demand certificate can be issued")
+			return
+		}
+
+		if !allowed {
+			logger.Debug().Msg("On-demand certificate issuance not allowed")
+			return
+		}
+
+		logger.Debug().Msg("Issuing on-demand certificate")
+
+		dom, cert, err := p.resolveCertificate(ctx, types.Domain{Main: domain}, storeName)
+		if err != nil {
+			logger.Error().Err(err).Msg("Unable to obtain on-demand ACME certificate")
+			return
+		}
+
+		if err := p.addCertificateForDomain(dom, cert, storeName); err != nil {
+			logger.Error().Err(err).Msg("Error adding on-demand certificate")
+		}
+	})
+
+	return true
+}
@@ -0,0 +1,255 @@
+package acme
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+func TestNewOnDemand(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      OnDemand
+		expectedErr bool
+	}{
+		{
+			desc:        "no ask endpoint nor allowed domains",
+			expectedErr: true,
+		},
+		{
+			desc:   "allowed domains",
+			config: OnDemand{AllowedDomains: []string{"*.example.com"}, MaxConcurrentIssuances: 1, MaxIssuances: 1, IssuancePeriod: ptypes.Duration(time.Hour)},
+		},
+		{
+			desc:   "ask endpoint",
+			config: OnDemand{Ask: "http://127.0.0.1:8080/ask", MaxConcurrentIssuances: 1, MaxIssuances: 1, IssuancePeriod: ptypes.Duration(time.Hour)},
+		},
+		{
+			desc:        "invalid ask endpoint scheme",
+			config:      OnDemand{Ask: "ftp://127.0.0.1/ask"},
+			expectedErr: true,
+		},
+		{
+			desc:        "no concurrent issuance",
+			config:      OnDemand{Ask: "http://127.0.0.1:8080/ask", MaxIssuances: 1, IssuancePeriod: ptypes.Duration(time.Hour)},
+			expectedErr: true,
+		},
+		{
+			desc:        "no issuance period",
+			config:      OnDemand{Ask: "http://127.0.0.1:8080/ask", MaxConcurrentIssuances: 1, MaxIssuances: 1},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newOnDemand(&test.config)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOnDemand_allowed(t *testing.T) {
+	var askCalls atomic.Int32
+	askServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		askCalls.Add(1)
+
+		switch req.URL.Query().Get("domain") {
+		case "allowed.com":
+			rw.WriteHeader(http.StatusOK)
+		case "error.com":
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			rw.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(askServer.Close)
+
+	testCases := []struct {
+		desc             string
+		config           OnDemand
+		domain           string
+		expected         bool
+		expectedErr      bool
+		expectedRefused  bool
+		expectedAskCalls int32
+	}{
+		{
+			desc:     "allowed domain",
+			config:   OnDemand{AllowedDomains: []string{"*.example.com"}, Ask: askServer.URL},
+			domain:   "foo.example.com",
+			expected: true,
+		},
+		{
+			desc:            "not allowed domain without ask endpoint",
+			config:          OnDemand{AllowedDomains: []string{"*.example.com"}},
+			domain:          "example.org",
+			expectedRefused: true,
+		},
+		{
+			desc:             "allowed by the ask endpoint",
+			config:           OnDemand{Ask: askServer.URL},
+			domain:           "allowed.com",
+			expected:         true,
+			expectedAskCalls: 1,
+		},
+		{
+			desc:             "refused by the ask endpoint",
+			config:           OnDemand{Ask: askServer.URL},
+			domain:           "refused.com",
+			expectedRefused:  true,
+			expectedAskCalls: 1,
+		},
+		{
+			desc:             "ask endpoint error",
+			config:           OnDemand{Ask: askServer.URL},
+			domain:           "error.com",
+			expectedErr:      true,
+			expectedAskCalls: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			askCalls.Store(0)
+
+			test.config.SetDefaults()
+			o, err := newOnDemand(&test.config)
+			require.NoError(t, err)
+
+			allowed, err := o.allowed(t.Context(), test.domain)
+			if test.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, allowed)
+			assert.Equal(t, test.expectedAskCalls, askCalls.Load())
+
+			_, refused := o.refused.Get(test.domain)
+			assert.Equal(t, test.expectedRefused, refused)
+		})
+	}
+}
+
+func TestOnDemand_reserve(t *testing.T) {
+	o, err := newOnDemand(&OnDemand{
+		AllowedDomains:         []string{"example.com"},
+		RateLimitPeriod:        ptypes.Duration(100 * time.Millisecond),
+		RefusedCacheDuration:   ptypes.Duration(time.Hour),
+		MaxConcurrentIssuances: 10,
+		MaxIssuances:           10,
+		IssuancePeriod:         ptypes.Duration(time.Hour),
+	})
+	require.NoError(t, err)
+
+	assert.True(t, o.reserve("example.com"))
+	assert.True(t, o.reserve("foo.example.com"))
+
+	// Rate limited.
+	assert.False(t, o.reserve("example.com"))
+
+	time.Sleep(150 * time.Millisecond)
+
+	assert.True(t, o.reserve("example.com"))
+
+	// Refused domains are not attempted again.
+	allowed, err := o.allowed(t.Context(), "example.org")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	time.Sleep(150 * time.Millisecond)
+
+	assert.False(t, o.reserve("example.org"))
+}
+
+func TestOnDemand_reserve_limits(t *testing.T) {
+	o, err := newOnDemand(&OnDemand{
+		Ask:                    "http://127.0.0.1:8080/ask",
+		RateLimitPeriod:        ptypes.Duration(time.Hour),
+		RefusedCacheDuration:   ptypes.Duration(time.Hour),
+		MaxConcurrentIssuances: 1,
+		MaxIssuances:           2,
+		IssuancePeriod:         ptypes.Duration(time.Hour),
+	})
+	require.NoError(t, err)
+
+	assert.True(t, o.reserve("a.example.com"))
+
+	// Too many issuances in progress.
+	assert.False(t, o.reserve("b.example.com"))
+
+	o.release()
+
+	// The domain was not attempted, it can be reserved again.
+	assert.True(t, o.reserve("b.example.com"))
+
+	o.release()
+
+	// Too many issuances during the issuance period.
+	assert.False(t, o.reserve("c.example.com"))
+}
+
+func TestOnDemand_handles(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   OnDemand
+		domain   string
+		refused  bool
+		expected bool
+	}{
+		{
+			desc:     "allowed domain",
+			config:   OnDemand{AllowedDomains: []string{"*.example.com"}},
+			domain:   "foo.example.com",
+			expected: true,
+		},
+		{
+			desc:   "not allowed domain without ask endpoint",
+			config: OnDemand{AllowedDomains: []string{"*.example.com"}},
+			domain: "example.org",
+		},
+		{
+			desc:     "ask endpoint",
+			config:   OnDemand{Ask: "http://127.0.0.1:8080/ask"},
+			domain:   "example.org",
+			expected: true,
+		},
+		{
+			desc:    "refused domain",
+			config:  OnDemand{Ask: "http://127.0.0.1:8080/ask"},
+			domain:  "example.org",
+			refused: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.config.SetDefaults()
+			o, err := newOnDemand(&test.config)
+			require.NoError(t, err)
+
+			if test.refused {
+				o.refused.SetDefault(test.domain, struct{}{})
+			}
+
+			assert.Equal(t, test.expected, o.handles(test.domain))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package acme
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -16,8 +17,11 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -29,9 +33,11 @@ import (
 	"github.com/rs/zerolog/log"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/job"
 	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/observability/metrics"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -52,6 +58,10 @@ type Configuration struct {
 	KeyType              string   `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
 	EAB                  *EAB     `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
 	CertificatesDuration int      `description:"Certificates' duration in hours." json:"certificatesDuration,omitempty" toml:"certificatesDuration,omitempty" yaml:"certificatesDuration,omitempty" export:"true"`
+	DisableARI           bool     `description:"Disables the ACME Renewal Information (ARI) extension, used to renew the certificates during the window suggested by the CA." json:"disableARI,omitempty" toml:"disableARI,omitempty" yaml:"disableARI,omitempty" export:"true"`
+
+	SharedStorage *SharedStorage `description:"Storage shared by several Traefik instances, used instead of the storage file." json:"sharedStorage,omitempty" toml:"sharedStorage,omitempty" yaml:"sharedStorage,omitempty" export:"true"`
+	OnDemand      *OnDemand      `description:"Enables the issuance of certificates during the TLS handshakes, for the domains without certificate." json:"onDemand,omitempty" toml:"onDemand,omitempty" yaml:"onDemand,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	ClientTimeout               ptypes.Duration `description:"Timeout for a complete HTTP transaction with the ACME server." json:"clientTimeout,omitempty" toml:"clientTimeout,omitempty" yaml:"clientTimeout,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	ClientResponseHeaderTimeout ptypes.Duration `description:"Timeout for receiving the response headers when communicating with the ACME server." json:"clientResponseHeaderTimeout,omitempty" toml:"clientResponseHeaderTimeout,omitempty" yaml:"clientResponseHeaderTimeout,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -133,6 +143,10 @@ type Provider struct {
 
 	ResolverName string
 	Store        Store `json:"store,omitempty" toml:"store,omitempty" yaml:"store,omitempty"`
+	// sharedStore is set when the Store is shared with other Traefik instances.
+	sharedStore SharedStore
+	// onDemand is set when the on-demand certificate issuance is enabled.
+	onDemand *onDemand
 
 	TLSChallengeProvider  challenge.Provider
 	HTTPChallengeProvider challenge.Provider
@@ -149,6 +163,12 @@ type Provider struct {
 	pool                   *safe.Pool
 	resolvingDomains       map[string]struct{}
 	resolvingDomainsMutex  sync.RWMutex
+	metricsRegistry        metrics.Registry
+
+	renewals   map[string]*renewal
+	renewalsMu sync.Mutex
+	// ariUnsupported is set when the CA does not support the ACME Renewal Information extension.
+	ariUnsupported atomic.Bool
 }
 
 // SetTLSManager sets the tls manager to use.
@@ -156,6 +176,11 @@ func (p *Provider) SetTLSManager(tlsManager *traefiktls.Manager) {
 	p.tlsManager = tlsManager
 }
 
+// SetMetricsRegistry sets the metrics registry to use.
+func (p *Provider) SetMetricsRegistry(metricsRegistry metrics.Registry) {
+	p.metricsRegistry = metricsRegistry
+}
+
 // SetConfigListenerChan initializes the configFromListenerChan.
 func (p *Provider) SetConfigListenerChan(configFromListenerChan chan dynamic.Configuration) {
 	p.configFromListenerChan = configFromListenerChan
@@ -182,6 +207,18 @@ func (p *Provider) Init() error {
 		return errors.New("clientTimeout must be at least clientResponseHeaderTimeout")
 	}
 
+	if p.metricsRegistry == nil {
+		p.metricsRegistry = metrics.NewVoidRegistry()
+	}
+
+	if p.OnDemand != nil {
+		var err error
+		p.onDemand, err = newOnDemand(p.OnDemand)
+		if err != nil {
+			return fmt.Errorf("unable to initialize on-demand certificates: %w", err)
+		}
+	}
+
 	var err error
 	p.account, err = p.Store.GetAccount(p.ResolverName)
 	if err != nil {
@@ -205,6 +242,8 @@ func (p *Provider) Init() error {
 	// Init the currently resolved domain map
 	p.resolvingDomains = make(map[string]struct{})
 
+	p.sharedStore, _ = p.Store.(SharedStore)
+
 	return nil
 }
 
@@ -250,18 +289,27 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 	p.configurationChan <- msg
 
+	if p.onDemand != nil {
+		p.onDemand.ready.Store(true)
+	}
+
+	if p.sharedStore != nil {
+		p.watchSharedCertificates(ctx)
+	}
+
 	renewPeriod, renewInterval := getCertificateRenewDurations(p.CertificatesDuration)
+	checkInterval := p.getRenewalCheckInterval(renewInterval)
 	logger.Debug().Msgf("Attempt to renew certificates %q before expiry and check every %q",
-		renewPeriod, renewInterval)
+		renewPeriod, checkInterval)
 
-	p.renewCertificates(ctx, renewPeriod)
+	p.renewCertificates(ctx, renewPeriod, renewInterval)
 
-	ticker := time.NewTicker(renewInterval)
+	ticker := time.NewTicker(checkInterval)
 	pool.GoCtx(func(ctxPool context.Context) {
 		for {
 			select {
 			case <-ticker.C:
-				p.renewCertificates(ctx, renewPeriod)
+				p.renewCertificates(ctx, renewPeriod, renewInterval)
 			case <-ctxPool.Done():
 				ticker.Stop()
 				return
@@ -284,6 +332,24 @@ func (p *Provider) getClient() (*lego.Client, error) {
 		return p.client, nil
 	}
 
+	if p.sharedStore != nil {
+		unlock, err := p.lock(ctx, "account")
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
+		// The account may have been registered by another instance in the meantime.
+		account, err := p.Store.GetAccount(p.ResolverName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get ACME account: %w", err)
+		}
+
+		if account != nil && account.Registration != nil && isAccountMatchingCaServer(ctx, account.Registration.URI, p.CAServer) {
+			p.account = account
+		}
+	}
+
 	account, err := p.initAccount(ctx)
 	if err != nil {
 		return nil, err
@@ -682,6 +748,23 @@ func (p *Provider) resolveDefaultCertificate(ctx context.Context, domains []stri
 
 	defer p.removeResolvingDomains(append(domains, domainKey))
 
+	if p.sharedStore != nil {
+		unlock, err := p.lock(ctx, "domains/"+domainKey)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
+		if err := p.syncCertificates(); err != nil {
+			return nil, err
+		}
+
+		if p.certExists(domains) {
+			logger.Debug().Msgf("Default certificate for domains %+v obtained by another instance", domains)
+			return nil, nil
+		}
+	}
+
 	logger.Debug().Msgf("Loading ACME certificates %+v...", domains)
 
 	client, err := p.getClient()
@@ -710,6 +793,13 @@ func (p *Provider) resolveDefaultCertificate(ctx context.Context, domains []stri
 
 	logger.Debug().Msgf("Default certificate obtained for domains %+v", domains)
 
+	if p.sharedStore != nil {
+		// The certificate is stored before releasing the lock,
+		// for the other instances to find it instead of obtaining another one.
+		domain := types.Domain{Main: domains[0], SANs: domains[1:]}
+		return nil, p.addCertificateForDomain(domain, cert, traefiktls.DefaultTLSStoreName)
+	}
+
 	return cert, nil
 }
 
@@ -728,6 +818,27 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	defer p.removeResolvingDomains(uncheckedDomains)
 
 	logger := log.Ctx(ctx)
+
+	if p.sharedStore != nil {
+		sortedDomains := slices.Clone(domains)
+		slices.Sort(sortedDomains)
+
+		unlock, err := p.lock(ctx, "domains/"+strings.Join(sortedDomains, ","))
+		if err != nil {
+			return types.Domain{}, nil, err
+		}
+		defer unlock()
+
+		if err := p.syncCertificates(); err != nil {
+			return types.Domain{}, nil, err
+		}
+
+		if p.certificatesCover(uncheckedDomains) {
+			logger.Debug().Msgf("Certificates for domains %+v obtained by another instance", uncheckedDomains)
+			return types.Domain{}, nil, nil
+		}
+	}
+
 	logger.Debug().Msgf("Loading ACME certificates %+v...", uncheckedDomains)
 
 	client, err := p.getClient()
@@ -761,6 +872,12 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 		domain.SANs = uncheckedDomains[1:]
 	}
 
+	if p.sharedStore != nil {
+		// The certificate is stored before releasing the lock,
+		// for the other instances to find it instead of obtaining another one.
+		return types.Domain{}, nil, p.addCertificateForDomain(domain, cert, tlsStore)
+	}
+
 	return domain, cert, nil
 }
 
@@ -778,6 +895,19 @@ func (p *Provider) addCertificateForDomain(domain types.Domain, crt *certificate
 		return nil
 	}
 
+	if p.sharedStore != nil {
+		// The certificates are saved all at once, thus the ones added by other instances must be retrieved first.
+		unlock, err := p.lock(context.Background(), "certificates")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := p.syncCertificates(); err != nil {
+			return err
+		}
+	}
+
 	p.certificatesMu.Lock()
 	defer p.certificatesMu.Unlock()
 
@@ -905,62 +1035,236 @@ func (p *Provider) buildMessage() dynamic.Message {
 	return conf
 }
 
-func (p *Provider) renewCertificates(ctx context.Context, renewPeriod time.Duration) {
+func (p *Provider) renewCertificates(ctx context.Context, renewPeriod, renewInterval time.Duration) {
 	logger := log.Ctx(ctx)
 
 	logger.Info().Msg("Testing certificate renew...")
 
+	// The certificates are copied, as they can be updated during the renewals.
 	p.certificatesMu.RLock()
-
-	var certificates []*CertAndStore
+	certificates := make([]*CertAndStore, 0, len(p.certificates))
 	for _, cert := range p.certificates {
+		certificates = append(certificates, &CertAndStore{Certificate: cert.Certificate, Store: cert.Store})
+	}
+	p.certificatesMu.RUnlock()
+
+	keys := make(map[string]struct{})
+	for _, cert := range certificates {
+		key := renewalKey(cert)
+		keys[key] = struct{}{}
+
 		crt, err := getX509Certificate(ctx, &cert.Certificate)
 		// If there's an error, we assume the cert is broken, and needs update
-		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(renewPeriod)) {
-			certificates = append(certificates, cert)
+		if err != nil || crt == nil {
+			if !p.renewalDelayed(key) {
+				p.renewCertificate(ctx, key, cert, nil, "", renewPeriod, renewInterval)
+			}
+			continue
+		}
+
+		p.metricsRegistry.ACMECertsNotAfterTimestampGauge().
+			With("resolver", p.ResolverName, "cn", cert.Domain.Main, "sans", strings.Join(cert.Domain.SANs, ",")).
+			Set(float64(crt.NotAfter.Unix()))
+
+		if renew, replaces := p.shouldRenew(ctx, key, crt, renewPeriod, renewInterval); renew {
+			p.renewCertificate(ctx, key, cert, crt, replaces, renewPeriod, renewInterval)
 		}
 	}
 
-	p.certificatesMu.RUnlock()
+	p.pruneRenewals(keys)
+}
 
-	for _, cert := range certificates {
-		client, err := p.getClient()
+// renewCertificate renews the given certificate, and delays the next attempt in case of failure.
+func (p *Provider) renewCertificate(ctx context.Context, key string, cert *CertAndStore, crt *x509.Certificate, replaces string, renewPeriod, renewInterval time.Duration) {
+	logger := log.Ctx(ctx)
+
+	if err := p.obtainRenewedCertificate(ctx, cert, crt, replaces); err != nil {
+		logger.Error().Err(err).Msgf("Error renewing ACME certificate: %+v", cert.Domain)
+		p.renewalFailed(key, cert.Domain, renewPeriod, renewInterval)
+		return
+	}
+
+	p.renewalSucceeded(key)
+}
+
+// obtainRenewedCertificate obtains a new certificate for the domains of the given certificate, and stores it.
+// The replaces parameter is the ARI identifier of the replaced certificate, set when the renewal is suggested by the CA.
+func (p *Provider) obtainRenewedCertificate(ctx context.Context, cert *CertAndStore, crt *x509.Certificate, replaces string) error {
+	logger := log.Ctx(ctx)
+
+	if p.sharedStore != nil {
+		sortedDomains := cert.Domain.ToStrArray()
+		slices.Sort(sortedDomains)
+
+		unlock, err := p.lock(ctx, "domains/"+strings.Join(sortedDomains, ","))
 		if err != nil {
-			logger.Info().Err(err).Msgf("Error renewing ACME certificate: %+v", cert.Domain)
-			continue
+			return err
 		}
+		defer unlock()
 
-		logger.Info().Msgf("Renewing ACME certificate: %+v", cert.Domain)
+		previous := cert.Certificate.Certificate
 
-		res := certificate.Resource{
-			Domain:      cert.Domain.Main,
-			PrivateKey:  cert.Key,
-			Certificate: cert.Certificate.Certificate,
+		if err := p.syncCertificates(); err != nil {
+			return err
 		}
 
-		opts := &certificate.RenewOptions{
-			Bundle:         true,
-			EmailAddresses: p.EmailAddresses,
-			Profile:        p.Profile,
-			PreferredChain: p.PreferredChain,
+		if p.certificateReplaced(cert.Domain, previous) {
+			logger.Debug().Msgf("ACME certificate %+v renewed by another instance", cert.Domain)
+			return nil
 		}
+	}
 
-		renewedCert, err := client.Certificate.RenewWithOptions(res, opts)
+	client, err := p.getClient()
+	if err != nil {
+		return fmt.Errorf("cannot get ACME client: %w", err)
+	}
+
+	logger.Info().Msgf("Renewing ACME certificate: %+v", cert.Domain)
+
+	request := certificate.ObtainRequest{
+		Domains:        cert.Domain.ToStrArray(),
+		Bundle:         true,
+		EmailAddresses: p.EmailAddresses,
+		Profile:        p.Profile,
+		PreferredChain: p.PreferredChain,
+		ReplacesCertID: replaces,
+	}
+
+	// The private key is reused, unless the certificate is broken.
+	if crt != nil {
+		request.Domains = certcrypto.ExtractDomains(crt)
+
+		request.PrivateKey, err = certcrypto.ParsePEMPrivateKey(cert.Key)
 		if err != nil {
-			logger.Error().Err(err).Msgf("Error renewing ACME certificate: %v", cert.Domain)
-			continue
+			return fmt.Errorf("parsing private key: %w", err)
 		}
+	}
 
-		if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
-			logger.Error().Msgf("domains %v renew certificate with no value: %v", cert.Domain.ToStrArray(), cert)
-			continue
+	renewedCert, err := client.Certificate.Obtain(request)
+	if err != nil {
+		return err
+	}
+
+	if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
+		return fmt.Errorf("domains %v renew certificate with no value: %v", cert.Domain.ToStrArray(), cert)
+	}
+
+	// With a shared store, the certificate is stored before releasing the lock,
+	// for the other instances to find it instead of renewing it again.
+	if err := p.addCertificateForDomain(cert.Domain, renewedCert, cert.Store); err != nil {
+		return fmt.Errorf("adding certificate for domain: %w", err)
+	}
+
+	return nil
+}
+
+// certificateReplaced returns whether the certificate of the given domain is not the given one anymore.
+func (p *Provider) certificateReplaced(domain types.Domain, previous []byte) bool {
+	p.certificatesMu.RLock()
+	defer p.certificatesMu.RUnlock()
+
+	for _, cert := range p.certificates {
+		if reflect.DeepEqual(domain, cert.Domain) {
+			return !bytes.Equal(cert.Certificate.Certificate, previous)
 		}
+	}
 
-		err = p.addCertificateForDomain(cert.Domain, renewedCert, cert.Store)
-		if err != nil {
-			logger.Error().Err(err).Msg("Error adding certificate for domain")
+	return true
+}
+
+// lock acquires the lock with the given name, shared by the Traefik instances using the same store,
+// and returns the function releasing it.
+func (p *Provider) lock(ctx context.Context, name string) (func(), error) {
+	log.Ctx(ctx).Debug().Msgf("Acquiring ACME storage lock %q", name)
+
+	unlock, err := p.sharedStore.Lock(ctx, p.ResolverName+"/"+name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to acquire ACME storage lock: %w", err)
+	}
+
+	return unlock, nil
+}
+
+// syncCertificates retrieves the certificates stored by all the instances sharing the store.
+func (p *Provider) syncCertificates() error {
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		return fmt.Errorf("unable to get ACME certificates: %w", err)
+	}
+
+	p.updateCertificates(certificates)
+
+	return nil
+}
+
+// updateCertificates replaces the certificates, and sends the resulting configuration if they changed.
+func (p *Provider) updateCertificates(certificates []*CertAndStore) {
+	p.certificatesMu.Lock()
+	defer p.certificatesMu.Unlock()
+
+	if reflect.DeepEqual(p.certificates, certificates) {
+		return
+	}
+
+	p.certificates = certificates
+
+	p.configurationChan <- p.buildMessage()
+}
+
+// certificatesCover returns whether the given domains are all covered by the ACME certificates.
+func (p *Provider) certificatesCover(domains []string) bool {
+	p.certificatesMu.RLock()
+	defer p.certificatesMu.RUnlock()
+
+	var certDomains []string
+	for _, cert := range p.certificates {
+		certDomains = append(certDomains, strings.Join(cert.Domain.ToStrArray(), ","))
+	}
+
+	for _, domain := range domains {
+		if !isDomainAlreadyChecked(domain, certDomains) {
+			return false
 		}
 	}
+
+	return true
+}
+
+// watchSharedCertificates keeps the certificates up to date with the ones obtained by the other instances sharing the store.
+func (p *Provider) watchSharedCertificates(ctx context.Context) {
+	p.pool.GoCtx(func(ctxPool context.Context) {
+		logger := log.Ctx(ctx)
+
+		operation := func() error {
+			certificatesChan, err := p.sharedStore.WatchCertificates(ctxPool, p.ResolverName)
+			if err != nil {
+				return fmt.Errorf("unable to watch ACME certificates: %w", err)
+			}
+
+			for {
+				select {
+				case <-ctxPool.Done():
+					return nil
+
+				case certificates, ok := <-certificatesChan:
+					if !ok {
+						return errors.New("the ACME certificates watch channel is closed")
+					}
+
+					p.updateCertificates(certificates)
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Error().Err(err).Msgf("ACME storage error, retrying in %s", time)
+		}
+
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxPool), notify)
+		if err != nil {
+			logger.Error().Err(err).Msg("Cannot watch ACME certificates")
+		}
+	})
 }
 
 // Get provided certificate which check a domains list (Main and SANs)
@@ -0,0 +1,245 @@
+package acme
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+// ariCheckInterval is the maximum interval between two renewal checks when the ACME Renewal Information extension is used,
+// for the renewals suggested by the CA (e.g. after a revocation incident) to be handled quickly.
+const ariCheckInterval = 6 * time.Hour
+
+// renewal holds the renewal schedule of an ACME certificate.
+type renewal struct {
+	// serial is the serial number of the certificate the schedule is computed for.
+	serial string
+
+	// renewalInfo holds the renewal information suggested by the CA, if any.
+	renewalInfo *certificate.RenewalInfoResponse
+	// renewalInfoCheckAt is the time after which the renewal information must be fetched again.
+	renewalInfoCheckAt time.Time
+
+	// backOff computes the delay between the failed renewal attempts.
+	backOff *backoff.ExponentialBackOff
+	// retryAt is the time before which the renewal must not be attempted again after a failure.
+	retryAt time.Time
+}
+
+// renewalKey returns the key identifying the renewal schedule of the given certificate.
+func renewalKey(cert *CertAndStore) string {
+	return cert.Store + "/" + strings.Join(cert.Domain.ToStrArray(), ",")
+}
+
+// getRenewalCheckInterval returns the interval between two renewal checks.
+func (p *Provider) getRenewalCheckInterval(renewInterval time.Duration) time.Duration {
+	if p.DisableARI {
+		return renewInterval
+	}
+
+	return min(renewInterval, ariCheckInterval)
+}
+
+// shouldRenew returns whether the given certificate must be renewed now,
+// and the ARI identifier of the certificate to replace, when the renewal is suggested by the CA.
+func (p *Provider) shouldRenew(ctx context.Context, key string, crt *x509.Certificate, renewPeriod, renewInterval time.Duration) (bool, string) {
+	p.renewalsMu.Lock()
+
+	if p.renewals == nil {
+		p.renewals = make(map[string]*renewal)
+	}
+
+	serial := crt.SerialNumber.String()
+
+	r, ok := p.renewals[key]
+	if !ok || r.serial != serial {
+		r = &renewal{serial: serial}
+		p.renewals[key] = r
+	}
+
+	now := time.Now()
+
+	if now.Before(r.retryAt) {
+		p.renewalsMu.Unlock()
+		return false, ""
+	}
+
+	checkRenewalInfo := !p.DisableARI && !p.ariUnsupported.Load() && !now.Before(r.renewalInfoCheckAt)
+	if checkRenewalInfo {
+		r.renewalInfoCheckAt = now.Add(renewInterval)
+	}
+	renewalInfo := r.renewalInfo
+
+	p.renewalsMu.Unlock()
+
+	// The renewal information is fetched without holding the lock, for a slow CA not to block the other renewals.
+	if checkRenewalInfo {
+		if fetched, retryAfter := p.fetchRenewalInfo(ctx, crt, renewalInfo); fetched != nil {
+			renewalInfo = fetched
+
+			p.renewalsMu.Lock()
+			r.renewalInfo = fetched
+			if retryAfter > 0 {
+				r.renewalInfoCheckAt = time.Now().Add(retryAfter)
+			}
+			p.renewalsMu.Unlock()
+		}
+	}
+
+	if renewalInfo == nil || p.ariUnsupported.Load() {
+		return !now.Before(defaultRenewAt(crt, renewPeriod)), ""
+	}
+
+	if now.Before(renewalInfoRenewAt(crt, renewalInfo.SuggestedWindow)) {
+		return false, ""
+	}
+
+	certID, err := certificate.MakeARICertID(crt)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msgf("Unable to compute the ARI identifier of the ACME certificate for domains %v", crt.DNSNames)
+		return true, ""
+	}
+
+	return true, certID
+}
+
+// fetchRenewalInfo fetches the renewal information of the given certificate from the CA,
+// and returns it along with the delay before fetching it again, if suggested by the CA.
+// In case of failure, it returns nil: the previous renewal information is kept,
+// and the renewal falls back to the default schedule without it.
+func (p *Provider) fetchRenewalInfo(ctx context.Context, crt *x509.Certificate, previous *certificate.RenewalInfoResponse) (*certificate.RenewalInfoResponse, time.Duration) {
+	logger := log.Ctx(ctx)
+
+	client, err := p.getClient()
+	if err != nil {
+		logger.Debug().Err(err).Msg("Unable to get the ACME client to fetch the renewal information")
+		return nil, 0
+	}
+
+	renewalInfo, err := client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: crt})
+	if err != nil {
+		if errors.Is(err, api.ErrNoARI) {
+			logger.Info().Msg("The CA does not support the ACME Renewal Information extension, the certificates will be renewed before their expiry")
+			p.ariUnsupported.Store(true)
+			return nil, 0
+		}
+
+		logger.Warn().Err(err).Msgf("Unable to fetch the renewal information of the ACME certificate for domains %v", crt.DNSNames)
+		return nil, 0
+	}
+
+	if previous == nil || previous.SuggestedWindow != renewalInfo.SuggestedWindow {
+		logger.Debug().
+			Str("explanationURL", renewalInfo.ExplanationURL).
+			Msgf("Renewal window of the ACME certificate for domains %v suggested by the CA: %s - %s",
+				crt.DNSNames, renewalInfo.SuggestedWindow.Start, renewalInfo.SuggestedWindow.End)
+	}
+
+	return renewalInfo, renewalInfo.RetryAfter
+}
+
+// renewalDelayed returns whether the renewal of the certificate with the given key is delayed after a failure.
+func (p *Provider) renewalDelayed(key string) bool {
+	p.renewalsMu.Lock()
+	defer p.renewalsMu.Unlock()
+
+	r, ok := p.renewals[key]
+	return ok && time.Now().Before(r.retryAt)
+}
+
+// renewalSucceeded resets the renewal schedule of the certificate with the given key.
+func (p *Provider) renewalSucceeded(key string) {
+	p.renewalsMu.Lock()
+	defer p.renewalsMu.Unlock()
+
+	delete(p.renewals, key)
+}
+
+// renewalFailed delays the next renewal attempt of the certificate with the given key, with an exponential backoff.
+func (p *Provider) renewalFailed(key string, domain types.Domain, renewPeriod, renewInterval time.Duration) {
+	p.metricsRegistry.ACMECertsRenewalFailuresCounter().
+		With("resolver", p.ResolverName, "cn", domain.Main, "sans", strings.Join(domain.SANs, ",")).
+		Add(1)
+
+	p.renewalsMu.Lock()
+	defer p.renewalsMu.Unlock()
+
+	if p.renewals == nil {
+		p.renewals = make(map[string]*renewal)
+	}
+
+	// A broken certificate has no renewal schedule yet, as it cannot be parsed.
+	r, ok := p.renewals[key]
+	if !ok {
+		r = &renewal{}
+		p.renewals[key] = r
+	}
+
+	if r.backOff == nil {
+		r.backOff = newRenewalBackOff(renewPeriod, renewInterval)
+	}
+
+	r.retryAt = time.Now().Add(r.backOff.NextBackOff())
+}
+
+// pruneRenewals removes the renewal schedules of the certificates which are not managed anymore.
+func (p *Provider) pruneRenewals(keys map[string]struct{}) {
+	p.renewalsMu.Lock()
+	defer p.renewalsMu.Unlock()
+
+	for key := range p.renewals {
+		if _, ok := keys[key]; !ok {
+			delete(p.renewals, key)
+		}
+	}
+}
+
+// newRenewalBackOff returns the backoff used between the failed renewal attempts of a certificate.
+// The delay starts at the renew interval, and is bounded so that several attempts can be made during the renew period.
+func newRenewalBackOff(renewPeriod, renewInterval time.Duration) *backoff.ExponentialBackOff {
+	return backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(renewInterval),
+		backoff.WithMaxInterval(max(renewInterval, renewPeriod/8)),
+		backoff.WithMaxElapsedTime(0),
+	)
+}
+
+// renewalJitter returns a fraction in [0, 1) derived from the serial number of the given certificate.
+// The fraction is deterministic, for the instances sharing the certificates to agree on their renewal times.
+func renewalJitter(crt *x509.Certificate) float64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write(crt.SerialNumber.Bytes())
+
+	return float64(hash.Sum64()>>11) / (1 << 53)
+}
+
+// defaultRenewAt returns the renewal time of the given certificate when no renewal information is suggested by the CA.
+// The certificate is renewed during the renew period before its expiry, up to a third of this period earlier,
+// to spread the renewals of the certificates issued at the same time.
+func defaultRenewAt(crt *x509.Certificate, renewPeriod time.Duration) time.Time {
+	jitter := time.Duration(renewalJitter(crt) * float64(renewPeriod/3))
+
+	return crt.NotAfter.Add(-renewPeriod - jitter)
+}
+
+// renewalInfoRenewAt returns the renewal time of the given certificate, selected in the window suggested by the CA.
+// A window in the past, suggested for instance after a revocation incident, results in an immediate renewal.
+func renewalInfoRenewAt(crt *x509.Certificate, window acme.Window) time.Time {
+	if !window.End.After(window.Start) {
+		return window.Start
+	}
+
+	jitter := time.Duration(renewalJitter(crt) * float64(window.End.Sub(window.Start)))
+
+	return window.Start.Add(jitter)
+}
@@ -0,0 +1,476 @@
+package acme
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/observability/metrics"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestRenewalJitter(t *testing.T) {
+	for serial := range int64(100) {
+		crt := &x509.Certificate{SerialNumber: big.NewInt(serial)}
+
+		jitter := renewalJitter(crt)
+		assert.GreaterOrEqual(t, jitter, 0.0)
+		assert.Less(t, jitter, 1.0)
+
+		// The jitter is the same for the same certificate.
+		assert.InDelta(t, jitter, renewalJitter(&x509.Certificate{SerialNumber: big.NewInt(serial)}), 0)
+	}
+
+	assert.NotEqual(t, renewalJitter(&x509.Certificate{SerialNumber: big.NewInt(1)}), renewalJitter(&x509.Certificate{SerialNumber: big.NewInt(2)}))
+}
+
+func TestDefaultRenewAt(t *testing.T) {
+	notAfter := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	renewPeriod := 30 * 24 * time.Hour
+
+	for serial := range int64(100) {
+		renewAt := defaultRenewAt(&x509.Certificate{SerialNumber: big.NewInt(serial), NotAfter: notAfter}, renewPeriod)
+
+		assert.False(t, renewAt.After(notAfter.Add(-renewPeriod)))
+		assert.True(t, renewAt.After(notAfter.Add(-renewPeriod-renewPeriod/3)))
+	}
+}
+
+func TestRenewalInfoRenewAt(t *testing.T) {
+	start := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc        string
+		window      acme.Window
+		expectedMin time.Time
+		expectedMax time.Time
+	}{
+		{
+			desc:        "window",
+			window:      acme.Window{Start: start, End: start.Add(48 * time.Hour)},
+			expectedMin: start,
+			expectedMax: start.Add(48 * time.Hour),
+		},
+		{
+			desc:        "empty window",
+			window:      acme.Window{Start: start, End: start},
+			expectedMin: start,
+			expectedMax: start,
+		},
+		{
+			desc:        "inverted window",
+			window:      acme.Window{Start: start, End: start.Add(-time.Hour)},
+			expectedMin: start,
+			expectedMax: start,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for serial := range int64(100) {
+				renewAt := renewalInfoRenewAt(&x509.Certificate{SerialNumber: big.NewInt(serial)}, test.window)
+
+				assert.False(t, renewAt.Before(test.expectedMin))
+				assert.False(t, renewAt.After(test.expectedMax))
+			}
+		})
+	}
+}
+
+func TestProvider_shouldRenew(t *testing.T) {
+	renewPeriod := 30 * 24 * time.Hour
+
+	testCases := []struct {
+		desc       string
+		disableARI bool
+		notAfter   time.Time
+		renewal    *renewal
+		expected   bool
+		expectedID bool
+	}{
+		{
+			desc:       "not expiring",
+			disableARI: true,
+			notAfter:   time.Now().Add(60 * 24 * time.Hour),
+		},
+		{
+			desc:       "expiring during the renew period",
+			disableARI: true,
+			notAfter:   time.Now().Add(20 * 24 * time.Hour),
+			expected:   true,
+		},
+		{
+			desc:       "expired",
+			disableARI: true,
+			notAfter:   time.Now().Add(-time.Hour),
+			expected:   true,
+		},
+		{
+			desc:       "expiring but in backoff",
+			disableARI: true,
+			notAfter:   time.Now().Add(20 * 24 * time.Hour),
+			renewal:    &renewal{serial: "1", retryAt: time.Now().Add(time.Hour)},
+		},
+		{
+			desc:       "expiring after backoff",
+			disableARI: true,
+			notAfter:   time.Now().Add(20 * 24 * time.Hour),
+			renewal:    &renewal{serial: "1", retryAt: time.Now().Add(-time.Hour)},
+			expected:   true,
+		},
+		{
+			desc:       "backoff of a previous certificate",
+			disableARI: true,
+			notAfter:   time.Now().Add(20 * 24 * time.Hour),
+			renewal:    &renewal{serial: "2", retryAt: time.Now().Add(time.Hour)},
+			expected:   true,
+		},
+		{
+			desc:     "renewal window in the future",
+			notAfter: time.Now().Add(20 * 24 * time.Hour),
+			renewal: &renewal{
+				serial: "1",
+				renewalInfo: &certificate.RenewalInfoResponse{RenewalInfoResponse: acme.RenewalInfoResponse{
+					SuggestedWindow: acme.Window{Start: time.Now().Add(24 * time.Hour), End: time.Now().Add(48 * time.Hour)},
+				}},
+				renewalInfoCheckAt: time.Now().Add(time.Hour),
+			},
+		},
+		{
+			desc:     "renewal window in the past",
+			notAfter: time.Now().Add(60 * 24 * time.Hour),
+			renewal: &renewal{
+				serial: "1",
+				renewalInfo: &certificate.RenewalInfoResponse{RenewalInfoResponse: acme.RenewalInfoResponse{
+					SuggestedWindow: acme.Window{Start: time.Now().Add(-48 * time.Hour), End: time.Now().Add(-24 * time.Hour)},
+				}},
+				renewalInfoCheckAt: time.Now().Add(time.Hour),
+			},
+			expected:   true,
+			expectedID: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{Configuration: &Configuration{DisableARI: test.disableARI}}
+			if test.renewal != nil {
+				p.renewals = map[string]*renewal{"key": test.renewal}
+			}
+
+			crt := &x509.Certificate{
+				SerialNumber:   big.NewInt(1),
+				NotAfter:       test.notAfter,
+				AuthorityKeyId: []byte("authority"),
+			}
+
+			renew, replaces := p.shouldRenew(t.Context(), "key", crt, renewPeriod, 24*time.Hour)
+			assert.Equal(t, test.expected, renew)
+			assert.Equal(t, test.expectedID, replaces != "")
+		})
+	}
+}
+
+func TestProvider_renewalFailed(t *testing.T) {
+	p := &Provider{
+		Configuration:   &Configuration{DisableARI: true},
+		metricsRegistry: metrics.NewVoidRegistry(),
+	}
+
+	renewPeriod := 30 * 24 * time.Hour
+	renewInterval := 24 * time.Hour
+	crt := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(20 * 24 * time.Hour)}
+
+	renew, _ := p.shouldRenew(t.Context(), "key", crt, renewPeriod, renewInterval)
+	assert.True(t, renew)
+
+	p.renewalFailed("key", types.Domain{Main: "example.com"}, renewPeriod, renewInterval)
+
+	renew, _ = p.shouldRenew(t.Context(), "key", crt, renewPeriod, renewInterval)
+	assert.False(t, renew)
+
+	// The delay is bounded by the maximum interval, with the randomization.
+	for range 10 {
+		p.renewalFailed("key", types.Domain{Main: "example.com"}, renewPeriod, renewInterval)
+
+		delay := time.Until(p.renewals["key"].retryAt)
+		assert.Positive(t, delay)
+		assert.LessOrEqual(t, delay, renewPeriod/8*3/2)
+	}
+
+	// A successful renewal resets the schedule.
+	p.renewalSucceeded("key")
+
+	renew, _ = p.shouldRenew(t.Context(), "key", crt, renewPeriod, renewInterval)
+	assert.True(t, renew)
+}
+
+func TestProvider_renewCertificates_broken(t *testing.T) {
+	var orders atomic.Int32
+	caServer := newFailingACMEServerMock(t, &orders)
+
+	store := NewKVStore(newMemoryKVStore(), "traefik")
+	err := store.SaveCertificates("myresolver", []*CertAndStore{{
+		Certificate: Certificate{
+			Domain:      types.Domain{Main: "example.com"},
+			Certificate: []byte("broken"),
+			Key:         []byte("broken"),
+		},
+		Store: "default",
+	}})
+	require.NoError(t, err)
+
+	p := newSharedStoreProvider(t, caServer, store)
+
+	renewPeriod := 30 * 24 * time.Hour
+	renewInterval := 24 * time.Hour
+
+	p.renewCertificates(t.Context(), renewPeriod, renewInterval)
+	assert.Equal(t, int32(1), orders.Load())
+
+	// The next attempt is delayed after the failure, as for a valid certificate.
+	p.renewCertificates(t.Context(), renewPeriod, renewInterval)
+	assert.Equal(t, int32(1), orders.Load())
+}
+
+func TestProvider_renewCertificate_sharedStore(t *testing.T) {
+	var orders atomic.Int32
+	caServer := newACMEServerMock(t, &orders)
+
+	certPEM, keyPEM, err := generate.KeyPair("example.com", time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+
+	current := &CertAndStore{
+		Certificate: Certificate{
+			Domain:      types.Domain{Main: "example.com"},
+			Certificate: certPEM,
+			Key:         keyPEM,
+		},
+		Store: "default",
+	}
+
+	store := slowSaveStore{SharedStore: NewKVStore(newMemoryKVStore(), "traefik")}
+	err = store.SaveCertificates("myresolver", []*CertAndStore{current})
+	require.NoError(t, err)
+
+	renewPeriod := 30 * 24 * time.Hour
+	renewInterval := 24 * time.Hour
+
+	// The providers are created beforehand, as the ACME clients set up the shared HTTP client.
+	providers := []*Provider{
+		newSharedStoreProvider(t, caServer, store),
+		newSharedStoreProvider(t, caServer, store),
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range providers {
+		// Each instance renews the certificate it knows.
+		cert := &CertAndStore{Certificate: current.Certificate, Store: current.Store}
+
+		wg.Go(func() {
+			p.renewCertificate(t.Context(), "key", cert, nil, "", renewPeriod, renewInterval)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), orders.Load())
+
+	certificates, err := store.GetCertificates("myresolver")
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+	assert.NotEqual(t, certPEM, certificates[0].Certificate.Certificate)
+}
+
+// slowSaveStore is a SharedStore taking time to save the certificates, like a remote storage.
+type slowSaveStore struct {
+	SharedStore
+}
+
+func (s slowSaveStore) SaveCertificates(resolverName string, certificates []*CertAndStore) error {
+	time.Sleep(100 * time.Millisecond)
+
+	return s.SharedStore.SaveCertificates(resolverName, certificates)
+}
+
+// newSharedStoreProvider returns a provider using the given shared store and ACME server.
+func newSharedStoreProvider(t *testing.T, caServer *httptest.Server, store SharedStore) *Provider {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	config := lego.NewConfig(&Account{Email: "foo@example.com", PrivateKey: x509.MarshalPKCS1PrivateKey(privateKey)})
+	config.CADirURL = caServer.URL + "/directory"
+	config.HTTPClient = caServer.Client()
+
+	client, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	configurationChan := make(chan dynamic.Message)
+	go func() {
+		for {
+			select {
+			case <-t.Context().Done():
+				return
+			case <-configurationChan:
+			}
+		}
+	}()
+
+	p := &Provider{
+		Configuration:     &Configuration{DisableARI: true},
+		ResolverName:      "myresolver",
+		Store:             store,
+		sharedStore:       store,
+		client:            client,
+		configurationChan: configurationChan,
+		metricsRegistry:   metrics.NewVoidRegistry(),
+	}
+
+	err = p.syncCertificates()
+	require.NoError(t, err)
+
+	return p
+}
+
+// newFailingACMEServerMock returns an ACME server rejecting the orders, and counting them.
+func newFailingACMEServerMock(t *testing.T, orders *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Replay-Nonce", "nonce")
+		mux.ServeHTTP(rw, req)
+	}))
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/directory", func(rw http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(rw).Encode(acme.Directory{
+			NewNonceURL:   server.URL + "/nonce",
+			NewAccountURL: server.URL + "/account",
+			NewOrderURL:   server.URL + "/order",
+		})
+	})
+
+	mux.HandleFunc("/nonce", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/order", func(rw http.ResponseWriter, _ *http.Request) {
+		orders.Add(1)
+
+		rw.Header().Set("Content-Type", "application/problem+json")
+		rw.WriteHeader(http.StatusForbidden)
+		_, _ = rw.Write([]byte(`{"type":"urn:ietf:params:acme:error:rejectedIdentifier","detail":"rejected"}`))
+	})
+
+	return server
+}
+
+// newACMEServerMock returns an ACME server issuing certificates without challenges, and counting the orders.
+func newACMEServerMock(t *testing.T, orders *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Replay-Nonce", "nonce")
+		mux.ServeHTTP(rw, req)
+	}))
+	t.Cleanup(server.Close)
+
+	var (
+		identifiersMu sync.Mutex
+		identifiers   []acme.Identifier
+	)
+
+	mux.HandleFunc("/directory", func(rw http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(rw).Encode(acme.Directory{
+			NewNonceURL:   server.URL + "/nonce",
+			NewAccountURL: server.URL + "/account",
+			NewOrderURL:   server.URL + "/order",
+		})
+	})
+
+	mux.HandleFunc("/nonce", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/order", func(rw http.ResponseWriter, req *http.Request) {
+		orders.Add(1)
+
+		var jws struct {
+			Payload string `json:"payload"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&jws); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var order acme.Order
+		if err := json.Unmarshal(payload, &order); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		identifiersMu.Lock()
+		identifiers = order.Identifiers
+		identifiersMu.Unlock()
+
+		rw.Header().Set("Location", server.URL+"/order/1")
+		rw.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(rw).Encode(acme.Order{
+			Status:      acme.StatusReady,
+			Identifiers: order.Identifiers,
+			Finalize:    server.URL + "/finalize",
+		})
+	})
+
+	mux.HandleFunc("/finalize", func(rw http.ResponseWriter, _ *http.Request) {
+		identifiersMu.Lock()
+		defer identifiersMu.Unlock()
+
+		_ = json.NewEncoder(rw).Encode(acme.Order{
+			Status:      acme.StatusValid,
+			Identifiers: identifiers,
+			Certificate: server.URL + "/certificate",
+		})
+	})
+
+	mux.HandleFunc("/certificate", func(rw http.ResponseWriter, _ *http.Request) {
+		certPEM, _, err := generate.KeyPair("example.com", time.Now().Add(90*24*time.Hour))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write(certPEM)
+	})
+
+	return server
+}
@@ -0,0 +1,95 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/consul"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/etcd"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/redis"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/zk"
+)
+
+// lockTTL is the duration after which a lock held by an unresponsive Traefik instance expires.
+const lockTTL = 30 * time.Second
+
+// SharedStorage holds the configuration of a storage shared by several Traefik instances.
+type SharedStorage struct {
+	Consul     *consul.ProviderBuilder `description:"Stores the ACME data in Consul." json:"consul,omitempty" toml:"consul,omitempty" yaml:"consul,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Etcd       *etcd.Provider          `description:"Stores the ACME data in etcd." json:"etcd,omitempty" toml:"etcd,omitempty" yaml:"etcd,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Redis      *redis.Provider         `description:"Stores the ACME data in Redis." json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ZooKeeper  *zk.Provider            `description:"Stores the ACME data in ZooKeeper." json:"zooKeeper,omitempty" toml:"zooKeeper,omitempty" yaml:"zooKeeper,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Kubernetes *KubernetesStorage      `description:"Stores the ACME data in Kubernetes Secrets." json:"kubernetes,omitempty" toml:"kubernetes,omitempty" yaml:"kubernetes,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// Validate validates that exactly one storage backend is configured.
+func (s *SharedStorage) Validate() error {
+	var count int
+	for _, configured := range []bool{s.Consul != nil, s.Etcd != nil, s.Redis != nil, s.ZooKeeper != nil, s.Kubernetes != nil} {
+		if configured {
+			count++
+		}
+	}
+
+	switch count {
+	case 0:
+		return errors.New("no shared storage backend defined")
+	case 1:
+		return nil
+	default:
+		return errors.New("only one shared storage backend can be defined")
+	}
+}
+
+// NewStore creates the SharedStore corresponding to the configured storage backend.
+func (s *SharedStorage) NewStore(ctx context.Context) (SharedStore, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	if s.Kubernetes != nil {
+		return s.Kubernetes.NewStore(ctx)
+	}
+
+	var (
+		client  store.Store
+		rootKey string
+		err     error
+	)
+	switch {
+	case s.Consul != nil:
+		client, err = s.Consul.NewStore(ctx)
+		rootKey = s.Consul.RootKey
+	case s.Etcd != nil:
+		client, err = s.Etcd.NewStore(ctx)
+		rootKey = s.Etcd.RootKey
+	case s.Redis != nil:
+		client, err = s.Redis.NewStore(ctx)
+		rootKey = s.Redis.RootKey
+	case s.ZooKeeper != nil:
+		client, err = s.ZooKeeper.NewStore(ctx)
+		rootKey = s.ZooKeeper.RootKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating KV store client: %w", err)
+	}
+
+	return NewKVStore(client, rootKey), nil
+}
+
+// withoutEmptyCertificates returns the given certificates, without the ones with no value.
+func withoutEmptyCertificates(certificates []*CertAndStore) []*CertAndStore {
+	var nonEmpty []*CertAndStore
+	for _, certificate := range certificates {
+		if certificate == nil || len(certificate.Certificate.Certificate) == 0 || len(certificate.Key) == 0 {
+			continue
+		}
+
+		nonEmpty = append(nonEmpty, certificate)
+	}
+
+	return nonEmpty
+}
@@ -0,0 +1,46 @@
+package acme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/etcd"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/redis"
+)
+
+func TestSharedStorage_Validate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		storage     SharedStorage
+		expectedErr string
+	}{
+		{
+			desc:        "no backend",
+			expectedErr: "no shared storage backend defined",
+		},
+		{
+			desc:    "one backend",
+			storage: SharedStorage{Etcd: &etcd.Provider{}},
+		},
+		{
+			desc:        "several backends",
+			storage:     SharedStorage{Etcd: &etcd.Provider{}, Redis: &redis.Provider{}},
+			expectedErr: "only one shared storage backend can be defined",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.storage.Validate()
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
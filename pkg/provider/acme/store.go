@@ -1,5 +1,7 @@
 package acme
 
+import "context"
+
 // StoredData represents the data managed by Store.
 type StoredData struct {
 	Account      *Account
@@ -13,3 +15,16 @@ type Store interface {
 	GetCertificates(resolverName string) ([]*CertAndStore, error)
 	SaveCertificates(resolverName string, certificates []*CertAndStore) error
 }
+
+// SharedStore is a Store shared by several Traefik instances.
+// Its data can be updated at any time by the other instances.
+type SharedStore interface {
+	Store
+
+	// Lock blocks until the lock with the given name, shared by all the instances, is acquired,
+	// and returns the function releasing it.
+	Lock(ctx context.Context, name string) (func(), error)
+	// WatchCertificates sends the certificates of the given resolver each time they are updated,
+	// until the given context is done.
+	WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error)
+}
@@ -147,6 +147,14 @@ func NewProviderAggregator(conf static.Providers) *ProviderAggregator {
 		p.quietAddProvider(conf.HTTP)
 	}
 
+	if conf.Git != nil {
+		p.quietAddProvider(conf.Git)
+	}
+
+	if conf.DNS != nil {
+		p.quietAddProvider(conf.DNS)
+	}
+
 	return p
 }
 
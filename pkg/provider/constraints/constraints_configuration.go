@@ -0,0 +1,52 @@
+package constraints
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// FilterConfiguration removes from the given configuration the routers, services and middlewares
+// whose labels do not match the expression (see MatchLabels).
+func FilterConfiguration(ctx context.Context, conf *dynamic.Configuration, expr string) error {
+	if expr == "" || conf == nil {
+		return nil
+	}
+
+	// Checks the expression once, so that the filters below cannot fail.
+	if _, err := MatchLabels(nil, expr); err != nil {
+		return err
+	}
+
+	if conf.HTTP != nil {
+		filter(ctx, "HTTP router", conf.HTTP.Routers, expr, func(rt *dynamic.Router) map[string]string { return rt.Labels })
+		filter(ctx, "HTTP service", conf.HTTP.Services, expr, func(svc *dynamic.Service) map[string]string { return svc.Labels })
+		filter(ctx, "HTTP middleware", conf.HTTP.Middlewares, expr, func(mw *dynamic.Middleware) map[string]string { return mw.Labels })
+	}
+
+	if conf.TCP != nil {
+		filter(ctx, "TCP router", conf.TCP.Routers, expr, func(rt *dynamic.TCPRouter) map[string]string { return rt.Labels })
+		filter(ctx, "TCP service", conf.TCP.Services, expr, func(svc *dynamic.TCPService) map[string]string { return svc.Labels })
+		filter(ctx, "TCP middleware", conf.TCP.Middlewares, expr, func(mw *dynamic.TCPMiddleware) map[string]string { return mw.Labels })
+	}
+
+	if conf.UDP != nil {
+		filter(ctx, "UDP router", conf.UDP.Routers, expr, func(rt *dynamic.UDPRouter) map[string]string { return rt.Labels })
+		filter(ctx, "UDP service", conf.UDP.Services, expr, func(svc *dynamic.UDPService) map[string]string { return svc.Labels })
+	}
+
+	return nil
+}
+
+func filter[V any](ctx context.Context, kind string, elements map[string]V, expr string, labels func(V) map[string]string) {
+	for name, element := range elements {
+		// The expression is known to be valid.
+		if matches, _ := MatchLabels(labels(element), expr); matches {
+			continue
+		}
+
+		log.Ctx(ctx).Debug().Msgf("%s %q pruned by constraint expression: %q", kind, name, expr)
+		delete(elements, name)
+	}
+}
@@ -0,0 +1,56 @@
+package constraints
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestFilterConfiguration(t *testing.T) {
+	prod := map[string]string{"env": "prod"}
+	staging := map[string]string{"env": "staging"}
+
+	conf := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:     map[string]*dynamic.Router{"foo": {Labels: prod}, "bar": {Labels: staging}, "baz": {}},
+			Services:    map[string]*dynamic.Service{"foo": {Labels: prod}, "bar": {Labels: staging}},
+			Middlewares: map[string]*dynamic.Middleware{"foo": {Labels: prod}, "bar": {Labels: staging}},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:     map[string]*dynamic.TCPRouter{"foo": {Labels: prod}, "bar": {Labels: staging}},
+			Services:    map[string]*dynamic.TCPService{"foo": {Labels: prod}, "bar": {Labels: staging}},
+			Middlewares: map[string]*dynamic.TCPMiddleware{"foo": {Labels: prod}, "bar": {Labels: staging}},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  map[string]*dynamic.UDPRouter{"foo": {Labels: prod}, "bar": {Labels: staging}},
+			Services: map[string]*dynamic.UDPService{"foo": {Labels: prod}, "bar": {Labels: staging}},
+		},
+	}
+
+	require.NoError(t, FilterConfiguration(t.Context(), conf, "Label(`env`, `prod`)"))
+
+	assert.Equal(t, map[string]*dynamic.Router{"foo": {Labels: prod}}, conf.HTTP.Routers)
+	assert.Equal(t, map[string]*dynamic.Service{"foo": {Labels: prod}}, conf.HTTP.Services)
+	assert.Equal(t, map[string]*dynamic.Middleware{"foo": {Labels: prod}}, conf.HTTP.Middlewares)
+	assert.Equal(t, map[string]*dynamic.TCPRouter{"foo": {Labels: prod}}, conf.TCP.Routers)
+	assert.Equal(t, map[string]*dynamic.TCPService{"foo": {Labels: prod}}, conf.TCP.Services)
+	assert.Equal(t, map[string]*dynamic.TCPMiddleware{"foo": {Labels: prod}}, conf.TCP.Middlewares)
+	assert.Equal(t, map[string]*dynamic.UDPRouter{"foo": {Labels: prod}}, conf.UDP.Routers)
+	assert.Equal(t, map[string]*dynamic.UDPService{"foo": {Labels: prod}}, conf.UDP.Services)
+}
+
+func TestFilterConfiguration_invalidExpression(t *testing.T) {
+	conf := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{"foo": {}},
+		},
+	}
+
+	require.Error(t, FilterConfiguration(t.Context(), conf, "Label(`env`"))
+	assert.Len(t, conf.HTTP.Routers, 1)
+
+	require.NoError(t, FilterConfiguration(t.Context(), conf, ""))
+	assert.Len(t, conf.HTTP.Routers, 1)
+}
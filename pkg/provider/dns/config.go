@@ -0,0 +1,152 @@
+package dns
+
+import (
+	"cmp"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// buildConfiguration builds the services from the endpoints resolved for them.
+func (p *Provider) buildConfiguration(endpoints map[string][]endpoint) *dynamic.Configuration {
+	configuration := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:           make(map[string]*dynamic.Router),
+			Middlewares:       make(map[string]*dynamic.Middleware),
+			Services:          make(map[string]*dynamic.Service),
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
+		},
+	}
+
+	for name, resolved := range endpoints {
+		service, ok := p.Services[name]
+		if !ok {
+			continue
+		}
+
+		maps.Copy(configuration.HTTP.Services, buildServices(name, service, resolved))
+	}
+
+	return configuration
+}
+
+// buildServices builds the services of the given endpoints.
+// The endpoints are grouped by priority, the lowest priority being used first (RFC 2782).
+// When the health check is enabled, a failover service falls back to the groups of higher priority,
+// otherwise only the group of lowest priority is used.
+func buildServices(name string, service *Service, endpoints []endpoint) map[string]*dynamic.Service {
+	groups := groupByPriority(endpoints)
+
+	if service.HealthCheck == nil || len(groups) <= 1 {
+		var group []endpoint
+		if len(groups) > 0 {
+			group = groups[0]
+		}
+
+		return map[string]*dynamic.Service{
+			name: buildLoadBalancer(service, group),
+		}
+	}
+
+	services := make(map[string]*dynamic.Service)
+	for i, group := range groups {
+		services[priorityServiceName(name, group[0].priority)] = buildLoadBalancer(service, group)
+
+		if i == len(groups)-1 {
+			break
+		}
+
+		fallback := priorityServiceName(name, groups[i+1][0].priority)
+		if i+1 < len(groups)-1 {
+			fallback = failoverServiceName(name, i+1)
+		}
+
+		failoverName := name
+		if i > 0 {
+			failoverName = failoverServiceName(name, i)
+		}
+
+		services[failoverName] = &dynamic.Service{
+			Failover: &dynamic.Failover{
+				Service:  priorityServiceName(name, group[0].priority),
+				Fallback: fallback,
+			},
+		}
+	}
+
+	return services
+}
+
+func buildLoadBalancer(service *Service, endpoints []endpoint) *dynamic.Service {
+	lb := &dynamic.ServersLoadBalancer{}
+	lb.SetDefaults()
+
+	// Records with a zero weight only receive requests when all the records of their priority have a zero weight.
+	weighted := slices.ContainsFunc(endpoints, func(e endpoint) bool { return e.weight > 0 })
+
+	for _, e := range endpoints {
+		server := dynamic.Server{
+			URL: (&url.URL{Scheme: service.Scheme, Host: e.address()}).String(),
+		}
+
+		if weighted {
+			weight := int(e.weight)
+			server.Weight = &weight
+		}
+
+		lb.Servers = append(lb.Servers, server)
+	}
+
+	if service.HealthCheck != nil {
+		lb.HealthCheck = &dynamic.ServerHealthCheck{}
+		lb.HealthCheck.SetDefaults()
+		lb.HealthCheck.Path = service.HealthCheck.Path
+
+		if service.HealthCheck.Interval > 0 {
+			lb.HealthCheck.Interval = service.HealthCheck.Interval
+		}
+
+		if service.HealthCheck.Timeout > 0 {
+			lb.HealthCheck.Timeout = service.HealthCheck.Timeout
+		}
+	}
+
+	return &dynamic.Service{LoadBalancer: lb}
+}
+
+// groupByPriority groups the endpoints by priority, sorted by increasing priority.
+// The endpoints of a group are sorted by address, so that the resolution order does not change the configuration.
+func groupByPriority(endpoints []endpoint) [][]endpoint {
+	sorted := slices.Clone(endpoints)
+	slices.SortFunc(sorted, func(a, b endpoint) int {
+		return cmp.Or(
+			cmp.Compare(a.priority, b.priority),
+			cmp.Compare(a.ip, b.ip),
+			cmp.Compare(a.port, b.port),
+			cmp.Compare(a.weight, b.weight),
+		)
+	})
+	sorted = slices.Compact(sorted)
+
+	var groups [][]endpoint
+	for i, e := range sorted {
+		if i == 0 || e.priority != sorted[i-1].priority {
+			groups = append(groups, nil)
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], e)
+	}
+
+	return groups
+}
+
+func priorityServiceName(name string, priority uint16) string {
+	return name + "-priority-" + strconv.Itoa(int(priority))
+}
+
+func failoverServiceName(name string, index int) string {
+	return name + "-failover-" + strconv.Itoa(index)
+}
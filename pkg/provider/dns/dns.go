@@ -0,0 +1,241 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/provider"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+const providerName = "dns"
+
+// Types of records resolved for a service.
+const (
+	recordTypeSRV = "SRV"
+	recordTypeA   = "A"
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider is a provider.Provider implementation that builds services from DNS records.
+type Provider struct {
+	Services           map[string]*Service `description:"Defines the services to build from DNS records." json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	Nameservers        []string            `description:"Defines the DNS servers (host:port) to query. Defaults to the servers of the resolv.conf file." json:"nameservers,omitempty" toml:"nameservers,omitempty" yaml:"nameservers,omitempty" export:"true"`
+	ResolvConfig       string              `description:"Defines the resolv.conf file used when no DNS server is defined." json:"resolvConfig,omitempty" toml:"resolvConfig,omitempty" yaml:"resolvConfig,omitempty" export:"true"`
+	RefreshInterval    ptypes.Duration     `description:"Defines the maximum interval between two resolutions. The records are resolved again when their TTL expires, if sooner." json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+	MinRefreshInterval ptypes.Duration     `description:"Defines the minimum interval between two resolutions, whatever the TTL of the records." json:"minRefreshInterval,omitempty" toml:"minRefreshInterval,omitempty" yaml:"minRefreshInterval,omitempty" export:"true"`
+	Timeout            ptypes.Duration     `description:"Defines the timeout of the DNS queries." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+
+	resolver *resolver
+}
+
+// Service defines a service built from DNS records.
+type Service struct {
+	Record      string       `description:"Defines the name of the DNS records to resolve." json:"record,omitempty" toml:"record,omitempty" yaml:"record,omitempty" export:"true"`
+	Type        string       `description:"Defines the type of the DNS records to resolve: SRV, or A for the A and AAAA records." json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty" export:"true"`
+	Port        int          `description:"Defines the port of the servers. Required for A records, and overrides the port of SRV records." json:"port,omitempty" toml:"port,omitempty" yaml:"port,omitempty" export:"true"`
+	Scheme      string       `description:"Defines the scheme used to reach the servers." json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty" export:"true"`
+	HealthCheck *HealthCheck `description:"Enables the health check of the servers, required to fail over to the SRV records of lower priority." json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// HealthCheck defines the health check of the servers of a service.
+type HealthCheck struct {
+	Path     string          `description:"Defines the server URL path for the health check endpoint." json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	Interval ptypes.Duration `description:"Defines the frequency of the health check calls." json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	Timeout  ptypes.Duration `description:"Defines the maximum duration Traefik will wait for a health check request before considering the server unhealthy." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.ResolvConfig = "/etc/resolv.conf"
+	p.RefreshInterval = ptypes.Duration(30 * time.Second)
+	p.MinRefreshInterval = ptypes.Duration(5 * time.Second)
+	p.Timeout = ptypes.Duration(5 * time.Second)
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if len(p.Services) == 0 {
+		return errors.New("at least one service is required")
+	}
+
+	for name, service := range p.Services {
+		if service == nil {
+			return fmt.Errorf("service %q: record is required", name)
+		}
+
+		if err := service.init(); err != nil {
+			return fmt.Errorf("service %q: %w", name, err)
+		}
+	}
+
+	if p.RefreshInterval <= 0 {
+		return errors.New("refresh interval must be greater than 0")
+	}
+
+	if p.MinRefreshInterval < 0 || p.MinRefreshInterval > p.RefreshInterval {
+		return errors.New("min refresh interval must be positive and lower than the refresh interval")
+	}
+
+	nameservers := p.Nameservers
+	if len(nameservers) == 0 {
+		config, err := dns.ClientConfigFromFile(p.ResolvConfig)
+		if err != nil {
+			return fmt.Errorf("invalid resolver configuration file %s: %w", p.ResolvConfig, err)
+		}
+
+		for _, server := range config.Servers {
+			nameservers = append(nameservers, net.JoinHostPort(server, config.Port))
+		}
+	}
+
+	if len(nameservers) == 0 {
+		return errors.New("no DNS server defined")
+	}
+
+	p.resolver = &resolver{
+		client:      &dns.Client{Timeout: time.Duration(p.Timeout)},
+		nameservers: nameservers,
+	}
+
+	return nil
+}
+
+func (s *Service) init() error {
+	if s.Record == "" {
+		return errors.New("record is required")
+	}
+
+	s.Type = strings.ToUpper(s.Type)
+
+	switch s.Type {
+	case "":
+		s.Type = recordTypeSRV
+	case recordTypeSRV:
+	case recordTypeA:
+		if s.Port == 0 {
+			return errors.New("port is required for A records")
+		}
+	default:
+		return fmt.Errorf("unsupported record type %q", s.Type)
+	}
+
+	if s.Port < 0 || s.Port > 65535 {
+		return fmt.Errorf("invalid port %d", s.Port)
+	}
+
+	if s.Scheme == "" {
+		s.Scheme = "http"
+	}
+
+	return nil
+}
+
+// Provide allows the provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(ctx context.Context) {
+		logger := log.Ctx(ctx).With().Str(logs.ProviderName, providerName).Logger()
+		ctx = logger.WithContext(ctx)
+
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		endpoints := make(map[string][]endpoint)
+		var lastConfiguration *dynamic.Configuration
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			ttl := p.resolve(ctx, endpoints)
+
+			configuration := p.buildConfiguration(endpoints)
+			if !reflect.DeepEqual(configuration, lastConfiguration) {
+				lastConfiguration = configuration
+
+				select {
+				case <-ctx.Done():
+					return
+				case configurationChan <- dynamic.Message{
+					ProviderName:  providerName,
+					Configuration: configuration.DeepCopy(),
+				}:
+				}
+			}
+
+			timer.Reset(p.nextRefresh(ttl))
+		}
+	})
+
+	return nil
+}
+
+// resolve updates the endpoints of the services, and returns the lowest TTL of the resolved records.
+// The endpoints of a service which cannot be resolved are kept as is.
+func (p *Provider) resolve(ctx context.Context, endpoints map[string][]endpoint) time.Duration {
+	var ttl time.Duration
+
+	for _, name := range slices.Sorted(maps.Keys(p.Services)) {
+		service := p.Services[name]
+
+		var (
+			resolved   []endpoint
+			serviceTTL time.Duration
+			err        error
+		)
+		switch service.Type {
+		case recordTypeA:
+			resolved, serviceTTL, err = p.resolver.lookupA(ctx, service.Record, service.Port)
+		default:
+			resolved, serviceTTL, err = p.resolver.lookupSRV(ctx, service.Record)
+		}
+
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str(logs.ServiceName, name).Msgf("Cannot resolve %s records of %s", service.Type, service.Record)
+			continue
+		}
+
+		if len(resolved) == 0 {
+			log.Ctx(ctx).Warn().Str(logs.ServiceName, name).Msgf("No %s record found for %s", service.Type, service.Record)
+		}
+
+		if service.Port != 0 {
+			for i := range resolved {
+				resolved[i].port = service.Port
+			}
+		}
+
+		endpoints[name] = resolved
+
+		if serviceTTL > 0 && (ttl == 0 || serviceTTL < ttl) {
+			ttl = serviceTTL
+		}
+	}
+
+	return ttl
+}
+
+// nextRefresh returns the duration until the next resolution, honoring the TTL of the records within the refresh interval bounds.
+func (p *Provider) nextRefresh(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > time.Duration(p.RefreshInterval) {
+		return time.Duration(p.RefreshInterval)
+	}
+
+	return max(ttl, time.Duration(p.MinRefreshInterval))
+}
@@ -0,0 +1,384 @@
+package dns
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"k8s.io/utils/ptr"
+)
+
+// zone is an in-memory DNS zone served by a local DNS server.
+type zone struct {
+	mu      sync.Mutex
+	records []dns.RR
+}
+
+func (z *zone) set(t *testing.T, records ...string) {
+	t.Helper()
+
+	var rrs []dns.RR
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		require.NoError(t, err)
+
+		rrs = append(rrs, rr)
+	}
+
+	z.mu.Lock()
+	z.records = rrs
+	z.mu.Unlock()
+}
+
+func (z *zone) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	m := &dns.Msg{}
+	m.SetReply(r)
+
+	question := r.Question[0]
+
+	var found bool
+	for _, rr := range z.records {
+		if !strings.EqualFold(rr.Header().Name, question.Name) {
+			continue
+		}
+
+		found = true
+		if rr.Header().Rrtype == question.Qtype {
+			m.Answer = append(m.Answer, rr)
+		}
+	}
+
+	if !found {
+		m.Rcode = dns.RcodeNameError
+	}
+
+	_ = w.WriteMsg(m)
+}
+
+func startDNSServer(t *testing.T, z *zone) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: conn, Handler: z, NotifyStartedFunc: func() { close(started) }}
+
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	<-started
+
+	return conn.LocalAddr().String()
+}
+
+func TestProvider_Init(t *testing.T) {
+	tests := []struct {
+		desc     string
+		services map[string]*Service
+		expErr   bool
+	}{
+		{
+			desc:   "no service",
+			expErr: true,
+		},
+		{
+			desc:     "no record",
+			services: map[string]*Service{"whoami": {}},
+			expErr:   true,
+		},
+		{
+			desc:     "unsupported type",
+			services: map[string]*Service{"whoami": {Record: "whoami.test", Type: "MX"}},
+			expErr:   true,
+		},
+		{
+			desc:     "A record without port",
+			services: map[string]*Service{"whoami": {Record: "whoami.test", Type: "A"}},
+			expErr:   true,
+		},
+		{
+			desc:     "A record",
+			services: map[string]*Service{"whoami": {Record: "whoami.test", Type: "a", Port: 80}},
+		},
+		{
+			desc:     "SRV record",
+			services: map[string]*Service{"whoami": {Record: "_http._tcp.whoami.test"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var provider Provider
+			provider.SetDefaults()
+			provider.Services = test.services
+			provider.Nameservers = []string{"127.0.0.1:53"}
+
+			err := provider.Init()
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestProvider_nextRefresh(t *testing.T) {
+	provider := Provider{
+		RefreshInterval:    ptypes.Duration(30 * time.Second),
+		MinRefreshInterval: ptypes.Duration(5 * time.Second),
+	}
+
+	assert.Equal(t, 30*time.Second, provider.nextRefresh(0))
+	assert.Equal(t, 30*time.Second, provider.nextRefresh(time.Minute))
+	assert.Equal(t, 10*time.Second, provider.nextRefresh(10*time.Second))
+	assert.Equal(t, 5*time.Second, provider.nextRefresh(time.Second))
+}
+
+func TestProvider_resolve(t *testing.T) {
+	z := &zone{}
+	z.set(t,
+		"_http._tcp.whoami.test. 60 IN SRV 10 3 8080 a.whoami.test.",
+		"_http._tcp.whoami.test. 30 IN SRV 10 1 8081 b.whoami.test.",
+		"a.whoami.test. 60 IN A 10.0.0.1",
+		"b.whoami.test. 10 IN A 10.0.0.2",
+		"b.whoami.test. 60 IN AAAA ::2",
+		"whoami.test. 60 IN A 10.0.0.3",
+	)
+
+	var provider Provider
+	provider.SetDefaults()
+	provider.Nameservers = []string{startDNSServer(t, z)}
+	provider.Services = map[string]*Service{
+		"srv":     {Record: "_http._tcp.whoami.test"},
+		"a":       {Record: "whoami.test", Type: "A", Port: 80, Scheme: "https"},
+		"missing": {Record: "_http._tcp.missing.test"},
+	}
+
+	require.NoError(t, provider.Init())
+
+	endpoints := make(map[string][]endpoint)
+	ttl := provider.resolve(t.Context(), endpoints)
+
+	assert.Equal(t, 10*time.Second, ttl)
+
+	expected := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:     map[string]*dynamic.Router{},
+			Middlewares: map[string]*dynamic.Middleware{},
+			Services: map[string]*dynamic.Service{
+				"srv": {LoadBalancer: loadBalancer(
+					dynamic.Server{URL: "http://10.0.0.1:8080", Weight: ptr.To(3)},
+					dynamic.Server{URL: "http://10.0.0.2:8081", Weight: ptr.To(1)},
+					dynamic.Server{URL: "http://[::2]:8081", Weight: ptr.To(1)},
+				)},
+				"a": {LoadBalancer: loadBalancer(
+					dynamic.Server{URL: "https://10.0.0.3:80"},
+				)},
+				"missing": {LoadBalancer: loadBalancer()},
+			},
+			ServersTransports: map[string]*dynamic.ServersTransport{},
+		},
+	}
+
+	assert.Equal(t, expected, provider.buildConfiguration(endpoints))
+}
+
+func TestProvider_resolve_keepsEndpointsOnError(t *testing.T) {
+	var provider Provider
+	provider.SetDefaults()
+	provider.Timeout = ptypes.Duration(100 * time.Millisecond)
+	provider.Services = map[string]*Service{
+		"whoami": {Record: "_http._tcp.whoami.test"},
+	}
+
+	// Nothing answers on this address.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	provider.Nameservers = []string{conn.LocalAddr().String()}
+
+	require.NoError(t, provider.Init())
+
+	previous := []endpoint{{ip: "10.0.0.1", port: 8080}}
+	endpoints := map[string][]endpoint{"whoami": previous}
+
+	provider.resolve(t.Context(), endpoints)
+
+	assert.Equal(t, previous, endpoints["whoami"])
+}
+
+func Test_buildServices(t *testing.T) {
+	endpoints := []endpoint{
+		{ip: "10.0.0.3", port: 80, priority: 30},
+		{ip: "10.0.0.2", port: 80, priority: 20, weight: 0},
+		{ip: "10.0.0.1", port: 80, priority: 10, weight: 2},
+		{ip: "10.0.0.4", port: 80, priority: 10, weight: 0},
+	}
+
+	testCases := []struct {
+		desc     string
+		service  *Service
+		expected map[string]*dynamic.Service
+	}{
+		{
+			desc:    "without health check",
+			service: &Service{Scheme: "http"},
+			expected: map[string]*dynamic.Service{
+				"whoami": {LoadBalancer: loadBalancer(
+					dynamic.Server{URL: "http://10.0.0.1:80", Weight: ptr.To(2)},
+					dynamic.Server{URL: "http://10.0.0.4:80", Weight: ptr.To(0)},
+				)},
+			},
+		},
+		{
+			desc:    "with health check",
+			service: &Service{Scheme: "http", HealthCheck: &HealthCheck{Path: "/health"}},
+			expected: map[string]*dynamic.Service{
+				"whoami": {Failover: &dynamic.Failover{
+					Service:  "whoami-priority-10",
+					Fallback: "whoami-failover-1",
+				}},
+				"whoami-failover-1": {Failover: &dynamic.Failover{
+					Service:  "whoami-priority-20",
+					Fallback: "whoami-priority-30",
+				}},
+				"whoami-priority-10": {LoadBalancer: withHealthCheck(loadBalancer(
+					dynamic.Server{URL: "http://10.0.0.1:80", Weight: ptr.To(2)},
+					dynamic.Server{URL: "http://10.0.0.4:80", Weight: ptr.To(0)},
+				), "/health")},
+				"whoami-priority-20": {LoadBalancer: withHealthCheck(loadBalancer(
+					dynamic.Server{URL: "http://10.0.0.2:80"},
+				), "/health")},
+				"whoami-priority-30": {LoadBalancer: withHealthCheck(loadBalancer(
+					dynamic.Server{URL: "http://10.0.0.3:80"},
+				), "/health")},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, buildServices("whoami", test.service, endpoints))
+		})
+	}
+}
+
+func TestProvider_Provide(t *testing.T) {
+	z := &zone{}
+	z.set(t,
+		"_http._tcp.whoami.test. 1 IN SRV 10 1 8080 a.whoami.test.",
+		"a.whoami.test. 1 IN A 10.0.0.1",
+	)
+
+	var provider Provider
+	provider.SetDefaults()
+	provider.Nameservers = []string{startDNSServer(t, z)}
+	provider.RefreshInterval = ptypes.Duration(time.Second)
+	provider.MinRefreshInterval = ptypes.Duration(100 * time.Millisecond)
+	provider.Services = map[string]*Service{
+		"whoami": {Record: "_http._tcp.whoami.test"},
+	}
+
+	require.NoError(t, provider.Init())
+
+	configurationChan := make(chan dynamic.Message, 10)
+	require.NoError(t, provider.Provide(configurationChan, safe.NewPool(t.Context())))
+
+	msg := receive(t, configurationChan)
+	assert.Equal(t, "dns", msg.ProviderName)
+	assert.Equal(t, loadBalancer(dynamic.Server{URL: "http://10.0.0.1:8080", Weight: ptr.To(1)}), msg.Configuration.HTTP.Services["whoami"].LoadBalancer)
+
+	z.set(t,
+		"_http._tcp.whoami.test. 1 IN SRV 10 1 8080 a.whoami.test.",
+		"a.whoami.test. 1 IN A 10.0.0.2",
+	)
+
+	msg = receive(t, configurationChan)
+	assert.Equal(t, loadBalancer(dynamic.Server{URL: "http://10.0.0.2:8080", Weight: ptr.To(1)}), msg.Configuration.HTTP.Services["whoami"].LoadBalancer)
+
+	// The configuration is sent again only when the records change.
+	time.Sleep(1500 * time.Millisecond)
+	assert.Empty(t, configurationChan)
+}
+
+func TestProvider_Provide_stopWhileSending(t *testing.T) {
+	z := &zone{}
+	z.set(t, "whoami.test. 1 IN A 10.0.0.1")
+
+	var provider Provider
+	provider.SetDefaults()
+	provider.Nameservers = []string{startDNSServer(t, z)}
+	provider.Services = map[string]*Service{
+		"whoami": {Record: "whoami.test", Port: 80},
+	}
+
+	require.NoError(t, provider.Init())
+
+	// The configuration is never received.
+	configurationChan := make(chan dynamic.Message)
+
+	pool := safe.NewPool(t.Context())
+	require.NoError(t, provider.Provide(configurationChan, pool))
+
+	// Leaves time to the provider to resolve the records, and to block on sending the configuration.
+	time.Sleep(200 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the provider is not stopped while sending the configuration")
+	}
+}
+
+func receive(t *testing.T, configurationChan <-chan dynamic.Message) dynamic.Message {
+	t.Helper()
+
+	select {
+	case msg := <-configurationChan:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the configuration")
+		return dynamic.Message{}
+	}
+}
+
+func loadBalancer(servers ...dynamic.Server) *dynamic.ServersLoadBalancer {
+	lb := &dynamic.ServersLoadBalancer{}
+	lb.SetDefaults()
+	lb.Servers = servers
+
+	return lb
+}
+
+func withHealthCheck(lb *dynamic.ServersLoadBalancer, path string) *dynamic.ServersLoadBalancer {
+	lb.HealthCheck = &dynamic.ServerHealthCheck{}
+	lb.HealthCheck.SetDefaults()
+	lb.HealthCheck.Path = path
+
+	return lb
+}
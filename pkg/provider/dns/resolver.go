@@ -0,0 +1,176 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// endpoint is an address resolved for a service.
+type endpoint struct {
+	ip       string
+	port     int
+	priority uint16
+	weight   uint16
+}
+
+type resolver struct {
+	client      *dns.Client
+	nameservers []string
+}
+
+// lookupSRV resolves the SRV records of the given name, and the addresses of their targets.
+// It returns the endpoints, and the lowest TTL of the records.
+func (r *resolver) lookupSRV(ctx context.Context, name string) ([]endpoint, time.Duration, error) {
+	msg, err := r.exchange(ctx, name, dns.TypeSRV)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records := msg.Answer
+
+	// The addresses of the targets are usually provided in the additional section.
+	additional := make(map[string][]string)
+	for _, rr := range msg.Extra {
+		if ip := recordIP(rr); ip != "" {
+			additional[rr.Header().Name] = append(additional[rr.Header().Name], ip)
+			records = append(records, rr)
+		}
+	}
+
+	ttl := minTTL(records)
+
+	var endpoints []endpoint
+	for _, rr := range msg.Answer {
+		srv, ok := rr.(*dns.SRV)
+		if !ok {
+			continue
+		}
+
+		// A target of "." means that the service is decidedly not available at this domain (RFC 2782).
+		if srv.Target == "." {
+			continue
+		}
+
+		ips, ok := additional[srv.Target]
+		if !ok {
+			var targetTTL time.Duration
+			ips, targetTTL, err = r.lookupIPs(ctx, srv.Target)
+			if err != nil {
+				return nil, 0, fmt.Errorf("resolving target %s: %w", srv.Target, err)
+			}
+
+			if targetTTL > 0 && (ttl == 0 || targetTTL < ttl) {
+				ttl = targetTTL
+			}
+		}
+
+		for _, ip := range ips {
+			endpoints = append(endpoints, endpoint{
+				ip:       ip,
+				port:     int(srv.Port),
+				priority: srv.Priority,
+				weight:   srv.Weight,
+			})
+		}
+	}
+
+	return endpoints, ttl, nil
+}
+
+// lookupA resolves the A and AAAA records of the given name.
+// It returns the endpoints, and the lowest TTL of the records.
+func (r *resolver) lookupA(ctx context.Context, name string, port int) ([]endpoint, time.Duration, error) {
+	ips, ttl, err := r.lookupIPs(ctx, name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var endpoints []endpoint
+	for _, ip := range ips {
+		endpoints = append(endpoints, endpoint{ip: ip, port: port})
+	}
+
+	return endpoints, ttl, nil
+}
+
+func (r *resolver) lookupIPs(ctx context.Context, name string) ([]string, time.Duration, error) {
+	var (
+		ips     []string
+		answers []dns.RR
+	)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		msg, err := r.exchange(ctx, name, qtype)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, rr := range msg.Answer {
+			if ip := recordIP(rr); ip != "" {
+				ips = append(ips, ip)
+				answers = append(answers, rr)
+			}
+		}
+	}
+
+	return ips, minTTL(answers), nil
+}
+
+// exchange queries the DNS servers in turn until one of them answers.
+// A name which does not exist results in a message without answers.
+func (r *resolver) exchange(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+
+	var errs []error
+	for _, server := range r.nameservers {
+		resp, _, err := r.client.ExchangeContext(ctx, msg, server)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("exchange error for server %s: %w", server, err))
+			continue
+		}
+
+		switch resp.Rcode {
+		case dns.RcodeSuccess:
+			return resp, nil
+		case dns.RcodeNameError:
+			return &dns.Msg{}, nil
+		default:
+			errs = append(errs, fmt.Errorf("server %s answered %s", server, dns.RcodeToString[resp.Rcode]))
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func recordIP(rr dns.RR) string {
+	switch record := rr.(type) {
+	case *dns.A:
+		return record.A.String()
+	case *dns.AAAA:
+		return record.AAAA.String()
+	default:
+		return ""
+	}
+}
+
+func minTTL(records []dns.RR) time.Duration {
+	var ttl time.Duration
+	for _, rr := range records {
+		recordTTL := time.Duration(rr.Header().Ttl) * time.Second
+		if recordTTL > 0 && (ttl == 0 || recordTTL < ttl) {
+			ttl = recordTTL
+		}
+	}
+
+	return ttl
+}
+
+func (e endpoint) address() string {
+	return net.JoinHostPort(e.ip, strconv.Itoa(e.port))
+}
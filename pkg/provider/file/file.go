@@ -21,6 +21,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
 	"github.com/traefik/traefik/v3/pkg/provider"
+	"github.com/traefik/traefik/v3/pkg/provider/constraints"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -36,6 +37,7 @@ type Provider struct {
 	Watch                     bool   `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
 	Filename                  string `description:"Load dynamic configuration from a file." json:"filename,omitempty" toml:"filename,omitempty" yaml:"filename,omitempty" export:"true"`
 	DebugLogGeneratedTemplate bool   `description:"Enable debug logging of generated configuration template." json:"debugLogGeneratedTemplate,omitempty" toml:"debugLogGeneratedTemplate,omitempty" yaml:"debugLogGeneratedTemplate,omitempty" export:"true"`
+	Constraints               string `description:"Constraints is an expression that Traefik matches against the labels of the routers, services and middlewares to determine whether to load them." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -46,6 +48,10 @@ func (p *Provider) SetDefaults() {
 
 // Init the provider.
 func (p *Provider) Init() error {
+	if _, err := constraints.MatchLabels(nil, p.Constraints); err != nil {
+		return fmt.Errorf("invalid constraints expression: %w", err)
+	}
+
 	return nil
 }
 
@@ -206,7 +212,7 @@ func (p *Provider) addWatcher(pool *safe.Pool, items []string, configurationChan
 
 // applyConfiguration builds the configuration and sends it to the given configurationChan.
 func (p *Provider) applyConfiguration(configurationChan chan<- dynamic.Message) error {
-	configuration, err := p.buildConfiguration()
+	configuration, err := p.BuildConfiguration()
 	if err != nil {
 		return err
 	}
@@ -215,11 +221,24 @@ func (p *Provider) applyConfiguration(configurationChan chan<- dynamic.Message)
 	return nil
 }
 
-// buildConfiguration loads configuration either from file or a directory
+// BuildConfiguration loads configuration either from file or a directory
 // specified by 'Filename'/'Directory' and returns a 'Configuration' object.
-func (p *Provider) buildConfiguration() (*dynamic.Configuration, error) {
+func (p *Provider) BuildConfiguration() (*dynamic.Configuration, error) {
 	ctx := log.With().Str(logs.ProviderName, providerName).Logger().WithContext(context.Background())
 
+	configuration, err := p.buildConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := constraints.FilterConfiguration(ctx, configuration, p.Constraints); err != nil {
+		return nil, fmt.Errorf("applying constraints: %w", err)
+	}
+
+	return configuration, nil
+}
+
+func (p *Provider) buildConfiguration(ctx context.Context) (*dynamic.Configuration, error) {
 	if len(p.Directory) > 0 {
 		configurations, err := p.collectFileConfigs(ctx, p.Directory, "")
 		if err != nil {
@@ -86,6 +86,61 @@ func TestTLSCertificateContent(t *testing.T) {
 	require.Equal(t, "CONTENT", configuration.TCP.ServersTransports["default"].TLS.RootCAs[0].String())
 }
 
+func TestBuildConfiguration_constraints(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dynamic.yml")
+	err := os.WriteFile(filename, []byte(`
+http:
+  routers:
+    foo:
+      rule: Host(`+"`foo.localhost`"+`)
+      service: foo
+      labels:
+        env: prod
+    bar:
+      rule: Host(`+"`bar.localhost`"+`)
+      service: foo
+      labels:
+        env: staging
+  middlewares:
+    shared:
+      stripPrefix:
+        prefixes: [/foo]
+  services:
+    foo:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1:8000
+      labels:
+        env: prod
+udp:
+  routers:
+    baz:
+      service: baz
+      labels:
+        env: staging
+`), 0o644)
+	require.NoError(t, err)
+
+	provider := &Provider{
+		Filename: filename,
+		// The elements without env label are shared by all the environments.
+		Constraints: "Label(`env`, `prod`) || !LabelRegex(`env`, `.+`)",
+	}
+	require.NoError(t, provider.Init())
+
+	configuration, err := provider.BuildConfiguration()
+	require.NoError(t, err)
+
+	assert.Len(t, configuration.HTTP.Routers, 1)
+	assert.Contains(t, configuration.HTTP.Routers, "foo")
+	assert.Contains(t, configuration.HTTP.Middlewares, "shared")
+	assert.Contains(t, configuration.HTTP.Services, "foo")
+	assert.Empty(t, configuration.UDP.Routers)
+
+	provider.Constraints = "Label("
+	require.Error(t, provider.Init())
+}
+
 func TestErrorWhenEmptyConfig(t *testing.T) {
 	provider := &Provider{}
 	configChan := make(chan dynamic.Message)
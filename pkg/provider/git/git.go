@@ -0,0 +1,309 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/provider"
+	"github.com/traefik/traefik/v3/pkg/provider/file"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+const providerName = "git"
+
+// urlPattern matches the URLs in the git messages, which can hold credentials.
+var urlPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^\s'"]+`)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider is a provider.Provider implementation that loads the dynamic configuration from a Git repository.
+type Provider struct {
+	Repository                string          `description:"Defines the URL or the local path of the Git repository." json:"repository,omitempty" toml:"repository,omitempty" yaml:"repository,omitempty" loggable:"false"`
+	Ref                       string          `description:"Defines the branch, tag, or commit to check out. Defaults to the default branch of the repository." json:"ref,omitempty" toml:"ref,omitempty" yaml:"ref,omitempty" export:"true"`
+	Directory                 string          `description:"Load dynamic configuration from one or more .yml or .toml files in a directory of the repository." json:"directory,omitempty" toml:"directory,omitempty" yaml:"directory,omitempty" export:"true"`
+	Filename                  string          `description:"Load dynamic configuration from a file of the repository." json:"filename,omitempty" toml:"filename,omitempty" yaml:"filename,omitempty" export:"true"`
+	CloneDirectory            string          `description:"Defines the local directory where the repository is cloned. Defaults to a directory in the temporary directory." json:"cloneDirectory,omitempty" toml:"cloneDirectory,omitempty" yaml:"cloneDirectory,omitempty" export:"true"`
+	PollInterval              ptypes.Duration `description:"Defines the interval between two pulls of the repository. Zero disables polling." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
+	Webhook                   *Webhook        `description:"Enables the webhook triggering the pulls of the repository." json:"webhook,omitempty" toml:"webhook,omitempty" yaml:"webhook,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	DebugLogGeneratedTemplate bool            `description:"Enable debug logging of generated configuration template." json:"debugLogGeneratedTemplate,omitempty" toml:"debugLogGeneratedTemplate,omitempty" yaml:"debugLogGeneratedTemplate,omitempty" export:"true"`
+
+	// refresh triggers a pull of the repository.
+	refresh chan struct{}
+
+	statusMu sync.RWMutex
+	status   Status
+}
+
+// Webhook holds the webhook configuration.
+type Webhook struct {
+	Insecure bool   `description:"Activates the webhook directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Secret   string `description:"Defines the secret used to authenticate the webhook calls." json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty" loggable:"false"`
+}
+
+// Status describes the state of the repository checkout.
+type Status struct {
+	Repository string    `json:"repository"`
+	Ref        string    `json:"ref,omitempty"`
+	Commit     string    `json:"commit,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt,omitzero"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.PollInterval = ptypes.Duration(time.Minute)
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if p.Repository == "" {
+		return errors.New("non-empty repository is required")
+	}
+
+	if (p.Directory == "") == (p.Filename == "") {
+		return errors.New("exactly one of directory or filename must be defined")
+	}
+
+	if p.PollInterval < 0 {
+		return errors.New("poll interval must be positive")
+	}
+
+	if p.CloneDirectory == "" {
+		hash := sha256.Sum256([]byte(p.Repository))
+		p.CloneDirectory = filepath.Join(os.TempDir(), "traefik-git-"+hex.EncodeToString(hash[:8]))
+	}
+
+	p.refresh = make(chan struct{}, 1)
+	p.status = Status{Repository: redactRepository(p.Repository), Ref: p.Ref}
+
+	return nil
+}
+
+// Provide allows the provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(ctx context.Context) {
+		logger := log.Ctx(ctx).With().Str(logs.ProviderName, providerName).Logger()
+		ctx = logger.WithContext(ctx)
+
+		var tick <-chan time.Time
+		if p.PollInterval > 0 {
+			ticker := time.NewTicker(time.Duration(p.PollInterval))
+			defer ticker.Stop()
+
+			tick = ticker.C
+		}
+
+		var lastCommit string
+		for {
+			commit, err := p.update(ctx, configurationChan, lastCommit)
+			if err != nil {
+				logger.Error().Err(err).Msg("Cannot update the configuration from the repository")
+			} else {
+				lastCommit = commit
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+			case <-p.refresh:
+			}
+		}
+	})
+
+	return nil
+}
+
+// Refresh triggers a pull of the repository, unless one is already pending.
+func (p *Provider) Refresh() {
+	select {
+	case p.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// Status returns the state of the repository checkout.
+func (p *Provider) Status() Status {
+	p.statusMu.RLock()
+	defer p.statusMu.RUnlock()
+
+	return p.status
+}
+
+// update pulls the repository, and sends the configuration if the checked out commit is not the last one.
+func (p *Provider) update(ctx context.Context, configurationChan chan<- dynamic.Message, lastCommit string) (string, error) {
+	commit, err := p.checkout(ctx)
+	if err != nil {
+		p.setStatus("", err)
+		return "", err
+	}
+
+	if commit == lastCommit {
+		p.setStatus(commit, nil)
+		return commit, nil
+	}
+
+	fileProvider := &file.Provider{
+		DebugLogGeneratedTemplate: p.DebugLogGeneratedTemplate,
+	}
+	if p.Directory != "" {
+		fileProvider.Directory = filepath.Join(p.CloneDirectory, p.Directory)
+	} else {
+		fileProvider.Filename = filepath.Join(p.CloneDirectory, p.Filename)
+	}
+
+	configuration, err := fileProvider.BuildConfiguration()
+	if err != nil {
+		err = fmt.Errorf("building configuration of commit %s: %w", commit, err)
+		p.setStatus("", err)
+		return "", err
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Configuration loaded from commit %s", commit)
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case configurationChan <- dynamic.Message{ProviderName: providerName, Configuration: configuration}:
+	}
+
+	p.setStatus(commit, nil)
+
+	return commit, nil
+}
+
+func (p *Provider) setStatus(commit string, err error) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+
+	if err != nil {
+		p.status.Error = err.Error()
+		return
+	}
+
+	if commit != p.status.Commit {
+		p.status.UpdatedAt = time.Now()
+	}
+
+	p.status.Commit = commit
+	p.status.Error = ""
+}
+
+// checkout fetches the repository, checks out the configured reference, and returns the checked out commit.
+func (p *Provider) checkout(ctx context.Context) (string, error) {
+	if _, err := os.Stat(filepath.Join(p.CloneDirectory, ".git")); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(p.CloneDirectory, 0o700); err != nil {
+			return "", err
+		}
+
+		if _, err := p.git(ctx, "init", "--quiet"); err != nil {
+			return "", err
+		}
+
+		if _, err := p.git(ctx, "remote", "add", "origin", p.Repository); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	} else if _, err := p.git(ctx, "remote", "set-url", "origin", p.Repository); err != nil {
+		return "", err
+	}
+
+	if _, err := p.git(ctx, "fetch", "--quiet", "--force", "--prune", "--tags", "origin", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return "", err
+	}
+
+	commit, err := p.resolveRef(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := p.git(ctx, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return "", err
+	}
+
+	if _, err := p.git(ctx, "clean", "--quiet", "-ffdx"); err != nil {
+		return "", err
+	}
+
+	return commit, nil
+}
+
+// resolveRef returns the commit targeted by the configured reference,
+// which is either a branch, a tag, or a commit.
+func (p *Provider) resolveRef(ctx context.Context) (string, error) {
+	var candidates []string
+	if p.Ref == "" {
+		if _, err := p.git(ctx, "remote", "set-head", "origin", "--auto"); err != nil {
+			return "", err
+		}
+
+		candidates = []string{"refs/remotes/origin/HEAD"}
+	} else {
+		candidates = []string{"refs/remotes/origin/" + p.Ref, "refs/tags/" + p.Ref, p.Ref}
+	}
+
+	for _, candidate := range candidates {
+		commit, err := p.git(ctx, "rev-parse", "--quiet", "--verify", candidate+"^{commit}")
+		if err == nil {
+			return commit, nil
+		}
+	}
+
+	return "", fmt.Errorf("reference %q not found", p.Ref)
+}
+
+// git runs a git command in the clone directory, and returns its trimmed output.
+func (p *Provider) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = p.CloneDirectory
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// The error is exposed in the status of the provider, so the credentials of the URLs are removed from the git messages.
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, redactURLs(strings.TrimSpace(stderr.String())))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// redactURLs removes the credentials from the URLs of the given git message.
+func redactURLs(message string) string {
+	return urlPattern.ReplaceAllStringFunc(message, redactRepository)
+}
+
+// redactRepository removes the credentials from the repository URL.
+// The user name is redacted as well when there is no password, as it is then usually a token.
+func redactRepository(repository string) string {
+	u, err := url.Parse(repository)
+	if err != nil || u.User == nil {
+		return repository
+	}
+
+	if _, ok := u.User.Password(); !ok {
+		u.User = url.User("xxxxx")
+		return u.String()
+	}
+
+	return u.Redacted()
+}
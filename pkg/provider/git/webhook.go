@@ -0,0 +1,66 @@
+package git
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// maxWebhookBodySize is the maximum size of the webhook payloads, which are only read to check their signature.
+const maxWebhookBodySize = 10 << 20
+
+// CreateRouter creates a router for the webhook.
+func (p *Provider) CreateRouter() *mux.Router {
+	router := mux.NewRouter()
+	router.Methods(http.MethodPost).Path("/api/git/webhook").HandlerFunc(p.serveWebhook)
+	return router
+}
+
+// serveWebhook triggers a pull of the repository,
+// when the request is authenticated by either a GitHub signature or a GitLab token.
+func (p *Provider) serveWebhook(rw http.ResponseWriter, req *http.Request) {
+	if p.Webhook != nil && p.Webhook.Secret != "" {
+		body, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxWebhookBodySize))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !authenticate(req, body, p.Webhook.Secret) {
+			http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	p.Refresh()
+
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+func authenticate(req *http.Request, body []byte, secret string) bool {
+	if token := req.Header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+
+	signature, ok := strings.CutPrefix(req.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	// purposely ignoring the error, as no error can be returned from the implementation.
+	_, _ = mac.Write(body)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
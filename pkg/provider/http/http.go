@@ -2,11 +2,17 @@ package http
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"hash/fnv"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,24 +33,49 @@ var _ provider.Provider = (*Provider)(nil)
 
 const defaultMaxResponseBodySize = -1
 
+// Modes of watching the endpoint for configuration changes.
+const (
+	modePoll     = "poll"
+	modeLongPoll = "longPoll"
+	modeSSE      = "sse"
+)
+
+// signatureHeader is the header holding the base64 encoded Ed25519 signature of the configuration.
+const signatureHeader = "X-Traefik-Signature"
+
 // Provider is a provider.Provider implementation that queries an HTTP(s) endpoint for a configuration.
 type Provider struct {
-	Endpoint     string            `description:"Load configuration from this endpoint." json:"endpoint" toml:"endpoint" yaml:"endpoint"`
-	PollInterval ptypes.Duration   `description:"Polling interval for endpoint." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
-	PollTimeout  ptypes.Duration   `description:"Polling timeout for endpoint." json:"pollTimeout,omitempty" toml:"pollTimeout,omitempty" yaml:"pollTimeout,omitempty" export:"true"`
-	Headers      map[string]string `description:"Define custom headers to be sent to the endpoint." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
-	TLS          *types.ClientTLS  `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Endpoint     string              `description:"Load configuration from this endpoint." json:"endpoint" toml:"endpoint" yaml:"endpoint"`
+	PollInterval ptypes.Duration     `description:"Polling interval for endpoint." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
+	PollTimeout  ptypes.Duration     `description:"Polling timeout for endpoint." json:"pollTimeout,omitempty" toml:"pollTimeout,omitempty" yaml:"pollTimeout,omitempty" export:"true"`
+	Headers      map[string]string   `description:"Define custom headers to be sent to the endpoint." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	TLS          *types.ClientTLS    `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Mode         string              `description:"Defines how the endpoint is watched for configuration changes: poll, longPoll or sse." json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
+	SignatureKey types.FileOrContent `description:"Defines the PEM encoded Ed25519 public key verifying the signature of the configuration." json:"signatureKey,omitempty" toml:"signatureKey,omitempty" yaml:"signatureKey,omitempty"`
 
 	httpClient            *http.Client
+	streamClient          *http.Client
+	signatureKey          ed25519.PublicKey
 	lastConfigurationHash uint64
+	lastETag              string
 	MaxResponseBodySize   int64 `description:"Defines the maximum size of the response body in bytes." json:"maxResponseBodySize,omitempty" toml:"maxResponseBodySize,omitempty" yaml:"maxResponseBodySize,omitempty" export:"true"`
 }
 
+// payload is a configuration received from the endpoint.
+type payload struct {
+	data []byte
+	// format is the extension of the configuration format (.yaml or .toml), JSON being decoded as YAML.
+	format    string
+	signature string
+	etag      string
+}
+
 // SetDefaults sets the default values.
 func (p *Provider) SetDefaults() {
 	p.PollInterval = ptypes.Duration(5 * time.Second)
 	p.PollTimeout = ptypes.Duration(5 * time.Second)
 	p.MaxResponseBodySize = defaultMaxResponseBodySize
+	p.Mode = modePoll
 }
 
 // Init the provider.
@@ -57,10 +88,24 @@ func (p *Provider) Init() error {
 		return errors.New("poll interval must be greater than 0")
 	}
 
+	timeout := time.Duration(p.PollTimeout)
+
+	switch p.Mode {
+	case "", modePoll, modeSSE:
+	case modeLongPoll:
+		// The endpoint holds the request up to the poll interval.
+		timeout += time.Duration(p.PollInterval)
+	default:
+		return fmt.Errorf("unsupported mode %q", p.Mode)
+	}
+
 	p.httpClient = &http.Client{
-		Timeout: time.Duration(p.PollTimeout),
+		Timeout: timeout,
 	}
 
+	// The event stream is long-lived, and is only interrupted when the provider stops.
+	p.streamClient = &http.Client{}
+
 	if p.TLS != nil {
 		tlsConfig, err := p.TLS.CreateTLSConfig(context.Background())
 		if err != nil {
@@ -70,6 +115,16 @@ func (p *Provider) Init() error {
 		p.httpClient.Transport = &http.Transport{
 			TLSClientConfig: tlsConfig,
 		}
+		p.streamClient.Transport = p.httpClient.Transport
+	}
+
+	if p.SignatureKey != "" {
+		key, err := parseSignatureKey(p.SignatureKey)
+		if err != nil {
+			return fmt.Errorf("unable to parse signature key: %w", err)
+		}
+
+		p.signatureKey = key
 	}
 
 	return nil
@@ -82,23 +137,13 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		ctxLog := logger.WithContext(routineCtx)
 
 		operation := func() error {
-			if err := p.updateConfiguration(configurationChan); err != nil {
-				return err
-			}
-
-			ticker := time.NewTicker(time.Duration(p.PollInterval))
-			defer ticker.Stop()
-
-			for {
-				select {
-				case <-ticker.C:
-					if err := p.updateConfiguration(configurationChan); err != nil {
-						return err
-					}
-
-				case <-routineCtx.Done():
-					return nil
-				}
+			switch p.Mode {
+			case modeSSE:
+				return p.stream(routineCtx, configurationChan)
+			case modeLongPoll:
+				return p.longPoll(routineCtx, configurationChan)
+			default:
+				return p.poll(routineCtx, configurationChan)
 			}
 		}
 
@@ -114,51 +159,136 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	return nil
 }
 
-func (p *Provider) updateConfiguration(configurationChan chan<- dynamic.Message) error {
-	configData, err := p.fetchConfigurationData()
+// poll fetches the configuration at each poll interval.
+func (p *Provider) poll(ctx context.Context, configurationChan chan<- dynamic.Message) error {
+	if err := p.updateConfiguration(ctx, configurationChan, 0); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Duration(p.PollInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.updateConfiguration(ctx, configurationChan, 0); err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// longPoll fetches the configuration continuously,
+// the endpoint being expected to hold the requests until the configuration changes, or up to the poll interval.
+func (p *Provider) longPoll(ctx context.Context, configurationChan chan<- dynamic.Message) error {
+	wait := time.Duration(p.PollInterval)
+
+	for {
+		start := time.Now()
+		lastConfigurationHash := p.lastConfigurationHash
+
+		if err := p.updateConfiguration(ctx, configurationChan, wait); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		// An endpoint not supporting long polling answers immediately,
+		// it is then polled at the poll interval.
+		var delay time.Duration
+		if p.lastConfigurationHash == lastConfigurationHash {
+			delay = wait - time.Since(start)
+		}
+
+		if delay <= 0 {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (p *Provider) updateConfiguration(ctx context.Context, configurationChan chan<- dynamic.Message, wait time.Duration) error {
+	configPayload, err := p.fetchConfigurationData(ctx, wait)
 	if err != nil {
 		return fmt.Errorf("cannot fetch configuration data: %w", err)
 	}
 
+	// The configuration has not been modified.
+	if configPayload == nil {
+		return nil
+	}
+
+	return p.processPayload(ctx, configurationChan, configPayload)
+}
+
+// processPayload verifies and decodes the given payload,
+// and sends the configuration if it has changed since the last one.
+func (p *Provider) processPayload(ctx context.Context, configurationChan chan<- dynamic.Message, configPayload *payload) error {
+	if p.signatureKey != nil {
+		if err := verifySignature(p.signatureKey, configPayload); err != nil {
+			return fmt.Errorf("cannot verify configuration signature: %w", err)
+		}
+	}
+
 	fnvHasher := fnv.New64()
 
-	if _, err = fnvHasher.Write(configData); err != nil {
+	if _, err := fnvHasher.Write(configPayload.data); err != nil {
 		return fmt.Errorf("cannot hash configuration data: %w", err)
 	}
 
 	hash := fnvHasher.Sum64()
 	if hash == p.lastConfigurationHash {
+		p.lastETag = configPayload.etag
 		return nil
 	}
 
-	p.lastConfigurationHash = hash
-
-	configuration, err := decodeConfiguration(configData)
+	configuration, err := decodeConfiguration(configPayload.data, configPayload.format)
 	if err != nil {
 		return fmt.Errorf("cannot decode configuration data: %w", err)
 	}
 
-	configurationChan <- dynamic.Message{
+	select {
+	case <-ctx.Done():
+		return nil
+	case configurationChan <- dynamic.Message{
 		ProviderName:  "http",
 		Configuration: configuration,
+	}:
 	}
 
+	p.lastConfigurationHash = hash
+	p.lastETag = configPayload.etag
+
 	return nil
 }
 
 // fetchConfigurationData fetches the configuration data from the configured endpoint.
-func (p *Provider) fetchConfigurationData() ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, p.Endpoint, http.NoBody)
+// It returns a nil payload when the configuration has not been modified since the last fetch.
+// A non-zero wait asks the endpoint to hold the request until the configuration changes, or up to the given duration.
+func (p *Provider) fetchConfigurationData(ctx context.Context, wait time.Duration) (*payload, error) {
+	req, err := p.newRequest(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("create fetch request: %w", err)
 	}
 
-	for k, v := range p.Headers {
-		if strings.EqualFold(k, "Host") {
-			req.Host = v
-		} else {
-			req.Header.Set(k, v)
-		}
+	if p.lastETag != "" {
+		req.Header.Set("If-None-Match", p.lastETag)
+	}
+
+	if wait > 0 {
+		req.Header.Set("Prefer", "wait="+strconv.Itoa(int(wait.Seconds())))
 	}
 
 	res, err := p.httpClient.Do(req)
@@ -168,12 +298,27 @@ func (p *Provider) fetchConfigurationData() ([]byte, error) {
 
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+
 	if res.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("received non-ok response code: %d", res.StatusCode)
 	}
 
+	configPayload := &payload{
+		format:    formatFromContentType(res.Header.Get("Content-Type")),
+		signature: res.Header.Get(signatureHeader),
+		etag:      res.Header.Get("ETag"),
+	}
+
 	if p.MaxResponseBodySize < 0 {
-		return io.ReadAll(res.Body)
+		configPayload.data, err = io.ReadAll(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading response body: %w", err)
+		}
+
+		return configPayload, nil
 	}
 
 	data, err := io.ReadAll(io.LimitReader(res.Body, p.MaxResponseBodySize+1))
@@ -184,11 +329,86 @@ func (p *Provider) fetchConfigurationData() ([]byte, error) {
 		return nil, errors.New("response body too large")
 	}
 
-	return data, nil
+	configPayload.data = data
+
+	return configPayload, nil
+}
+
+func (p *Provider) newRequest(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Endpoint, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range p.Headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+		} else {
+			req.Header.Set(k, v)
+		}
+	}
+
+	return req, nil
+}
+
+// formatFromContentType returns the configuration format matching the given content type.
+func formatFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ".yaml"
+	}
+
+	switch mediaType {
+	case "application/toml", "text/toml":
+		return ".toml"
+	default:
+		return ".yaml"
+	}
+}
+
+func parseSignatureKey(signatureKey types.FileOrContent) (ed25519.PublicKey, error) {
+	data, err := signatureKey.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T, only Ed25519 keys are supported", key)
+	}
+
+	return edKey, nil
+}
+
+func verifySignature(key ed25519.PublicKey, configPayload *payload) error {
+	if configPayload.signature == "" {
+		return errors.New("missing signature")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(configPayload.signature)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+
+	if !ed25519.Verify(key, configPayload.data, signature) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
 }
 
-// decodeConfiguration decodes and returns the dynamic configuration from the given data.
-func decodeConfiguration(data []byte) (*dynamic.Configuration, error) {
+// decodeConfiguration decodes and returns the dynamic configuration from the given data, in the given format.
+func decodeConfiguration(data []byte, format string) (*dynamic.Configuration, error) {
 	configuration := &dynamic.Configuration{
 		HTTP: &dynamic.HTTPConfiguration{
 			Routers:           make(map[string]*dynamic.Router),
@@ -211,7 +431,7 @@ func decodeConfiguration(data []byte) (*dynamic.Configuration, error) {
 		},
 	}
 
-	err := file.DecodeContent(string(data), ".yaml", configuration)
+	err := file.DecodeContent(string(data), format, configuration)
 	if err != nil {
 		return nil, err
 	}
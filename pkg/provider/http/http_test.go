@@ -1,10 +1,17 @@
 package http
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,6 +21,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
 	"k8s.io/utils/ptr"
 )
 
@@ -22,6 +30,8 @@ func TestProvider_Init(t *testing.T) {
 		desc         string
 		endpoint     string
 		pollInterval ptypes.Duration
+		mode         string
+		signatureKey types.FileOrContent
 		expErr       bool
 	}{
 		{
@@ -33,12 +43,33 @@ func TestProvider_Init(t *testing.T) {
 			endpoint: "http://localhost:8080",
 			expErr:   true,
 		},
+		{
+			desc:         "should return an error if mode is unsupported",
+			endpoint:     "http://localhost:8080",
+			pollInterval: ptypes.Duration(time.Second),
+			mode:         "foo",
+			expErr:       true,
+		},
+		{
+			desc:         "should return an error if signature key is invalid",
+			endpoint:     "http://localhost:8080",
+			pollInterval: ptypes.Duration(time.Second),
+			signatureKey: "foo",
+			expErr:       true,
+		},
 		{
 			desc:         "should not return an error",
 			endpoint:     "http://localhost:8080",
 			pollInterval: ptypes.Duration(time.Second),
 			expErr:       false,
 		},
+		{
+			desc:         "should not return an error in sse mode",
+			endpoint:     "http://localhost:8080",
+			pollInterval: ptypes.Duration(time.Second),
+			mode:         "sse",
+			expErr:       false,
+		},
 	}
 
 	for _, test := range tests {
@@ -46,6 +77,8 @@ func TestProvider_Init(t *testing.T) {
 			provider := &Provider{
 				Endpoint:     test.endpoint,
 				PollInterval: test.pollInterval,
+				Mode:         test.mode,
+				SignatureKey: test.signatureKey,
 			}
 
 			err := provider.Init()
@@ -67,6 +100,7 @@ func TestProvider_SetDefaults(t *testing.T) {
 	assert.Equal(t, provider.PollInterval, ptypes.Duration(5*time.Second))
 	assert.Equal(t, provider.PollTimeout, ptypes.Duration(5*time.Second))
 	assert.Equal(t, int64(-1), provider.MaxResponseBodySize)
+	assert.Equal(t, "poll", provider.Mode)
 }
 
 func TestProvider_fetchConfigurationData(t *testing.T) {
@@ -154,11 +188,16 @@ func TestProvider_fetchConfigurationData(t *testing.T) {
 			err := provider.Init()
 			require.NoError(t, err)
 
-			configData, err := provider.fetchConfigurationData()
+			configPayload, err := provider.fetchConfigurationData(t.Context(), 0)
 			test.expErr(t, err)
 
 			assert.True(t, handlerCalled)
-			assert.Equal(t, test.expData, configData)
+			if test.expData == nil {
+				assert.Nil(t, configPayload)
+				return
+			}
+
+			assert.Equal(t, test.expData, configPayload.data)
 		})
 	}
 }
@@ -167,6 +206,7 @@ func TestProvider_decodeConfiguration(t *testing.T) {
 	tests := []struct {
 		desc       string
 		configData []byte
+		format     string
 		expConfig  *dynamic.Configuration
 		expErr     bool
 	}{
@@ -174,10 +214,40 @@ func TestProvider_decodeConfiguration(t *testing.T) {
 			desc:       "should return an error if the configuration data cannot be decoded",
 			expErr:     true,
 			configData: []byte("{"),
+			format:     ".yaml",
 		},
 		{
 			desc:       "should return the decoded dynamic configuration",
 			configData: []byte(`{"tcp":{"routers":{"foo":{}}}}`),
+			format:     ".yaml",
+			expConfig: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:           make(map[string]*dynamic.Router),
+					Middlewares:       make(map[string]*dynamic.Middleware),
+					Services:          make(map[string]*dynamic.Service),
+					ServersTransports: make(map[string]*dynamic.ServersTransport),
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"foo": {},
+					},
+					Services:          make(map[string]*dynamic.TCPService),
+					ServersTransports: map[string]*dynamic.TCPServersTransport{},
+				},
+				TLS: &dynamic.TLSConfiguration{
+					Stores:  make(map[string]tls.Store),
+					Options: make(map[string]tls.Options),
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  make(map[string]*dynamic.UDPRouter),
+					Services: make(map[string]*dynamic.UDPService),
+				},
+			},
+		},
+		{
+			desc:       "should return the decoded dynamic configuration from TOML",
+			configData: []byte("[tcp.routers.foo]\n"),
+			format:     ".toml",
 			expConfig: &dynamic.Configuration{
 				HTTP: &dynamic.HTTPConfiguration{
 					Routers:           make(map[string]*dynamic.Router),
@@ -206,7 +276,7 @@ func TestProvider_decodeConfiguration(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			configuration, err := decodeConfiguration(test.configData)
+			configuration, err := decodeConfiguration(test.configData, test.format)
 			if test.expErr {
 				require.Error(t, err)
 				return
@@ -303,3 +373,199 @@ func TestProvider_ProvideConfigurationOnlyOnceIfUnchanged(t *testing.T) {
 
 	assert.Len(t, configurationChan, 1)
 }
+
+func TestProvider_fetchConfigurationData_conditional(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		rw.Header().Set("ETag", `"v1"`)
+		rw.Header().Set("Content-Type", "application/toml")
+		_, _ = rw.Write([]byte("[tcp.routers.foo]\n"))
+	}))
+	defer srv.Close()
+
+	var provider Provider
+	provider.SetDefaults()
+	provider.Endpoint = srv.URL
+
+	require.NoError(t, provider.Init())
+
+	configurationChan := make(chan dynamic.Message, 10)
+
+	require.NoError(t, provider.updateConfiguration(t.Context(), configurationChan, 0))
+	require.Len(t, configurationChan, 1)
+	assert.Contains(t, (<-configurationChan).Configuration.TCP.Routers, "foo")
+	assert.Equal(t, `"v1"`, provider.lastETag)
+
+	configPayload, err := provider.fetchConfigurationData(t.Context(), 0)
+	require.NoError(t, err)
+	assert.Nil(t, configPayload)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestProvider_Provide_longPoll(t *testing.T) {
+	changed := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			assert.Equal(t, "wait=10", req.Header.Get("Prefer"))
+
+			// Holds the request until the configuration changes.
+			select {
+			case <-changed:
+			case <-req.Context().Done():
+				return
+			}
+
+			rw.Header().Set("ETag", `"v2"`)
+			_, _ = rw.Write([]byte(`{"tcp":{"routers":{"bar":{}}}}`))
+			return
+		}
+
+		if req.Header.Get("If-None-Match") == `"v2"` {
+			<-req.Context().Done()
+			return
+		}
+
+		rw.Header().Set("ETag", `"v1"`)
+		_, _ = rw.Write([]byte(`{"tcp":{"routers":{"foo":{}}}}`))
+	}))
+	defer srv.Close()
+
+	var provider Provider
+	provider.SetDefaults()
+	provider.Endpoint = srv.URL
+	provider.Mode = "longPoll"
+	provider.PollInterval = ptypes.Duration(10 * time.Second)
+
+	require.NoError(t, provider.Init())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	configurationChan := make(chan dynamic.Message)
+	require.NoError(t, provider.Provide(configurationChan, safe.NewPool(ctx)))
+
+	assert.Contains(t, receive(t, configurationChan).Configuration.TCP.Routers, "foo")
+
+	close(changed)
+
+	assert.Contains(t, receive(t, configurationChan).Configuration.TCP.Routers, "bar")
+}
+
+func TestProvider_Provide_sse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "text/event-stream", req.Header.Get("Accept"))
+
+		rw.Header().Set("Content-Type", "text/event-stream")
+
+		_, _ = fmt.Fprint(rw, ": keep-alive\n\n")
+		_, _ = fmt.Fprint(rw, "id: 1\ndata: {\"tcp\":{\"routers\":{\"foo\":{}}}}\n\n")
+		_, _ = fmt.Fprint(rw, "id: 2\ncontentType: application/toml\ndata: [tcp.routers.bar]\ndata: [tcp.routers.baz]\n\n")
+		rw.(http.Flusher).Flush()
+
+		<-req.Context().Done()
+	}))
+	defer srv.Close()
+
+	var provider Provider
+	provider.SetDefaults()
+	provider.Endpoint = srv.URL
+	provider.Mode = "sse"
+
+	require.NoError(t, provider.Init())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	configurationChan := make(chan dynamic.Message)
+	require.NoError(t, provider.Provide(configurationChan, safe.NewPool(ctx)))
+
+	assert.Contains(t, receive(t, configurationChan).Configuration.TCP.Routers, "foo")
+
+	routers := receive(t, configurationChan).Configuration.TCP.Routers
+	assert.Contains(t, routers, "bar")
+	assert.Contains(t, routers, "baz")
+}
+
+func TestProvider_signature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	signatureKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	data := []byte(`{"tcp":{"routers":{"foo":{}}}}`)
+	validSignature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, data))
+	invalidSignature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte("{}")))
+
+	tests := []struct {
+		desc      string
+		signature string
+		expErr    bool
+	}{
+		{
+			desc:      "valid signature",
+			signature: validSignature,
+		},
+		{
+			desc:      "invalid signature",
+			signature: invalidSignature,
+			expErr:    true,
+		},
+		{
+			desc:   "missing signature",
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.signature != "" {
+					rw.Header().Set("X-Traefik-Signature", test.signature)
+				}
+				_, _ = rw.Write(data)
+			}))
+			defer srv.Close()
+
+			var provider Provider
+			provider.SetDefaults()
+			provider.Endpoint = srv.URL
+			provider.SignatureKey = types.FileOrContent(signatureKey)
+
+			require.NoError(t, provider.Init())
+
+			configurationChan := make(chan dynamic.Message, 1)
+
+			err := provider.updateConfiguration(t.Context(), configurationChan, 0)
+			if test.expErr {
+				require.Error(t, err)
+				assert.Empty(t, configurationChan)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, configurationChan, 1)
+		})
+	}
+}
+
+func receive(t *testing.T, configurationChan chan dynamic.Message) dynamic.Message {
+	t.Helper()
+
+	select {
+	case msg := <-configurationChan:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the configuration")
+		return dynamic.Message{}
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// stream receives the configurations from the Server-Sent Events sent by the endpoint,
+// each event holding a complete configuration.
+func (p *Provider) stream(ctx context.Context, configurationChan chan<- dynamic.Message) error {
+	req, err := p.newRequest(ctx)
+	if err != nil {
+		return fmt.Errorf("create stream request: %w", err)
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	if p.lastETag != "" {
+		req.Header.Set("Last-Event-ID", p.lastETag)
+	}
+
+	res, err := p.streamClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("do stream request: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("received non-ok response code: %d", res.StatusCode)
+	}
+
+	events := &eventReader{reader: bufio.NewReader(res.Body), maxSize: p.MaxResponseBodySize}
+
+	for {
+		configPayload, err := events.next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				return errors.New("event stream closed by the endpoint")
+			}
+			return fmt.Errorf("reading event stream: %w", err)
+		}
+
+		if err := p.processPayload(ctx, configurationChan, configPayload); err != nil {
+			return err
+		}
+	}
+}
+
+// eventReader reads the events of a Server-Sent Events stream.
+// Besides the standard fields, the events can hold the signature of the configuration in a signature field,
+// and its content type in a contentType field.
+type eventReader struct {
+	reader  *bufio.Reader
+	maxSize int64
+}
+
+// next returns the payload of the next event holding data.
+func (r *eventReader) next() (*payload, error) {
+	var (
+		data        bytes.Buffer
+		hasData     bool
+		id          string
+		signature   string
+		contentType string
+	)
+
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		// An empty line dispatches the event.
+		if line == "" {
+			if !hasData {
+				signature, contentType = "", ""
+				continue
+			}
+
+			return &payload{
+				data:      bytes.TrimSuffix(data.Bytes(), []byte("\n")),
+				format:    formatFromContentType(contentType),
+				signature: signature,
+				etag:      id,
+			}, nil
+		}
+
+		// Lines starting with a colon are comments, usually used as keep-alives.
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			hasData = true
+			data.WriteString(value)
+			data.WriteByte('\n')
+
+			if r.maxSize >= 0 && int64(data.Len()-1) > r.maxSize {
+				return nil, errors.New("event data too large")
+			}
+		case "id":
+			id = value
+		case "signature":
+			signature = value
+		case "contentType":
+			contentType = value
+		default:
+			// The event and retry fields, as well as the unknown ones, are ignored.
+		}
+	}
+}
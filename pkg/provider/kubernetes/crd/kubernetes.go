@@ -120,7 +120,8 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 					// Note that event is the *first* event that came in during this throttling interval -- if we're hitting our throttle, we may have dropped events.
 					// This is fine, because we don't treat different event types differently.
 					// But if we do in the future, we'll need to track more information about the dropped events.
-					conf := p.loadConfigurationFromCRD(ctxLog, k8sClient)
+					conf, namespaces := p.loadConfigurationFromCRD(ctxLog, k8sClient)
+					conf.Namespaces = namespaces
 
 					confHash, err := hashstructure.Hash(conf, nil)
 					switch {
@@ -223,17 +224,21 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	return client, nil
 }
 
-func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client) *dynamic.Configuration {
+// loadConfigurationFromCRD returns the configuration built from the resources,
+// and the namespaces of the resources its routers, middlewares and services come from.
+func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client) (*dynamic.Configuration, *dynamic.Namespaces) {
 	stores, tlsConfigs := buildTLSStores(ctx, client)
 	if tlsConfigs == nil {
 		tlsConfigs = make(map[string]*tls.CertAndStores)
 	}
 
+	namespaces := dynamic.NewNamespaces()
+
 	conf := &dynamic.Configuration{
 		// TODO: choose between mutating and returning tlsConfigs
-		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs),
-		TCP:  p.loadIngressRouteTCPConfiguration(ctx, client, tlsConfigs),
-		UDP:  p.loadIngressRouteUDPConfiguration(ctx, client),
+		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs, namespaces),
+		TCP:  p.loadIngressRouteTCPConfiguration(ctx, client, tlsConfigs, namespaces),
+		UDP:  p.loadIngressRouteUDPConfiguration(ctx, client, namespaces),
 		TLS: &dynamic.TLSConfiguration{
 			Options: buildTLSOptions(ctx, client),
 			Stores:  stores,
@@ -243,6 +248,18 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 	// Done after because tlsConfigs is mutated by the others above.
 	conf.TLS.Certificates = getTLSConfig(tlsConfigs)
 
+	for _, tlsOption := range client.GetTLSOptions() {
+		// The default TLS options are shared by all the namespaces.
+		if tlsOption.Name == tls.DefaultTLSConfigName {
+			continue
+		}
+
+		id := makeID(tlsOption.Namespace, tlsOption.Name)
+		if _, ok := conf.TLS.Options[id]; ok {
+			namespaces.TLSOptions[id] = tlsOption.Namespace
+		}
+	}
+
 	for _, middleware := range client.GetMiddlewares() {
 		id := provider.Normalize(makeID(middleware.Namespace, middleware.Name))
 		logger := log.Ctx(ctx).With().Str(logs.MiddlewareName, id).Logger()
@@ -276,6 +293,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			serviceName := id + "-errorpage-service"
 			errorPage.Service = serviceName
 			conf.HTTP.Services[serviceName] = errorPageService
+			namespaces.Services[serviceName] = middleware.Namespace
 		}
 
 		plugin, err := createPluginMiddleware(client, middleware.Namespace, middleware.Spec.Plugin)
@@ -330,6 +348,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			GrpcWeb:           middleware.Spec.GrpcWeb,
 			Plugin:            plugin,
 		}
+		namespaces.Middlewares[id] = middleware.Namespace
 	}
 
 	for _, middlewareTCP := range client.GetMiddlewareTCPs() {
@@ -340,6 +359,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			IPWhiteList:  middlewareTCP.Spec.IPWhiteList,
 			IPAllowList:  middlewareTCP.Spec.IPAllowList,
 		}
+		namespaces.TCPMiddlewares[id] = middlewareTCP.Namespace
 	}
 
 	cb := configBuilder{
@@ -350,7 +370,9 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 	}
 
 	for _, service := range client.GetTraefikServices() {
-		err := cb.buildTraefikService(ctx, service, conf.HTTP.Services)
+		services := map[string]*dynamic.Service{}
+		err := cb.buildTraefikService(ctx, service, services)
+		addServices(conf.HTTP.Services, namespaces.Services, service.Namespace, services)
 		if err != nil {
 			log.Ctx(ctx).Error().Str(logs.ServiceName, service.Name).Err(err).
 				Msg("Error while building TraefikService")
@@ -512,6 +534,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		}
 
 		id := provider.Normalize(makeID(serversTransport.Namespace, serversTransport.Name))
+		namespaces.ServersTransports[id] = serversTransport.Namespace
 		conf.HTTP.ServersTransports[id] = &dynamic.ServersTransport{
 			ServerName:          serversTransport.Spec.ServerName,
 			InsecureSkipVerify:  serversTransport.Spec.InsecureSkipVerify,
@@ -520,7 +543,9 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			CipherSuites:        cipherSuites,
 			MinVersion:          minVersion,
 			MaxVersion:          maxVersion,
+			CurvePreferences:    serversTransport.Spec.CurvePreferences,
 			DisableHTTP2:        serversTransport.Spec.DisableHTTP2,
+			HTTP3:               serversTransport.Spec.HTTP3,
 			MaxIdleConnsPerHost: serversTransport.Spec.MaxIdleConnsPerHost,
 			ForwardingTimeouts:  forwardingTimeout,
 			PeerCertURI:         serversTransport.Spec.PeerCertURI,
@@ -632,6 +657,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 				InsecureSkipVerify: serversTransportTCP.Spec.TLS.InsecureSkipVerify,
 				RootCAs:            rootCAs,
 				Certificates:       certs,
+				CurvePreferences:   serversTransportTCP.Spec.TLS.CurvePreferences,
 				PeerCertURI:        serversTransportTCP.Spec.TLS.PeerCertURI,
 			}
 
@@ -639,10 +665,11 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		}
 
 		id := provider.Normalize(makeID(serversTransportTCP.Namespace, serversTransportTCP.Name))
+		namespaces.TCPServersTransports[id] = serversTransportTCP.Namespace
 		conf.TCP.ServersTransports[id] = &tcpServerTransport
 	}
 
-	return conf
+	return conf, namespaces
 }
 
 func (p *Provider) createErrorPageMiddleware(ctx context.Context, client Client, namespace string, errorPage *traefikv1alpha1.ErrorPage) (*dynamic.ErrorPage, *dynamic.Service, error) {
@@ -25,7 +25,7 @@ const (
 	httpProtocol  = "http"
 )
 
-func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.HTTPConfiguration {
+func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores, namespaces *dynamic.Namespaces) *dynamic.HTTPConfiguration {
 	conf := &dynamic.HTTPConfiguration{
 		Routers:           map[string]*dynamic.Router{},
 		Middlewares:       map[string]*dynamic.Middleware{},
@@ -99,7 +99,9 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 					},
 				}
 
-				errBuild := cb.buildServicesLB(ctx, ingressRoute.Namespace, spec, serviceName, conf.Services)
+				services := map[string]*dynamic.Service{}
+				errBuild := cb.buildServicesLB(ctx, ingressRoute.Namespace, spec, serviceName, services)
+				addServices(conf.Services, namespaces.Services, ingressRoute.Namespace, services)
 				if errBuild != nil {
 					logger.Error().Err(errBuild).Send()
 					continue
@@ -113,6 +115,7 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 
 				if serversLB != nil {
 					conf.Services[serviceName] = serversLB
+					namespaces.Services[serviceName] = ingressRoute.Namespace
 				} else {
 					serviceName = fullName
 				}
@@ -166,12 +169,21 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 			p.applyRouterTransform(ctx, r, ingressRoute)
 
 			conf.Routers[normalized] = r
+			namespaces.Routers[normalized] = ingressRoute.Namespace
 		}
 	}
 
 	return conf
 }
 
+// addServices adds to conf the services built for a resource of the given namespace.
+func addServices(conf map[string]*dynamic.Service, namespaces map[string]string, namespace string, services map[string]*dynamic.Service) {
+	for name, service := range services {
+		conf[name] = service
+		namespaces[name] = namespace
+	}
+}
+
 func makeMiddlewareKeys(ctx context.Context, namespace string, middlewares []traefikv1alpha1.MiddlewareRef, allowCrossNamespace bool) ([]string, error) {
 	var mds []string
 
@@ -844,7 +856,7 @@ func getTLSHTTP(ctx context.Context, ingressRoute *traefikv1alpha1.IngressRoute,
 // an error is returned if the scheme provided is invalid.
 func parseServiceProtocol(providedScheme, portName string, portNumber int32) (string, error) {
 	switch providedScheme {
-	case httpProtocol, httpsProtocol, "h2c":
+	case httpProtocol, httpsProtocol, "h2c", "h3":
 		return providedScheme, nil
 	case "":
 		if portNumber == 443 || strings.HasPrefix(portName, httpsProtocol) {
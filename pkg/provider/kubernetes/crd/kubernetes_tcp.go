@@ -17,7 +17,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.TCPConfiguration {
+func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores, namespaces *dynamic.Namespaces) *dynamic.TCPConfiguration {
 	conf := &dynamic.TCPConfiguration{
 		Routers:           map[string]*dynamic.TCPRouter{},
 		Middlewares:       map[string]*dynamic.TCPMiddleware{},
@@ -79,11 +79,13 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 				// i.e. the service on top is directly a load balancer of servers.
 				if len(route.Services) == 1 {
 					conf.Services[serviceName] = balancerServerTCP
+					namespaces.TCPServices[serviceName] = ingressRouteTCP.Namespace
 					break
 				}
 
 				serviceKey := fmt.Sprintf("%s-%s-%s", serviceName, service.Name, &service.Port)
 				conf.Services[serviceKey] = balancerServerTCP
+				namespaces.TCPServices[serviceKey] = ingressRouteTCP.Namespace
 
 				srv := dynamic.TCPWRRService{Name: serviceKey}
 				srv.SetDefaults()
@@ -93,6 +95,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 
 				if conf.Services[serviceName] == nil {
 					conf.Services[serviceName] = &dynamic.TCPService{Weighted: &dynamic.TCPWeightedRoundRobin{}}
+					namespaces.TCPServices[serviceName] = ingressRouteTCP.Namespace
 				}
 				conf.Services[serviceName].Weighted.Services = append(conf.Services[serviceName].Weighted.Services, srv)
 			}
@@ -139,6 +142,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 			}
 
 			conf.Routers[serviceName] = r
+			namespaces.TCPRouters[serviceName] = ingressRouteTCP.Namespace
 		}
 	}
 
@@ -1805,7 +1805,7 @@ func TestLoadIngressRouteTCPs(t *testing.T) {
 				AllowEmptyServices:        test.allowEmptyServices,
 			}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -5403,6 +5403,7 @@ func TestLoadIngressRoutes(t *testing.T) {
 							MaxVersion:          "VersionTLS12",
 							MaxIdleConnsPerHost: 42,
 							DisableHTTP2:        true,
+							HTTP3:               &dynamic.ServersTransportHTTP3{DisableFallback: true},
 							ForwardingTimeouts: &dynamic.ForwardingTimeouts{
 								DialTimeout:           ptypes.Duration(42 * time.Second),
 								ResponseHeaderTimeout: ptypes.Duration(42 * time.Second),
@@ -6332,7 +6333,7 @@ func TestLoadIngressRoutes(t *testing.T) {
 				AllowEmptyServices:        test.allowEmptyServices,
 			}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -6408,7 +6409,7 @@ func TestLoadIngressRoutes_multipleEndpointAddresses(t *testing.T) {
 	}
 
 	p := Provider{}
-	conf := p.loadConfigurationFromCRD(t.Context(), client)
+	conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 
 	service, ok := conf.HTTP.Services["default-test-route-6b204d94623b3df4370c"]
 	require.True(t, ok)
@@ -6420,6 +6421,42 @@ func TestLoadIngressRoutes_multipleEndpointAddresses(t *testing.T) {
 	assert.Equal(t, wantConf, conf)
 }
 
+func TestLoadIngressRoutes_namespaces(t *testing.T) {
+	k8sObjects, crdObjects := readResources(t, []string{"services.yml", "with_namespaces_prefixed.yml"})
+
+	kubeClient := kubefake.NewClientset(k8sObjects...)
+	crdClient := traefikcrdfake.NewClientset(crdObjects...)
+
+	client := newClientImpl(kubeClient, crdClient)
+
+	stopCh := make(chan struct{})
+
+	eventCh, err := client.WatchAll(nil, stopCh)
+	require.NoError(t, err)
+
+	// just wait for the first event
+	<-eventCh
+
+	p := Provider{AllowCrossNamespace: true}
+	conf, namespaces := p.loadConfigurationFromCRD(t.Context(), client)
+
+	// The names of the team namespace are prefixed by the name of the team-b namespace.
+	require.Contains(t, conf.HTTP.Routers, "team-b-foo-6f97418635c7e18853da")
+	require.Contains(t, conf.HTTP.Routers, "team-b-foo-1f773b7f0ac1aad6d729")
+
+	expected := dynamic.NewNamespaces()
+	expected.Routers["team-b-foo-6f97418635c7e18853da"] = "team"
+	expected.Routers["team-b-foo-1f773b7f0ac1aad6d729"] = "team-b"
+	expected.Middlewares["team-b-mw"] = "team"
+	expected.Services["team-b-foo-6f97418635c7e18853da"] = "team"
+	expected.Services["team-b-foo-1f773b7f0ac1aad6d729"] = "team-b"
+	expected.TLSOptions["team-b-opt"] = "team"
+	expected.ServersTransports["team-b-st"] = "team"
+	expected.TCPServersTransports["team-b-st"] = "team"
+
+	assert.Equal(t, expected, namespaces)
+}
+
 func TestLoadIngressRouteUDPs(t *testing.T) {
 	testCases := []struct {
 		desc               string
@@ -7049,7 +7086,7 @@ func TestLoadIngressRouteUDPs(t *testing.T) {
 				AllowEmptyServices:        test.allowEmptyServices,
 			}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -8547,7 +8584,7 @@ func TestCrossNamespace(t *testing.T) {
 
 			p := Provider{AllowCrossNamespace: test.allowCrossNamespace}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -8817,7 +8854,7 @@ func TestExternalNameService(t *testing.T) {
 
 			p := Provider{AllowExternalNameServices: test.allowExternalNameService}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -8999,7 +9036,7 @@ func TestNativeLB(t *testing.T) {
 
 			p := Provider{}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -9267,7 +9304,7 @@ func TestNodePortLB(t *testing.T) {
 				DisableClusterScopeResources: test.disableClusterScope,
 			}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -9911,7 +9948,7 @@ func TestGlobalNativeLB(t *testing.T) {
 
 			p := Provider{NativeLBByDefault: test.NativeLBByDefault}
 
-			conf := p.loadConfigurationFromCRD(t.Context(), client)
+			conf, _ := p.loadConfigurationFromCRD(t.Context(), client)
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -13,7 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client Client) *dynamic.UDPConfiguration {
+func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client Client, namespaces *dynamic.Namespaces) *dynamic.UDPConfiguration {
 	conf := &dynamic.UDPConfiguration{
 		Routers:  map[string]*dynamic.UDPRouter{},
 		Services: map[string]*dynamic.UDPService{},
@@ -54,11 +54,13 @@ func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client
 				// i.e. the service on top is directly a load balancer of servers.
 				if len(route.Services) == 1 {
 					conf.Services[serviceName] = balancerServerUDP
+					namespaces.UDPServices[serviceName] = ingressRouteUDP.Namespace
 					break
 				}
 
 				serviceKey := fmt.Sprintf("%s-%s-%s", serviceName, service.Name, &service.Port)
 				conf.Services[serviceKey] = balancerServerUDP
+				namespaces.UDPServices[serviceKey] = ingressRouteUDP.Namespace
 
 				srv := dynamic.UDPWRRService{Name: serviceKey}
 				srv.SetDefaults()
@@ -68,6 +70,7 @@ func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client
 
 				if conf.Services[serviceName] == nil {
 					conf.Services[serviceName] = &dynamic.UDPService{Weighted: &dynamic.UDPWeightedRoundRobin{}}
+					namespaces.UDPServices[serviceName] = ingressRouteUDP.Namespace
 				}
 				conf.Services[serviceName].Weighted.Services = append(conf.Services[serviceName].Weighted.Services, srv)
 			}
@@ -76,6 +79,7 @@ func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client
 				EntryPoints: ingressRouteUDP.Spec.EntryPoints,
 				Service:     serviceName,
 			}
+			namespaces.UDPRouters[serviceName] = ingressRouteUDP.Namespace
 		}
 	}
 
@@ -45,6 +45,8 @@ type ServersTransportSpec struct {
 	MinVersion string `json:"minVersion,omitempty"`
 	// MaxVersion defines the maximum TLS version to use when contacting backend servers.
 	MaxVersion string `json:"maxVersion,omitempty"`
+	// CurvePreferences defines the preferred elliptic curves and key exchange groups, including the hybrid post-quantum X25519MLKEM768, to use when contacting backend servers.
+	CurvePreferences []string `json:"curvePreferences,omitempty"`
 	// MaxIdleConnsPerHost controls the maximum idle (keep-alive) to keep per-host.
 	// +kubebuilder:validation:Minimum=-1
 	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
@@ -52,6 +54,8 @@ type ServersTransportSpec struct {
 	ForwardingTimeouts *ForwardingTimeouts `json:"forwardingTimeouts,omitempty"`
 	// DisableHTTP2 disables HTTP/2 for connections with backend servers.
 	DisableHTTP2 bool `json:"disableHTTP2,omitempty"`
+	// HTTP3 enables HTTP/3 for connections with backend servers using the https scheme.
+	HTTP3 *dynamic.ServersTransportHTTP3 `json:"http3,omitempty"`
 	// PeerCertURI defines the peer cert URI used to match against SAN URI during the peer certificate verification.
 	PeerCertURI string `json:"peerCertURI,omitempty"`
 	// Spiffe defines the SPIFFE configuration.
@@ -59,6 +59,8 @@ type TLSClientConfig struct {
 	RootCAsSecrets []string `json:"rootCAsSecrets,omitempty"`
 	// CertificatesSecrets defines a list of secret storing client certificates for mTLS.
 	CertificatesSecrets []string `json:"certificatesSecrets,omitempty"`
+	// CurvePreferences defines the preferred elliptic curves and key exchange groups, including the hybrid post-quantum X25519MLKEM768, to use when contacting backend servers.
+	CurvePreferences []string `json:"curvePreferences,omitempty"`
 	// MaxIdleConnsPerHost controls the maximum idle (keep-alive) to keep per-host.
 	// PeerCertURI defines the peer cert URI used to match against SAN URI during the peer certificate verification.
 	PeerCertURI string `json:"peerCertURI,omitempty"`
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/kvtools/consul"
+	"github.com/kvtools/valkeyrie"
+	"github.com/kvtools/valkeyrie/store"
 	"github.com/traefik/traefik/v3/pkg/provider"
 	"github.com/traefik/traefik/v3/pkg/provider/kv"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -58,6 +60,30 @@ func (p *ProviderBuilder) BuildProviders() []*Provider {
 	return providers
 }
 
+// NewStore creates a client of the configured Consul store.
+// As the client is bound to a single namespace, at most one namespace can be configured.
+func (p *ProviderBuilder) NewStore(ctx context.Context) (store.Store, error) {
+	var namespace string
+	switch len(p.Namespaces) {
+	case 0:
+	case 1:
+		namespace = p.Namespaces[0]
+	default:
+		return nil, errors.New("a Consul store cannot use multiple namespaces")
+	}
+
+	if namespace == "*" {
+		return nil, errors.New("wildcard namespace is not supported")
+	}
+
+	config, err := storeConfig(p.Token, namespace, p.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return valkeyrie.NewStore(ctx, consul.StoreName, p.Endpoints, config)
+}
+
 // Provider holds configurations of the provider.
 type Provider struct {
 	kv.Provider
@@ -81,24 +107,33 @@ func (p *Provider) Init() error {
 		p.name = providerName
 	}
 
+	config, err := storeConfig(p.token, p.namespace, p.tls)
+	if err != nil {
+		return err
+	}
+
+	return p.Provider.Init(consul.StoreName, p.name, config)
+}
+
+// Namespace returns the namespace of the Consul provider.
+func (p *Provider) Namespace() string {
+	return p.namespace
+}
+
+func storeConfig(token, namespace string, tls *types.ClientTLS) (*consul.Config, error) {
 	config := &consul.Config{
 		ConnectionTimeout: 3 * time.Second,
-		Token:             p.token,
-		Namespace:         p.namespace,
+		Token:             token,
+		Namespace:         namespace,
 	}
 
-	if p.tls != nil {
+	if tls != nil {
 		var err error
-		config.TLS, err = p.tls.CreateTLSConfig(context.Background())
+		config.TLS, err = tls.CreateTLSConfig(context.Background())
 		if err != nil {
-			return fmt.Errorf("unable to create client TLS configuration: %w", err)
+			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
 		}
 	}
 
-	return p.Provider.Init(consul.StoreName, p.name, config)
-}
-
-// Namespace returns the namespace of the Consul provider.
-func (p *Provider) Namespace() string {
-	return p.namespace
+	return config, nil
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/kvtools/etcdv3"
+	"github.com/kvtools/valkeyrie"
+	"github.com/kvtools/valkeyrie/store"
 	"github.com/traefik/traefik/v3/pkg/provider"
 	"github.com/traefik/traefik/v3/pkg/provider/kv"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -30,6 +32,25 @@ func (p *Provider) SetDefaults() {
 
 // Init the provider.
 func (p *Provider) Init() error {
+	config, err := p.storeConfig()
+	if err != nil {
+		return err
+	}
+
+	return p.Provider.Init(etcdv3.StoreName, "etcd", config)
+}
+
+// NewStore creates a client of the configured etcd store.
+func (p *Provider) NewStore(ctx context.Context) (store.Store, error) {
+	config, err := p.storeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return valkeyrie.NewStore(ctx, etcdv3.StoreName, p.Endpoints, config)
+}
+
+func (p *Provider) storeConfig() (*etcdv3.Config, error) {
 	config := &etcdv3.Config{
 		ConnectionTimeout: 3 * time.Second,
 		Username:          p.Username,
@@ -40,9 +61,9 @@ func (p *Provider) Init() error {
 		var err error
 		config.TLS, err = p.TLS.CreateTLSConfig(context.Background())
 		if err != nil {
-			return fmt.Errorf("unable to create client TLS configuration: %w", err)
+			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
 		}
 	}
 
-	return p.Provider.Init(etcdv3.StoreName, "etcd", config)
+	return config, nil
 }
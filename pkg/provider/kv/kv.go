@@ -15,6 +15,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/kv"
 	"github.com/traefik/traefik/v3/pkg/job"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/provider/constraints"
 	"github.com/traefik/traefik/v3/pkg/safe"
 )
 
@@ -24,6 +25,8 @@ type Provider struct {
 
 	Endpoints []string `description:"KV store endpoints." json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
 
+	Constraints string `description:"Constraints is an expression that Traefik matches against the labels of the routers, services and middlewares to determine whether to load them." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
+
 	name     string
 	kvClient store.Store
 }
@@ -39,6 +42,10 @@ func (p *Provider) Init(storeType, name string, config valkeyrie.Config) error {
 
 	p.name = name
 
+	if _, err := constraints.MatchLabels(nil, p.Constraints); err != nil {
+		return fmt.Errorf("invalid constraints expression: %w", err)
+	}
+
 	kvClient, err := p.createKVClient(ctx, storeType, config)
 	if err != nil {
 		return fmt.Errorf("failed to Connect to KV store: %w", err)
@@ -153,6 +160,10 @@ func (p *Provider) buildConfiguration(ctx context.Context) (*dynamic.Configurati
 		return nil, err
 	}
 
+	if err := constraints.FilterConfiguration(ctx, cfg, p.Constraints); err != nil {
+		return nil, fmt.Errorf("applying constraints: %w", err)
+	}
+
 	return cfg, nil
 }
 
@@ -958,6 +958,40 @@ func Test_buildConfiguration(t *testing.T) {
 	assert.Equal(t, expected, cfg)
 }
 
+func Test_buildConfiguration_constraints(t *testing.T) {
+	provider := newProviderMock(mapToPairs(map[string]string{
+		"traefik/http/routers/foo/rule":                      "Host(`foo.localhost`)",
+		"traefik/http/routers/foo/service":                   "foo",
+		"traefik/http/routers/foo/labels/env":                "prod",
+		"traefik/http/routers/bar/rule":                      "Host(`bar.localhost`)",
+		"traefik/http/routers/bar/service":                   "bar",
+		"traefik/http/routers/bar/labels/env":                "staging",
+		"traefik/http/services/foo/labels/env":               "prod",
+		"traefik/http/services/foo/weighted/services/0/name": "bar",
+		"traefik/tcp/routers/baz/rule":                       "HostSNI(`*`)",
+		"traefik/tcp/routers/baz/service":                    "baz",
+	}))
+	provider.Constraints = "Label(`env`, `prod`)"
+
+	cfg, err := provider.buildConfiguration(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]*dynamic.Router{
+		"foo": {
+			Rule:    "Host(`foo.localhost`)",
+			Service: "foo",
+			Labels:  map[string]string{"env": "prod"},
+		},
+	}, cfg.HTTP.Routers)
+	assert.Contains(t, cfg.HTTP.Services, "foo")
+	assert.Empty(t, cfg.TCP.Routers)
+
+	provider.Constraints = "Label(`env`"
+
+	_, err = provider.buildConfiguration(t.Context())
+	require.Error(t, err)
+}
+
 func Test_buildConfiguration_KV_error(t *testing.T) {
 	provider := &Provider{
 		RootKey: "traefik",
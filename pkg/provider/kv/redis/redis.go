@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/kvtools/redis"
+	"github.com/kvtools/valkeyrie"
+	"github.com/kvtools/valkeyrie/store"
 	"github.com/traefik/traefik/v3/pkg/provider"
 	"github.com/traefik/traefik/v3/pkg/provider/kv"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -45,6 +47,25 @@ func (p *Provider) SetDefaults() {
 
 // Init the provider.
 func (p *Provider) Init() error {
+	config, err := p.storeConfig()
+	if err != nil {
+		return err
+	}
+
+	return p.Provider.Init(redis.StoreName, "redis", config)
+}
+
+// NewStore creates a client of the configured Redis store.
+func (p *Provider) NewStore(ctx context.Context) (store.Store, error) {
+	config, err := p.storeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return valkeyrie.NewStore(ctx, redis.StoreName, p.Endpoints, config)
+}
+
+func (p *Provider) storeConfig() (*redis.Config, error) {
 	config := &redis.Config{
 		Username: p.Username,
 		Password: p.Password,
@@ -55,7 +76,7 @@ func (p *Provider) Init() error {
 		var err error
 		config.TLS, err = p.TLS.CreateTLSConfig(context.Background())
 		if err != nil {
-			return fmt.Errorf("unable to create client TLS configuration: %w", err)
+			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
 		}
 	}
 
@@ -74,7 +95,7 @@ func (p *Provider) Init() error {
 		}
 
 		if count > 1 {
-			return errors.New("latencyStrategy, randomStrategy and replicaStrategy options are mutually exclusive, please use only one of those options")
+			return nil, errors.New("latencyStrategy, randomStrategy and replicaStrategy options are mutually exclusive, please use only one of those options")
 		}
 
 		clusterClient := p.Sentinel.LatencyStrategy || p.Sentinel.RandomStrategy
@@ -90,5 +111,5 @@ func (p *Provider) Init() error {
 		}
 	}
 
-	return p.Provider.Init(redis.StoreName, "redis", config)
+	return config, nil
 }
@@ -1,8 +1,11 @@
 package zk
 
 import (
+	"context"
 	"time"
 
+	"github.com/kvtools/valkeyrie"
+	"github.com/kvtools/valkeyrie/store"
 	"github.com/kvtools/zookeeper"
 	"github.com/traefik/traefik/v3/pkg/provider"
 	"github.com/traefik/traefik/v3/pkg/provider/kv"
@@ -26,11 +29,18 @@ func (p *Provider) SetDefaults() {
 
 // Init the provider.
 func (p *Provider) Init() error {
-	config := &zookeeper.Config{
+	return p.Provider.Init(zookeeper.StoreName, "zookeeper", p.storeConfig())
+}
+
+// NewStore creates a client of the configured ZooKeeper store.
+func (p *Provider) NewStore(ctx context.Context) (store.Store, error) {
+	return valkeyrie.NewStore(ctx, zookeeper.StoreName, p.Endpoints, p.storeConfig())
+}
+
+func (p *Provider) storeConfig() *zookeeper.Config {
+	return &zookeeper.Config{
 		ConnectionTimeout: 3 * time.Second,
 		Username:          p.Username,
 		Password:          p.Password,
 	}
-
-	return p.Provider.Init(zookeeper.StoreName, "zookeeper", config)
 }
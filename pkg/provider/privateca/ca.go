@@ -0,0 +1,298 @@
+package privateca
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+const (
+	rootDuration         = 10 * 365 * 24 * time.Hour
+	intermediateDuration = 365 * 24 * time.Hour
+
+	// backdate is subtracted from the start of the validity period of the certificates,
+	// to tolerate clock skews between Traefik and the clients.
+	backdate = 5 * time.Minute
+)
+
+// certificateAuthority issues the leaf certificates, signed by an intermediate CA, itself signed by the root CA.
+type certificateAuthority struct {
+	keyType certcrypto.KeyType
+
+	root    *x509.Certificate
+	rootPEM []byte
+	rootKey crypto.Signer
+
+	intermediate    *x509.Certificate
+	intermediatePEM []byte
+	intermediateKey crypto.Signer
+}
+
+// generateRoot generates a self-signed root CA certificate.
+func generateRoot(commonName string, keyType certcrypto.KeyType) (*Certificate, error) {
+	key, err := generateKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newTemplate(time.Now(), rootDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Subject = pkix.Name{CommonName: commonName + " Root"}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.MaxPathLen = 1
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("creating root certificate: %w", err)
+	}
+
+	return &Certificate{Certificate: encodeCertificate(der), Key: certcrypto.PEMEncode(key)}, nil
+}
+
+// newCertificateAuthority creates a certificateAuthority given a PEM encoded root certificate and its private key.
+func newCertificateAuthority(root *Certificate, keyType certcrypto.KeyType) (*certificateAuthority, error) {
+	cert, key, err := parseCertificate(root)
+	if err != nil {
+		return nil, fmt.Errorf("parsing root certificate: %w", err)
+	}
+
+	if !cert.IsCA {
+		return nil, errors.New("root certificate is not a CA certificate")
+	}
+
+	if key == nil {
+		return nil, errors.New("root private key is missing")
+	}
+
+	return &certificateAuthority{
+		keyType: keyType,
+		root:    cert,
+		rootPEM: root.Certificate,
+		rootKey: key,
+	}, nil
+}
+
+// setIntermediate sets the intermediate CA certificate, if it has been signed by the root CA.
+func (c *certificateAuthority) setIntermediate(intermediate *Certificate) error {
+	cert, key, err := parseCertificate(intermediate)
+	if err != nil {
+		return fmt.Errorf("parsing intermediate certificate: %w", err)
+	}
+
+	if key == nil {
+		return errors.New("intermediate private key is missing")
+	}
+
+	if err := cert.CheckSignatureFrom(c.root); err != nil {
+		return fmt.Errorf("intermediate certificate not signed by the root certificate: %w", err)
+	}
+
+	c.intermediate = cert
+	c.intermediatePEM = intermediate.Certificate
+	c.intermediateKey = key
+
+	return nil
+}
+
+// generateIntermediate generates a new intermediate CA certificate signed by the root CA, and uses it to sign the next certificates.
+func (c *certificateAuthority) generateIntermediate(commonName string) (*Certificate, error) {
+	key, err := generateKey(c.keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newTemplate(time.Now(), intermediateDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Subject = pkix.Name{CommonName: commonName + " Intermediate"}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.MaxPathLenZero = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	template.NotAfter = capNotAfter(template.NotAfter, c.root)
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.root, key.Public(), c.rootKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating intermediate certificate: %w", err)
+	}
+
+	intermediate := &Certificate{Certificate: encodeCertificate(der), Key: certcrypto.PEMEncode(key)}
+	if err := c.setIntermediate(intermediate); err != nil {
+		return nil, err
+	}
+
+	return intermediate, nil
+}
+
+// issueServerCertificate issues a server certificate for the given domain.
+// The domains which are IP addresses are added to the IP addresses of the Subject Alternative Name.
+func (c *certificateAuthority) issueServerCertificate(domain types.Domain, duration time.Duration) (*Certificate, error) {
+	template, err := newTemplate(time.Now(), duration)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Subject = pkix.Name{CommonName: domain.Main}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	for _, name := range domain.ToStrArray() {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+			continue
+		}
+
+		template.DNSNames = append(template.DNSNames, name)
+	}
+
+	cert, err := c.issue(template)
+	if err != nil {
+		return nil, err
+	}
+
+	cert.Domain = domain
+
+	return cert, nil
+}
+
+// issueClientCertificate issues a client certificate for the given ServersTransport.
+func (c *certificateAuthority) issueClientCertificate(st ServersTransport, duration time.Duration) (*Certificate, error) {
+	template, err := newTemplate(time.Now(), duration)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Subject = pkix.Name{CommonName: st.CommonName}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	for _, rawURI := range st.URIs {
+		uri, err := url.Parse(rawURI)
+		if err != nil {
+			return nil, fmt.Errorf("parsing URI %q: %w", rawURI, err)
+		}
+
+		template.URIs = append(template.URIs, uri)
+	}
+
+	cert, err := c.issue(template)
+	if err != nil {
+		return nil, err
+	}
+
+	cert.ServersTransport = st.Name
+
+	return cert, nil
+}
+
+// issue generates a private key and a leaf certificate signed by the intermediate CA.
+// The returned certificate chain contains the leaf and the intermediate certificates.
+func (c *certificateAuthority) issue(template *x509.Certificate) (*Certificate, error) {
+	if c.intermediate == nil {
+		return nil, errors.New("no intermediate certificate")
+	}
+
+	key, err := generateKey(c.keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.BasicConstraintsValid = true
+	template.NotAfter = capNotAfter(template.NotAfter, c.intermediate)
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.intermediate, key.Public(), c.intermediateKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating certificate: %w", err)
+	}
+
+	chain := append(encodeCertificate(der), c.intermediatePEM...)
+
+	return &Certificate{Certificate: chain, Key: certcrypto.PEMEncode(key)}, nil
+}
+
+// newTemplate creates a certificate template with a random serial number, valid from now for the given duration.
+func newTemplate(now time.Time, duration time.Duration) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("generating serial number: %w", err)
+	}
+
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(duration),
+	}, nil
+}
+
+// capNotAfter returns the given expiration date, capped to the expiration date of the issuer.
+func capNotAfter(notAfter time.Time, issuer *x509.Certificate) time.Time {
+	if notAfter.After(issuer.NotAfter) {
+		return issuer.NotAfter
+	}
+
+	return notAfter
+}
+
+func generateKey(keyType certcrypto.KeyType) (crypto.Signer, error) {
+	key, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("generating private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return signer, nil
+}
+
+// parseCertificate parses the first certificate of the given PEM encoded chain, and its private key.
+func parseCertificate(cert *Certificate) (*x509.Certificate, crypto.Signer, error) {
+	block, _ := pem.Decode(cert.Certificate)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, nil, errors.New("no PEM encoded certificate found")
+	}
+
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+
+	if len(cert.Key) == 0 {
+		return parsed, nil, nil
+	}
+
+	key, err := certcrypto.ParsePEMPrivateKey(cert.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return parsed, signer, nil
+}
+
+func encodeCertificate(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
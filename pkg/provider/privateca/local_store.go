@@ -0,0 +1,95 @@
+package privateca
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/provider/acme"
+)
+
+var _ Store = (*LocalStore)(nil)
+
+// LocalStore is a Store implementation persisting the data in a local file.
+// As the data holds the private keys of the CA, the file must not be readable by other users.
+type LocalStore struct {
+	filename string
+
+	lock       sync.Mutex
+	storedData map[string]*StoredData
+}
+
+// NewLocalStore initializes a new LocalStore with a file name.
+func NewLocalStore(filename string) *LocalStore {
+	return &LocalStore{filename: filename}
+}
+
+// Get returns the data of the given resolver.
+func (s *LocalStore) Get(resolverName string) (*StoredData, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	if s.storedData[resolverName] == nil {
+		return &StoredData{}, nil
+	}
+
+	return s.storedData[resolverName], nil
+}
+
+// Save stores the data of the given resolver, and writes the file.
+func (s *LocalStore) Save(resolverName string, data *StoredData) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	s.storedData[resolverName] = data
+
+	content, err := json.MarshalIndent(s.storedData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling private CA data: %w", err)
+	}
+
+	if err := os.WriteFile(s.filename, content, 0o600); err != nil {
+		return fmt.Errorf("writing private CA storage file: %w", err)
+	}
+
+	return nil
+}
+
+// load reads the file content, if not already done.
+// This method is not thread safe, the lock must be held.
+func (s *LocalStore) load() error {
+	if s.storedData != nil {
+		return nil
+	}
+
+	storedData := map[string]*StoredData{}
+
+	hasData, err := acme.CheckFile(s.filename)
+	if err != nil {
+		return err
+	}
+
+	if hasData {
+		content, err := os.ReadFile(s.filename)
+		if err != nil {
+			return fmt.Errorf("reading private CA storage file: %w", err)
+		}
+
+		if err := json.Unmarshal(content, &storedData); err != nil {
+			return fmt.Errorf("unmarshaling private CA storage file: %w", err)
+		}
+	}
+
+	s.storedData = storedData
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package privateca
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestLocalStore_SaveAndGet(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "privateca.json")
+
+	store := NewLocalStore(filename)
+
+	data, err := store.Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, &StoredData{}, data)
+
+	expected := &StoredData{
+		Root:         &Certificate{Certificate: []byte("root"), Key: []byte("root key")},
+		Intermediate: &Certificate{Certificate: []byte("intermediate"), Key: []byte("intermediate key")},
+		Certificates: []*Certificate{
+			{Domain: types.Domain{Main: "a.internal"}, Certificate: []byte("cert"), Key: []byte("key")},
+			{ServersTransport: "internal", Certificate: []byte("client cert"), Key: []byte("client key")},
+		},
+	}
+	require.NoError(t, store.Save("foo", expected))
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(filename)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	}
+
+	data, err = NewLocalStore(filename).Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+}
@@ -0,0 +1,678 @@
+package privateca
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/provider/acme"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+const resolverSuffix = ".privateca"
+
+// Configuration holds the private CA configuration provided by users.
+type Configuration struct {
+	Storage              string              `description:"Storage to use." json:"storage,omitempty" toml:"storage,omitempty" yaml:"storage,omitempty" export:"true"`
+	CommonName           string              `description:"Common name prefix of the generated root and intermediate CA certificates." json:"commonName,omitempty" toml:"commonName,omitempty" yaml:"commonName,omitempty" export:"true"`
+	RootCertificate      types.FileOrContent `description:"PEM encoded root CA certificate to use, instead of generating one." json:"rootCertificate,omitempty" toml:"rootCertificate,omitempty" yaml:"rootCertificate,omitempty"`
+	RootKey              types.FileOrContent `description:"PEM encoded private key of the root CA certificate." json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty" loggable:"false"`
+	KeyType              string              `description:"KeyType used for generating the private keys. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
+	CertificatesDuration ptypes.Duration     `description:"Duration of the issued certificates. The certificates are renewed when a third of their duration remains." json:"certificatesDuration,omitempty" toml:"certificatesDuration,omitempty" yaml:"certificatesDuration,omitempty" export:"true"`
+
+	ServersTransports []ServersTransport `description:"Servers transports authenticating to the servers with a client certificate issued by the private CA." json:"serversTransports,omitempty" toml:"serversTransports,omitempty" yaml:"serversTransports,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *Configuration) SetDefaults() {
+	c.Storage = "privateca.json"
+	c.CommonName = "Traefik Private CA"
+	c.KeyType = "EC256"
+	c.CertificatesDuration = ptypes.Duration(24 * time.Hour)
+}
+
+// ServersTransport defines the HTTP and TCP servers transports provided by the private CA.
+// They present a client certificate issued by the private CA, and trust the servers certificates issued by the private CA.
+type ServersTransport struct {
+	Name       string   `description:"Name of the HTTP and TCP servers transports." json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	CommonName string   `description:"Common name of the client certificate." json:"commonName,omitempty" toml:"commonName,omitempty" yaml:"commonName,omitempty" export:"true"`
+	URIs       []string `description:"URIs of the client certificate Subject Alternative Name, like SPIFFE IDs." json:"uris,omitempty" toml:"uris,omitempty" yaml:"uris,omitempty" export:"true"`
+	ServerName string   `description:"Defines the serverName used to contact the servers." json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty" export:"true"`
+}
+
+// Provider is the private CA certificates provider implementation.
+// It receives configuration updates (e.g. new router, with new domain) from Traefik core,
+// issues the corresponding TLS certificates with its own certificate authority,
+// and sends back to Traefik core a configuration updated with the certificates.
+type Provider struct {
+	*Configuration
+
+	ResolverName string
+	Store        Store
+
+	dynConfigs  chan dynamic.Configuration // updates from Traefik core
+	dynMessages chan<- dynamic.Message     // update to Traefik core
+
+	certsMu      sync.RWMutex
+	ca           *certificateAuthority
+	root         *Certificate // Only set when the root has been generated, to be persisted.
+	intermediate *Certificate
+	serverCerts  map[string]*Certificate // Indexed by domain.
+	clientCerts  map[string]*Certificate // Indexed by servers transport name.
+}
+
+// ThrottleDuration implements the aggregator.throttled interface, in order to
+// ensure that this provider is unthrottled.
+func (p *Provider) ThrottleDuration() time.Duration {
+	return 0
+}
+
+// Init implements the provider.Provider interface.
+// It loads or generates the certificate authority, and loads the stored certificates.
+func (p *Provider) Init() error {
+	if p.Store == nil {
+		return errors.New("no store found for the private CA provider")
+	}
+
+	if p.CertificatesDuration <= 0 {
+		return errors.New("certificatesDuration must be greater than zero")
+	}
+
+	seen := map[string]struct{}{}
+	for _, st := range p.ServersTransports {
+		if st.Name == "" {
+			return errors.New("servers transport name cannot be empty")
+		}
+
+		if _, ok := seen[st.Name]; ok {
+			return fmt.Errorf("servers transport %q is defined multiple times", st.Name)
+		}
+		seen[st.Name] = struct{}{}
+	}
+
+	p.dynConfigs = make(chan dynamic.Configuration)
+	p.serverCerts = make(map[string]*Certificate)
+	p.clientCerts = make(map[string]*Certificate)
+
+	logger := log.With().Str(logs.ProviderName, p.ResolverName+resolverSuffix).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	data, err := p.Store.Get(p.ResolverName)
+	if err != nil {
+		return fmt.Errorf("getting stored data: %w", err)
+	}
+
+	if err := p.initCA(ctx, data); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, cert := range data.Certificates {
+		if !p.isValid(cert, now) {
+			logger.Debug().Msgf("Discarding invalid stored certificate for %v", cert.Domain.ToStrArray())
+			continue
+		}
+
+		if cert.ServersTransport != "" {
+			p.clientCerts[cert.ServersTransport] = cert
+			continue
+		}
+
+		p.serverCerts[domainKey(cert.Domain)] = cert
+	}
+
+	return p.save()
+}
+
+// HandleConfigUpdate hands out a configuration update to the provider.
+func (p *Provider) HandleConfigUpdate(cfg dynamic.Configuration) {
+	p.dynConfigs <- cfg
+}
+
+// Provide starts the provider, which will henceforth send configuration
+// updates on dynMessages.
+func (p *Provider) Provide(dynMessages chan<- dynamic.Message, pool *safe.Pool) error {
+	p.dynMessages = dynMessages
+
+	logger := log.With().Str(logs.ProviderName, p.ResolverName+resolverSuffix).Logger()
+
+	pool.GoCtx(func(ctx context.Context) {
+		p.watchDomains(logger.WithContext(ctx))
+	})
+
+	pool.GoCtx(func(ctx context.Context) {
+		p.renewCertificates(logger.WithContext(ctx))
+	})
+
+	return nil
+}
+
+// initCA loads or generates the root CA, and the intermediate CA signing the certificates.
+func (p *Provider) initCA(ctx context.Context, data *StoredData) error {
+	logger := log.Ctx(ctx)
+
+	keyType := acme.GetKeyType(ctx, p.KeyType)
+
+	root := data.Root
+	switch {
+	case p.RootCertificate != "":
+		cert, err := p.RootCertificate.Read()
+		if err != nil {
+			return fmt.Errorf("reading root certificate: %w", err)
+		}
+
+		key, err := p.RootKey.Read()
+		if err != nil {
+			return fmt.Errorf("reading root private key: %w", err)
+		}
+
+		// The configured root is not persisted, as it is already managed by the user.
+		root = &Certificate{Certificate: cert, Key: key}
+		p.root = nil
+
+	case root == nil:
+		var err error
+		root, err = generateRoot(p.CommonName, keyType)
+		if err != nil {
+			return fmt.Errorf("generating root certificate: %w", err)
+		}
+
+		logger.Info().Msg("Generated a new root CA certificate")
+		p.root = root
+
+	default:
+		p.root = root
+	}
+
+	ca, err := newCertificateAuthority(root, keyType)
+	if err != nil {
+		return err
+	}
+	p.ca = ca
+
+	if data.Intermediate != nil {
+		if err := ca.setIntermediate(data.Intermediate); err != nil {
+			logger.Warn().Err(err).Msg("Unable to use the stored intermediate CA certificate, a new one is generated")
+		} else {
+			p.intermediate = data.Intermediate
+		}
+	}
+
+	if p.intermediate == nil || needsRenewal(ca.intermediate, time.Now()) {
+		return p.renewIntermediate(ctx)
+	}
+
+	return nil
+}
+
+// renewIntermediate generates a new intermediate CA certificate.
+// The certificates signed by the previous intermediate remain valid until they are renewed.
+func (p *Provider) renewIntermediate(ctx context.Context) error {
+	intermediate, err := p.ca.generateIntermediate(p.CommonName)
+	if err != nil {
+		return fmt.Errorf("generating intermediate certificate: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msg("Generated a new intermediate CA certificate")
+	p.intermediate = intermediate
+
+	return nil
+}
+
+// watchDomains watches for the domains of the routers using the resolver, and issues their certificates.
+func (p *Provider) watchDomains(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case cfg := <-p.dynConfigs:
+			domains := p.findDomains(ctx, cfg)
+
+			p.certsMu.Lock()
+			purged := p.purgeUnusedCerts(domains)
+			issued := p.issueServerCerts(ctx, domains)
+			p.certsMu.Unlock()
+
+			if !purged && !issued {
+				continue
+			}
+
+			p.saveAndSend(ctx)
+		}
+	}
+}
+
+// renewCertificates issues the client certificates, and routinely renews the certificates before they expire.
+func (p *Provider) renewCertificates(ctx context.Context) {
+	p.certsMu.Lock()
+	p.issueClientCerts(ctx, time.Now())
+	p.certsMu.Unlock()
+
+	// The stored certificates are sent right away, to not wait for a configuration update.
+	p.saveAndSend(ctx)
+
+	ticker := time.NewTicker(renewalCheckInterval(time.Duration(p.CertificatesDuration)))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if p.renewExpiringCerts(ctx, time.Now()) {
+				p.saveAndSend(ctx)
+			}
+		}
+	}
+}
+
+// renewExpiringCerts renews the intermediate, server and client certificates which need it,
+// and returns whether some certificates have been renewed.
+func (p *Provider) renewExpiringCerts(ctx context.Context, now time.Time) bool {
+	logger := log.Ctx(ctx)
+
+	p.certsMu.Lock()
+	defer p.certsMu.Unlock()
+
+	var renewed bool
+	if needsRenewal(p.ca.intermediate, now) {
+		if err := p.renewIntermediate(ctx); err != nil {
+			logger.Error().Err(err).Msg("Unable to renew the intermediate CA certificate")
+		} else {
+			renewed = true
+		}
+	}
+
+	var domainsToRenew []types.Domain
+	for _, cert := range p.serverCerts {
+		if !p.needsRenewal(cert, now) {
+			continue
+		}
+
+		domainsToRenew = append(domainsToRenew, cert.Domain)
+	}
+
+	for _, domain := range domainsToRenew {
+		delete(p.serverCerts, domainKey(domain))
+	}
+
+	if p.issueServerCerts(ctx, domainsToRenew) {
+		renewed = true
+	}
+
+	if p.issueClientCerts(ctx, now) {
+		renewed = true
+	}
+
+	return renewed
+}
+
+// findDomains goes through the given dynamic.Configuration and returns all
+// the domains of the routers using the resolver.
+func (p *Provider) findDomains(ctx context.Context, cfg dynamic.Configuration) []types.Domain {
+	logger := log.Ctx(ctx)
+
+	var domains []types.Domain
+
+	if cfg.HTTP != nil {
+		for _, router := range cfg.HTTP.Routers {
+			if router.TLS == nil || router.TLS.CertResolver != p.ResolverName {
+				continue
+			}
+
+			// As a domain list is explicitly defined we are only using the configured domains.
+			if len(router.TLS.Domains) > 0 {
+				domains = append(domains, router.TLS.Domains...)
+				continue
+			}
+
+			parsedDomains, err := httpmuxer.ParseDomains(router.Rule)
+			if err != nil {
+				logger.Error().Err(err).Msg("Unable to parse HTTP router domains")
+				continue
+			}
+
+			if len(parsedDomains) > 0 {
+				domains = append(domains, types.Domain{Main: parsedDomains[0], SANs: parsedDomains[1:]})
+			}
+		}
+	}
+
+	if cfg.TCP != nil {
+		for _, router := range cfg.TCP.Routers {
+			if router.TLS == nil || router.TLS.CertResolver != p.ResolverName {
+				continue
+			}
+
+			// As a domain list is explicitly defined we are only using the configured domains.
+			if len(router.TLS.Domains) > 0 {
+				domains = append(domains, router.TLS.Domains...)
+				continue
+			}
+
+			parsedDomains, err := tcpmuxer.ParseHostSNI(router.Rule)
+			if err != nil {
+				logger.Error().Err(err).Msg("Unable to parse TCP router domains")
+				continue
+			}
+
+			if len(parsedDomains) > 0 {
+				domains = append(domains, types.Domain{Main: parsedDomains[0], SANs: parsedDomains[1:]})
+			}
+		}
+	}
+
+	return deduplicateDomains(domains)
+}
+
+// purgeUnusedCerts removes the server certificates of the domains which are not used anymore,
+// and returns whether some certificates have been removed.
+// This method is not thread safe, the certsMu lock must be held.
+func (p *Provider) purgeUnusedCerts(domains []types.Domain) bool {
+	newServerCerts := make(map[string]*Certificate)
+	for _, domain := range domains {
+		if cert, ok := p.serverCerts[domainKey(domain)]; ok {
+			newServerCerts[domainKey(domain)] = cert
+		}
+	}
+
+	purged := len(p.serverCerts) > len(newServerCerts)
+
+	p.serverCerts = newServerCerts
+
+	return purged
+}
+
+// issueServerCerts issues the certificates of the given domains which do not have one yet,
+// and returns whether some certificates have been issued.
+// This method is not thread safe, the certsMu lock must be held.
+func (p *Provider) issueServerCerts(ctx context.Context, domains []types.Domain) bool {
+	logger := log.Ctx(ctx)
+
+	var issued bool
+	for _, domain := range domains {
+		if _, ok := p.serverCerts[domainKey(domain)]; ok {
+			continue
+		}
+
+		cert, err := p.ca.issueServerCertificate(domain, time.Duration(p.CertificatesDuration))
+		if err != nil {
+			logger.Error().Err(err).Msgf("Unable to issue certificate for domains %v", domain.ToStrArray())
+			continue
+		}
+
+		logger.Debug().Msgf("Issued certificate for domains %v", domain.ToStrArray())
+
+		p.serverCerts[domainKey(domain)] = cert
+		issued = true
+	}
+
+	return issued
+}
+
+// issueClientCerts issues the client certificates of the servers transports which are missing,
+// which do not match the configuration anymore, or which need to be renewed,
+// and returns whether some certificates have been issued.
+// This method is not thread safe, the certsMu lock must be held.
+func (p *Provider) issueClientCerts(ctx context.Context, now time.Time) bool {
+	logger := log.Ctx(ctx)
+
+	clientCerts := make(map[string]*Certificate)
+
+	var issued bool
+	for _, st := range p.ServersTransports {
+		cert, ok := p.clientCerts[st.Name]
+		if ok && matchServersTransport(cert, st) && !p.needsRenewal(cert, now) {
+			clientCerts[st.Name] = cert
+			continue
+		}
+
+		cert, err := p.ca.issueClientCertificate(st, time.Duration(p.CertificatesDuration))
+		if err != nil {
+			logger.Error().Err(err).Msgf("Unable to issue client certificate for servers transport %q", st.Name)
+			continue
+		}
+
+		logger.Debug().Msgf("Issued client certificate for servers transport %q", st.Name)
+
+		clientCerts[st.Name] = cert
+		issued = true
+	}
+
+	p.clientCerts = clientCerts
+
+	return issued
+}
+
+// saveAndSend persists the CA and certificates, and sends them to Traefik core.
+func (p *Provider) saveAndSend(ctx context.Context) {
+	if err := p.save(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Unable to store the private CA data")
+	}
+
+	p.sendDynamicConfig()
+}
+
+// save persists the CA and certificates in the store.
+func (p *Provider) save() error {
+	p.certsMu.RLock()
+	defer p.certsMu.RUnlock()
+
+	data := &StoredData{
+		Root:         p.root,
+		Intermediate: p.intermediate,
+	}
+
+	for _, key := range sortedKeys(p.serverCerts) {
+		data.Certificates = append(data.Certificates, p.serverCerts[key])
+	}
+
+	for _, name := range sortedKeys(p.clientCerts) {
+		data.Certificates = append(data.Certificates, p.clientCerts[name])
+	}
+
+	return p.Store.Save(p.ResolverName, data)
+}
+
+// sendDynamicConfig sends a dynamic.Message with the dynamic.Configuration
+// containing the issued certificates, and the servers transports using the client certificates.
+func (p *Provider) sendDynamicConfig() {
+	p.certsMu.RLock()
+	defer p.certsMu.RUnlock()
+
+	cfg := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
+		},
+		TCP: &dynamic.TCPConfiguration{
+			ServersTransports: make(map[string]*dynamic.TCPServersTransport),
+		},
+		TLS: &dynamic.TLSConfiguration{},
+	}
+
+	// The certificates are always sent sorted, to make sure that two identical sets,
+	// that would be sorted differently, do not trigger another configuration update.
+	for _, key := range sortedKeys(p.serverCerts) {
+		cert := p.serverCerts[key]
+
+		// Only the default store is supported.
+		cfg.TLS.Certificates = append(cfg.TLS.Certificates, &traefiktls.CertAndStores{
+			Stores: []string{traefiktls.DefaultTLSStoreName},
+			Certificate: traefiktls.Certificate{
+				CertFile: types.FileOrContent(cert.Certificate),
+				KeyFile:  types.FileOrContent(cert.Key),
+			},
+		})
+	}
+
+	rootCAs := []types.FileOrContent{types.FileOrContent(p.ca.rootPEM)}
+
+	for _, st := range p.ServersTransports {
+		cert, ok := p.clientCerts[st.Name]
+		if !ok {
+			continue
+		}
+
+		certificates := traefiktls.Certificates{{
+			CertFile: types.FileOrContent(cert.Certificate),
+			KeyFile:  types.FileOrContent(cert.Key),
+		}}
+
+		httpTransport := &dynamic.ServersTransport{
+			ServerName:         st.ServerName,
+			RootCAs:            rootCAs,
+			Certificates:       certificates,
+			ForwardingTimeouts: &dynamic.ForwardingTimeouts{},
+		}
+		httpTransport.ForwardingTimeouts.SetDefaults()
+		cfg.HTTP.ServersTransports[st.Name] = httpTransport
+
+		tcpTransport := &dynamic.TCPServersTransport{
+			TLS: &dynamic.TLSClientConfig{
+				ServerName:   st.ServerName,
+				RootCAs:      rootCAs,
+				Certificates: certificates,
+			},
+		}
+		tcpTransport.SetDefaults()
+		cfg.TCP.ServersTransports[st.Name] = tcpTransport
+	}
+
+	p.dynMessages <- dynamic.Message{
+		ProviderName:  p.ResolverName + resolverSuffix,
+		Configuration: cfg,
+	}
+}
+
+// isValid returns whether the given certificate chain has been issued by the root CA, and is valid at the given time.
+func (p *Provider) isValid(cert *Certificate, now time.Time) bool {
+	leaf, intermediates, err := parseChain(cert.Certificate)
+	if err != nil {
+		return false
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(p.ca.root)
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+
+	return err == nil
+}
+
+// needsRenewal returns whether the given certificate chain must be renewed at the given time.
+func (p *Provider) needsRenewal(cert *Certificate, now time.Time) bool {
+	leaf, _, err := parseChain(cert.Certificate)
+	if err != nil {
+		return true
+	}
+
+	return needsRenewal(leaf, now)
+}
+
+// needsRenewal returns whether less than a third of the validity period of the given certificate remains at the given time.
+func needsRenewal(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+
+	return cert.NotAfter.Sub(now) < lifetime/3
+}
+
+// renewalCheckInterval returns the interval between the renewal checks,
+// which must be short enough to renew the certificates before they expire.
+func renewalCheckInterval(duration time.Duration) time.Duration {
+	return max(duration/6, time.Minute)
+}
+
+// matchServersTransport returns whether the given client certificate has been issued with the given servers transport configuration.
+func matchServersTransport(cert *Certificate, st ServersTransport) bool {
+	leaf, _, err := parseChain(cert.Certificate)
+	if err != nil {
+		return false
+	}
+
+	var uris []string
+	for _, uri := range leaf.URIs {
+		uris = append(uris, uri.String())
+	}
+
+	return leaf.Subject.CommonName == st.CommonName && slices.Equal(uris, st.URIs)
+}
+
+// parseChain parses the given PEM encoded certificate chain,
+// and returns the leaf certificate and the pool of the intermediate certificates.
+func parseChain(chain []byte) (*x509.Certificate, *x509.CertPool, error) {
+	var leaf *x509.Certificate
+	intermediates := x509.NewCertPool()
+
+	for block, rest := pem.Decode(chain); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if leaf == nil {
+			leaf = cert
+			continue
+		}
+
+		intermediates.AddCert(cert)
+	}
+
+	if leaf == nil {
+		return nil, nil, errors.New("no certificate found")
+	}
+
+	return leaf, intermediates, nil
+}
+
+// deduplicateDomains removes the duplicated domains from the provided list.
+func deduplicateDomains(domains []types.Domain) []types.Domain {
+	seen := map[string]struct{}{}
+
+	var deduplicated []types.Domain
+	for _, domain := range domains {
+		if _, ok := seen[domainKey(domain)]; ok {
+			continue
+		}
+
+		deduplicated = append(deduplicated, domain)
+		seen[domainKey(domain)] = struct{}{}
+	}
+
+	return deduplicated
+}
+
+func domainKey(domain types.Domain) string {
+	return strings.Join(domain.ToStrArray(), ",")
+}
+
+func sortedKeys(certs map[string]*Certificate) []string {
+	var keys []string
+	for key := range certs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
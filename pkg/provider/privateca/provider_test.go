@@ -0,0 +1,351 @@
+package privateca
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestProvider_Init(t *testing.T) {
+	store := newMemoryStore()
+
+	p := newTestProvider(t, store, nil)
+
+	data, err := store.Get("test")
+	require.NoError(t, err)
+	require.NotNil(t, data.Root)
+	require.NotNil(t, data.Intermediate)
+
+	// A new provider using the same store reuses the stored CA.
+	other := newTestProvider(t, store, nil)
+
+	assert.Equal(t, p.ca.rootPEM, other.ca.rootPEM)
+	assert.Equal(t, p.ca.intermediatePEM, other.ca.intermediatePEM)
+}
+
+func TestProvider_Init_configuredRoot(t *testing.T) {
+	root, err := generateRoot("Custom CA", "P256")
+	require.NoError(t, err)
+
+	store := newMemoryStore()
+
+	p := newTestProvider(t, store, func(config *Configuration) {
+		config.RootCertificate = types.FileOrContent(root.Certificate)
+		config.RootKey = types.FileOrContent(root.Key)
+	})
+
+	assert.Equal(t, "Custom CA Root", p.ca.root.Subject.CommonName)
+	assert.NoError(t, p.ca.intermediate.CheckSignatureFrom(p.ca.root))
+
+	// The configured root is not persisted.
+	data, err := store.Get("test")
+	require.NoError(t, err)
+	assert.Nil(t, data.Root)
+	assert.NotNil(t, data.Intermediate)
+}
+
+func TestProvider_Init_invalidConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config func(config *Configuration)
+	}{
+		{
+			desc: "zero certificates duration",
+			config: func(config *Configuration) {
+				config.CertificatesDuration = 0
+			},
+		},
+		{
+			desc: "servers transport without name",
+			config: func(config *Configuration) {
+				config.ServersTransports = []ServersTransport{{CommonName: "traefik"}}
+			},
+		},
+		{
+			desc: "duplicated servers transport",
+			config: func(config *Configuration) {
+				config.ServersTransports = []ServersTransport{{Name: "foo"}, {Name: "foo"}}
+			},
+		},
+		{
+			desc: "root certificate without key",
+			config: func(config *Configuration) {
+				config.RootCertificate = "-----BEGIN CERTIFICATE-----"
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &Configuration{}
+			config.SetDefaults()
+			test.config(config)
+
+			p := &Provider{Configuration: config, ResolverName: "test", Store: newMemoryStore()}
+
+			assert.Error(t, p.Init())
+		})
+	}
+}
+
+func TestProvider_findDomains(t *testing.T) {
+	p := &Provider{ResolverName: "foo"}
+
+	cfg := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"rule": {
+					Rule: "Host(`a.internal`) || Host(`b.internal`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "foo"},
+				},
+				"domains": {
+					Rule: "Host(`c.internal`)",
+					TLS: &dynamic.RouterTLSConfig{
+						CertResolver: "foo",
+						Domains:      []types.Domain{{Main: "*.internal", SANs: []string{"internal"}}},
+					},
+				},
+				"duplicated": {
+					Rule: "Host(`a.internal`) || Host(`b.internal`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "foo"},
+				},
+				"other resolver": {
+					Rule: "Host(`d.internal`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "bar"},
+				},
+				"no TLS": {
+					Rule: "Host(`e.internal`)",
+				},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"tcp": {
+					Rule: "HostSNI(`f.internal`)",
+					TLS:  &dynamic.RouterTCPTLSConfig{CertResolver: "foo"},
+				},
+			},
+		},
+	}
+
+	domains := p.findDomains(t.Context(), cfg)
+
+	assert.ElementsMatch(t, []types.Domain{
+		{Main: "a.internal", SANs: []string{"b.internal"}},
+		{Main: "*.internal", SANs: []string{"internal"}},
+		{Main: "f.internal", SANs: []string{}},
+	}, domains)
+}
+
+func TestProvider_issueCertificates(t *testing.T) {
+	store := newMemoryStore()
+
+	p := newTestProvider(t, store, func(config *Configuration) {
+		config.ServersTransports = []ServersTransport{{
+			Name:       "internal",
+			CommonName: "traefik",
+			URIs:       []string{"spiffe://example.org/traefik"},
+			ServerName: "backend.internal",
+		}}
+	})
+
+	domains := []types.Domain{
+		{Main: "a.internal", SANs: []string{"b.internal", "10.0.0.1"}},
+		{Main: "c.internal"},
+	}
+
+	p.certsMu.Lock()
+	issued := p.issueServerCerts(t.Context(), domains)
+	p.certsMu.Unlock()
+	assert.True(t, issued)
+
+	p.certsMu.Lock()
+	issued = p.issueServerCerts(t.Context(), domains)
+	p.certsMu.Unlock()
+	assert.False(t, issued)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(p.ca.root)
+
+	cert := p.serverCerts["a.internal,b.internal,10.0.0.1"]
+	require.NotNil(t, cert)
+
+	leaf := verify(t, cert, roots, x509.ExtKeyUsageServerAuth)
+	assert.Equal(t, []string{"a.internal", "b.internal"}, leaf.DNSNames)
+	require.Len(t, leaf.IPAddresses, 1)
+	assert.Equal(t, "10.0.0.1", leaf.IPAddresses[0].String())
+	assert.WithinDuration(t, time.Now().Add(time.Hour), leaf.NotAfter, time.Minute)
+
+	p.certsMu.Lock()
+	issued = p.issueClientCerts(t.Context(), time.Now())
+	p.certsMu.Unlock()
+	assert.True(t, issued)
+
+	clientCert := p.clientCerts["internal"]
+	require.NotNil(t, clientCert)
+
+	leaf = verify(t, clientCert, roots, x509.ExtKeyUsageClientAuth)
+	assert.Equal(t, "traefik", leaf.Subject.CommonName)
+	require.Len(t, leaf.URIs, 1)
+	assert.Equal(t, "spiffe://example.org/traefik", leaf.URIs[0].String())
+
+	// The unused certificates are purged.
+	p.certsMu.Lock()
+	purged := p.purgeUnusedCerts(domains[1:])
+	p.certsMu.Unlock()
+	assert.True(t, purged)
+	assert.Len(t, p.serverCerts, 1)
+
+	// The certificates are restored by a new provider using the same store.
+	require.NoError(t, p.save())
+
+	other := newTestProvider(t, store, func(config *Configuration) {
+		config.ServersTransports = p.ServersTransports
+	})
+	assert.Equal(t, p.serverCerts, other.serverCerts)
+	assert.Equal(t, p.clientCerts, other.clientCerts)
+
+	// The client certificate is issued again when the servers transport configuration changes.
+	other.ServersTransports = []ServersTransport{{Name: "internal", CommonName: "proxy"}}
+
+	other.certsMu.Lock()
+	issued = other.issueClientCerts(t.Context(), time.Now())
+	other.certsMu.Unlock()
+	assert.True(t, issued)
+	assert.NotEqual(t, p.clientCerts["internal"], other.clientCerts["internal"])
+}
+
+func TestProvider_renewExpiringCerts(t *testing.T) {
+	p := newTestProvider(t, newMemoryStore(), func(config *Configuration) {
+		config.ServersTransports = []ServersTransport{{Name: "internal", CommonName: "traefik"}}
+	})
+
+	p.certsMu.Lock()
+	p.issueServerCerts(t.Context(), []types.Domain{{Main: "a.internal"}})
+	p.issueClientCerts(t.Context(), time.Now())
+	p.certsMu.Unlock()
+
+	serverCert := p.serverCerts["a.internal"]
+	clientCert := p.clientCerts["internal"]
+
+	assert.False(t, p.renewExpiringCerts(t.Context(), time.Now()))
+	assert.Same(t, serverCert, p.serverCerts["a.internal"])
+
+	// Less than a third of the certificates duration remains.
+	assert.True(t, p.renewExpiringCerts(t.Context(), time.Now().Add(45*time.Minute)))
+	assert.NotSame(t, serverCert, p.serverCerts["a.internal"])
+	assert.NotSame(t, clientCert, p.clientCerts["internal"])
+}
+
+func TestProvider_sendDynamicConfig(t *testing.T) {
+	p := newTestProvider(t, newMemoryStore(), func(config *Configuration) {
+		config.ServersTransports = []ServersTransport{{Name: "internal", CommonName: "traefik", ServerName: "backend.internal"}}
+	})
+
+	p.certsMu.Lock()
+	p.issueServerCerts(t.Context(), []types.Domain{{Main: "b.internal"}, {Main: "a.internal"}})
+	p.issueClientCerts(t.Context(), time.Now())
+	p.certsMu.Unlock()
+
+	messages := make(chan dynamic.Message, 1)
+	p.dynMessages = messages
+
+	p.sendDynamicConfig()
+
+	msg := <-messages
+	assert.Equal(t, "test.privateca", msg.ProviderName)
+
+	certs := msg.Configuration.TLS.Certificates
+	require.Len(t, certs, 2)
+	assert.Equal(t, types.FileOrContent(p.serverCerts["a.internal"].Certificate), certs[0].CertFile)
+	assert.Equal(t, types.FileOrContent(p.serverCerts["b.internal"].Certificate), certs[1].CertFile)
+
+	httpTransport := msg.Configuration.HTTP.ServersTransports["internal"]
+	require.NotNil(t, httpTransport)
+	assert.Equal(t, "backend.internal", httpTransport.ServerName)
+	assert.Equal(t, []types.FileOrContent{types.FileOrContent(p.ca.rootPEM)}, httpTransport.RootCAs)
+	require.Len(t, httpTransport.Certificates, 1)
+	assert.Equal(t, types.FileOrContent(p.clientCerts["internal"].Certificate), httpTransport.Certificates[0].CertFile)
+
+	tcpTransport := msg.Configuration.TCP.ServersTransports["internal"]
+	require.NotNil(t, tcpTransport)
+	require.NotNil(t, tcpTransport.TLS)
+	assert.Equal(t, httpTransport.Certificates, tcpTransport.TLS.Certificates)
+	assert.Equal(t, ptypes.Duration(30*time.Second), tcpTransport.DialTimeout)
+}
+
+func newTestProvider(t *testing.T, store Store, setup func(config *Configuration)) *Provider {
+	t.Helper()
+
+	config := &Configuration{}
+	config.SetDefaults()
+	config.CertificatesDuration = ptypes.Duration(time.Hour)
+
+	if setup != nil {
+		setup(config)
+	}
+
+	p := &Provider{Configuration: config, ResolverName: "test", Store: store}
+	require.NoError(t, p.Init())
+
+	return p
+}
+
+// verify verifies the given certificate chain against the given roots, and returns its leaf certificate.
+func verify(t *testing.T, cert *Certificate, roots *x509.CertPool, usage x509.ExtKeyUsage) *x509.Certificate {
+	t.Helper()
+
+	_, err := tls.X509KeyPair(cert.Certificate, cert.Key)
+	require.NoError(t, err)
+
+	leaf, intermediates, err := parseChain(cert.Certificate)
+	require.NoError(t, err)
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	require.NoError(t, err)
+
+	return leaf
+}
+
+type memoryStore struct {
+	mu   sync.Mutex
+	data map[string]*StoredData
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{data: make(map[string]*StoredData)}
+}
+
+func (s *memoryStore) Get(resolverName string) (*StoredData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[resolverName] == nil {
+		return &StoredData{}, nil
+	}
+
+	return s.data[resolverName], nil
+}
+
+func (s *memoryStore) Save(resolverName string, data *StoredData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[resolverName] = data
+
+	return nil
+}
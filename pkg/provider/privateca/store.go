@@ -0,0 +1,26 @@
+package privateca
+
+import "github.com/traefik/traefik/v3/pkg/types"
+
+// StoredData represents the data managed by Store.
+type StoredData struct {
+	Root         *Certificate
+	Intermediate *Certificate
+	Certificates []*Certificate
+}
+
+// Certificate holds a PEM encoded certificate chain and its PEM encoded private key.
+// The certificates issued for routers define the Domain,
+// and the client certificates define the name of the ServersTransport using them.
+type Certificate struct {
+	Domain           types.Domain `json:"domain,omitempty" toml:"domain,omitempty" yaml:"domain,omitempty"`
+	ServersTransport string       `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty"`
+	Certificate      []byte       `json:"certificate,omitempty" toml:"certificate,omitempty" yaml:"certificate,omitempty"`
+	Key              []byte       `json:"key,omitempty" toml:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// Store is a generic interface that represents a storage.
+type Store interface {
+	Get(resolverName string) (*StoredData, error)
+	Save(resolverName string, data *StoredData) error
+}
@@ -0,0 +1,174 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tls"
+)
+
+// resource is a kind of element of the dynamic configuration, which can be managed individually through the REST API.
+type resource interface {
+	get(configuration *dynamic.Configuration, name string) (any, bool)
+	decode(body io.Reader) (any, error)
+	validate(value any) error
+	validateAll(configuration *dynamic.Configuration) error
+	put(configuration *dynamic.Configuration, name string, value any)
+	delete(configuration *dynamic.Configuration, name string) bool
+}
+
+// resourceKind is a resource stored in a map of the dynamic configuration.
+type resourceKind[V any] struct {
+	// elements returns the map holding the elements of the resource kind,
+	// initializing the sections of the configuration if needed.
+	elements func(configuration *dynamic.Configuration) *map[string]V
+	check    func(value V) error
+}
+
+func (k resourceKind[V]) get(configuration *dynamic.Configuration, name string) (any, bool) {
+	value, ok := (*k.elements(configuration))[name]
+	return value, ok
+}
+
+func (k resourceKind[V]) decode(body io.Reader) (any, error) {
+	var value V
+	if err := json.NewDecoder(body).Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if v := reflect.ValueOf(&value).Elem(); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, errors.New("empty resource")
+	}
+
+	return value, nil
+}
+
+func (k resourceKind[V]) validate(value any) error {
+	if k.check == nil {
+		return nil
+	}
+
+	return k.check(value.(V))
+}
+
+func (k resourceKind[V]) validateAll(configuration *dynamic.Configuration) error {
+	for name, value := range *k.elements(configuration) {
+		if v := reflect.ValueOf(&value).Elem(); v.Kind() == reflect.Pointer && v.IsNil() {
+			return fmt.Errorf("%s: empty resource", name)
+		}
+
+		if err := k.validate(value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (k resourceKind[V]) put(configuration *dynamic.Configuration, name string, value any) {
+	elements := k.elements(configuration)
+	if *elements == nil {
+		*elements = make(map[string]V)
+	}
+
+	(*elements)[name] = value.(V)
+}
+
+func (k resourceKind[V]) delete(configuration *dynamic.Configuration, name string) bool {
+	elements := k.elements(configuration)
+	if _, ok := (*elements)[name]; !ok {
+		return false
+	}
+
+	delete(*elements, name)
+	return true
+}
+
+// resources returns the resource kinds which can be managed through the REST API,
+// by protocol and kind (e.g. http/routers).
+func (p *Provider) resources() map[string]resource {
+	return map[string]resource{
+		"http/routers": resourceKind[*dynamic.Router]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.Router { return &httpConfiguration(c).Routers },
+			check:    p.validateHTTPRouter,
+		},
+		"http/services": resourceKind[*dynamic.Service]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.Service { return &httpConfiguration(c).Services },
+			check:    validateHTTPService,
+		},
+		"http/middlewares": resourceKind[*dynamic.Middleware]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.Middleware {
+				return &httpConfiguration(c).Middlewares
+			},
+			check: validateHTTPMiddleware,
+		},
+		"http/serversTransports": resourceKind[*dynamic.ServersTransport]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.ServersTransport {
+				return &httpConfiguration(c).ServersTransports
+			},
+		},
+		"tcp/routers": resourceKind[*dynamic.TCPRouter]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.TCPRouter { return &tcpConfiguration(c).Routers },
+			check:    p.validateTCPRouter,
+		},
+		"tcp/services": resourceKind[*dynamic.TCPService]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.TCPService { return &tcpConfiguration(c).Services },
+			check:    validateTCPService,
+		},
+		"tcp/middlewares": resourceKind[*dynamic.TCPMiddleware]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.TCPMiddleware {
+				return &tcpConfiguration(c).Middlewares
+			},
+			check: validateTCPMiddleware,
+		},
+		"tcp/serversTransports": resourceKind[*dynamic.TCPServersTransport]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.TCPServersTransport {
+				return &tcpConfiguration(c).ServersTransports
+			},
+		},
+		"udp/routers": resourceKind[*dynamic.UDPRouter]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.UDPRouter { return &udpConfiguration(c).Routers },
+			check:    validateUDPRouter,
+		},
+		"udp/services": resourceKind[*dynamic.UDPService]{
+			elements: func(c *dynamic.Configuration) *map[string]*dynamic.UDPService { return &udpConfiguration(c).Services },
+			check:    validateUDPService,
+		},
+		"tls/options": resourceKind[tls.Options]{
+			elements: func(c *dynamic.Configuration) *map[string]tls.Options { return &tlsConfiguration(c).Options },
+			check:    validateTLSOptions,
+		},
+	}
+}
+
+func httpConfiguration(configuration *dynamic.Configuration) *dynamic.HTTPConfiguration {
+	if configuration.HTTP == nil {
+		configuration.HTTP = &dynamic.HTTPConfiguration{}
+	}
+	return configuration.HTTP
+}
+
+func tcpConfiguration(configuration *dynamic.Configuration) *dynamic.TCPConfiguration {
+	if configuration.TCP == nil {
+		configuration.TCP = &dynamic.TCPConfiguration{}
+	}
+	return configuration.TCP
+}
+
+func udpConfiguration(configuration *dynamic.Configuration) *dynamic.UDPConfiguration {
+	if configuration.UDP == nil {
+		configuration.UDP = &dynamic.UDPConfiguration{}
+	}
+	return configuration.UDP
+}
+
+func tlsConfiguration(configuration *dynamic.Configuration) *dynamic.TLSConfiguration {
+	if configuration.TLS == nil {
+		configuration.TLS = &dynamic.TLSConfiguration{}
+	}
+	return configuration.TLS
+}
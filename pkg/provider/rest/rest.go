@@ -1,23 +1,44 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/provider"
+	"github.com/traefik/traefik/v3/pkg/redactor"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/unrolled/render"
 )
 
 var _ provider.Provider = (*Provider)(nil)
 
+const providerName = "rest"
+
+var errPreconditionFailed = errors.New("precondition failed")
+
 // Provider is a provider.Provider implementation that provides a Rest API.
 type Provider struct {
-	Insecure          bool `description:"Activate REST Provider directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Insecure bool   `description:"Activate REST Provider directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Storage  string `description:"Defines the file where the configuration managed through the REST API is persisted, and restored from on startup." json:"storage,omitempty" toml:"storage,omitempty" yaml:"storage,omitempty" export:"true"`
+
+	// The default rule syntax is initialized with the configuration defined by the user with the core.DefaultRuleSyntax option.
+	DefaultRuleSyntax string `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
+
+	mu                sync.Mutex
+	configuration     *dynamic.Configuration
 	configurationChan chan<- dynamic.Message
 }
 
@@ -34,13 +55,18 @@ func (p *Provider) Init() error {
 // CreateRouter creates a router for the Rest API.
 func (p *Provider) CreateRouter() *mux.Router {
 	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/api/providers/rest").HandlerFunc(p.getConfiguration)
 	router.Methods(http.MethodPut).Path("/api/providers/{provider}").Handler(p)
+	router.Methods(http.MethodGet).Path("/api/providers/rest/{protocol}/{kind}/{name}").HandlerFunc(p.getResource)
+	router.Methods(http.MethodPut).Path("/api/providers/rest/{protocol}/{kind}/{name}").HandlerFunc(p.putResource)
+	router.Methods(http.MethodDelete).Path("/api/providers/rest/{protocol}/{kind}/{name}").HandlerFunc(p.deleteResource)
 	return router
 }
 
+// ServeHTTP replaces the whole configuration of the provider.
 func (p *Provider) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
-	if vars["provider"] != "rest" {
+	if vars["provider"] != providerName {
 		http.Error(rw, "Only 'rest' provider can be updated through the REST API", http.StatusBadRequest)
 		return
 	}
@@ -53,15 +79,324 @@ func (p *Provider) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	p.configurationChan <- dynamic.Message{ProviderName: "rest", Configuration: configuration}
-	if err := templatesRenderer.JSON(rw, http.StatusOK, configuration); err != nil {
+	if err := p.validateConfiguration(configuration.DeepCopy()); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.configurationChan == nil {
+		http.Error(rw, "The REST provider is not started", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := checkPreconditions(req, p.currentConfiguration(), true); err != nil {
+		http.Error(rw, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	if err := p.apply(configuration); err != nil {
+		log.Error().Err(err).Msg("Error applying configuration")
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeValue(rw, http.StatusOK, configuration)
+}
+
+func (p *Provider) getConfiguration(rw http.ResponseWriter, _ *http.Request) {
+	p.mu.Lock()
+	configuration := p.currentConfiguration()
+	p.mu.Unlock()
+
+	writeValue(rw, http.StatusOK, configuration)
+}
+
+func (p *Provider) getResource(rw http.ResponseWriter, req *http.Request) {
+	res, name, ok := p.lookupResource(rw, req)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	value, exists := res.get(p.currentConfiguration(), name)
+	p.mu.Unlock()
+
+	if !exists {
+		http.Error(rw, fmt.Sprintf("%s not found", name), http.StatusNotFound)
+		return
+	}
+
+	writeValue(rw, http.StatusOK, value)
+}
+
+// putResource creates or replaces a resource of the configuration.
+// The resource is only created when the If-None-Match header is "*",
+// and only replaced when the If-Match header matches its ETag.
+func (p *Provider) putResource(rw http.ResponseWriter, req *http.Request) {
+	res, name, ok := p.lookupResource(rw, req)
+	if !ok {
+		return
+	}
+
+	value, err := res.decode(req.Body)
+	if err != nil {
+		log.Error().Err(err).Msg("Error parsing configuration")
+		http.Error(rw, fmt.Sprintf("%+v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := res.validate(value); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.configurationChan == nil {
+		http.Error(rw, "The REST provider is not started", http.StatusServiceUnavailable)
+		return
+	}
+
+	configuration := p.currentConfiguration()
+
+	current, exists := res.get(configuration, name)
+	if err := checkPreconditions(req, current, exists); err != nil {
+		http.Error(rw, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	res.put(configuration, name, value)
+
+	if err := p.apply(configuration); err != nil {
+		log.Error().Err(err).Msg("Error applying configuration")
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if !exists {
+		status = http.StatusCreated
+	}
+
+	writeValue(rw, status, value)
+}
+
+func (p *Provider) deleteResource(rw http.ResponseWriter, req *http.Request) {
+	res, name, ok := p.lookupResource(rw, req)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.configurationChan == nil {
+		http.Error(rw, "The REST provider is not started", http.StatusServiceUnavailable)
+		return
+	}
+
+	configuration := p.currentConfiguration()
+
+	current, exists := res.get(configuration, name)
+	if !exists {
+		http.Error(rw, fmt.Sprintf("%s not found", name), http.StatusNotFound)
+		return
+	}
+
+	if err := checkPreconditions(req, current, exists); err != nil {
+		http.Error(rw, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	res.delete(configuration, name)
+
+	if err := p.apply(configuration); err != nil {
+		log.Error().Err(err).Msg("Error applying configuration")
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// writeValue writes the given configuration or resource, with its ETag.
+// The credentials are redacted from the response, but not from the ETag, so that it matches the stored value.
+func writeValue(rw http.ResponseWriter, status int, value any) {
+	// The value is redacted through a pointer, as the resources stored by value, such as the TLS options, cannot be set.
+	valuePtr := reflect.New(reflect.TypeOf(value))
+	valuePtr.Elem().Set(reflect.ValueOf(value))
+
+	redacted, err := redactor.Redact(valuePtr.Interface())
+	if err != nil {
+		log.Error().Err(err).Msg("Error redacting configuration")
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("ETag", etag(value))
+	if err := templatesRenderer.JSON(rw, status, redacted); err != nil {
 		log.Error().Err(err).Send()
 	}
 }
 
+func (p *Provider) lookupResource(rw http.ResponseWriter, req *http.Request) (resource, string, bool) {
+	vars := mux.Vars(req)
+
+	res, ok := p.resources()[vars["protocol"]+"/"+vars["kind"]]
+	if !ok {
+		http.Error(rw, fmt.Sprintf("unknown resource kind %s/%s", vars["protocol"], vars["kind"]), http.StatusNotFound)
+		return nil, "", false
+	}
+
+	if strings.Contains(vars["name"], "@") {
+		http.Error(rw, "the resource name must not contain the provider name", http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	return res, vars["name"], true
+}
+
+// currentConfiguration returns a copy of the current configuration.
+// It is the responsibility of the caller to hold the lock.
+func (p *Provider) currentConfiguration() *dynamic.Configuration {
+	if p.configuration == nil {
+		return &dynamic.Configuration{}
+	}
+	return p.configuration.DeepCopy()
+}
+
+// apply persists the given configuration, if a storage is defined, and sends it to Traefik.
+// It is the responsibility of the caller to hold the lock.
+func (p *Provider) apply(configuration *dynamic.Configuration) error {
+	if p.Storage != "" {
+		if err := writeStorage(p.Storage, configuration); err != nil {
+			return fmt.Errorf("persisting configuration: %w", err)
+		}
+	}
+
+	p.configuration = configuration
+	p.configurationChan <- dynamic.Message{ProviderName: providerName, Configuration: configuration.DeepCopy()}
+
+	return nil
+}
+
 // Provide allows the provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Storage != "" {
+		configuration, err := readStorage(p.Storage)
+		if err != nil {
+			return fmt.Errorf("reading storage %s: %w", p.Storage, err)
+		}
+
+		if configuration != nil {
+			p.configuration = configuration
+
+			pool.GoCtx(func(ctx context.Context) {
+				p.mu.Lock()
+				defer p.mu.Unlock()
+
+				// The configuration has already been sent if it has been updated in the meantime.
+				if p.configuration != configuration {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+				case configurationChan <- dynamic.Message{ProviderName: providerName, Configuration: configuration.DeepCopy()}:
+				}
+			})
+		}
+	}
+
 	p.configurationChan = configurationChan
 	return nil
 }
+
+func readStorage(path string) (*dynamic.Configuration, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := new(dynamic.Configuration)
+	if err := json.Unmarshal(data, configuration); err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// writeStorage atomically writes the configuration to the storage file.
+func writeStorage(path string, configuration *dynamic.Configuration) error {
+	data, err := json.MarshalIndent(configuration, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// checkPreconditions checks the If-Match and If-None-Match headers of the request against the ETag of the current value.
+func checkPreconditions(req *http.Request, current any, exists bool) error {
+	if ifMatch := req.Header.Get("If-Match"); ifMatch != "" {
+		if !exists || (ifMatch != "*" && !matchETag(ifMatch, etag(current))) {
+			return errPreconditionFailed
+		}
+	}
+
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if exists && (ifNoneMatch == "*" || matchETag(ifNoneMatch, etag(current))) {
+			return errPreconditionFailed
+		}
+	}
+
+	return nil
+}
+
+func matchETag(header, tag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// etag computes the entity tag of the given value from its JSON representation.
+func etag(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return `""`
+	}
+
+	hasher := fnv.New64a()
+	// purposely ignoring the error, as no error can be returned from the implementation.
+	_, _ = hasher.Write(data)
+	return strconv.Quote(strconv.FormatUint(hasher.Sum64(), 16))
+}
@@ -0,0 +1,217 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+func TestProvider_resources(t *testing.T) {
+	provider := &Provider{DefaultRuleSyntax: "v3"}
+	configurationChan := startProvider(t, provider)
+
+	router := provider.CreateRouter()
+
+	// Create a router.
+	resp := do(t, router, http.MethodPut, "/api/providers/rest/http/routers/foo", `{"rule":"Host(`+"`foo.localhost`"+`)","service":"bar"}`, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	routerETag := resp.Header().Get("ETag")
+	require.NotEmpty(t, routerETag)
+
+	msg := receive(t, configurationChan)
+	assert.Equal(t, "rest", msg.ProviderName)
+	assert.Equal(t, "bar", msg.Configuration.HTTP.Routers["foo"].Service)
+
+	// Create a service, the router is kept.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/services/bar", `{"loadBalancer":{"servers":[{"url":"http://127.0.0.1"}]}}`, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	msg = receive(t, configurationChan)
+	assert.Contains(t, msg.Configuration.HTTP.Routers, "foo")
+	assert.Contains(t, msg.Configuration.HTTP.Services, "bar")
+
+	resp = do(t, router, http.MethodGet, "/api/providers/rest/http/routers/foo", "", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, routerETag, resp.Header().Get("ETag"))
+
+	// Creating an existing router fails.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/routers/foo", `{"rule":"Host(`+"`foo.localhost`"+`)","service":"baz"}`, map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+
+	// Update the router.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/routers/foo", `{"rule":"Host(`+"`foo.localhost`"+`)","service":"baz"}`, map[string]string{"If-Match": routerETag})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.NotEqual(t, routerETag, resp.Header().Get("ETag"))
+
+	msg = receive(t, configurationChan)
+	assert.Equal(t, "baz", msg.Configuration.HTTP.Routers["foo"].Service)
+
+	// Updating a router from a stale version fails.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/routers/foo", `{"rule":"Host(`+"`foo.localhost`"+`)","service":"bar"}`, map[string]string{"If-Match": routerETag})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+
+	// Invalid resources are rejected.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/routers/invalid", `{"rule":"Host(","service":"bar"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/middlewares/invalid", `{"addPrefix":{"prefix":"/foo"},"stripPrefix":{"prefixes":["/foo"]}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/tls/options/invalid", `{"minVersion":"VersionTLS12","curvePreferences":["foo"]}`, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/foo/invalid", `{}`, nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// TLS options.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/tls/options/modern", `{"minVersion":"VersionTLS13"}`, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	msg = receive(t, configurationChan)
+	assert.Equal(t, "VersionTLS13", msg.Configuration.TLS.Options["modern"].MinVersion)
+
+	// Delete the router.
+	resp = do(t, router, http.MethodDelete, "/api/providers/rest/http/routers/foo", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	msg = receive(t, configurationChan)
+	assert.NotContains(t, msg.Configuration.HTTP.Routers, "foo")
+	assert.Contains(t, msg.Configuration.HTTP.Services, "bar")
+
+	resp = do(t, router, http.MethodDelete, "/api/providers/rest/http/routers/foo", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	assert.Empty(t, configurationChan)
+}
+
+func TestProvider_configuration(t *testing.T) {
+	provider := &Provider{DefaultRuleSyntax: "v3"}
+	configurationChan := startProvider(t, provider)
+
+	router := provider.CreateRouter()
+
+	resp := do(t, router, http.MethodGet, "/api/providers/rest", "", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	emptyETag := resp.Header().Get("ETag")
+
+	resp = do(t, router, http.MethodPut, "/api/providers/foo", `{}`, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = do(t, router, http.MethodPut, "/api/providers/rest", `{"tcp":{"routers":{"foo":{"rule":"HostSNI(`+"`*`"+`)","service":"bar"}}}}`, map[string]string{"If-Match": emptyETag})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	msg := receive(t, configurationChan)
+	assert.Equal(t, "bar", msg.Configuration.TCP.Routers["foo"].Service)
+
+	// The configuration has been updated since.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest", `{}`, map[string]string{"If-Match": emptyETag})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+
+	resp = do(t, router, http.MethodPut, "/api/providers/rest", `{"http":{"services":{"bar":{"loadBalancer":{},"weighted":{}}}}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "multi-types")
+
+	assert.Empty(t, configurationChan)
+}
+
+func TestProvider_redacted(t *testing.T) {
+	provider := &Provider{DefaultRuleSyntax: "v3"}
+	configurationChan := startProvider(t, provider)
+
+	router := provider.CreateRouter()
+
+	resp := do(t, router, http.MethodPut, "/api/providers/rest/http/middlewares/auth", `{"basicAuth":{"users":["test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"],"realm":"foo"}}`, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	assert.NotContains(t, resp.Body.String(), "test:")
+	assert.Contains(t, resp.Body.String(), "xxxx")
+	middlewareETag := resp.Header().Get("ETag")
+
+	// The credentials are sent to Traefik.
+	msg := receive(t, configurationChan)
+	assert.Equal(t, dynamic.Users{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}, msg.Configuration.HTTP.Middlewares["auth"].BasicAuth.Users)
+
+	resp = do(t, router, http.MethodGet, "/api/providers/rest/http/middlewares/auth", "", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "test:")
+	assert.Contains(t, resp.Body.String(), `"realm":"foo"`)
+	assert.Equal(t, middlewareETag, resp.Header().Get("ETag"))
+
+	resp = do(t, router, http.MethodGet, "/api/providers/rest", "", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "test:")
+
+	// The ETag is the one of the stored value, not of the redacted one.
+	resp = do(t, router, http.MethodPut, "/api/providers/rest/http/middlewares/auth", `{"basicAuth":{"users":["test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"],"realm":"bar"}}`, map[string]string{"If-Match": middlewareETag})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	msg = receive(t, configurationChan)
+	assert.Equal(t, "bar", msg.Configuration.HTTP.Middlewares["auth"].BasicAuth.Realm)
+
+	assert.Empty(t, configurationChan)
+}
+
+func TestProvider_storage(t *testing.T) {
+	storage := filepath.Join(t.TempDir(), "rest.json")
+
+	provider := &Provider{Storage: storage}
+	configurationChan := startProvider(t, provider)
+
+	resp := do(t, provider.CreateRouter(), http.MethodPut, "/api/providers/rest/udp/routers/foo", `{"service":"bar"}`, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	receive(t, configurationChan)
+
+	// The configuration is restored on startup.
+	restarted := &Provider{Storage: storage}
+	configurationChan = startProvider(t, restarted)
+
+	msg := receive(t, configurationChan)
+	assert.Equal(t, "bar", msg.Configuration.UDP.Routers["foo"].Service)
+}
+
+func startProvider(t *testing.T, provider *Provider) chan dynamic.Message {
+	t.Helper()
+
+	configurationChan := make(chan dynamic.Message, 10)
+
+	pool := safe.NewPool(t.Context())
+	t.Cleanup(pool.Stop)
+
+	require.NoError(t, provider.Init())
+	require.NoError(t, provider.Provide(configurationChan, pool))
+
+	return configurationChan
+}
+
+func do(t *testing.T, handler http.Handler, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	return rw
+}
+
+func receive(t *testing.T, configurationChan chan dynamic.Message) dynamic.Message {
+	t.Helper()
+
+	select {
+	case msg := <-configurationChan:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the configuration")
+		return dynamic.Message{}
+	}
+}
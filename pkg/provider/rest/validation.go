@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls"
+)
+
+// validateConfiguration checks the elements of the given configuration,
+// which must not be used afterward as the missing sections are initialized.
+func (p *Provider) validateConfiguration(configuration *dynamic.Configuration) error {
+	for kind, res := range p.resources() {
+		if err := res.validateAll(configuration); err != nil {
+			return fmt.Errorf("invalid %s: %w", kind, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) validateHTTPRouter(router *dynamic.Router) error {
+	if router.Rule == "" {
+		return nil
+	}
+
+	parser, err := httpmuxer.NewSyntaxParser()
+	if err != nil {
+		return err
+	}
+
+	return httpmuxer.NewMuxer(parser).AddRoute(router.Rule, p.ruleSyntax(router.RuleSyntax), 0, http.NotFoundHandler())
+}
+
+func (p *Provider) validateTCPRouter(router *dynamic.TCPRouter) error {
+	if router.Rule == "" {
+		return errors.New("empty rule")
+	}
+
+	muxer, err := tcpmuxer.NewMuxer()
+	if err != nil {
+		return err
+	}
+
+	return muxer.AddRoute(router.Rule, p.ruleSyntax(router.RuleSyntax), 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+}
+
+func (p *Provider) ruleSyntax(syntax string) string {
+	if syntax == "" || syntax == "default" {
+		return p.DefaultRuleSyntax
+	}
+	return syntax
+}
+
+func validateUDPRouter(router *dynamic.UDPRouter) error {
+	if router.Service == "" {
+		return errors.New("empty service")
+	}
+	return nil
+}
+
+func validateHTTPService(service *dynamic.Service) error {
+	return checkSingleType("service", service, "Middlewares", "Labels")
+}
+
+func validateTCPService(service *dynamic.TCPService) error {
+	return checkSingleType("service", service, "Labels")
+}
+
+func validateUDPService(service *dynamic.UDPService) error {
+	return checkSingleType("service", service, "Labels")
+}
+
+func validateHTTPMiddleware(middleware *dynamic.Middleware) error {
+	return checkSingleType("middleware", middleware, "Labels")
+}
+
+func validateTCPMiddleware(middleware *dynamic.TCPMiddleware) error {
+	return checkSingleType("middleware", middleware, "Labels")
+}
+
+func validateTLSOptions(options tls.Options) error {
+	return tls.ValidateOptions(options)
+}
+
+// checkSingleType checks that exactly one of the types of the given element is defined,
+// ignoring the given common fields.
+func checkSingleType(element string, value any, commonFields ...string) error {
+	v := reflect.ValueOf(value).Elem()
+
+	var count int
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() || field.Anonymous || slices.Contains(commonFields, field.Name) {
+			continue
+		}
+
+		switch v.Field(i).Kind() {
+		case reflect.Pointer:
+			if !v.Field(i).IsNil() {
+				count++
+			}
+		case reflect.Map:
+			if v.Field(i).Len() > 0 {
+				count++
+			}
+		default:
+		}
+	}
+
+	switch count {
+	case 0:
+		return fmt.Errorf("no %s type defined", element)
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("multi-types %s not supported, consider declaring two different pieces of %s instead", element, element)
+	}
+}
@@ -77,6 +77,7 @@ func (i *Provider) createConfiguration(ctx context.Context) *dynamic.Configurati
 	i.apiConfiguration(cfg)
 	i.pingConfiguration(cfg)
 	i.restConfiguration(cfg)
+	i.gitConfiguration(cfg)
 	i.prometheusConfiguration(cfg)
 	i.entryPointModels(cfg)
 	i.redirection(ctx, cfg)
@@ -373,6 +374,25 @@ func (i *Provider) restConfiguration(cfg *dynamic.Configuration) {
 	cfg.HTTP.Services["rest"] = &dynamic.Service{}
 }
 
+func (i *Provider) gitConfiguration(cfg *dynamic.Configuration) {
+	if i.staticCfg.Providers == nil || i.staticCfg.Providers.Git == nil || i.staticCfg.Providers.Git.Webhook == nil {
+		return
+	}
+
+	if i.staticCfg.Providers.Git.Webhook.Insecure {
+		cfg.HTTP.Routers["git"] = &dynamic.Router{
+			EntryPoints: []string{defaultInternalEntryPointName},
+			Service:     "git@internal",
+			Priority:    math.MaxInt,
+			Rule:        "Path(`/api/git/webhook`)",
+			// "default" stands for the default rule syntax in Traefik v3, i.e. the v3 syntax.
+			RuleSyntax: "default",
+		}
+	}
+
+	cfg.HTTP.Services["git"] = &dynamic.Service{}
+}
+
 func (i *Provider) prometheusConfiguration(cfg *dynamic.Configuration) {
 	if i.staticCfg.Metrics == nil || i.staticCfg.Metrics.Prometheus == nil {
 		return
@@ -13,6 +13,7 @@ import (
 	otypes "github.com/traefik/traefik/v3/pkg/observability/types"
 	"github.com/traefik/traefik/v3/pkg/ping"
 	acmeprovider "github.com/traefik/traefik/v3/pkg/provider/acme"
+	"github.com/traefik/traefik/v3/pkg/provider/git"
 	"github.com/traefik/traefik/v3/pkg/provider/rest"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
@@ -149,6 +150,28 @@ func Test_createConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "git_webhook_insecure.json",
+			staticCfg: static.Configuration{
+				Providers: &static.Providers{
+					Git: &git.Provider{
+						Webhook: &git.Webhook{
+							Insecure: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "git_webhook_secure.json",
+			staticCfg: static.Configuration{
+				Providers: &static.Providers{
+					Git: &git.Provider{
+						Webhook: &git.Webhook{},
+					},
+				},
+			},
+		},
 		{
 			desc: "prometheus_simple.json",
 			staticCfg: static.Configuration{
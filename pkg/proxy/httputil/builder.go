@@ -39,6 +39,17 @@ func (r *ProxyBuilder) Update(_ map[string]*dynamic.ServersTransport) {}
 
 // Build builds a new httputil.ReverseProxy with the given configuration.
 func (r *ProxyBuilder) Build(cfgName string, targetURL *url.URL, passHostHeader, preservePath bool, flushInterval time.Duration) (http.Handler, error) {
+	if targetURL.Scheme == "h3" {
+		serversTransport, err := r.transportManager.Get(cfgName)
+		if err != nil {
+			return nil, fmt.Errorf("getting ServersTransport: %w", err)
+		}
+
+		if serversTransport.HTTP3 == nil {
+			return nil, fmt.Errorf("the h3 scheme requires HTTP/3 to be enabled on the ServersTransport %s", cfgName)
+		}
+	}
+
 	roundTripper, err := r.transportManager.GetRoundTripper(cfgName)
 	if err != nil {
 		return nil, fmt.Errorf("getting RoundTripper: %w", err)
@@ -34,8 +34,19 @@ func TestEscapedPath(t *testing.T) {
 	assert.Equal(t, "/%3A%2F%2F", gotEscapedPath)
 }
 
+func TestBuild_h3SchemeWithoutHTTP3(t *testing.T) {
+	transportManager := &transportManagerMock{
+		roundTrippers:     map[string]http.RoundTripper{"default": &http.Transport{}},
+		serversTransports: map[string]*dynamic.ServersTransport{"default": {}},
+	}
+
+	_, err := NewProxyBuilder(transportManager, nil).Build("default", testhelpers.MustParseURL("h3://127.0.0.1:8443"), true, false, 0)
+	require.Error(t, err)
+}
+
 type transportManagerMock struct {
-	roundTrippers map[string]http.RoundTripper
+	roundTrippers     map[string]http.RoundTripper
+	serversTransports map[string]*dynamic.ServersTransport
 }
 
 func (t *transportManagerMock) GetRoundTripper(name string) (http.RoundTripper, error) {
@@ -51,6 +62,11 @@ func (t *transportManagerMock) GetTLSConfig(_ string) (*tls.Config, error) {
 	panic("implement me")
 }
 
-func (t *transportManagerMock) Get(_ string) (*dynamic.ServersTransport, error) {
-	panic("implement me")
+func (t *transportManagerMock) Get(name string) (*dynamic.ServersTransport, error) {
+	serversTransport, ok := t.serversTransports[name]
+	if !ok {
+		return nil, errors.New("no servers transport for " + name)
+	}
+
+	return serversTransport, nil
 }
@@ -92,7 +92,7 @@ func (t *wrapper) RoundTrip(req *http.Request) (*http.Response, error) {
 		case "http":
 			serverPort = 80
 			attrs = append(attrs, semconv.ServerPort(serverPort))
-		case "https":
+		case "https", "h3":
 			serverPort = 443
 			attrs = append(attrs, semconv.ServerPort(serverPort))
 		}
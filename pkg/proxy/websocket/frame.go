@@ -0,0 +1,179 @@
+package websocket
+
+import "encoding/binary"
+
+// WebSocket opcodes, see https://datatracker.ietf.org/doc/html/rfc6455#section-5.2.
+const (
+	opClose = 0x8
+	// Control frames have opcodes with the most significant bit set.
+	opControlMask = 0x8
+)
+
+// Close codes, see https://datatracker.ietf.org/doc/html/rfc6455#section-7.4.1.
+const (
+	// CloseGoingAway is the close code sent by Traefik when it closes a WebSocket connection.
+	CloseGoingAway = 1001
+	// CloseNoStatusReceived is the close code used for a close frame without status code.
+	CloseNoStatusReceived = 1005
+	// CloseAbnormalClosure is the close code used for a connection closed without any close frame.
+	CloseAbnormalClosure = 1006
+)
+
+// frameParser follows the frames of one direction of a WebSocket connection,
+// without buffering them, to report the messages and the close frames.
+type frameParser struct {
+	onMessage func()
+	onClose   func(code int)
+
+	// header holds the bytes of the header of the current frame, until it is complete.
+	header    [14]byte
+	headerLen int
+
+	inPayload bool
+	remaining uint64
+	offset    uint64
+
+	fin     bool
+	opcode  byte
+	masked  bool
+	maskKey [4]byte
+
+	closeCode [2]byte
+}
+
+// feed parses the given bytes, which continue the data already parsed.
+func (p *frameParser) feed(b []byte) {
+	for len(b) > 0 {
+		if !p.inPayload {
+			b = p.feedHeader(b)
+			continue
+		}
+
+		n := uint64(len(b))
+		if n > p.remaining {
+			n = p.remaining
+		}
+
+		if p.opcode == opClose {
+			for i := uint64(0); i < n && p.offset+i < 2; i++ {
+				c := b[i]
+				if p.masked {
+					c ^= p.maskKey[(p.offset+i)%4]
+				}
+				p.closeCode[p.offset+i] = c
+			}
+		}
+
+		p.offset += n
+		p.remaining -= n
+		b = b[n:]
+
+		if p.remaining == 0 {
+			p.endFrame()
+		}
+	}
+}
+
+// feedHeader consumes the bytes of the header of the current frame, and returns the remaining bytes.
+func (p *frameParser) feedHeader(b []byte) []byte {
+	for len(b) > 0 {
+		p.header[p.headerLen] = b[0]
+		p.headerLen++
+		b = b[1:]
+
+		size, ok := p.headerSize()
+		if !ok || p.headerLen < size {
+			continue
+		}
+
+		p.startFrame(size)
+		if p.remaining == 0 {
+			p.endFrame()
+		}
+
+		return b
+	}
+
+	return b
+}
+
+// headerSize returns the size of the header of the current frame, once it can be determined.
+func (p *frameParser) headerSize() (int, bool) {
+	if p.headerLen < 2 {
+		return 0, false
+	}
+
+	size := 2
+	switch p.header[1] & 0x7f {
+	case 126:
+		size += 2
+	case 127:
+		size += 8
+	}
+
+	if p.header[1]&0x80 != 0 {
+		size += 4
+	}
+
+	return size, true
+}
+
+func (p *frameParser) startFrame(size int) {
+	p.fin = p.header[0]&0x80 != 0
+	p.opcode = p.header[0] & 0x0f
+	p.masked = p.header[1]&0x80 != 0
+
+	maskOffset := 2
+	switch length := p.header[1] & 0x7f; length {
+	case 126:
+		p.remaining = uint64(binary.BigEndian.Uint16(p.header[2:4]))
+		maskOffset = 4
+	case 127:
+		p.remaining = binary.BigEndian.Uint64(p.header[2:10])
+		maskOffset = 10
+	default:
+		p.remaining = uint64(length)
+	}
+
+	if p.masked {
+		copy(p.maskKey[:], p.header[maskOffset:size])
+	}
+
+	p.inPayload = true
+	p.offset = 0
+}
+
+func (p *frameParser) endFrame() {
+	switch {
+	case p.opcode == opClose:
+		code := CloseNoStatusReceived
+		if p.offset >= 2 {
+			code = int(binary.BigEndian.Uint16(p.closeCode[:]))
+		}
+		if p.onClose != nil {
+			p.onClose(code)
+		}
+
+	case p.opcode&opControlMask == 0 && p.fin:
+		// A message is complete on the final fragment of a data frame.
+		if p.onMessage != nil {
+			p.onMessage()
+		}
+	}
+
+	p.headerLen = 0
+	p.inPayload = false
+	p.remaining = 0
+}
+
+// atFrameBoundary returns whether the data parsed so far ends on a complete frame.
+func (p *frameParser) atFrameBoundary() bool {
+	return !p.inPayload && p.headerLen == 0
+}
+
+// closeFrame returns an unmasked close frame with the given close code, as sent by a server.
+func closeFrame(code int) []byte {
+	frame := []byte{0x80 | opClose, 2, 0, 0}
+	binary.BigEndian.PutUint16(frame[2:], uint16(code))
+	return frame
+}
@@ -0,0 +1,154 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameParser(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		frames            [][]byte
+		expectedMessages  int
+		expectedCloseCode int
+	}{
+		{
+			desc:             "unmasked text message",
+			frames:           [][]byte{frame(true, 0x1, nil, []byte("hello"))},
+			expectedMessages: 1,
+		},
+		{
+			desc:             "masked text message",
+			frames:           [][]byte{frame(true, 0x1, []byte{1, 2, 3, 4}, []byte("hello"))},
+			expectedMessages: 1,
+		},
+		{
+			desc: "fragmented message",
+			frames: [][]byte{
+				frame(false, 0x1, nil, []byte("hel")),
+				frame(false, 0x0, nil, []byte("l")),
+				frame(true, 0x0, nil, []byte("o")),
+			},
+			expectedMessages: 1,
+		},
+		{
+			desc: "control frames are not messages",
+			frames: [][]byte{
+				frame(true, 0x9, nil, []byte("ping")),
+				frame(true, 0xa, nil, []byte("pong")),
+			},
+		},
+		{
+			desc:             "16 bits payload length",
+			frames:           [][]byte{frame(true, 0x2, []byte{1, 2, 3, 4}, bytes.Repeat([]byte("a"), 300))},
+			expectedMessages: 1,
+		},
+		{
+			desc:             "64 bits payload length",
+			frames:           [][]byte{frame(true, 0x2, nil, bytes.Repeat([]byte("a"), 70000))},
+			expectedMessages: 1,
+		},
+		{
+			desc:             "empty message",
+			frames:           [][]byte{frame(true, 0x1, nil, nil)},
+			expectedMessages: 1,
+		},
+		{
+			desc:              "masked close frame",
+			frames:            [][]byte{frame(true, 0x8, []byte{1, 2, 3, 4}, []byte{0x3, 0xe8, 'b', 'y', 'e'})},
+			expectedCloseCode: 1000,
+		},
+		{
+			desc:              "close frame without status code",
+			frames:            [][]byte{frame(true, 0x8, nil, nil)},
+			expectedCloseCode: CloseNoStatusReceived,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			data := bytes.Join(test.frames, nil)
+
+			// The data is parsed in one go, and byte per byte, to make sure that the parser state is kept between the calls.
+			for _, chunkSize := range []int{len(data), 1} {
+				var messages, closeCode int
+				parser := frameParser{
+					onMessage: func() { messages++ },
+					onClose:   func(code int) { closeCode = code },
+				}
+
+				for chunk := range chunks(data, chunkSize) {
+					parser.feed(chunk)
+				}
+
+				assert.Equal(t, test.expectedMessages, messages)
+				assert.Equal(t, test.expectedCloseCode, closeCode)
+				assert.True(t, parser.atFrameBoundary())
+			}
+		})
+	}
+}
+
+func TestCloseFrame(t *testing.T) {
+	var closeCode int
+	parser := frameParser{onClose: func(code int) { closeCode = code }}
+
+	parser.feed(closeFrame(CloseGoingAway))
+
+	assert.Equal(t, CloseGoingAway, closeCode)
+}
+
+// frame builds a WebSocket frame, which is masked when a mask key is given.
+func frame(fin bool, opcode byte, maskKey, payload []byte) []byte {
+	var b []byte
+
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	b = append(b, first)
+
+	var maskBit byte
+	if maskKey != nil {
+		maskBit = 0x80
+	}
+
+	switch {
+	case len(payload) < 126:
+		b = append(b, maskBit|byte(len(payload)))
+	case len(payload) <= 0xffff:
+		b = append(b, maskBit|126)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(payload)))
+	default:
+		b = append(b, maskBit|127)
+		b = binary.BigEndian.AppendUint64(b, uint64(len(payload)))
+	}
+
+	if maskKey == nil {
+		return append(b, payload...)
+	}
+
+	b = append(b, maskKey...)
+	for i, c := range payload {
+		b = append(b, c^maskKey[i%4])
+	}
+
+	return b
+}
+
+func chunks(data []byte, size int) func(yield func([]byte) bool) {
+	return func(yield func([]byte) bool) {
+		for len(data) > 0 {
+			n := min(size, len(data))
+			if !yield(data[:n]) {
+				return
+			}
+			data = data[n:]
+		}
+	}
+}
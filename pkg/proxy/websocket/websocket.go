@@ -0,0 +1,344 @@
+package websocket
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"github.com/traefik/traefik/v3/pkg/observability/metrics"
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	directionClientToServer = "client_to_server"
+	directionServerToClient = "server_to_client"
+)
+
+// closeFrameWriteTimeout is the maximum duration allowed to send a close frame to the client,
+// before closing a WebSocket connection.
+const closeFrameWriteTimeout = time.Second
+
+// handler tracks the WebSocket connections established through the next handler,
+// and enforces the configured limits on them.
+type handler struct {
+	next        http.Handler
+	serviceName string
+	registry    metrics.Registry
+
+	idleTimeout time.Duration
+	maxDuration time.Duration
+}
+
+// WrapHandler wraps the given proxy handler to observe and limit the WebSocket connections it establishes.
+// The config can be nil.
+func WrapHandler(next http.Handler, serviceName string, config *dynamic.WebSocket, registry metrics.Registry) http.Handler {
+	h := &handler{
+		next:        next,
+		serviceName: serviceName,
+		registry:    registry,
+	}
+
+	if config != nil {
+		h.idleTimeout = time.Duration(config.IdleTimeout)
+		h.maxDuration = time.Duration(config.MaxDuration)
+	}
+
+	return h
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !isWebSocketUpgrade(req) {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	wrw := &responseWriter{
+		ResponseWriter: rw,
+		newConn: func(conn net.Conn) *conn {
+			return h.newConn(req, conn)
+		},
+	}
+
+	h.next.ServeHTTP(wrw, req)
+
+	if wrw.conn == nil {
+		return
+	}
+
+	// The proxy is done with the connection once both copy loops are done,
+	// the connection is closed to make sure that the limits timers are stopped.
+	_ = wrw.conn.Close()
+
+	wrw.conn.finish(req)
+}
+
+func (h *handler) newConn(req *http.Request, netConn net.Conn) *conn {
+	c := &conn{Conn: netConn}
+	c.lastActivity.Store(time.Now().UnixNano())
+
+	c.metrics = newConnMetrics(req, h.serviceName, h.registry)
+	c.metrics.openConns.Add(1)
+
+	c.clientParser.onMessage = func() {
+		c.messages[0].Add(1)
+		c.metrics.messages[0].Add(1)
+	}
+	c.clientParser.onClose = c.recordCloseCode
+	c.serverParser.onMessage = func() {
+		c.messages[1].Add(1)
+		c.metrics.messages[1].Add(1)
+	}
+	c.serverParser.onClose = c.recordCloseCode
+
+	// The timers callbacks cannot run before the timers are stored.
+	c.timersMu.Lock()
+	defer c.timersMu.Unlock()
+
+	if h.idleTimeout > 0 {
+		c.idleTimeout = h.idleTimeout
+		c.idleTimer = time.AfterFunc(h.idleTimeout, c.checkIdle)
+	}
+
+	if h.maxDuration > 0 {
+		c.maxDurationTimer = time.AfterFunc(h.maxDuration, func() {
+			c.closeWithReason(CloseGoingAway, "max duration reached")
+		})
+	}
+
+	return c
+}
+
+type connMetrics struct {
+	openConns gokitmetrics.Gauge
+	// messages and bytes are indexed by direction: client to server, then server to client.
+	messages [2]gokitmetrics.Counter
+	bytes    [2]gokitmetrics.Counter
+	closes   gokitmetrics.Counter
+}
+
+func newConnMetrics(req *http.Request, serviceName string, registry metrics.Registry) connMetrics {
+	if registry == nil || !registry.IsSvcEnabled() || !observability.MetricsEnabled(req.Context()) {
+		return connMetrics{
+			openConns: discard.NewGauge(),
+			messages:  [2]gokitmetrics.Counter{discard.NewCounter(), discard.NewCounter()},
+			bytes:     [2]gokitmetrics.Counter{discard.NewCounter(), discard.NewCounter()},
+			closes:    discard.NewCounter(),
+		}
+	}
+
+	return connMetrics{
+		openConns: registry.ServiceWebSocketOpenConnsGauge().With("service", serviceName),
+		messages: [2]gokitmetrics.Counter{
+			registry.ServiceWebSocketMessagesCounter().With("service", serviceName, "direction", directionClientToServer),
+			registry.ServiceWebSocketMessagesCounter().With("service", serviceName, "direction", directionServerToClient),
+		},
+		bytes: [2]gokitmetrics.Counter{
+			registry.ServiceWebSocketBytesCounter().With("service", serviceName, "direction", directionClientToServer),
+			registry.ServiceWebSocketBytesCounter().With("service", serviceName, "direction", directionServerToClient),
+		},
+		closes: registry.ServiceWebSocketClosesCounter().With("service", serviceName),
+	}
+}
+
+// responseWriter wraps the connection hijacked by the proxy to switch protocols.
+type responseWriter struct {
+	http.ResponseWriter
+
+	newConn func(conn net.Conn) *conn
+	conn    *conn
+}
+
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("not a hijacker: %T", r.ResponseWriter)
+	}
+
+	netConn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The switching protocols response is written by the proxy with the returned bufio.ReadWriter,
+	// which still writes directly on the hijacked connection, thus only the WebSocket frames go through the conn wrapper.
+	r.conn = r.newConn(netConn)
+
+	return r.conn, brw, nil
+}
+
+func (r *responseWriter) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// conn is the client side of a WebSocket connection.
+// The data read from it is sent by the client, and the data written to it is sent by the server.
+type conn struct {
+	net.Conn
+
+	lastActivity atomic.Int64
+
+	metrics connMetrics
+
+	// clientParser is only used by Read, which is called by a single goroutine.
+	clientParser frameParser
+	// serverParser is only used by Write, under writeMu.
+	serverParser frameParser
+	writeMu      sync.Mutex
+
+	// messages and bytes are indexed by direction: client to server, then server to client.
+	messages [2]atomic.Int64
+	bytes    [2]atomic.Int64
+
+	closeCode   atomic.Int64
+	closeReason atomic.Pointer[string]
+
+	timersMu         sync.Mutex
+	closed           bool
+	idleTimeout      time.Duration
+	idleTimer        *time.Timer
+	maxDurationTimer *time.Timer
+
+	finishOnce sync.Once
+}
+
+func (c *conn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.lastActivity.Store(time.Now().UnixNano())
+		c.bytes[0].Add(int64(n))
+		c.metrics.bytes[0].Add(float64(n))
+		c.clientParser.feed(p[:n])
+	}
+
+	return n, err
+}
+
+func (c *conn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		c.lastActivity.Store(time.Now().UnixNano())
+		c.bytes[1].Add(int64(n))
+		c.metrics.bytes[1].Add(float64(n))
+		c.serverParser.feed(p[:n])
+	}
+
+	return n, err
+}
+
+func (c *conn) Close() error {
+	c.timersMu.Lock()
+	defer c.timersMu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	if c.idleTimer != nil {
+		c.idleTimer.Stop()
+	}
+	if c.maxDurationTimer != nil {
+		c.maxDurationTimer.Stop()
+	}
+
+	return c.Conn.Close()
+}
+
+func (c *conn) checkIdle() {
+	c.timersMu.Lock()
+	if c.closed {
+		c.timersMu.Unlock()
+		return
+	}
+
+	idle := time.Since(time.Unix(0, c.lastActivity.Load()))
+	if idle < c.idleTimeout {
+		c.idleTimer.Reset(c.idleTimeout - idle)
+		c.timersMu.Unlock()
+		return
+	}
+	c.timersMu.Unlock()
+
+	c.closeWithReason(CloseGoingAway, "idle timeout")
+}
+
+// closeWithReason closes the connection, after sending a close frame to the client when possible.
+func (c *conn) closeWithReason(code int, reason string) {
+	c.closeReason.CompareAndSwap(nil, &reason)
+
+	// The close frame cannot be sent while the server is writing,
+	// or in the middle of a frame sent by the server.
+	if c.writeMu.TryLock() {
+		if c.serverParser.atFrameBoundary() {
+			_ = c.Conn.SetWriteDeadline(time.Now().Add(closeFrameWriteTimeout))
+			if _, err := c.Conn.Write(closeFrame(code)); err == nil {
+				c.recordCloseCode(code)
+			}
+		}
+		c.writeMu.Unlock()
+	}
+
+	log.Debug().Str("remoteAddr", c.RemoteAddr().String()).
+		Msgf("Closing WebSocket connection: %s", reason)
+
+	_ = c.Close()
+}
+
+// recordCloseCode records the code of the first close frame of the connection.
+func (c *conn) recordCloseCode(code int) {
+	c.closeCode.CompareAndSwap(0, int64(code))
+}
+
+// finish reports the connection metrics and the access log fields, once the connection is closed.
+func (c *conn) finish(req *http.Request) {
+	c.finishOnce.Do(func() {
+		code := int(c.closeCode.Load())
+		if code == 0 {
+			code = CloseAbnormalClosure
+		}
+
+		c.metrics.openConns.Add(-1)
+		c.metrics.closes.With("code", strconv.Itoa(code)).Add(1)
+
+		table := accesslog.GetLogData(req)
+		if table == nil {
+			return
+		}
+
+		table.Core[accesslog.WebSocketCloseCode] = code
+		table.Core[accesslog.WebSocketClientMessages] = c.messages[0].Load()
+		table.Core[accesslog.WebSocketServerMessages] = c.messages[1].Load()
+		table.Core[accesslog.WebSocketClientBytes] = c.bytes[0].Load()
+		table.Core[accesslog.WebSocketServerBytes] = c.bytes[1].Load()
+
+		if reason := c.closeReason.Load(); reason != nil {
+			table.Core[accesslog.CloseReason] = *reason
+		}
+	})
+}
+
+// isWebSocketUpgrade returns whether the request is a WebSocket handshake request.
+func isWebSocketUpgrade(req *http.Request) bool {
+	return httpguts.HeaderValuesContainsToken(req.Header["Connection"], "Upgrade") &&
+		httpguts.HeaderValuesContainsToken(req.Header["Upgrade"], "websocket")
+}
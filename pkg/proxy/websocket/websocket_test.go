@@ -0,0 +1,207 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"strings"
+	"testing"
+	"time"
+
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func TestWrapHandler_accessLog(t *testing.T) {
+	backend := createEchoServer(t)
+
+	proxyURL, logData := createProxy(t, backend.URL, nil)
+
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(proxyURL, nil)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"foo", "foobar"} {
+		err = conn.WriteMessage(gorillawebsocket.TextMessage, []byte(msg))
+		require.NoError(t, err)
+
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, msg, string(data))
+	}
+
+	err = conn.WriteMessage(gorillawebsocket.CloseMessage, gorillawebsocket.FormatCloseMessage(gorillawebsocket.CloseNormalClosure, ""))
+	require.NoError(t, err)
+
+	_, _, err = conn.ReadMessage()
+	require.True(t, gorillawebsocket.IsCloseError(err, gorillawebsocket.CloseNormalClosure))
+
+	core := waitLogData(t, logData)
+
+	assert.Equal(t, gorillawebsocket.CloseNormalClosure, core[accesslog.WebSocketCloseCode])
+	assert.Equal(t, int64(2), core[accesslog.WebSocketClientMessages])
+	assert.Equal(t, int64(2), core[accesslog.WebSocketServerMessages])
+	// Client frames are masked, thus have 4 more bytes than the server frames.
+	// Each text message frame has a 2 bytes header, and each close frame a 2 bytes header and a 2 bytes code.
+	assert.Equal(t, int64(2+4+3+2+4+6+2+4+2), core[accesslog.WebSocketClientBytes])
+	assert.Equal(t, int64(2+3+2+6+2+2), core[accesslog.WebSocketServerBytes])
+	assert.NotContains(t, core, accesslog.CloseReason)
+}
+
+func TestWrapHandler_limits(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         *dynamic.WebSocket
+		pingInterval   time.Duration
+		expectedReason string
+	}{
+		{
+			desc:           "idle timeout",
+			config:         &dynamic.WebSocket{IdleTimeout: ptypes.Duration(200 * time.Millisecond)},
+			expectedReason: "idle timeout",
+		},
+		{
+			desc:           "max duration",
+			config:         &dynamic.WebSocket{IdleTimeout: ptypes.Duration(200 * time.Millisecond), MaxDuration: ptypes.Duration(500 * time.Millisecond)},
+			pingInterval:   50 * time.Millisecond,
+			expectedReason: "max duration reached",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backend := createEchoServer(t)
+
+			proxyURL, logData := createProxy(t, backend.URL, test.config)
+
+			conn, _, err := gorillawebsocket.DefaultDialer.Dial(proxyURL, nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			start := time.Now()
+
+			if test.pingInterval > 0 {
+				ctx, cancel := context.WithCancel(t.Context())
+				t.Cleanup(cancel)
+
+				go func() {
+					ticker := time.NewTicker(test.pingInterval)
+					defer ticker.Stop()
+
+					for {
+						select {
+						case <-ctx.Done():
+							return
+						case <-ticker.C:
+							if err := conn.WriteControl(gorillawebsocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+								return
+							}
+						}
+					}
+				}()
+			}
+
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+			var readErr error
+			for readErr == nil {
+				_, _, readErr = conn.ReadMessage()
+			}
+
+			assert.True(t, gorillawebsocket.IsCloseError(readErr, CloseGoingAway), "unexpected error: %v", readErr)
+			assert.GreaterOrEqual(t, time.Since(start), time.Duration(test.config.IdleTimeout))
+
+			core := waitLogData(t, logData)
+
+			assert.Equal(t, CloseGoingAway, core[accesslog.WebSocketCloseCode])
+			assert.Equal(t, test.expectedReason, core[accesslog.CloseReason])
+		})
+	}
+}
+
+func TestWrapHandler_notWebSocket(t *testing.T) {
+	var called bool
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		called = true
+		_, ok := rw.(*responseWriter)
+		assert.False(t, ok)
+	})
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "h2c")
+
+	WrapHandler(next, "foo", nil, nil).ServeHTTP(rw, req)
+
+	assert.True(t, called)
+}
+
+func createEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	upgrader := gorillawebsocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(rw, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if err := conn.WriteMessage(mt, msg); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// createProxy creates a WebSocket proxy towards the given backend,
+// and returns its URL and a channel receiving the access log data of the proxied connections.
+func createProxy(t *testing.T, backendURL string, config *dynamic.WebSocket) (string, <-chan accesslog.CoreLogData) {
+	t.Helper()
+
+	logData := make(chan accesslog.CoreLogData, 1)
+
+	handler := WrapHandler(httputil.NewSingleHostReverseProxy(testhelpers.MustParseURL(backendURL)), "foo", config, nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		data := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+		req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, data))
+
+		handler.ServeHTTP(rw, req)
+
+		logData <- data.Core
+	}))
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http"), logData
+}
+
+func waitLogData(t *testing.T, logData <-chan accesslog.CoreLogData) accesslog.CoreLogData {
+	t.Helper()
+
+	select {
+	case core := <-logData:
+		return core
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the access log data")
+		return nil
+	}
+}
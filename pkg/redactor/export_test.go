@@ -0,0 +1,8 @@
+package redactor
+
+// Exported for the tests of the static configuration,
+// which are in an external package as some of the providers depend on the redactor.
+var (
+	UpdateExpected  = updateExpected
+	AnonymizeIndent = func(baseConfig any) (string, error) { return anonymize(baseConfig, true) }
+)
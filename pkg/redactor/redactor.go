@@ -42,6 +42,23 @@ func removeCredentials(baseConfig any, indent bool) (string, error) {
 	return do(baseConfig, tagLoggable, false, indent)
 }
 
+// Redact returns a copy of the given value, in which the fields that have a loggable=false struct tag are redacted.
+// Unlike RemoveCredentials, it keeps the plugin configurations.
+func Redact[T any](value T) (T, error) {
+	var zero T
+
+	redacted, err := copystructure.Copy(value)
+	if err != nil {
+		return zero, err
+	}
+
+	if err := redact(reflect.ValueOf(redacted), tagLoggable, false, false); err != nil {
+		return zero, err
+	}
+
+	return redacted.(T), nil
+}
+
 // do marshals the given configuration, while redacting some of the fields
 // respectively to the given tag.
 func do(baseConfig any, tag string, redactByDefault, indent bool) (string, error) {
@@ -70,7 +87,13 @@ func doOnJSON(input string) string {
 }
 
 func doOnStruct(field reflect.Value, tag string, redactByDefault bool) error {
-	if field.Type().AssignableTo(reflect.TypeFor[dynamic.PluginConf]()) {
+	return redact(field, tag, redactByDefault, true)
+}
+
+// redact redacts the fields of the given value respectively to the given tag.
+// The plugin configurations, which are not described by struct tags, are reset when resetPlugins is true.
+func redact(field reflect.Value, tag string, redactByDefault, resetPlugins bool) error {
+	if resetPlugins && field.Type().AssignableTo(reflect.TypeFor[dynamic.PluginConf]()) {
 		resetPlugin(field)
 		return nil
 	}
@@ -78,7 +101,7 @@ func doOnStruct(field reflect.Value, tag string, redactByDefault bool) error {
 	switch field.Kind() {
 	case reflect.Ptr:
 		if !field.IsNil() {
-			if err := doOnStruct(field.Elem(), tag, redactByDefault); err != nil {
+			if err := redact(field.Elem(), tag, redactByDefault, resetPlugins); err != nil {
 				return err
 			}
 		}
@@ -102,7 +125,7 @@ func doOnStruct(field reflect.Value, tag string, redactByDefault bool) error {
 				fldPtr := reflect.New(fld.Type())
 				fldPtr.Elem().Set(fld)
 
-				if err := doOnStruct(fldPtr, tag, redactByDefault); err != nil {
+				if err := redact(fldPtr, tag, redactByDefault, resetPlugins); err != nil {
 					return err
 				}
 
@@ -111,7 +134,7 @@ func doOnStruct(field reflect.Value, tag string, redactByDefault bool) error {
 				continue
 			}
 
-			if err := doOnStruct(fld, tag, redactByDefault); err != nil {
+			if err := redact(fld, tag, redactByDefault, resetPlugins); err != nil {
 				return err
 			}
 		}
@@ -124,7 +147,7 @@ func doOnStruct(field reflect.Value, tag string, redactByDefault bool) error {
 				valPtr := reflect.New(val.Type())
 				valPtr.Elem().Set(val)
 
-				if err := doOnStruct(valPtr, tag, redactByDefault); err != nil {
+				if err := redact(valPtr, tag, redactByDefault, resetPlugins); err != nil {
 					return err
 				}
 
@@ -133,13 +156,13 @@ func doOnStruct(field reflect.Value, tag string, redactByDefault bool) error {
 				continue
 			}
 
-			if err := doOnStruct(val, tag, redactByDefault); err != nil {
+			if err := redact(val, tag, redactByDefault, resetPlugins); err != nil {
 				return err
 			}
 		}
 	case reflect.Slice:
 		for j := range field.Len() {
-			if err := doOnStruct(field.Index(j), tag, redactByDefault); err != nil {
+			if err := redact(field.Index(j), tag, redactByDefault, resetPlugins); err != nil {
 				return err
 			}
 		}
@@ -1,18 +1,36 @@
 package server
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
 	otypes "github.com/traefik/traefik/v3/pkg/observability/types"
+	"github.com/traefik/traefik/v3/pkg/rules"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
+// MergeConfigurations merges the configurations of the providers into the configuration to apply,
+// the given configurations being left untouched.
+func MergeConfigurations(configurations dynamic.Configurations, defaultEntryPoints []string) dynamic.Configuration {
+	conf := mergeConfiguration(configurations.DeepCopy(), defaultEntryPoints)
+	return applyModel(conf)
+}
+
 func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoints []string) dynamic.Configuration {
 	// TODO: see if we can use DeepCopies inside, so that the given argument is left
 	// untouched, and the modified copy is returned.
@@ -124,12 +142,20 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 			}
 		}
 
+		if configuration.Namespaces != nil {
+			if conf.Namespaces == nil {
+				conf.Namespaces = dynamic.NewNamespaces()
+			}
+			mergeNamespaces(conf.Namespaces, configuration.Namespaces, pvd)
+		}
+
 		if configuration.TLS != nil {
 			for _, cert := range configuration.TLS.Certificates {
 				if slices.Contains(cert.Stores, tlsalpn01.ACMETLS1Protocol) && pvd != "tlsalpn.acme" {
 					continue
 				}
 
+				cert.Provider = pvd
 				conf.TLS.Certificates = append(conf.TLS.Certificates, cert)
 			}
 
@@ -171,6 +197,29 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 	return conf
 }
 
+// mergeNamespaces adds the namespaces of the elements of the given provider to dst, by qualified element name.
+func mergeNamespaces(dst, src *dynamic.Namespaces, pvd string) {
+	pairs := []struct{ dst, src map[string]string }{
+		{dst.Routers, src.Routers},
+		{dst.Middlewares, src.Middlewares},
+		{dst.Services, src.Services},
+		{dst.TCPRouters, src.TCPRouters},
+		{dst.TCPMiddlewares, src.TCPMiddlewares},
+		{dst.TCPServices, src.TCPServices},
+		{dst.UDPRouters, src.UDPRouters},
+		{dst.UDPServices, src.UDPServices},
+		{dst.TLSOptions, src.TLSOptions},
+		{dst.ServersTransports, src.ServersTransports},
+		{dst.TCPServersTransports, src.TCPServersTransports},
+	}
+
+	for _, pair := range pairs {
+		for name, namespace := range pair.src {
+			pair.dst[provider.MakeQualifiedName(pvd, name)] = namespace
+		}
+	}
+}
+
 func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 	if cfg.HTTP != nil && len(cfg.HTTP.Models) > 0 {
 		rts := make(map[string]*dynamic.Router)
@@ -242,6 +291,13 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 					if len(eps) > 1 {
 						rtName = epName + "-" + name
 						modelRouterNames[name] = append(modelRouterNames[name], rtName)
+
+						// The router for the entry point belongs to the namespace of the original router.
+						if cfg.Namespaces != nil {
+							if namespace, ok := cfg.Namespaces.Routers[name]; ok {
+								cfg.Namespaces.Routers[rtName] = namespace
+							}
+						}
 					}
 
 					rts[rtName] = cp
@@ -348,3 +404,559 @@ func applyDefaultObservabilityModel(cfg dynamic.Configuration) {
 }
 
 func pointer[T any](v T) *T { return &v }
+
+// Tenancy isolates the configurations of the providers bound to tenants.
+// The routers of a tenant can only match the hostnames and path prefixes of the tenant, use its entry points and certificate resolvers,
+// and reference the services, middlewares, TLS options and serversTransports of the tenant, of the tenants it allows,
+// or of the providers bound to no tenant, the internal provider excepted unless the tenant allows it.
+type Tenancy struct {
+	tenants map[string]*static.Tenant
+
+	// bindings are the tenant names, by provider, or by provider/namespace.
+	bindings map[string]string
+}
+
+// NewTenancy creates a Tenancy for the given tenants, or returns nil if there is none.
+func NewTenancy(tenants map[string]*static.Tenant) *Tenancy {
+	if len(tenants) == 0 {
+		return nil
+	}
+
+	t := &Tenancy{
+		tenants:  tenants,
+		bindings: make(map[string]string),
+	}
+
+	for name, tenant := range tenants {
+		for _, pvd := range tenant.Providers {
+			t.bindings[pvd] = name
+		}
+	}
+
+	return t
+}
+
+// Deny removes from the runtime configuration the routers violating the tenancy, adding the violation to their errors.
+// The returned function adds them back, so that they are reported along with the routers which have been built.
+func (t *Tenancy) Deny(ctx context.Context, conf *runtime.Configuration) func() {
+	if t == nil {
+		return func() {}
+	}
+
+	deniedRouters := make(map[string]*runtime.RouterInfo)
+	for name, router := range conf.Routers {
+		if err := t.checkRouter(name, router.Router, conf); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str(logs.RouterName, name).Msg("Router denied by tenancy")
+			router.AddError(err, true)
+			deniedRouters[name] = router
+		}
+	}
+
+	deniedTCPRouters := make(map[string]*runtime.TCPRouterInfo)
+	for name, router := range conf.TCPRouters {
+		if err := t.checkTCPRouter(name, router.TCPRouter, conf); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str(logs.RouterName, name).Msg("TCP router denied by tenancy")
+			router.AddError(err, true)
+			deniedTCPRouters[name] = router
+		}
+	}
+
+	deniedUDPRouters := make(map[string]*runtime.UDPRouterInfo)
+	for name, router := range conf.UDPRouters {
+		if err := t.checkUDPRouter(name, router.UDPRouter, conf); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str(logs.RouterName, name).Msg("UDP router denied by tenancy")
+			router.AddError(err, true)
+			deniedUDPRouters[name] = router
+		}
+	}
+
+	maps.DeleteFunc(conf.Routers, func(name string, _ *runtime.RouterInfo) bool { return deniedRouters[name] != nil })
+	maps.DeleteFunc(conf.TCPRouters, func(name string, _ *runtime.TCPRouterInfo) bool { return deniedTCPRouters[name] != nil })
+	maps.DeleteFunc(conf.UDPRouters, func(name string, _ *runtime.UDPRouterInfo) bool { return deniedUDPRouters[name] != nil })
+
+	return func() {
+		maps.Copy(conf.Routers, deniedRouters)
+		maps.Copy(conf.TCPRouters, deniedTCPRouters)
+		maps.Copy(conf.UDPRouters, deniedUDPRouters)
+	}
+}
+
+// tenantOf returns the name of the tenant owning the element of the given kind and qualified name, or an empty string if none does.
+// The namespace of an element is the one recorded by its Kubernetes provider, never guessed from its name,
+// and a namespace binding takes precedence over the binding of its provider.
+func (t *Tenancy) tenantOf(ref reference, conf *runtime.Configuration) string {
+	pvd := providerOf(ref.name)
+	if pvd == "" {
+		return ""
+	}
+
+	if namespace := namespaceOf(ref, conf); namespace != "" {
+		if tenantName, ok := t.bindings[pvd+"/"+namespace]; ok {
+			return tenantName
+		}
+	}
+
+	return t.bindings[pvd]
+}
+
+func (t *Tenancy) checkRouter(name string, router *dynamic.Router, conf *runtime.Configuration) error {
+	tenantName := t.tenantOf(reference{kind: kindRouter, name: name}, conf)
+	if tenantName == "" {
+		return nil
+	}
+	tenant := t.tenants[tenantName]
+
+	// The child routers are only reached through the entry points of their parents, which are checked as references.
+	if len(router.ParentRefs) == 0 || len(router.EntryPoints) > 0 {
+		if err := checkEntryPoints(tenant, router.EntryPoints); err != nil {
+			return err
+		}
+	}
+
+	// The child routers only match the requests matched by their parents, which are checked as references.
+	if len(router.ParentRefs) == 0 {
+		if err := checkRule(router.Rule, httpmuxer.ParseRule, "Host", tenant.Hosts, tenant.PathPrefixes); err != nil {
+			return err
+		}
+	}
+
+	refs := make(map[reference]struct{})
+	for _, parentRef := range router.ParentRefs {
+		refs[reference{kind: kindRouter, name: parentRef}] = struct{}{}
+	}
+
+	pvd := providerOf(name)
+	if router.TLS != nil {
+		if err := checkTLS(tenant, router.TLS.CertResolver, router.TLS.Domains); err != nil {
+			return err
+		}
+
+		addTLSOptionsReference(refs, pvd, router.TLS.Options)
+	}
+
+	for _, middleware := range router.Middlewares {
+		addMiddlewareReferences(refs, qualify(pvd, middleware), conf)
+	}
+
+	if router.Service != "" {
+		addServiceReferences(refs, qualify(pvd, router.Service), conf)
+	}
+
+	return t.checkReferences(tenantName, refs, conf)
+}
+
+func (t *Tenancy) checkTCPRouter(name string, router *dynamic.TCPRouter, conf *runtime.Configuration) error {
+	tenantName := t.tenantOf(reference{kind: kindTCPRouter, name: name}, conf)
+	if tenantName == "" {
+		return nil
+	}
+	tenant := t.tenants[tenantName]
+
+	if err := checkEntryPoints(tenant, router.EntryPoints); err != nil {
+		return err
+	}
+
+	if err := checkRule(router.Rule, tcpmuxer.ParseRule, "HostSNI", tenant.Hosts, nil); err != nil {
+		return err
+	}
+
+	refs := make(map[reference]struct{})
+
+	pvd := providerOf(name)
+	if router.TLS != nil {
+		if err := checkTLS(tenant, router.TLS.CertResolver, router.TLS.Domains); err != nil {
+			return err
+		}
+
+		addTLSOptionsReference(refs, pvd, router.TLS.Options)
+	}
+
+	for _, middleware := range router.Middlewares {
+		refs[reference{kind: kindTCPMiddleware, name: qualify(pvd, middleware)}] = struct{}{}
+	}
+
+	if router.Service != "" {
+		addTCPServiceReferences(refs, qualify(pvd, router.Service), conf)
+	}
+
+	return t.checkReferences(tenantName, refs, conf)
+}
+
+func (t *Tenancy) checkUDPRouter(name string, router *dynamic.UDPRouter, conf *runtime.Configuration) error {
+	tenantName := t.tenantOf(reference{kind: kindUDPRouter, name: name}, conf)
+	if tenantName == "" {
+		return nil
+	}
+
+	if err := checkEntryPoints(t.tenants[tenantName], router.EntryPoints); err != nil {
+		return err
+	}
+
+	refs := make(map[reference]struct{})
+	if router.Service != "" {
+		addUDPServiceReferences(refs, qualify(providerOf(name), router.Service), conf)
+	}
+
+	return t.checkReferences(tenantName, refs, conf)
+}
+
+// restrictedProviders are the providers bound to no tenant whose elements can only be referenced by the tenants allowing them.
+// The internal provider exposes the API, including the REST provider endpoint, which must not be routed by a tenant.
+var restrictedProviders = []string{"internal"}
+
+// checkReferences checks that the given elements belong to the tenant, to a tenant it allows, or to no tenant.
+// The elements of the restricted providers must also be allowed by the tenant.
+func (t *Tenancy) checkReferences(tenantName string, refs map[reference]struct{}, conf *runtime.Configuration) error {
+	sortedRefs := slices.SortedFunc(maps.Keys(refs), func(a, b reference) int {
+		return cmp.Or(strings.Compare(a.name, b.name), strings.Compare(a.kind, b.kind))
+	})
+
+	tenant := t.tenants[tenantName]
+	for _, ref := range sortedRefs {
+		owner := t.tenantOf(ref, conf)
+		if owner == "" {
+			pvd := providerOf(ref.name)
+			if slices.Contains(restrictedProviders, pvd) && !slices.Contains(tenant.AllowedProviders, pvd) {
+				return fmt.Errorf("tenant %q is not allowed to reference %s %q of provider %q", tenantName, ref.kind, ref.name, pvd)
+			}
+
+			continue
+		}
+
+		if owner == tenantName || slices.Contains(tenant.AllowedTenants, owner) {
+			continue
+		}
+
+		return fmt.Errorf("tenant %q is not allowed to reference %s %q of tenant %q", tenantName, ref.kind, ref.name, owner)
+	}
+
+	return nil
+}
+
+// checkTLS checks that the certificate resolver is allowed for the tenant,
+// and that the domains of the certificates to resolve are hosts of the tenant.
+func checkTLS(tenant *static.Tenant, certResolver string, domains []types.Domain) error {
+	if certResolver != "" && len(tenant.CertResolvers) > 0 && !slices.Contains(tenant.CertResolvers, certResolver) {
+		return fmt.Errorf("certResolver %q is not allowed for the tenant", certResolver)
+	}
+
+	if len(tenant.Hosts) == 0 {
+		return nil
+	}
+
+	for _, domain := range domains {
+		for _, host := range domain.ToStrArray() {
+			if !isHostAllowed(tenant.Hosts, host) {
+				return fmt.Errorf("TLS domain %q is not a host of the tenant", host)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkEntryPoints checks that the given entry points are allowed for the tenant.
+// A router without entry points is attached to all of them, which is only allowed when the tenant does not restrict them.
+func checkEntryPoints(tenant *static.Tenant, entryPoints []string) error {
+	if len(tenant.EntryPoints) == 0 {
+		return nil
+	}
+
+	if len(entryPoints) == 0 {
+		return errors.New("a router without entryPoints uses all the entry points, which is not allowed for the tenant")
+	}
+
+	for _, entryPoint := range entryPoints {
+		if !slices.Contains(tenant.EntryPoints, entryPoint) {
+			return fmt.Errorf("entryPoint %q is not allowed for the tenant", entryPoint)
+		}
+	}
+
+	return nil
+}
+
+// checkRule checks that every request matched by the rule is matched by a host matcher with one of the given hosts,
+// and by a path matcher with one of the given path prefixes.
+func checkRule(rule string, parse func(string) (*rules.Tree, error), hostMatcher string, hosts, pathPrefixes []string) error {
+	if len(hosts) == 0 && len(pathPrefixes) == 0 {
+		return nil
+	}
+
+	tree, err := parse(rule)
+	if err != nil {
+		return fmt.Errorf("checking rule against tenant: %w", err)
+	}
+
+	if len(hosts) > 0 && !isConstrained(tree, []string{hostMatcher}, func(_, host string) bool { return isHostAllowed(hosts, host) }) {
+		return fmt.Errorf("rule %q is not restricted to the hosts of the tenant", rule)
+	}
+
+	if len(pathPrefixes) > 0 && !isConstrained(tree, []string{"Path", "PathPrefix"}, func(matcher, path string) bool { return isPathAllowed(pathPrefixes, matcher, path) }) {
+		return fmt.Errorf("rule %q is not restricted to the path prefixes of the tenant", rule)
+	}
+
+	return nil
+}
+
+// isConstrained tells whether every request matched by the tree is matched by one of the given matchers with allowed values.
+func isConstrained(tree *rules.Tree, matchers []string, allowed func(matcher, value string) bool) bool {
+	switch tree.Matcher {
+	case "and":
+		return isConstrained(tree.RuleLeft, matchers, allowed) || isConstrained(tree.RuleRight, matchers, allowed)
+	case "or":
+		return isConstrained(tree.RuleLeft, matchers, allowed) && isConstrained(tree.RuleRight, matchers, allowed)
+	default:
+		if tree.Not || !slices.Contains(matchers, tree.Matcher) || len(tree.Value) == 0 {
+			return false
+		}
+
+		for _, value := range tree.Value {
+			if !allowed(tree.Matcher, value) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+func isHostAllowed(hosts []string, host string) bool {
+	host = strings.ToLower(host)
+
+	for _, allowed := range hosts {
+		allowed = strings.ToLower(allowed)
+
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+
+		if host == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isPathAllowed tells whether the paths matched by the given Path or PathPrefix matcher value are under one of the prefixes.
+// The prefixes match whole path segments, whereas the PathPrefix matcher is a plain string prefix:
+// PathPrefix(`/api`) also matches /api-b, thus it is only allowed from PathPrefix(`/api/`) for the /api prefix.
+func isPathAllowed(prefixes []string, matcher, path string) bool {
+	for _, prefix := range prefixes {
+		if matcher == "Path" && path == prefix {
+			return true
+		}
+
+		if !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func addServiceReferences(refs map[reference]struct{}, name string, conf *runtime.Configuration) {
+	ref := reference{kind: kindService, name: name}
+	if _, ok := refs[ref]; ok {
+		return
+	}
+	refs[ref] = struct{}{}
+
+	service, ok := conf.Services[name]
+	if !ok {
+		return
+	}
+
+	pvd := providerOf(name)
+	for _, middleware := range service.Middlewares {
+		addMiddlewareReferences(refs, qualify(pvd, middleware), conf)
+	}
+
+	if service.LoadBalancer != nil && service.LoadBalancer.ServersTransport != "" {
+		refs[reference{kind: kindServersTransport, name: qualify(pvd, service.LoadBalancer.ServersTransport)}] = struct{}{}
+	}
+
+	var children []string
+	switch {
+	case service.Weighted != nil:
+		for _, child := range service.Weighted.Services {
+			children = append(children, child.Name)
+		}
+	case service.HighestRandomWeight != nil:
+		for _, child := range service.HighestRandomWeight.Services {
+			children = append(children, child.Name)
+		}
+	case service.Mirroring != nil:
+		children = append(children, service.Mirroring.Service)
+		for _, mirror := range service.Mirroring.Mirrors {
+			children = append(children, mirror.Name)
+		}
+	case service.Failover != nil:
+		children = append(children, service.Failover.Service, service.Failover.Fallback)
+	}
+
+	for _, child := range children {
+		addServiceReferences(refs, qualify(pvd, child), conf)
+	}
+}
+
+func addMiddlewareReferences(refs map[reference]struct{}, name string, conf *runtime.Configuration) {
+	ref := reference{kind: kindMiddleware, name: name}
+	if _, ok := refs[ref]; ok {
+		return
+	}
+	refs[ref] = struct{}{}
+
+	middleware, ok := conf.Middlewares[name]
+	if !ok || middleware.Chain == nil {
+		return
+	}
+
+	pvd := providerOf(name)
+	for _, child := range middleware.Chain.Middlewares {
+		addMiddlewareReferences(refs, qualify(pvd, child), conf)
+	}
+}
+
+// addTLSOptionsReference adds the TLS options of a router of the given provider, unless they are the default ones, shared by all routers.
+func addTLSOptionsReference(refs map[reference]struct{}, pvd, options string) {
+	if options == "" || options == tls.DefaultTLSConfigName {
+		return
+	}
+
+	refs[reference{kind: kindTLSOptions, name: qualify(pvd, options)}] = struct{}{}
+}
+
+func addTCPServiceReferences(refs map[reference]struct{}, name string, conf *runtime.Configuration) {
+	ref := reference{kind: kindTCPService, name: name}
+	if _, ok := refs[ref]; ok {
+		return
+	}
+	refs[ref] = struct{}{}
+
+	service, ok := conf.TCPServices[name]
+	if !ok {
+		return
+	}
+
+	pvd := providerOf(name)
+	if service.LoadBalancer != nil && service.LoadBalancer.ServersTransport != "" {
+		refs[reference{kind: kindTCPServersTransport, name: qualify(pvd, service.LoadBalancer.ServersTransport)}] = struct{}{}
+	}
+
+	var children []string
+	switch {
+	case service.Weighted != nil:
+		for _, child := range service.Weighted.Services {
+			children = append(children, child.Name)
+		}
+	case service.Mirroring != nil:
+		children = append(children, service.Mirroring.Service)
+		for _, mirror := range service.Mirroring.Mirrors {
+			children = append(children, mirror.Name)
+		}
+	}
+
+	for _, child := range children {
+		addTCPServiceReferences(refs, qualify(pvd, child), conf)
+	}
+}
+
+func addUDPServiceReferences(refs map[reference]struct{}, name string, conf *runtime.Configuration) {
+	ref := reference{kind: kindUDPService, name: name}
+	if _, ok := refs[ref]; ok {
+		return
+	}
+	refs[ref] = struct{}{}
+
+	service, ok := conf.UDPServices[name]
+	if !ok || service.Weighted == nil {
+		return
+	}
+
+	pvd := providerOf(name)
+	for _, child := range service.Weighted.Services {
+		addUDPServiceReferences(refs, qualify(pvd, child.Name), conf)
+	}
+}
+
+// Kinds of the elements checked by the tenancy.
+const (
+	kindRouter        = "router"
+	kindMiddleware    = "middleware"
+	kindService       = "service"
+	kindTCPRouter     = "TCP router"
+	kindTCPMiddleware = "TCP middleware"
+	kindTCPService    = "TCP service"
+	kindUDPRouter     = "UDP router"
+	kindUDPService    = "UDP service"
+
+	kindTLSOptions          = "TLS options"
+	kindServersTransport    = "serversTransport"
+	kindTCPServersTransport = "TCP serversTransport"
+)
+
+// reference is an element of the configuration, identified by its kind and qualified name.
+type reference struct {
+	kind string
+	name string
+}
+
+// namespaceOf returns the Kubernetes namespace of the given element, as recorded by its provider.
+func namespaceOf(ref reference, conf *runtime.Configuration) string {
+	if conf.Namespaces == nil {
+		return ""
+	}
+
+	var namespaces map[string]string
+	switch ref.kind {
+	case kindRouter:
+		namespaces = conf.Namespaces.Routers
+	case kindMiddleware:
+		namespaces = conf.Namespaces.Middlewares
+	case kindService:
+		namespaces = conf.Namespaces.Services
+	case kindTCPRouter:
+		namespaces = conf.Namespaces.TCPRouters
+	case kindTCPMiddleware:
+		namespaces = conf.Namespaces.TCPMiddlewares
+	case kindTCPService:
+		namespaces = conf.Namespaces.TCPServices
+	case kindUDPRouter:
+		namespaces = conf.Namespaces.UDPRouters
+	case kindUDPService:
+		namespaces = conf.Namespaces.UDPServices
+	case kindTLSOptions:
+		namespaces = conf.Namespaces.TLSOptions
+	case kindServersTransport:
+		namespaces = conf.Namespaces.ServersTransports
+	case kindTCPServersTransport:
+		namespaces = conf.Namespaces.TCPServersTransports
+	}
+
+	return namespaces[ref.name]
+}
+
+// qualify returns the qualified name of the element referenced from the given provider.
+func qualify(pvd, name string) string {
+	if strings.Contains(name, "@") {
+		return name
+	}
+
+	return provider.MakeQualifiedName(pvd, name)
+}
+
+func providerOf(qualifiedName string) string {
+	if i := strings.LastIndex(qualifiedName, "@"); i >= 0 {
+		return qualifiedName[i+1:]
+	}
+
+	return ""
+}
@@ -6,8 +6,11 @@ import (
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/stretchr/testify/assert"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
 	otypes "github.com/traefik/traefik/v3/pkg/observability/types"
 	"github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
 func Test_mergeConfiguration(t *testing.T) {
@@ -157,6 +160,7 @@ func Test_mergeConfiguration_tlsCertificates(t *testing.T) {
 			expected: []*tls.CertAndStores{{
 				Certificate: tls.Certificate{CertFile: "foo", KeyFile: "bar"},
 				Stores:      []string{tlsalpn01.ACMETLS1Protocol},
+				Provider:    "tlsalpn.acme",
 			}},
 		},
 	}
@@ -1230,3 +1234,340 @@ func Test_applyModel(t *testing.T) {
 		})
 	}
 }
+
+func TestTenancy_Deny(t *testing.T) {
+	tenants := map[string]*static.Tenant{
+		"team-a": {
+			Providers:     []string{"rest", "kubernetescrd/team-a"},
+			Hosts:         []string{"a.example.com", "*.a.example.com"},
+			PathPrefixes:  []string{"/api"},
+			EntryPoints:   []string{"websecure"},
+			CertResolvers: []string{"le"},
+		},
+		"team-b": {
+			Providers:        []string{"consul", "kubernetescrd/team-b"},
+			AllowedTenants:   []string{"team-a"},
+			AllowedProviders: []string{"internal"},
+		},
+		"team-c": {
+			Providers:    []string{"docker"},
+			Hosts:        []string{"a.example.com"},
+			PathPrefixes: []string{"/api-b"},
+		},
+	}
+
+	testCases := []struct {
+		desc        string
+		routerName  string
+		namespace   string
+		router      *dynamic.Router
+		tcpRouter   *dynamic.TCPRouter
+		udpRouter   *dynamic.UDPRouter
+		expectedErr string
+	}{
+		{
+			desc:       "router of a provider bound to no tenant",
+			routerName: "foo@file",
+			router:     &dynamic.Router{Rule: "Host(`b.example.com`)", Service: "svc@consul", EntryPoints: []string{"web"}},
+		},
+		{
+			desc:       "router allowed by its tenant",
+			routerName: "foo@rest",
+			router:     &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/v1`)", Service: "svc", Middlewares: []string{"mw@file"}, EntryPoints: []string{"websecure"}},
+		},
+		{
+			desc:       "router matching a subdomain of its tenant",
+			routerName: "foo@rest",
+			router:     &dynamic.Router{Rule: "(Host(`x.a.example.com`) || Host(`a.example.com`)) && Path(`/api`)", Service: "svc", EntryPoints: []string{"websecure"}},
+		},
+		{
+			desc:        "router matching another host",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`b.example.com`) && PathPrefix(`/api`)", Service: "svc", EntryPoints: []string{"websecure"}},
+			expectedErr: "rule \"Host(`b.example.com`) && PathPrefix(`/api`)\" is not restricted to the hosts of the tenant",
+		},
+		{
+			desc:        "router matching any host",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) || PathPrefix(`/api`)", Service: "svc", EntryPoints: []string{"websecure"}},
+			expectedErr: "rule \"Host(`a.example.com`) || PathPrefix(`/api`)\" is not restricted to the hosts of the tenant",
+		},
+		{
+			desc:        "router matching another path",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/apis`)", Service: "svc", EntryPoints: []string{"websecure"}},
+			expectedErr: "rule \"Host(`a.example.com`) && PathPrefix(`/apis`)\" is not restricted to the path prefixes of the tenant",
+		},
+		{
+			desc:        "router matching the path prefix of another tenant",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api`)", Service: "svc", EntryPoints: []string{"websecure"}},
+			expectedErr: "rule \"Host(`a.example.com`) && PathPrefix(`/api`)\" is not restricted to the path prefixes of the tenant",
+		},
+		{
+			desc:        "router matching the path of another tenant",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && Path(`/api-b/v1`)", Service: "svc", EntryPoints: []string{"websecure"}},
+			expectedErr: "rule \"Host(`a.example.com`) && Path(`/api-b/v1`)\" is not restricted to the path prefixes of the tenant",
+		},
+		{
+			desc:       "router matching its path prefix on a host shared with another tenant",
+			routerName: "foo@docker",
+			router:     &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api-b/`)", Service: "svc@docker"},
+		},
+		{
+			desc:       "router matching the exact path of its tenant",
+			routerName: "foo@docker",
+			router:     &dynamic.Router{Rule: "Host(`a.example.com`) && Path(`/api-b`)", Service: "svc@docker"},
+		},
+		{
+			desc:        "router using another entry point",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api`)", Service: "svc", EntryPoints: []string{"web"}},
+			expectedErr: "entryPoint \"web\" is not allowed for the tenant",
+		},
+		{
+			desc:        "router referencing the service of another tenant",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "svc@consul", EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference service \"svc@consul\" of tenant \"team-b\"",
+		},
+		{
+			desc:        "router referencing the service of another tenant through a weighted service",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "wrr", EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference service \"svc@consul\" of tenant \"team-b\"",
+		},
+		{
+			desc:        "router referencing the middleware of another tenant through a chain",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "svc", Middlewares: []string{"chain"}, EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference middleware \"team-b-mw@kubernetescrd\" of tenant \"team-b\"",
+		},
+		{
+			desc:        "router referencing the REST provider endpoint",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "rest@internal", EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference service \"rest@internal\" of provider \"internal\"",
+		},
+		{
+			desc:        "router referencing an internal middleware",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "svc", Middlewares: []string{"dashboard_redirect@internal"}, EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference middleware \"dashboard_redirect@internal\" of provider \"internal\"",
+		},
+		{
+			desc:       "router referencing the API of a tenant allowing the internal provider",
+			routerName: "foo@consul",
+			router:     &dynamic.Router{Rule: "Host(`b.example.com`)", Service: "api@internal", EntryPoints: []string{"web"}},
+		},
+		{
+			desc:        "router referencing the TLS options of another tenant",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "svc", EntryPoints: []string{"websecure"}, TLS: &dynamic.RouterTLSConfig{Options: "opt@consul"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference TLS options \"opt@consul\" of tenant \"team-b\"",
+		},
+		{
+			desc:       "router using the default TLS options",
+			routerName: "foo@rest",
+			router:     &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "svc", EntryPoints: []string{"websecure"}, TLS: &dynamic.RouterTLSConfig{Options: "default", CertResolver: "le"}},
+		},
+		{
+			desc:        "router using another certificate resolver",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "svc", EntryPoints: []string{"websecure"}, TLS: &dynamic.RouterTLSConfig{CertResolver: "other"}},
+			expectedErr: "certResolver \"other\" is not allowed for the tenant",
+		},
+		{
+			desc:       "router resolving a certificate for another host",
+			routerName: "foo@rest",
+			router: &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "svc", EntryPoints: []string{"websecure"}, TLS: &dynamic.RouterTLSConfig{
+				CertResolver: "le",
+				Domains:      []types.Domain{{Main: "a.example.com", SANs: []string{"b.example.com"}}},
+			}},
+			expectedErr: "TLS domain \"b.example.com\" is not a host of the tenant",
+		},
+		{
+			desc:        "router referencing the serversTransport of another tenant",
+			routerName:  "foo@rest",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "st", EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference serversTransport \"st@consul\" of tenant \"team-b\"",
+		},
+		{
+			desc:        "router of a namespace referencing the serversTransport of another namespace",
+			routerName:  "team-a-foo-1234@kubernetescrd",
+			namespace:   "team-a",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "team-a-svc-80", EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference serversTransport \"team-b-st@kubernetescrd\" of tenant \"team-b\"",
+		},
+		{
+			desc:        "TCP router referencing the serversTransport of another tenant",
+			routerName:  "foo@rest",
+			tcpRouter:   &dynamic.TCPRouter{Rule: "HostSNI(`a.example.com`)", Service: "st", EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference TCP serversTransport \"st@consul\" of tenant \"team-b\"",
+		},
+		{
+			desc:       "router referencing the service of an allowed tenant",
+			routerName: "foo@consul",
+			router:     &dynamic.Router{Rule: "Host(`b.example.com`)", Service: "svc@rest", EntryPoints: []string{"web"}},
+		},
+		{
+			desc:        "router of a namespace referencing the service of another namespace",
+			routerName:  "team-a-foo-1234@kubernetescrd",
+			namespace:   "team-a",
+			router:      &dynamic.Router{Rule: "Host(`a.example.com`) && PathPrefix(`/api/`)", Service: "team-b-svc-80", EntryPoints: []string{"websecure"}},
+			expectedErr: "tenant \"team-a\" is not allowed to reference service \"team-b-svc-80@kubernetescrd\" of tenant \"team-b\"",
+		},
+		{
+			desc:       "router of an unbound namespace prefixed by a bound one",
+			routerName: "team-a-x-foo-1234@kubernetescrd",
+			namespace:  "team-a-x",
+			router:     &dynamic.Router{Rule: "Host(`x.example.com`)", Service: "team-b-svc-80", EntryPoints: []string{"web"}},
+		},
+		{
+			desc:        "router of a bound namespace named like another namespace",
+			routerName:  "team-a-x-foo-1234@kubernetescrd",
+			namespace:   "team-a",
+			router:      &dynamic.Router{Rule: "Host(`x.example.com`)", Service: "team-b-svc-80", EntryPoints: []string{"websecure"}},
+			expectedErr: "rule \"Host(`x.example.com`)\" is not restricted to the hosts of the tenant",
+		},
+		{
+			desc:       "router of a namespace unknown to the tenancy",
+			routerName: "team-a-foo-1234@kubernetescrd",
+			router:     &dynamic.Router{Rule: "Host(`x.example.com`)", Service: "team-b-svc-80", EntryPoints: []string{"web"}},
+		},
+		{
+			desc:        "TCP router matching another host",
+			routerName:  "foo@rest",
+			tcpRouter:   &dynamic.TCPRouter{Rule: "HostSNI(`*`)", Service: "svc", EntryPoints: []string{"websecure"}},
+			expectedErr: "rule \"HostSNI(`*`)\" is not restricted to the hosts of the tenant",
+		},
+		{
+			desc:       "TCP router allowed by its tenant",
+			routerName: "foo@rest",
+			tcpRouter:  &dynamic.TCPRouter{Rule: "HostSNI(`a.example.com`)", Service: "svc", EntryPoints: []string{"websecure"}},
+		},
+		{
+			desc:       "UDP router allowed by its tenant",
+			routerName: "foo@rest",
+			udpRouter:  &dynamic.UDPRouter{Service: "svc", EntryPoints: []string{"websecure"}},
+		},
+		{
+			desc:        "UDP router without entry points",
+			routerName:  "foo@rest",
+			udpRouter:   &dynamic.UDPRouter{Service: "svc"},
+			expectedErr: "a router without entryPoints uses all the entry points, which is not allowed for the tenant",
+		},
+		{
+			desc:       "UDP router without entry points of a tenant not restricting them",
+			routerName: "foo@consul",
+			udpRouter:  &dynamic.UDPRouter{Service: "svc"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{},
+					Services: map[string]*dynamic.Service{
+						"svc@rest":   {},
+						"svc@consul": {},
+						"wrr@rest": {Weighted: &dynamic.WeightedRoundRobin{Services: []dynamic.WRRService{
+							{Name: "svc"},
+							{Name: "svc@consul"},
+						}}},
+						"st@rest":                     {LoadBalancer: &dynamic.ServersLoadBalancer{ServersTransport: "st@consul"}},
+						"team-a-svc-80@kubernetescrd": {LoadBalancer: &dynamic.ServersLoadBalancer{ServersTransport: "team-b-st"}},
+					},
+					Middlewares: map[string]*dynamic.Middleware{
+						"chain@rest":              {Chain: &dynamic.Chain{Middlewares: []string{"mw@file", "team-b-mw@kubernetescrd"}}},
+						"team-b-mw@kubernetescrd": {},
+						"mw@file":                 {},
+					},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{
+						"st@rest": {LoadBalancer: &dynamic.TCPServersLoadBalancer{ServersTransport: "st@consul"}},
+					},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers: map[string]*dynamic.UDPRouter{},
+				},
+				Namespaces: &dynamic.Namespaces{
+					Routers:     map[string]string{},
+					Middlewares: map[string]string{"team-b-mw@kubernetescrd": "team-b"},
+					Services: map[string]string{
+						"team-a-svc-80@kubernetescrd": "team-a",
+						"team-b-svc-80@kubernetescrd": "team-b",
+					},
+					ServersTransports: map[string]string{"team-b-st@kubernetescrd": "team-b"},
+				},
+			}
+
+			if test.namespace != "" {
+				conf.Namespaces.Routers[test.routerName] = test.namespace
+			}
+
+			switch {
+			case test.router != nil:
+				conf.HTTP.Routers[test.routerName] = test.router
+			case test.tcpRouter != nil:
+				conf.TCP.Routers[test.routerName] = test.tcpRouter
+			case test.udpRouter != nil:
+				conf.UDP.Routers[test.routerName] = test.udpRouter
+			}
+
+			rtConf := runtime.NewConfig(conf)
+
+			restore := NewTenancy(tenants).Deny(t.Context(), rtConf)
+
+			var errs []string
+			switch {
+			case test.router != nil:
+				router := rtConf.Routers[test.routerName]
+				if test.expectedErr != "" {
+					assert.Nil(t, router)
+				} else {
+					assert.NotNil(t, router)
+				}
+
+				restore()
+
+				errs = rtConf.Routers[test.routerName].Err
+			case test.tcpRouter != nil:
+				router := rtConf.TCPRouters[test.routerName]
+				if test.expectedErr != "" {
+					assert.Nil(t, router)
+				} else {
+					assert.NotNil(t, router)
+				}
+
+				restore()
+
+				errs = rtConf.TCPRouters[test.routerName].Err
+			default:
+				router := rtConf.UDPRouters[test.routerName]
+				if test.expectedErr != "" {
+					assert.Nil(t, router)
+				} else {
+					assert.NotNil(t, router)
+				}
+
+				restore()
+
+				errs = rtConf.UDPRouters[test.routerName].Err
+			}
+
+			if test.expectedErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			assert.Equal(t, []string{test.expectedErr}, errs)
+		})
+	}
+}
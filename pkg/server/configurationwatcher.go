@@ -3,18 +3,28 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
 	"github.com/traefik/traefik/v3/pkg/provider"
 	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/server/history"
 	"github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
 
+// configurationsUpdate holds the configurations received from the providers,
+// and the ones resulting from the transformers, which are applied.
+type configurationsUpdate struct {
+	configurations dynamic.Configurations
+	transformed    dynamic.Configurations
+}
+
 // ConfigurationWatcher watches configuration changes.
 type ConfigurationWatcher struct {
 	providerAggregator provider.Provider
@@ -23,13 +33,18 @@ type ConfigurationWatcher struct {
 
 	allProvidersConfigs chan dynamic.Message
 
-	newConfigs chan dynamic.Configurations
+	newConfigs chan configurationsUpdate
 
 	requiredProvider       string
 	configurationListeners []func(dynamic.Configuration)
 
 	configurationTransformers []func(context.Context, dynamic.Configurations) dynamic.Configurations
 
+	history *history.History
+
+	maxDisabledRouters float64
+	buildRouters       func(dynamic.Configuration) (*runtime.Configuration, error)
+
 	routinesPool *safe.Pool
 }
 
@@ -43,7 +58,7 @@ func NewConfigurationWatcher(
 	return &ConfigurationWatcher{
 		providerAggregator:  pvd,
 		allProvidersConfigs: make(chan dynamic.Message, 100),
-		newConfigs:          make(chan dynamic.Configurations),
+		newConfigs:          make(chan configurationsUpdate),
 		routinesPool:        routinesPool,
 		defaultEntryPoints:  defaultEntryPoints,
 		requiredProvider:    requiredProvider,
@@ -73,6 +88,18 @@ func (c *ConfigurationWatcher) AddTransformer(transformer func(context.Context,
 	c.configurationTransformers = append(c.configurationTransformers, transformer)
 }
 
+// SetHistory sets the history recording the applied configurations, and from which they can be rolled back.
+func (c *ConfigurationWatcher) SetHistory(h *history.History) {
+	c.history = h
+}
+
+// SetRoutersGuard rejects the configurations which would disable more than the given fraction of the enabled routers.
+// The status of the routers of a configuration is the one of the runtime configuration returned by buildRouters.
+func (c *ConfigurationWatcher) SetRoutersGuard(maxDisabledRouters float64, buildRouters func(dynamic.Configuration) (*runtime.Configuration, error)) {
+	c.maxDisabledRouters = maxDisabledRouters
+	c.buildRouters = buildRouters
+}
+
 func (c *ConfigurationWatcher) startProviderAggregator() {
 	log.Info().Msgf("Starting provider aggregator %T", c.providerAggregator)
 
@@ -95,14 +122,14 @@ func (c *ConfigurationWatcher) receiveConfigurations(ctx context.Context) {
 	newConfigurations := make(dynamic.Configurations)
 	transformedConfigurations := make(dynamic.Configurations)
 
-	var output chan dynamic.Configurations
+	var output chan configurationsUpdate
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		// DeepCopy is necessary because transformedConfigurations gets modified later by the consumer of c.newConfigs.
-		case output <- transformedConfigurations.DeepCopy():
+		// DeepCopy is necessary because the configurations get modified later by the consumer of c.newConfigs.
+		case output <- configurationsUpdate{configurations: newConfigurations.DeepCopy(), transformed: transformedConfigurations.DeepCopy()}:
 			output = nil
 
 		default:
@@ -136,35 +163,74 @@ func (c *ConfigurationWatcher) receiveConfigurations(ctx context.Context) {
 
 				newConfigurations[configMsg.ProviderName] = configMsg.Configuration.DeepCopy()
 
-				transformedConfigurations = newConfigurations
-				for _, transform := range c.configurationTransformers {
-					transformedConfigurations = transform(logger.WithContext(ctx), transformedConfigurations.DeepCopy())
-				}
+				transformedConfigurations = c.transform(logger.WithContext(ctx), newConfigurations)
 
 				output = c.newConfigs
 
-			// DeepCopy is necessary because newConfigurations gets modified later by the consumer of c.newConfigs.
-			case output <- transformedConfigurations.DeepCopy():
+			// DeepCopy is necessary because the configurations get modified later by the consumer of c.newConfigs.
+			case output <- configurationsUpdate{configurations: newConfigurations.DeepCopy(), transformed: transformedConfigurations.DeepCopy()}:
 				output = nil
 			}
 		}
 	}
 }
 
+// transform returns the given configurations modified by the transformers.
+func (c *ConfigurationWatcher) transform(ctx context.Context, configurations dynamic.Configurations) dynamic.Configurations {
+	transformed := configurations
+	for _, transform := range c.configurationTransformers {
+		transformed = transform(ctx, transformed.DeepCopy())
+	}
+
+	return transformed
+}
+
 // applyConfigurations receives the full set of configurations from
 // receiveConfigurations and applies them if they differ from the previous set.
 // It waits for the required provider's configuration before applying any configs.
+// It also applies the configurations rolled back from the history, if any,
+// which are recorded before the transformers run, and thus go through them again.
 func (c *ConfigurationWatcher) applyConfigurations(ctx context.Context) {
 	var lastConfigurations dynamic.Configurations
+
+	// routersStatus holds the status of the routers of the last applied configuration, when the routers guard is set.
+	var routersStatus map[string]string
+
+	var rollbacks <-chan history.Entry
+	if c.history != nil {
+		rollbacks = c.history.Rollbacks()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case newConfigs, ok := <-c.newConfigs:
+		case entry := <-rollbacks:
+			log.Ctx(ctx).Info().Msgf("Rolling back to the configuration version %d", entry.Version)
+
+			configurations := entry.Configurations()
+			newConfigs := c.transform(ctx, configurations)
+			conf := MergeConfigurations(newConfigs, c.defaultEntryPoints)
+
+			// The routers status is updated, but a rollback is never rejected.
+			if c.buildRouters != nil {
+				if status, err := c.routersStatus(conf); err != nil {
+					log.Ctx(ctx).Error().Err(err).Msg("Unable to compute the routers status")
+				} else {
+					routersStatus = status
+				}
+			}
+
+			c.apply(configurations, conf, entry.Version)
+
+			lastConfigurations = newConfigs
+		case update, ok := <-c.newConfigs:
 			if !ok {
 				return
 			}
 
+			newConfigs := update.transformed
+
 			// We wait for first configuration of the required provider before applying configurations.
 			if _, ok := newConfigs[c.requiredProvider]; c.requiredProvider != "" && !ok {
 				continue
@@ -174,18 +240,89 @@ func (c *ConfigurationWatcher) applyConfigurations(ctx context.Context) {
 				continue
 			}
 
-			conf := mergeConfiguration(newConfigs.DeepCopy(), c.defaultEntryPoints)
-			conf = applyModel(conf)
-
-			for _, listener := range c.configurationListeners {
-				listener(conf)
+			conf := MergeConfigurations(newConfigs, c.defaultEntryPoints)
+
+			if c.buildRouters != nil {
+				status, err := c.routersStatus(conf)
+				if err != nil {
+					log.Ctx(ctx).Error().Err(err).Msg("Unable to compute the routers status, applying the configuration anyway")
+				} else {
+					if err := c.checkDisabledRouters(routersStatus, status); err != nil {
+						log.Ctx(ctx).Error().Err(err).Msg("Rejecting the configuration")
+						if c.history != nil {
+							c.history.Reject(update.configurations, MergeConfigurations(update.configurations, c.defaultEntryPoints), err.Error())
+						}
+						continue
+					}
+
+					routersStatus = status
+				}
 			}
 
+			c.apply(update.configurations, conf, 0)
+
 			lastConfigurations = newConfigs
 		}
 	}
 }
 
+// apply records the given configurations of the providers in the history, if any,
+// and passes the given configuration, resulting from their transformation, to the listeners.
+// The history holds the configurations as received, e.g. with their secret references unresolved.
+func (c *ConfigurationWatcher) apply(configurations dynamic.Configurations, conf dynamic.Configuration, rollbackOf int) {
+	if c.history != nil {
+		c.history.Add(configurations, MergeConfigurations(configurations, c.defaultEntryPoints), rollbackOf)
+	}
+
+	for _, listener := range c.configurationListeners {
+		listener(conf)
+	}
+}
+
+// routersStatus returns the status of the routers of the given configuration, by protocol and name.
+func (c *ConfigurationWatcher) routersStatus(conf dynamic.Configuration) (map[string]string, error) {
+	rtConf, err := c.buildRouters(*conf.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]string)
+	for name, rt := range rtConf.Routers {
+		status["http/"+name] = rt.Status
+	}
+	for name, rt := range rtConf.TCPRouters {
+		status["tcp/"+name] = rt.Status
+	}
+	for name, rt := range rtConf.UDPRouters {
+		status["udp/"+name] = rt.Status
+	}
+
+	return status, nil
+}
+
+// checkDisabledRouters returns an error if the routers which were enabled, and are disabled in the new configuration,
+// exceed the allowed fraction of the enabled routers. The routers which are removed are not taken into account.
+func (c *ConfigurationWatcher) checkDisabledRouters(previous, current map[string]string) error {
+	var enabled, disabled int
+	for name, status := range previous {
+		if status != runtime.StatusEnabled {
+			continue
+		}
+
+		enabled++
+
+		if current[name] == runtime.StatusDisabled {
+			disabled++
+		}
+	}
+
+	if enabled == 0 || float64(disabled)/float64(enabled) <= c.maxDisabledRouters {
+		return nil
+	}
+
+	return fmt.Errorf("%d of the %d enabled routers would be disabled, which exceeds the allowed fraction of %v", disabled, enabled, c.maxDisabledRouters)
+}
+
 func logConfiguration(logger zerolog.Logger, configMsg dynamic.Message) {
 	if logger.GetLevel() > zerolog.DebugLevel {
 		return
@@ -3,15 +3,21 @@ package server
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/secret"
 	"github.com/traefik/traefik/v3/pkg/provider/aggregator"
 	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/server/history"
 	th "github.com/traefik/traefik/v3/pkg/testhelpers"
 	"github.com/traefik/traefik/v3/pkg/tls"
 )
@@ -992,3 +998,214 @@ func TestConfigurationWatcher_MultipleTransformers(t *testing.T) {
 	assert.Equal(t, 1, callCount1)
 	assert.Equal(t, 1, callCount2)
 }
+
+func TestConfigurationWatcher_History(t *testing.T) {
+	routinesPool := safe.NewPool(t.Context())
+	t.Cleanup(routinesPool.Stop)
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{
+			{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(
+						th.WithRouters(th.WithRouter("foo", th.WithEntryPoints("e"), th.WithServiceName("scv"))),
+					),
+				},
+			},
+			{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(
+						th.WithRouters(th.WithRouter("bar", th.WithEntryPoints("e"), th.WithServiceName("scv"))),
+					),
+				},
+			},
+		},
+	}
+
+	configurationHistory := history.New(10)
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher.SetHistory(configurationHistory)
+
+	var lock sync.Mutex
+	var routers [][]string
+
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		routers = append(routers, slices.Sorted(maps.Keys(conf.HTTP.Routers)))
+	})
+
+	watcher.Start()
+	t.Cleanup(watcher.Stop)
+
+	require.Eventually(t, func() bool { return configurationHistory.Latest() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, configurationHistory.Rollback(1))
+
+	require.Eventually(t, func() bool { return configurationHistory.Latest() == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	entries := configurationHistory.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, 1, entries[0].RollbackOf)
+	assert.Equal(t, []string{"mock"}, entries[0].ChangedProviders)
+	assert.Equal(t, []string{"mock"}, entries[0].Providers)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	assert.Equal(t, [][]string{{"foo@mock"}, {"bar@mock"}, {"foo@mock"}}, routers)
+}
+
+func TestConfigurationWatcher_History_transformers(t *testing.T) {
+	t.Setenv("TRAEFIK_TEST_USERS", "test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/")
+
+	routinesPool := safe.NewPool(t.Context())
+	t.Cleanup(routinesPool.Stop)
+
+	authConfiguration := func(routerName string) *dynamic.Configuration {
+		conf := &dynamic.Configuration{
+			HTTP: th.BuildConfiguration(
+				th.WithRouters(th.WithRouter(routerName, th.WithEntryPoints("e"), th.WithServiceName("scv"))),
+			),
+		}
+		conf.HTTP.Middlewares = map[string]*dynamic.Middleware{
+			"auth": {BasicAuth: &dynamic.BasicAuth{Users: dynamic.Users{"env:TRAEFIK_TEST_USERS"}}},
+		}
+
+		return conf
+	}
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{
+			{ProviderName: "mock", Configuration: authConfiguration("foo")},
+			{ProviderName: "mock", Configuration: authConfiguration("bar")},
+		},
+	}
+
+	configurationHistory := history.New(10)
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher.AddTransformer(secret.NewResolvers([]string{"TRAEFIK_TEST_"}, nil).Transformer([]string{"mock"}))
+	watcher.SetHistory(configurationHistory)
+
+	var lock sync.Mutex
+	var users []dynamic.Users
+
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		users = append(users, conf.HTTP.Middlewares["auth@mock"].BasicAuth.Users)
+	})
+
+	watcher.Start()
+	t.Cleanup(watcher.Stop)
+
+	require.Eventually(t, func() bool { return configurationHistory.Latest() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// The secret is rotated before the rollback.
+	t.Setenv("TRAEFIK_TEST_USERS", "test:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0")
+
+	require.NoError(t, configurationHistory.Rollback(1))
+
+	require.Eventually(t, func() bool { return configurationHistory.Latest() == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	// The history holds the secret references, not the resolved secrets.
+	for _, entry := range configurationHistory.Entries() {
+		assert.Equal(t, dynamic.Users{"env:TRAEFIK_TEST_USERS"}, entry.Configurations()["mock"].HTTP.Middlewares["auth"].BasicAuth.Users)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	assert.Equal(t, []dynamic.Users{
+		{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"},
+		{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"},
+		{"test:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0"},
+	}, users)
+}
+
+func TestConfigurationWatcher_RoutersGuard(t *testing.T) {
+	routinesPool := safe.NewPool(t.Context())
+	t.Cleanup(routinesPool.Stop)
+
+	routers := func(services ...string) *dynamic.Configuration {
+		var opts []func(*dynamic.Router) string
+		for i, service := range services {
+			opts = append(opts, th.WithRouter("router"+strconv.Itoa(i), th.WithEntryPoints("e"), th.WithServiceName(service)))
+		}
+
+		return &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(opts...))}
+	}
+
+	pvd := &mockProvider{
+		wait: 100 * time.Millisecond,
+		messages: []dynamic.Message{
+			{ProviderName: "mock", Configuration: routers("scv", "scv", "scv", "scv")},
+			// Disables 3 of the 4 enabled routers.
+			{ProviderName: "mock", Configuration: routers("missing", "missing", "missing", "scv")},
+			// Disables 1 of the 4 enabled routers.
+			{ProviderName: "mock", Configuration: routers("missing", "scv", "scv", "scv")},
+		},
+	}
+
+	h := history.New(10)
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher.SetHistory(h)
+	watcher.SetRoutersGuard(0.5, func(conf dynamic.Configuration) (*runtime.Configuration, error) {
+		rtConf := runtime.NewConfig(conf)
+		for _, rt := range rtConf.Routers {
+			if rt.Service == "missing" {
+				rt.AddError(errors.New("missing service"), true)
+			} else {
+				rt.Status = runtime.StatusEnabled
+			}
+		}
+
+		return rtConf, nil
+	})
+
+	var lock sync.Mutex
+	var disabled []int
+
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		var count int
+		for _, rt := range conf.HTTP.Routers {
+			if rt.Service == "missing" {
+				count++
+			}
+		}
+		disabled = append(disabled, count)
+	})
+
+	watcher.Start()
+	t.Cleanup(watcher.Stop)
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return len(disabled) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	assert.Equal(t, []int{0, 1}, disabled)
+
+	// The rejected configuration is recorded in the history.
+	entries := h.Entries()
+	require.Len(t, entries, 3)
+	assert.Empty(t, entries[0].Rejected)
+	assert.NotEmpty(t, entries[1].Rejected)
+	assert.Empty(t, entries[2].Rejected)
+	assert.Equal(t, 3, h.Latest())
+}
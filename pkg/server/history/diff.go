@@ -0,0 +1,114 @@
+package history
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// Change is a value which is removed, added, or changed between two configurations.
+type Change struct {
+	Path string          `json:"path"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+// Diff returns the values which are removed, added, or changed between the given configurations, sorted by path.
+func Diff(oldConfiguration, newConfiguration *dynamic.Configuration) ([]Change, error) {
+	oldData, err := json.Marshal(oldConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	newData, err := json.Marshal(newConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffJSON(oldData, newData)
+}
+
+func diffJSON(oldData, newData []byte) ([]Change, error) {
+	oldValues, err := flattenJSON(oldData)
+	if err != nil {
+		return nil, err
+	}
+
+	newValues, err := flattenJSON(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := slices.Collect(maps.Keys(oldValues))
+	for path := range newValues {
+		if _, ok := oldValues[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	var changes []Change
+	for _, path := range paths {
+		oldValue := oldValues[path]
+		newValue := newValues[path]
+
+		if oldValue != nil && newValue != nil && string(oldValue) == string(newValue) {
+			continue
+		}
+
+		changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+	}
+
+	return changes, nil
+}
+
+// flattenJSON returns the leaf values of the given JSON document, by path.
+func flattenJSON(data []byte) (map[string]json.RawMessage, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]json.RawMessage)
+	if err := flatten(values, "", value); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func flatten(values map[string]json.RawMessage, path string, value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elt := range v {
+			eltPath := key
+			if path != "" {
+				eltPath = path + "." + key
+			}
+
+			if err := flatten(values, eltPath, elt); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		for i, elt := range v {
+			if err := flatten(values, path+"["+strconv.Itoa(i)+"]", elt); err != nil {
+				return err
+			}
+		}
+
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		values[path] = data
+	}
+
+	return nil
+}
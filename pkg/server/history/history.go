@@ -0,0 +1,225 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/redactor"
+)
+
+// ErrVersionNotFound is returned when a version is not, or no longer, in the history.
+var ErrVersionNotFound = errors.New("version not found")
+
+// Entry is a dynamic configuration applied, or rejected, by Traefik.
+type Entry struct {
+	Version int       `json:"version"`
+	Date    time.Time `json:"date"`
+	// Providers are the providers of the configuration.
+	Providers []string `json:"providers"`
+	// ChangedProviders are the providers whose configuration changed since the previous entry.
+	ChangedProviders []string `json:"changedProviders,omitempty"`
+	// RollbackOf is the version restored by the entry, if it results from a rollback.
+	RollbackOf int `json:"rollbackOf,omitempty"`
+	// Rejected is the reason why the configuration has not been applied, if it has been rejected.
+	// A rejected configuration can still be applied by rolling back to it.
+	Rejected string `json:"rejected,omitempty"`
+
+	configurations dynamic.Configurations
+	configuration  *dynamic.Configuration
+}
+
+// Configurations returns the configurations of the providers of the entry.
+func (e Entry) Configurations() dynamic.Configurations {
+	return e.configurations.DeepCopy()
+}
+
+// History holds the last configurations applied, or rejected, by Traefik.
+type History struct {
+	maxEntries int
+
+	lock        sync.RWMutex
+	entries     []Entry
+	lastVersion int
+
+	rollbacks chan Entry
+}
+
+// New creates a History keeping the given number of entries.
+func New(maxEntries int) *History {
+	return &History{
+		maxEntries: maxEntries,
+		rollbacks:  make(chan Entry, 1),
+	}
+}
+
+// Add records the given configurations of the providers, merged into the given configuration, as the applied one.
+// rollbackOf is the version restored by these configurations, if any.
+func (h *History) Add(configurations dynamic.Configurations, configuration dynamic.Configuration, rollbackOf int) Entry {
+	return h.add(configurations, configuration, rollbackOf, "")
+}
+
+// Reject records the given configurations of the providers, merged into the given configuration, as rejected for the given reason.
+// The changed providers of the entry are the ones whose configuration differs from the applied one.
+func (h *History) Reject(configurations dynamic.Configurations, configuration dynamic.Configuration, reason string) Entry {
+	return h.add(configurations, configuration, 0, reason)
+}
+
+func (h *History) add(configurations dynamic.Configurations, configuration dynamic.Configuration, rollbackOf int, rejected string) Entry {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var previous dynamic.Configurations
+	if i := h.lastApplied(len(h.entries)); i >= 0 {
+		previous = h.entries[i].configurations
+	}
+
+	var changed []string
+	for name, conf := range configurations {
+		if !reflect.DeepEqual(previous[name], conf) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range previous {
+		if _, ok := configurations[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+
+	h.lastVersion++
+
+	entry := Entry{
+		Version:          h.lastVersion,
+		Date:             time.Now().UTC(),
+		Providers:        slices.Sorted(maps.Keys(configurations)),
+		ChangedProviders: changed,
+		RollbackOf:       rollbackOf,
+		Rejected:         rejected,
+		configurations:   configurations.DeepCopy(),
+		configuration:    configuration.DeepCopy(),
+	}
+
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.maxEntries {
+		h.entries = slices.Delete(h.entries, 0, len(h.entries)-h.maxEntries)
+	}
+
+	return entry
+}
+
+// Entries returns the entries of the history, the most recent first.
+func (h *History) Entries() []Entry {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	entries := slices.Clone(h.entries)
+	slices.Reverse(entries)
+
+	return entries
+}
+
+// Get returns the entry of the given version.
+func (h *History) Get(version int) (Entry, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	for _, entry := range h.entries {
+		if entry.Version == version {
+			return entry, nil
+		}
+	}
+
+	return Entry{}, fmt.Errorf("%w: %d", ErrVersionNotFound, version)
+}
+
+// Latest returns the version of the last applied configuration, or zero if none has been applied yet.
+func (h *History) Latest() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if i := h.lastApplied(len(h.entries)); i >= 0 {
+		return h.entries[i].Version
+	}
+
+	return 0
+}
+
+// Previous returns the version of the configuration applied before the given version, or zero if there is none.
+func (h *History) Previous(version int) int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	end := slices.IndexFunc(h.entries, func(entry Entry) bool { return entry.Version >= version })
+	if end < 0 {
+		end = len(h.entries)
+	}
+
+	if i := h.lastApplied(end); i >= 0 {
+		return h.entries[i].Version
+	}
+
+	return 0
+}
+
+// lastApplied returns the index of the last applied entry before the given index, or -1 if there is none.
+// It is the responsibility of the caller to hold the lock.
+func (h *History) lastApplied(end int) int {
+	for i := end - 1; i >= 0; i-- {
+		if h.entries[i].Rejected == "" {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Diff returns the changes between the configurations of the given versions, without their credentials.
+func (h *History) Diff(fromVersion, toVersion int) ([]Change, error) {
+	from, err := h.Get(fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := h.Get(toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fromData, err := redactor.RemoveCredentials(from.configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	toData, err := redactor.RemoveCredentials(to.configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffJSON([]byte(fromData), []byte(toData))
+}
+
+// Rollback requests the configuration of the given version to be applied again.
+func (h *History) Rollback(version int) error {
+	entry, err := h.Get(version)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case h.rollbacks <- entry:
+		return nil
+	default:
+		return errors.New("a rollback is already in progress")
+	}
+}
+
+// Rollbacks returns the channel receiving the entries to roll back to.
+func (h *History) Rollbacks() <-chan Entry {
+	return h.rollbacks
+}
@@ -0,0 +1,148 @@
+package history
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestHistory_Add(t *testing.T) {
+	h := New(2)
+
+	foo := routerConfiguration("foo", "Host(`foo.localhost`)")
+	bar := routerConfiguration("bar", "Host(`bar.localhost`)")
+
+	h.Add(dynamic.Configurations{"file": foo}, *foo, 0)
+	h.Add(dynamic.Configurations{"file": foo, "docker": bar}, *bar, 0)
+	h.Add(dynamic.Configurations{"file": bar}, *bar, 1)
+
+	entries := h.Entries()
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, 3, entries[0].Version)
+	assert.Equal(t, []string{"file"}, entries[0].Providers)
+	assert.Equal(t, []string{"docker", "file"}, entries[0].ChangedProviders)
+	assert.Equal(t, 1, entries[0].RollbackOf)
+
+	assert.Equal(t, 2, entries[1].Version)
+	assert.Equal(t, []string{"docker", "file"}, entries[1].Providers)
+	assert.Equal(t, []string{"docker"}, entries[1].ChangedProviders)
+
+	assert.Equal(t, 3, h.Latest())
+
+	_, err := h.Get(1)
+	require.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestHistory_Reject(t *testing.T) {
+	h := New(10)
+
+	foo := routerConfiguration("foo", "Host(`foo.localhost`)")
+	bar := routerConfiguration("bar", "Host(`bar.localhost`)")
+
+	h.Add(dynamic.Configurations{"file": foo}, *foo, 0)
+	h.Reject(dynamic.Configurations{"file": bar}, *bar, "too many routers disabled")
+	h.Add(dynamic.Configurations{"file": foo, "docker": bar}, *bar, 0)
+
+	entries := h.Entries()
+	require.Len(t, entries, 3)
+
+	// The changed providers are computed against the last applied configuration.
+	assert.Equal(t, 3, entries[0].Version)
+	assert.Empty(t, entries[0].Rejected)
+	assert.Equal(t, []string{"docker"}, entries[0].ChangedProviders)
+
+	assert.Equal(t, 2, entries[1].Version)
+	assert.Equal(t, "too many routers disabled", entries[1].Rejected)
+	assert.Equal(t, []string{"file"}, entries[1].ChangedProviders)
+
+	assert.Equal(t, 3, h.Latest())
+	assert.Equal(t, 1, h.Previous(3))
+	assert.Equal(t, 1, h.Previous(2))
+	assert.Equal(t, 0, h.Previous(1))
+
+	// A rejected configuration can be applied by rolling back to it.
+	require.NoError(t, h.Rollback(2))
+}
+
+func TestHistory_Diff(t *testing.T) {
+	h := New(10)
+
+	foo := routerConfiguration("foo", "Host(`foo.localhost`)")
+	foo.TLS = &dynamic.TLSConfiguration{
+		Certificates: []*tls.CertAndStores{{Certificate: tls.Certificate{CertFile: "cert", KeyFile: types.FileOrContent("key1")}}},
+	}
+	h.Add(dynamic.Configurations{"file": foo}, *foo, 0)
+
+	bar := routerConfiguration("foo", "Host(`bar.localhost`)")
+	bar.TLS = &dynamic.TLSConfiguration{
+		Certificates: []*tls.CertAndStores{{Certificate: tls.Certificate{CertFile: "cert", KeyFile: types.FileOrContent("key2")}}},
+	}
+	h.Add(dynamic.Configurations{"file": bar}, *bar, 0)
+
+	changes, err := h.Diff(1, 2)
+	require.NoError(t, err)
+
+	// The keys are redacted, so their change is not visible.
+	expected := []Change{{
+		Path: "http.routers.foo.rule",
+		Old:  json.RawMessage(`"Host(` + "`foo.localhost`" + `)"`),
+		New:  json.RawMessage(`"Host(` + "`bar.localhost`" + `)"`),
+	}}
+	assert.Equal(t, expected, changes)
+
+	_, err = h.Diff(1, 3)
+	require.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestHistory_Rollback(t *testing.T) {
+	h := New(10)
+
+	foo := routerConfiguration("foo", "Host(`foo.localhost`)")
+	h.Add(dynamic.Configurations{"file": foo}, *foo, 0)
+
+	require.ErrorIs(t, h.Rollback(2), ErrVersionNotFound)
+
+	require.NoError(t, h.Rollback(1))
+	require.Error(t, h.Rollback(1))
+
+	entry := <-h.Rollbacks()
+	assert.Equal(t, 1, entry.Version)
+	assert.Equal(t, dynamic.Configurations{"file": foo}, entry.Configurations())
+}
+
+func TestDiff(t *testing.T) {
+	oldConfiguration := routerConfiguration("foo", "Host(`foo.localhost`)")
+	oldConfiguration.HTTP.Routers["bar"] = &dynamic.Router{Rule: "Host(`bar.localhost`)", Middlewares: []string{"a", "b"}}
+
+	newConfiguration := routerConfiguration("foo", "Host(`foo.localhost`)")
+	newConfiguration.HTTP.Routers["bar"] = &dynamic.Router{Rule: "Host(`bar.localhost`)", Middlewares: []string{"a"}, Priority: 10}
+
+	changes, err := Diff(oldConfiguration, newConfiguration)
+	require.NoError(t, err)
+
+	expected := []Change{
+		{Path: "http.routers.bar.middlewares[1]", Old: json.RawMessage(`"b"`)},
+		{Path: "http.routers.bar.priority", New: json.RawMessage(`10`)},
+	}
+	assert.Equal(t, expected, changes)
+
+	changes, err = Diff(oldConfiguration, oldConfiguration)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func routerConfiguration(name, rule string) *dynamic.Configuration {
+	return &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				name: {Rule: rule, Service: name},
+			},
+		},
+	}
+}
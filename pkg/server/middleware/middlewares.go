@@ -39,6 +39,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/retry"
 	"github.com/traefik/traefik/v3/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v3/pkg/middlewares/stripprefixregex"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tlsclientcertauth"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/server/recursion"
 )
@@ -281,6 +282,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// TLSClientCertAuth
+	if config.TLSClientCertAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return tlsclientcertauth.New(ctx, next, *config.TLSClientCertAuth, middlewareName)
+		}
+	}
+
 	// AuthTLSPassCertificateToUpstream
 	if config.AuthTLSPassCertificateToUpstream != nil {
 		if middleware != nil {
@@ -17,6 +17,8 @@ import (
 	"github.com/traefik/traefik/v3/pkg/observability/metrics"
 	"github.com/traefik/traefik/v3/pkg/observability/tracing"
 	otypes "github.com/traefik/traefik/v3/pkg/observability/types"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/udp"
 )
 
 // ObservabilityMgr is a manager for observability (AccessLogs, Metrics and Tracing) enablement.
@@ -80,6 +82,24 @@ func (o *ObservabilityMgr) BuildEPChain(ctx context.Context, entryPointName stri
 	return chain
 }
 
+// WrapTCPHandler wraps the given TCP router handler with the access logger, if access logs are enabled for TCP connections.
+func (o *ObservabilityMgr) WrapTCPHandler(entryPointName, routerName, serviceName string, internal bool, next tcp.Handler) tcp.Handler {
+	if !o.shouldAccessLogConnections(internal) {
+		return next
+	}
+
+	return o.accessLoggerMiddleware.NewTCPHandler(entryPointName, routerName, serviceName, next)
+}
+
+// WrapUDPHandler wraps the given UDP router handler with the access logger, if access logs are enabled for UDP sessions.
+func (o *ObservabilityMgr) WrapUDPHandler(entryPointName, routerName, serviceName string, internal bool, next udp.Handler) udp.Handler {
+	if !o.shouldAccessLogConnections(internal) {
+		return next
+	}
+
+	return o.accessLoggerMiddleware.NewUDPHandler(entryPointName, routerName, serviceName, next)
+}
+
 // MetricsRegistry is an accessor to the metrics registry.
 func (o *ObservabilityMgr) MetricsRegistry() metrics.Registry {
 	if o == nil {
@@ -152,6 +172,19 @@ func (o *ObservabilityMgr) shouldAccessLog(internal bool, observabilityConfig dy
 	return observabilityConfig.AccessLogs == nil || *observabilityConfig.AccessLogs
 }
 
+// shouldAccessLogConnections returns whether the access logs should be enabled for TCP and UDP connections.
+func (o *ObservabilityMgr) shouldAccessLogConnections(internal bool) bool {
+	if o == nil || o.accessLoggerMiddleware == nil {
+		return false
+	}
+
+	if o.config.AccessLog == nil || !o.config.AccessLog.AddTCPAndUDP {
+		return false
+	}
+
+	return !internal || o.config.AccessLog.AddInternals
+}
+
 // shouldMeter returns whether the metrics should be enabled for the given serviceName and the observability config.
 func (o *ObservabilityMgr) shouldMeter(internal bool, observabilityConfig dynamic.RouterObservabilityConfig) bool {
 	if o == nil || o.metricsRegistry == nil {
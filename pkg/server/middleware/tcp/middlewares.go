@@ -11,6 +11,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/inflightconn"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipallowlist"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipwhitelist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/tlsclientcertauth"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -113,6 +114,13 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// TLSClientCertAuth
+	if config.TLSClientCertAuth != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return tlsclientcertauth.New(ctx, next, *config.TLSClientCertAuth, middlewareName)
+		}
+	}
+
 	if middleware == nil {
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
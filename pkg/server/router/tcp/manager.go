@@ -15,6 +15,7 @@ import (
 	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	tcpservice "github.com/traefik/traefik/v3/pkg/server/service/tcp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -34,6 +35,7 @@ type Manager struct {
 	httpHandlers       map[string]http.Handler
 	httpsHandlers      map[string]http.Handler
 	tlsManager         *traefiktls.Manager
+	observabilityMgr   *middleware.ObservabilityMgr
 	conf               *runtime.Configuration
 }
 
@@ -44,6 +46,7 @@ func NewManager(conf *runtime.Configuration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
+	observabilityMgr *middleware.ObservabilityMgr,
 ) *Manager {
 	return &Manager{
 		serviceManager:     serviceManager,
@@ -51,6 +54,7 @@ func NewManager(conf *runtime.Configuration,
 		httpHandlers:       httpHandlers,
 		httpsHandlers:      httpsHandlers,
 		tlsManager:         tlsManager,
+		observabilityMgr:   observabilityMgr,
 		conf:               conf,
 	}
 }
@@ -67,7 +71,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 		logger := log.Ctx(rootCtx).With().Str(logs.EntryPointName, entryPointName).Logger()
 		ctx := logger.WithContext(rootCtx)
 
-		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
+		handler, err := m.buildEntryPointHandler(ctx, entryPointName, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
 		if err != nil {
 			logger.Error().Err(err).Send()
 			continue
@@ -98,7 +102,7 @@ type nameAndConfig struct {
 	TLSConfig  *tls.Config
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler) (*Router, error) {
+func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName string, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler) (*Router, error) {
 	// Build a new Router.
 	router, err := NewRouter()
 	if err != nil {
@@ -106,6 +110,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	}
 
 	router.SetHTTPHandler(handlerHTTP)
+	router.SetECHKeys(m.tlsManager.GetECHKeys)
 
 	// Even though the error is seemingly ignored (aside from logging it),
 	// we actually rely later on the fact that a tls config is nil (which happens when an error is returned) to take special steps
@@ -255,13 +260,13 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		router.AddHTTPTLSConfig(hostSNI, defaultTLSConf)
 	}
 
-	m.addTCPHandlers(ctx, configs, router)
+	m.addTCPHandlers(ctx, entryPointName, configs, router)
 
 	return router, nil
 }
 
 // addTCPHandlers creates the TCP handlers defined in configs, and adds them to router.
-func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, router *Router) {
+func (m *Manager) addTCPHandlers(ctx context.Context, entryPointName string, configs map[string]*runtime.TCPRouterInfo, router *Router) {
 	for routerName, routerConfig := range configs {
 		logger := log.Ctx(ctx).With().Str(logs.RouterName, routerName).Logger()
 		ctxRouter := logger.WithContext(provider.AddInContext(ctx, routerName))
@@ -310,7 +315,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 
 		var handler tcp.Handler
 		if routerConfig.TLS == nil || routerConfig.TLS.Passthrough {
-			handler, err = m.buildTCPHandler(ctxRouter, routerConfig)
+			handler, err = m.buildTCPHandler(ctxRouter, entryPointName, routerName, routerConfig)
 			if err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error().Err(err).Send()
@@ -385,7 +390,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 		// This seems to be the case so far with the existing matchers (HostSNI, and ClientIP), so it's all good.
 		// Otherwise, we would have to do as for HTTPS, i.e. disallow different TLS configs for the same HostSNIs.
 
-		handler, err = m.buildTCPHandler(ctxRouter, routerConfig)
+		handler, err = m.buildTCPHandler(ctxRouter, entryPointName, routerName, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error().Err(err).Send()
@@ -407,7 +412,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 	}
 }
 
-func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
+func (m *Manager) buildTCPHandler(ctx context.Context, entryPointName, routerName string, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
 	var qualifiedNames []string
 	for _, name := range router.Middlewares {
 		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
@@ -425,5 +430,15 @@ func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouter
 
 	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
 
-	return tcp.NewChain().Extend(*mHandler).Then(sHandler)
+	handler, err := tcp.NewChain().Extend(*mHandler).Then(sHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	// The access logger is wrapped by the TLS handler when TLS is terminated,
+	// so that the TLS connection state is available when the connection is logged.
+	internal := strings.HasSuffix(routerName, "@internal")
+	serviceName := provider.GetQualifiedName(ctx, router.Service)
+
+	return m.observabilityMgr.WrapTCPHandler(entryPointName, routerName, serviceName, internal, handler), nil
 }
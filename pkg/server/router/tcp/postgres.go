@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net"
 	"sync"
 	"time"
 
@@ -48,7 +49,7 @@ func (r *Router) servePostgres(conn *peekConn) error {
 		return fmt.Errorf("reading PostgresStartTLSMsg: %w", err)
 	}
 
-	hello, err := clientHelloInfo(conn)
+	hello, err := clientHelloInfo(conn, r.echKeys)
 	if err != nil {
 		return fmt.Errorf("reading clientHello: %w", err)
 	}
@@ -101,6 +102,11 @@ type postgresConn struct {
 	errChan   chan error
 }
 
+// NetConn returns the wrapped connection.
+func (c *postgresConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
 // Read reads bytes from the underlying connection (tcp.WriteCloser).
 // On first call, it actually only injects the PostgresStartTLSMsg,
 // in order to behave as a Postgres TLS client that initiates a STARTTLS handshake.
@@ -48,6 +48,10 @@ type Router struct {
 	// hostHTTPTLSConfig contains TLS configs keyed by SNI.
 	// A nil config is the hint to set up a brokenTLSRouter.
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+
+	// echKeys returns the ECH keys used to decrypt the ClientHello messages,
+	// so that the connections are routed on the inner SNI.
+	echKeys func() []tls.EncryptedClientHelloKey
 }
 
 // NewRouter returns a new TCP router.
@@ -142,7 +146,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		return
 	}
 
-	hello, err := clientHelloInfo(pConn)
+	hello, err := clientHelloInfo(pConn, r.echKeys)
 	if err != nil {
 		var opErr *net.OpError
 		if !errors.Is(err, io.EOF) && (!errors.As(err, &opErr) || !opErr.Timeout()) {
@@ -152,6 +156,8 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		return
 	}
 
+	pConn.serverName = hello.serverName
+
 	// The deadline was set to avoid blocking on the initial read of the ClientHello,
 	// but now that we have it, we can remove it,
 	// and delegate this to underlying TCP server (for now only handled by HTTP Server).
@@ -301,6 +307,11 @@ func (r *Router) SetHTTPSHandler(handler http.Handler, config *tls.Config) {
 	r.httpsTLSConfig = config
 }
 
+// SetECHKeys sets the function returning the ECH keys used to decrypt the ClientHello messages before routing.
+func (r *Router) SetECHKeys(echKeys func() []tls.EncryptedClientHelloKey) {
+	r.echKeys = echKeys
+}
+
 func (r *Router) EnableACMETLSPassthrough() {
 	r.acmeTLSPassthrough = true
 }
@@ -344,6 +355,9 @@ type peekConn struct {
 
 	peeked []byte
 	reader *bufio.Reader
+
+	// serverName is the SNI read from the ClientHello, if any.
+	serverName string
 }
 
 func newPeekConn(conn tcp.WriteCloser) *peekConn {
@@ -353,6 +367,16 @@ func newPeekConn(conn tcp.WriteCloser) *peekConn {
 	}
 }
 
+// ServerName returns the server name (SNI) sent by the client in its ClientHello, if any.
+func (c *peekConn) ServerName() string {
+	return c.serverName
+}
+
+// NetConn returns the wrapped connection.
+func (c *peekConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
 // Peek allows peeking into the connection without consuming bytes, by using the bufio.Reader's Peek method.
 func (c *peekConn) Peek(n int) ([]byte, error) {
 	return c.reader.Peek(n)
@@ -390,8 +414,9 @@ type clientHello struct {
 
 // clientHelloInfo returns various data from the clientHello handshake,
 // without consuming any bytes from conn.
+// When the ClientHello is encrypted with one of the given ECH keys, the data is the one of the inner ClientHello.
 // It returns an error if it can't peek the first byte from the connection.
-func clientHelloInfo(conn *peekConn) (*clientHello, error) {
+func clientHelloInfo(conn *peekConn, echKeys func() []tls.EncryptedClientHelloKey) (*clientHello, error) {
 	hdr, err := conn.Peek(1)
 	if err != nil {
 		return nil, fmt.Errorf("peeking first byte: %w", err)
@@ -416,14 +441,24 @@ func clientHelloInfo(conn *peekConn) (*clientHello, error) {
 		sni    string
 		protos []string
 	)
-	server := tls.Server(readOnlyConn{conn: conn}, &tls.Config{
+	config := &tls.Config{
 		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
 			sni = hello.ServerName
 			protos = hello.SupportedProtos
 			// This error prevents unnecessary additional steps in the TLS ClientHello message processing.
 			return nil, errClientHelloRead
 		},
-	})
+	}
+
+	if echKeys != nil {
+		// The ECH decryption happens before the call to GetConfigForClient,
+		// which therefore gets the inner ClientHello when the decryption succeeds.
+		config.GetEncryptedClientHelloKeys = func(_ *tls.ClientHelloInfo) ([]tls.EncryptedClientHelloKey, error) {
+			return echKeys(), nil
+		}
+	}
+
+	server := tls.Server(readOnlyConn{conn: conn}, config)
 
 	if handshakeErr := server.Handshake(); !errors.Is(handshakeErr, errClientHelloRead) {
 		return nil, fmt.Errorf("reading client hello: %w", handshakeErr)
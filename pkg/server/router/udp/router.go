@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
+	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	udpservice "github.com/traefik/traefik/v3/pkg/server/service/udp"
 	"github.com/traefik/traefik/v3/pkg/udp"
@@ -15,17 +17,20 @@ import (
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *udpservice.Manager
-	conf           *runtime.Configuration
+	serviceManager   *udpservice.Manager
+	observabilityMgr *middleware.ObservabilityMgr
+	conf             *runtime.Configuration
 }
 
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *udpservice.Manager,
+	observabilityMgr *middleware.ObservabilityMgr,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		conf:           conf,
+		serviceManager:   serviceManager,
+		observabilityMgr: observabilityMgr,
+		conf:             conf,
 	}
 }
 
@@ -44,7 +49,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 			logger.Warn().Msg("Config has more than one udp router for a given entrypoint.")
 		}
 
-		handlers := m.buildEntryPointHandlers(ctx, routers)
+		handlers := m.buildEntryPointHandlers(ctx, entryPointName, routers)
 
 		if len(handlers) > 0 {
 			// As UDP support only one router per entrypoint, we only take the first one.
@@ -62,7 +67,7 @@ func (m *Manager) getUDPRouters(ctx context.Context, entryPoints []string) map[s
 	return make(map[string]map[string]*runtime.UDPRouterInfo)
 }
 
-func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) []udp.Handler {
+func (m *Manager) buildEntryPointHandlers(ctx context.Context, entryPointName string, configs map[string]*runtime.UDPRouterInfo) []udp.Handler {
 	var rtNames []string
 	for routerName := range configs {
 		rtNames = append(rtNames, routerName)
@@ -93,6 +98,10 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
+		internal := strings.HasSuffix(routerName, "@internal")
+		serviceName := provider.GetQualifiedName(ctxRouter, routerConfig.Service)
+		handler = m.observabilityMgr.WrapUDPHandler(entryPointName, routerName, serviceName, internal, handler)
+
 		handlers = append(handlers, handler)
 	}
 
@@ -115,7 +115,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				UDPRouters:  test.routerConfig,
 			}
 			serviceManager := udp.NewManager(conf)
-			routerManager := NewManager(conf, serviceManager)
+			routerManager := NewManager(conf, serviceManager, nil)
 
 			_ = routerManager.BuildHandlers(t.Context(), entryPoints)
 
@@ -3,7 +3,9 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
@@ -37,6 +39,11 @@ type RouterFactory struct {
 
 	dialerManager *tcp.DialerManager
 
+	tenancy *Tenancy
+
+	// logger, if set, is the logger of the routers creation.
+	logger *zerolog.Logger
+
 	cancelPrevState func()
 
 	parser httpmuxer.SyntaxParser
@@ -86,10 +93,19 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 		pluginBuilder:    pluginBuilder,
 		dialerManager:    dialerManager,
 		allowACMEByPass:  allowACMEByPass,
+		tenancy:          NewTenancy(staticConfiguration.Tenants),
 		parser:           parser,
 	}, nil
 }
 
+// DisableLogs disables the logs of the routers creation,
+// e.g. when the routers are only built to check the resulting runtime configuration.
+func (f *RouterFactory) DisableLogs() {
+	// A disabled logger is not stored in the context, in favor of the default one.
+	logger := zerolog.New(io.Discard).Level(zerolog.PanicLevel)
+	f.logger = &logger
+}
+
 // CreateRouters creates new TCPRouters and UDPRouters.
 func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string]*tcprouter.Router, map[string]udp.Handler) {
 	if f.cancelPrevState != nil {
@@ -98,6 +114,12 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 
 	var ctx context.Context
 	ctx, f.cancelPrevState = context.WithCancel(context.Background())
+	if f.logger != nil {
+		ctx = f.logger.WithContext(ctx)
+	}
+
+	// The routers denied by the tenancy are not built.
+	restoreDeniedRouters := f.tenancy.Deny(ctx, rtConf)
 
 	// HTTP
 	serviceManager := f.managerFactory.Build(rtConf)
@@ -120,7 +142,7 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 
 	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares)
 
-	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
+	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager, f.observabilityMgr)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
 	for ep, r := range routersTCP {
@@ -133,9 +155,11 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 
 	// UDP
 	svcUDPManager := udpsvc.NewManager(rtConf)
-	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager)
+	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager, f.observabilityMgr)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
+	restoreDeniedRouters()
+
 	rtConf.PopulateUsedBy()
 
 	return routersTCP, routersUDP
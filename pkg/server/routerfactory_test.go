@@ -12,6 +12,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	"github.com/traefik/traefik/v3/pkg/server/service"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -54,7 +55,7 @@ func TestReuseService(t *testing.T) {
 	transportManager := service.NewTransportManager(nil)
 	transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 
-	managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, proxyBuilderMock{}, nil)
+	managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, proxyBuilderMock{}, nil, nil, nil, nil)
 	tlsManager := tls.NewManager(nil)
 
 	dialerManager := tcp.NewDialerManager(nil)
@@ -180,7 +181,7 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 			transportManager := service.NewTransportManager(nil)
 			transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 
-			managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, proxyBuilderMock{}, nil)
+			managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, proxyBuilderMock{}, nil, nil, nil, nil)
 			tlsManager := tls.NewManager(nil)
 
 			dialerManager := tcp.NewDialerManager(nil)
@@ -226,7 +227,7 @@ func TestInternalServices(t *testing.T) {
 	transportManager := service.NewTransportManager(nil)
 	transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 
-	managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, nil, nil)
+	managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, nil, nil, nil, nil, nil)
 	tlsManager := tls.NewManager(nil)
 
 	dialerManager := tcp.NewDialerManager(nil)
@@ -275,7 +276,7 @@ func TestRecursionService(t *testing.T) {
 	transportManager := service.NewTransportManager(nil)
 	transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 
-	managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, nil, nil)
+	managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, nil, nil, nil, nil, nil)
 	tlsManager := tls.NewManager(nil)
 
 	dialerManager := tcp.NewDialerManager(nil)
@@ -308,3 +309,74 @@ func (p proxyBuilderMock) Build(_ string, _ *url.URL, _, _ bool, _ time.Duration
 func (p proxyBuilderMock) Update(_ map[string]*dynamic.ServersTransport) {
 	panic("implement me")
 }
+
+func TestTenancy(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	staticConfig := static.Configuration{
+		EntryPoints: map[string]*static.EntryPoint{
+			"web": {},
+		},
+		Tenants: map[string]*static.Tenant{
+			"team-a": {
+				Providers: []string{"provider1"},
+				Hosts:     []string{"a.example.com"},
+			},
+		},
+	}
+
+	dynamicConfigs := th.BuildConfiguration(
+		th.WithRouters(
+			th.WithRouter("foo@provider1",
+				th.WithEntryPoints("web"),
+				th.WithServiceName("bar"),
+				th.WithRule("Host(`a.example.com`)")),
+			th.WithRouter("hijack@provider1",
+				th.WithEntryPoints("web"),
+				th.WithServiceName("bar"),
+				th.WithRule("Host(`b.example.com`)")),
+		),
+		th.WithServices(
+			th.WithService("bar@provider1", th.WithServiceServersLoadBalancer(th.WithServers(th.WithServer(testServer.URL)))),
+		),
+	)
+
+	transportManager := service.NewTransportManager(nil)
+	transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+
+	managerFactory := service.NewManagerFactory(staticConfig, nil, nil, transportManager, proxyBuilderMock{}, nil, nil, nil, nil)
+	tlsManager := tls.NewManager(nil)
+
+	dialerManager := tcp.NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"default@internal": {}})
+	factory, err := NewRouterFactory(staticConfig, managerFactory, tlsManager, nil, nil, dialerManager)
+	require.NoError(t, err)
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{HTTP: dynamicConfigs})
+	entryPointsHandlers, _ := factory.CreateRouters(rtConf)
+
+	reqHost := requestdecorator.New(nil)
+
+	// Test that the router of the tenant host returns a status 200.
+	responseRecorderOk := &httptest.ResponseRecorder{}
+	requestOk := httptest.NewRequest(http.MethodGet, "http://a.example.com/", nil)
+	reqHost.ServeHTTP(responseRecorderOk, requestOk, entryPointsHandlers["web"].GetHTTPHandler().ServeHTTP)
+
+	assert.Equal(t, http.StatusOK, responseRecorderOk.Result().StatusCode, "status code")
+
+	// Test that the router of another host is denied, and returns a status 404.
+	responseRecorderDenied := &httptest.ResponseRecorder{}
+	requestDenied := httptest.NewRequest(http.MethodGet, "http://b.example.com/", nil)
+	reqHost.ServeHTTP(responseRecorderDenied, requestDenied, entryPointsHandlers["web"].GetHTTPHandler().ServeHTTP)
+
+	assert.Equal(t, http.StatusNotFound, responseRecorderDenied.Result().StatusCode, "status code")
+
+	require.NotNil(t, rtConf.Routers["hijack@provider1"])
+	assert.Equal(t, runtime.StatusDisabled, rtConf.Routers["hijack@provider1"].Status)
+	assert.Contains(t, rtConf.Routers["hijack@provider1"].Err, "rule \"Host(`b.example.com`)\" is not restricted to the hosts of the tenant")
+	assert.Empty(t, rtConf.Routers["foo@provider1"].Err)
+	assert.Contains(t, rtConf.Services["bar@provider1"].UsedBy, "hijack@provider1")
+}
@@ -40,6 +40,7 @@ type key string
 
 const (
 	connStateKey       key    = "connState"
+	proxyProtocolTLVs  key    = "proxyProtocolTLVs"
 	debugConnectionEnv string = "DEBUG_CONNECTION"
 )
 
@@ -396,6 +397,11 @@ func (c *writeCloserWrapper) CloseWrite() error {
 	return c.writeCloser.CloseWrite()
 }
 
+// NetConn returns the wrapped connection.
+func (c *writeCloserWrapper) NetConn() net.Conn {
+	return c.Conn
+}
+
 // writeCloser returns the given connection, augmented with the WriteCloser
 // implementation, if any was found within the underlying conn.
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
@@ -677,6 +683,18 @@ func newHTTPServer(ctx context.Context, ln net.Listener, configuration *static.E
 
 	handler = denyFragment(handler)
 
+	var tlvHeaders map[string]string
+	if configuration.ProxyProtocol != nil && len(configuration.ProxyProtocol.TLVHeaders) > 0 {
+		tlvHeaders = configuration.ProxyProtocol.TLVHeaders
+		for name := range tlvHeaders {
+			if err := tcp.ValidateProxyProtocolTLVName(name); err != nil {
+				return nil, fmt.Errorf("invalid proxyProtocol tlvHeaders: %w", err)
+			}
+		}
+
+		handler = proxyProtocolTLVHeaders(handler, tlvHeaders)
+	}
+
 	serverHTTP := &http.Server{
 		Protocols:      &protocols,
 		Handler:        handler,
@@ -717,6 +735,9 @@ func newHTTPServer(ctx context.Context, ln net.Listener, configuration *static.E
 	serverHTTP.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
 		// This adds an empty struct in order to store a RoundTripper in the ConnContext in case of Kerberos or NTLM.
 		ctx = service.AddTransportOnContext(ctx)
+		if len(tlvHeaders) > 0 {
+			ctx = context.WithValue(ctx, proxyProtocolTLVs, tcp.ProxyProtocolTLVs(c))
+		}
 		if prevConnContext != nil {
 			return prevConnContext(ctx, c)
 		}
@@ -760,6 +781,29 @@ func (t *trackedConnection) Close() error {
 	return t.WriteCloser.Close()
 }
 
+// NetConn returns the wrapped connection.
+func (t *trackedConnection) NetConn() net.Conn {
+	return t.WriteCloser
+}
+
+// proxyProtocolTLVHeaders sets the configured request headers from the TLVs of the PROXY protocol v2 header.
+// The headers are always removed first, so that a client cannot forge them.
+func proxyProtocolTLVHeaders(h http.Handler, tlvHeaders map[string]string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		tlvs, _ := req.Context().Value(proxyProtocolTLVs).([]proxyproto.TLV)
+
+		for name, header := range tlvHeaders {
+			req.Header.Del(header)
+
+			if value, ok := tcp.LookupProxyProtocolTLV(tlvs, name); ok {
+				req.Header.Set(header, value)
+			}
+		}
+
+		h.ServeHTTP(rw, req)
+	})
+}
+
 // denyFragment rejects the request if the URL path contains a fragment (hash character).
 // When go receives an HTTP request, it assumes the absence of fragment URL.
 // However, it is still possible to send a fragment in the request.
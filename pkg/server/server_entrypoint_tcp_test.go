@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
@@ -423,6 +424,34 @@ func Test_denyFragment(t *testing.T) {
 	}
 }
 
+func Test_proxyProtocolTLVHeaders(t *testing.T) {
+	handler := proxyProtocolTLVHeaders(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "vpce-123", req.Header.Get("X-Vpce-Id"))
+		assert.Empty(t, req.Header.Values("X-Authority"))
+
+		rw.WriteHeader(http.StatusOK)
+	}), map[string]string{
+		tcp.TLVAWSVPCEndpointID: "X-Vpce-Id",
+		tcp.TLVAuthority:        "X-Authority",
+	})
+
+	tlvs := []proxyproto.TLV{
+		// AWS VPC endpoint ID TLV: the 0x01 subtype followed by the ID.
+		{Type: 0xEA, Value: append([]byte{0x01}, "vpce-123"...)},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), proxyProtocolTLVs, tlvs))
+	// Headers sent by the client are not trusted.
+	req.Header.Set("X-Vpce-Id", "forged")
+	req.Header.Set("X-Authority", "forged")
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
 func TestSanitizePath(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"golang.org/x/net/http/httpguts"
+)
+
+// quicBrokenDuration is the duration during which requests are sent over TCP to a server that could not be reached with QUIC.
+const quicBrokenDuration = 5 * time.Minute
+
+// quicDialError is returned when a QUIC connection cannot be established with a server.
+type quicDialError struct {
+	err error
+}
+
+func (e *quicDialError) Error() string {
+	return fmt.Sprintf("dialing QUIC connection: %v", e.err)
+}
+
+func (e *quicDialError) Unwrap() error {
+	return e.err
+}
+
+// h3RoundTripper sends the requests to the servers over HTTP/3,
+// and falls back to HTTP/1.1 or HTTP/2 over TCP when a server cannot be reached with QUIC.
+type h3RoundTripper struct {
+	http3 *http3.Transport
+
+	// tcp is the round tripper used for the requests which cannot be sent over HTTP/3,
+	// and for the fallback when it is enabled.
+	tcp      http.RoundTripper
+	fallback bool
+
+	brokenMu sync.Mutex
+	broken   map[string]time.Time
+}
+
+// newHTTP3Transport creates the HTTP/3 transport of the given ServersTransport, on which HTTP/3 is enabled.
+// It must be closed when the ServersTransport is updated or removed, to close its QUIC connections.
+func newHTTP3Transport(cfg *dynamic.ServersTransport, tlsConfig *tls.Config) *http3.Transport {
+	quicConfig := &quic.Config{}
+	if cfg.ForwardingTimeouts != nil {
+		quicConfig.HandshakeIdleTimeout = time.Duration(cfg.ForwardingTimeouts.DialTimeout)
+		quicConfig.MaxIdleTimeout = time.Duration(cfg.ForwardingTimeouts.IdleConnTimeout)
+	}
+
+	return &http3.Transport{
+		TLSClientConfig: tlsConfig,
+		QUICConfig:      quicConfig,
+		Dial: func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+			conn, err := quic.DialAddrEarly(ctx, addr, tlsCfg, cfg)
+			if err != nil {
+				return nil, &quicDialError{err: err}
+			}
+			return conn, nil
+		},
+	}
+}
+
+func newH3RoundTripper(cfg *dynamic.ServersTransport, h3Transport *http3.Transport, tcp http.RoundTripper) *h3RoundTripper {
+	return &h3RoundTripper{
+		http3:    h3Transport,
+		tcp:      tcp,
+		fallback: !cfg.HTTP3.DisableFallback,
+		broken:   make(map[string]time.Time),
+	}
+}
+
+func (r *h3RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Only the requests to servers using the h3 or https scheme go through QUIC.
+	if req.URL.Scheme != "h3" && req.URL.Scheme != "https" {
+		return r.tcp.RoundTrip(req)
+	}
+
+	// The request is cloned, as the caller keeps its ownership.
+	if req.URL.Scheme == "h3" {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = "https"
+	}
+
+	// Protocols that start with a Connection Upgrade, such as Websocket, are not supported over HTTP/3.
+	if httpguts.HeaderValuesContainsToken(req.Header["Connection"], "Upgrade") {
+		return r.tcp.RoundTrip(req)
+	}
+
+	if r.fallback && r.isBroken(req.URL.Host) {
+		return r.tcp.RoundTrip(req)
+	}
+
+	// When the connection cannot be established, the request is not sent,
+	// but the HTTP/3 transport closes the request body, which cannot be sent again over TCP.
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	resp, err := r.http3.RoundTrip(req)
+
+	var dialErr *quicDialError
+	if !r.fallback || !errors.As(err, &dialErr) {
+		return resp, err
+	}
+
+	log.Ctx(req.Context()).Debug().Err(err).Msgf("Unable to reach %s with QUIC, falling back to TCP", req.URL.Host)
+
+	r.markBroken(req.URL.Host)
+
+	if !replayable {
+		return nil, err
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	return r.tcp.RoundTrip(req)
+}
+
+func (r *h3RoundTripper) isBroken(host string) bool {
+	r.brokenMu.Lock()
+	defer r.brokenMu.Unlock()
+
+	until, ok := r.broken[host]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(until) {
+		delete(r.broken, host)
+		return false
+	}
+
+	return true
+}
+
+func (r *h3RoundTripper) markBroken(host string) {
+	r.brokenMu.Lock()
+	defer r.brokenMu.Unlock()
+
+	r.broken[host] = time.Now().Add(quicBrokenDuration)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestHTTP3(t *testing.T) {
+	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
+	require.NoError(t, err)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &http3.Server{
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		}),
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}}),
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+
+	go func() { _ = srv.Serve(conn) }()
+
+	testCases := []struct {
+		desc      string
+		scheme    string
+		transport *dynamic.ServersTransport
+	}{
+		{
+			desc:   "h3 scheme",
+			scheme: "h3",
+			transport: &dynamic.ServersTransport{
+				InsecureSkipVerify: true,
+				HTTP3:              &dynamic.ServersTransportHTTP3{},
+			},
+		},
+		{
+			desc:   "https scheme with HTTP/3 enabled",
+			scheme: "https",
+			transport: &dynamic.ServersTransport{
+				InsecureSkipVerify: true,
+				HTTP3:              &dynamic.ServersTransportHTTP3{},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			transportManager := NewTransportManager(nil)
+			transportManager.Update(map[string]*dynamic.ServersTransport{"test": test.transport})
+
+			tr, err := transportManager.GetRoundTripper("test")
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, test.scheme+"://"+conn.LocalAddr().String(), http.NoBody)
+			require.NoError(t, err)
+
+			resp, err := tr.RoundTrip(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "HTTP/3.0", resp.Proto)
+
+			// The request of the caller is not modified.
+			assert.Equal(t, test.scheme, req.URL.Scheme)
+
+			// The round tripper dedicated to the connections authenticated with Kerberos or NTLM also uses HTTP/3.
+			sticky := tr.(*kerberosRoundTripper).new()
+
+			resp, err = sticky.RoundTrip(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "HTTP/3.0", resp.Proto)
+		})
+	}
+}
+
+func TestHTTP3_closedOnUpdate(t *testing.T) {
+	transportManager := NewTransportManager(nil)
+	transportManager.Update(map[string]*dynamic.ServersTransport{
+		"test":  {HTTP3: &dynamic.ServersTransportHTTP3{}},
+		"other": {},
+	})
+
+	h3Transport := transportManager.http3Transports["test"]
+	require.NotNil(t, h3Transport)
+
+	transportManager.Update(map[string]*dynamic.ServersTransport{
+		"test":  {HTTP3: &dynamic.ServersTransportHTTP3{DisableFallback: true}},
+		"other": {},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "https://127.0.0.1", http.NoBody)
+
+	_, err := h3Transport.RoundTrip(req)
+	assert.ErrorIs(t, err, http3.ErrTransportClosed)
+
+	h3Transport = transportManager.http3Transports["test"]
+	require.NotNil(t, h3Transport)
+
+	transportManager.Update(map[string]*dynamic.ServersTransport{"other": {}})
+
+	_, err = h3Transport.RoundTrip(req)
+	assert.ErrorIs(t, err, http3.ErrTransportClosed)
+
+	assert.NotContains(t, transportManager.http3Transports, "test")
+	assert.Nil(t, transportManager.http3Transports["other"])
+}
+
+func TestHTTP3_fallback(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		http3         *dynamic.ServersTransportHTTP3
+		expectedError bool
+	}{
+		{
+			desc:  "fallback to TCP",
+			http3: &dynamic.ServersTransportHTTP3{},
+		},
+		{
+			desc:          "fallback disabled",
+			http3:         &dynamic.ServersTransportHTTP3{DisableFallback: true},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The server only listens on TCP, thus it cannot be reached with QUIC.
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			}))
+			srv.EnableHTTP2 = true
+			srv.StartTLS()
+			t.Cleanup(srv.Close)
+
+			transportManager := NewTransportManager(nil)
+			transportManager.Update(map[string]*dynamic.ServersTransport{
+				"test": {
+					InsecureSkipVerify: true,
+					ForwardingTimeouts: &dynamic.ForwardingTimeouts{DialTimeout: ptypes.Duration(500 * time.Millisecond)},
+					HTTP3:              test.http3,
+				},
+			})
+
+			tr, err := transportManager.GetRoundTripper("test")
+			require.NoError(t, err)
+
+			client := http.Client{Transport: tr}
+
+			srvURL, err := url.Parse(srv.URL)
+			require.NoError(t, err)
+			srvURL.Scheme = "h3"
+
+			// The second request directly goes through TCP, as the server is known to be unreachable with QUIC.
+			for range 2 {
+				resp, err := client.Get(srvURL.String())
+				if test.expectedError {
+					require.Error(t, err)
+					continue
+				}
+				require.NoError(t, err)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "HTTP/2.0", resp.Proto)
+			}
+		})
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/api"
 	"github.com/traefik/traefik/v3/pkg/api/dashboard"
+	"github.com/traefik/traefik/v3/pkg/config/export"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
 	"github.com/traefik/traefik/v3/pkg/observability/metrics"
 	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/server/history"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
 )
 
 // ManagerFactory a factory of service manager.
@@ -23,6 +26,7 @@ type ManagerFactory struct {
 
 	api              func(configuration *runtime.Configuration) http.Handler
 	restHandler      http.Handler
+	gitHandler       http.Handler
 	dashboardHandler http.Handler
 	metricsHandler   http.Handler
 	pingHandler      http.Handler
@@ -32,7 +36,7 @@ type ManagerFactory struct {
 }
 
 // NewManagerFactory creates a new ManagerFactory.
-func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, observabilityMgr *middleware.ObservabilityMgr, transportManager *TransportManager, proxyBuilder ProxyBuilder, acmeHTTPHandler http.Handler) *ManagerFactory {
+func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, observabilityMgr *middleware.ObservabilityMgr, transportManager *TransportManager, proxyBuilder ProxyBuilder, acmeHTTPHandler http.Handler, tlsManager *traefiktls.Manager, configurationHistory *history.History, configurationSnapshot *export.Snapshot) *ManagerFactory {
 	factory := &ManagerFactory{
 		observabilityMgr: observabilityMgr,
 		routinesPool:     routinesPool,
@@ -42,7 +46,7 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 	}
 
 	if staticConfiguration.API != nil {
-		apiRouterBuilder := api.NewBuilder(staticConfiguration)
+		apiRouterBuilder := api.NewBuilder(staticConfiguration, tlsManager, configurationHistory, configurationSnapshot)
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = dashboard.Handler{BasePath: staticConfiguration.API.BasePath}
@@ -63,6 +67,10 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 		factory.restHandler = staticConfiguration.Providers.Rest.CreateRouter()
 	}
 
+	if staticConfiguration.Providers != nil && staticConfiguration.Providers.Git != nil && staticConfiguration.Providers.Git.Webhook != nil {
+		factory.gitHandler = staticConfiguration.Providers.Git.CreateRouter()
+	}
+
 	if staticConfiguration.Metrics != nil && staticConfiguration.Metrics.Prometheus != nil {
 		factory.metricsHandler = metrics.PrometheusHandler()
 	}
@@ -84,6 +92,6 @@ func (f *ManagerFactory) Build(configuration *runtime.Configuration) *Manager {
 		apiHandler = f.api(configuration)
 	}
 
-	internalHandlers := NewInternalHandlers(apiHandler, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, f.acmeHTTPHandler)
+	internalHandlers := NewInternalHandlers(apiHandler, f.restHandler, f.gitHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, f.acmeHTTPHandler)
 	return NewManager(configuration.Services, f.observabilityMgr, f.routinesPool, f.transportManager, f.proxyBuilder, internalHandlers)
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/retry"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
 	"github.com/traefik/traefik/v3/pkg/proxy/httputil"
+	"github.com/traefik/traefik/v3/pkg/proxy/websocket"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/cookie"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
@@ -123,7 +124,7 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 	value := reflect.ValueOf(*conf.Service)
 	var count int
 	for i := range value.NumField() {
-		if value.Type().Field(i).Name != "Middlewares" && !value.Field(i).IsNil() {
+		if name := value.Type().Field(i).Name; name != "Middlewares" && name != "Labels" && !value.Field(i).IsNil() {
 			count++
 		}
 	}
@@ -478,6 +479,10 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 			return nil, fmt.Errorf("error building proxy for server URL %s: %w", server.URL, err)
 		}
 
+		// The WebSocket connections are observed directly on the proxy,
+		// which is the one hijacking the client connection to switch protocols.
+		proxy = websocket.WrapHandler(proxy, qualifiedSvcName, service.WebSocket, m.observabilityMgr.MetricsRegistry())
+
 		if passiveHealthChecker != nil {
 			// If passive health check is enabled, we wrap the proxy with the passive health checker.
 			proxy = passiveHealthChecker.WrapHandler(ctx, proxy, target.String())
@@ -62,7 +62,7 @@ type smartRoundTripper struct {
 	http  *http.Transport
 }
 
-func (m *smartRoundTripper) Clone() http.RoundTripper {
+func (m *smartRoundTripper) Clone() *smartRoundTripper {
 	h := m.http.Clone()
 	h2 := m.http2.Clone()
 	return &smartRoundTripper{http: h, http2: h2}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/healthcheck"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
@@ -48,7 +49,18 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		return nil, fmt.Errorf("the service %q does not exist", serviceQualifiedName)
 	}
 
-	if conf.LoadBalancer != nil && conf.Weighted != nil {
+	var count int
+	if conf.LoadBalancer != nil {
+		count++
+	}
+	if conf.Weighted != nil {
+		count++
+	}
+	if conf.Mirroring != nil {
+		count++
+	}
+
+	if count > 1 {
 		err := errors.New("cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 		conf.AddError(err, true)
 		return nil, err
@@ -152,6 +164,31 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 
 		return loadBalancer, nil
 
+	case conf.Mirroring != nil:
+		handler, err := m.BuildTCP(ctx, conf.Mirroring.Service)
+		if err != nil {
+			return nil, err
+		}
+
+		maxBufferSize := dynamic.TCPMirroringDefaultMaxBufferSize
+		if conf.Mirroring.MaxBufferSize != nil {
+			maxBufferSize = *conf.Mirroring.MaxBufferSize
+		}
+
+		mirroring := tcp.NewMirroring(handler, maxBufferSize, conf.Mirroring.HealthCheck != nil)
+		for _, mirror := range conf.Mirroring.Mirrors {
+			mirrorHandler, err := m.BuildTCP(ctx, mirror.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := mirroring.AddMirror(mirrorHandler, mirror.Percent); err != nil {
+				return nil, err
+			}
+		}
+
+		return mirroring, nil
+
 	default:
 		err := fmt.Errorf("the service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
@@ -276,6 +276,78 @@ func TestManager_BuildTCP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "Mirroring",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "foobar@provider-1",
+							Mirrors: []dynamic.TCPMirrorService{
+								{Name: "foobar2@provider-1", Percent: 50},
+							},
+						},
+					},
+				},
+				"foobar@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.12:80"}},
+						},
+					},
+				},
+				"foobar2@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.13:80"}},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "Mirroring with invalid percent",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "foobar@provider-1",
+							Mirrors: []dynamic.TCPMirrorService{
+								{Name: "foobar@provider-1", Percent: 101},
+							},
+						},
+					},
+				},
+				"foobar@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.12:80"}},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "percent must be between 0 and 100",
+		},
+		{
+			desc:        "Mirroring and load balancer",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{},
+						Mirroring:    &dynamic.TCPMirroring{Service: "foobar@provider-1"},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead",
+		},
 	}
 
 	for _, test := range testCases {
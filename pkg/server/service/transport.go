@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/quic-go/quic-go/http3"
 	"github.com/rs/zerolog/log"
 	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
@@ -32,10 +33,11 @@ type SpiffeX509Source interface {
 
 // TransportManager handles transports for backend communication.
 type TransportManager struct {
-	rtLock        sync.RWMutex
-	roundTrippers map[string]http.RoundTripper
-	configs       map[string]*dynamic.ServersTransport
-	tlsConfigs    map[string]*tls.Config
+	rtLock          sync.RWMutex
+	roundTrippers   map[string]http.RoundTripper
+	http3Transports map[string]*http3.Transport
+	configs         map[string]*dynamic.ServersTransport
+	tlsConfigs      map[string]*tls.Config
 
 	spiffeX509Source SpiffeX509Source
 }
@@ -44,6 +46,7 @@ type TransportManager struct {
 func NewTransportManager(spiffeX509Source SpiffeX509Source) *TransportManager {
 	return &TransportManager{
 		roundTrippers:    make(map[string]http.RoundTripper),
+		http3Transports:  make(map[string]*http3.Transport),
 		configs:          make(map[string]*dynamic.ServersTransport),
 		tlsConfigs:       make(map[string]*tls.Config),
 		spiffeX509Source: spiffeX509Source,
@@ -58,6 +61,7 @@ func (t *TransportManager) Update(newConfigs map[string]*dynamic.ServersTranspor
 	for configName, config := range t.configs {
 		newConfig, ok := newConfigs[configName]
 		if !ok {
+			t.closeHTTP3Transport(configName)
 			delete(t.configs, configName)
 			delete(t.roundTrippers, configName)
 			delete(t.tlsConfigs, configName)
@@ -68,6 +72,8 @@ func (t *TransportManager) Update(newConfigs map[string]*dynamic.ServersTranspor
 			continue
 		}
 
+		t.closeHTTP3Transport(configName)
+
 		var err error
 
 		var tlsConfig *tls.Config
@@ -76,7 +82,7 @@ func (t *TransportManager) Update(newConfigs map[string]*dynamic.ServersTranspor
 		}
 		t.tlsConfigs[configName] = tlsConfig
 
-		t.roundTrippers[configName], err = t.createRoundTripper(newConfig, tlsConfig)
+		t.roundTrippers[configName], t.http3Transports[configName], err = t.createRoundTripper(newConfig, tlsConfig)
 		if err != nil {
 			log.Error().Err(err).Msgf("Could not configure HTTP Transport %s, fallback on default transport", configName)
 			t.roundTrippers[configName] = http.DefaultTransport
@@ -96,7 +102,7 @@ func (t *TransportManager) Update(newConfigs map[string]*dynamic.ServersTranspor
 		}
 		t.tlsConfigs[newConfigName] = tlsConfig
 
-		t.roundTrippers[newConfigName], err = t.createRoundTripper(newConfig, tlsConfig)
+		t.roundTrippers[newConfigName], t.http3Transports[newConfigName], err = t.createRoundTripper(newConfig, tlsConfig)
 		if err != nil {
 			log.Error().Err(err).Msgf("Could not configure HTTP Transport %s, fallback on default transport", newConfigName)
 			t.roundTrippers[newConfigName] = http.DefaultTransport
@@ -106,6 +112,24 @@ func (t *TransportManager) Update(newConfigs map[string]*dynamic.ServersTranspor
 	t.configs = newConfigs
 }
 
+// closeHTTP3Transport closes the HTTP/3 transport of the given ServersTransport, and thus its QUIC connections.
+func (t *TransportManager) closeHTTP3Transport(name string) {
+	h3Transport, ok := t.http3Transports[name]
+	if !ok {
+		return
+	}
+
+	delete(t.http3Transports, name)
+
+	if h3Transport == nil {
+		return
+	}
+
+	if err := h3Transport.Close(); err != nil {
+		log.Debug().Err(err).Msgf("Error while closing the HTTP/3 transport of %s", name)
+	}
+}
+
 // GetRoundTripper gets a roundtripper corresponding to the given transport name.
 func (t *TransportManager) GetRoundTripper(name string) (http.RoundTripper, error) {
 	if len(name) == 0 {
@@ -169,7 +193,7 @@ func (t *TransportManager) createTLSConfig(cfg *dynamic.ServersTransport) (*tls.
 		config = tlsconfig.MTLSClientConfig(t.spiffeX509Source, t.spiffeX509Source, spiffeAuthorizer)
 	}
 
-	if cfg.InsecureSkipVerify || len(cfg.RootCAs) > 0 || len(cfg.ServerName) > 0 || len(cfg.Certificates) > 0 || cfg.PeerCertURI != "" || len(cfg.CipherSuites) > 0 || cfg.MaxVersion != "" || cfg.MinVersion != "" {
+	if cfg.InsecureSkipVerify || len(cfg.RootCAs) > 0 || len(cfg.ServerName) > 0 || len(cfg.Certificates) > 0 || cfg.PeerCertURI != "" || len(cfg.CipherSuites) > 0 || cfg.MaxVersion != "" || cfg.MinVersion != "" || len(cfg.CurvePreferences) > 0 {
 		if config != nil {
 			return nil, errors.New("TLS and SPIFFE configuration cannot be defined at the same time")
 		}
@@ -213,6 +237,19 @@ func (t *TransportManager) createTLSConfig(cfg *dynamic.ServersTransport) (*tls.
 			}
 		}
 
+		var curvePreferences []tls.CurveID
+		if len(cfg.CurvePreferences) > 0 {
+			var err error
+			curvePreferences, err = traefiktls.ParseCurvePreferences(cfg.CurvePreferences)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := traefiktls.ValidateCurvePreferences(curvePreferences, maxVersion); err != nil {
+				return nil, fmt.Errorf("invalid curvePreferences: %w", err)
+			}
+		}
+
 		config = &tls.Config{
 			ServerName:         cfg.ServerName,
 			InsecureSkipVerify: cfg.InsecureSkipVerify,
@@ -221,6 +258,7 @@ func (t *TransportManager) createTLSConfig(cfg *dynamic.ServersTransport) (*tls.
 			CipherSuites:       cipherSuites,
 			MinVersion:         minVersion,
 			MaxVersion:         maxVersion,
+			CurvePreferences:   curvePreferences,
 		}
 
 		if cfg.PeerCertURI != "" {
@@ -287,13 +325,14 @@ func customDialContext(d *net.Dialer, cfg *dynamic.ForwardingTimeouts) func(ctx
 	}
 }
 
-// createRoundTripper creates an http.RoundTripper configured with the Transport configuration settings.
+// createRoundTripper creates an http.RoundTripper configured with the Transport configuration settings,
+// and returns the HTTP/3 transport it uses, if HTTP/3 is enabled.
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost in Traefik at this point in time.
 // Setting this value to the default of 100 could lead to confusing behavior and backwards compatibility issues.
-func (t *TransportManager) createRoundTripper(cfg *dynamic.ServersTransport, tlsConfig *tls.Config) (http.RoundTripper, error) {
+func (t *TransportManager) createRoundTripper(cfg *dynamic.ServersTransport, tlsConfig *tls.Config) (http.RoundTripper, *http3.Transport, error) {
 	if cfg == nil {
-		return nil, errors.New("no transport configuration given")
+		return nil, nil, errors.New("no transport configuration given")
 	}
 
 	dialer := &net.Dialer{
@@ -323,26 +362,48 @@ func (t *TransportManager) createRoundTripper(cfg *dynamic.ServersTransport, tls
 		transport.DialContext = customDialContext(dialer, cfg.ForwardingTimeouts)
 	}
 
+	var h3Transport *http3.Transport
+	if cfg.HTTP3 != nil {
+		h3Transport = newHTTP3Transport(cfg, tlsConfig)
+	}
+
 	// Return directly HTTP/1.1 transport when HTTP/2 is disabled
 	if cfg.DisableHTTP2 {
 		return &kerberosRoundTripper{
-			OriginalRoundTripper: transport,
+			OriginalRoundTripper: withHTTP3(cfg, h3Transport, transport, transport),
 			new: func() http.RoundTripper {
-				return transport.Clone()
+				clone := transport.Clone()
+				return withHTTP3(cfg, h3Transport, clone, clone)
 			},
-		}, nil
+		}, h3Transport, nil
 	}
 
 	rt, err := newSmartRoundTripper(transport, cfg.ForwardingTimeouts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+
 	return &kerberosRoundTripper{
-		OriginalRoundTripper: rt,
+		OriginalRoundTripper: withHTTP3(cfg, h3Transport, transport, rt),
 		new: func() http.RoundTripper {
-			return rt.Clone()
+			clone := rt.Clone()
+			return withHTTP3(cfg, h3Transport, clone.http2, clone)
 		},
-	}, nil
+	}, h3Transport, nil
+}
+
+// withHTTP3 returns rt as is when HTTP/3 is disabled, i.e. when there is no HTTP/3 transport.
+// Otherwise, it registers the HTTP/3 round tripper for the h3 scheme on the given transport, and returns it in place of rt.
+// The clones of a round tripper share its HTTP/3 transport.
+func withHTTP3(cfg *dynamic.ServersTransport, h3Transport *http3.Transport, transport *http.Transport, rt http.RoundTripper) http.RoundTripper {
+	if h3Transport == nil {
+		return rt
+	}
+
+	h3RT := newH3RoundTripper(cfg, h3Transport, rt)
+	transport.RegisterProtocol("h3", h3RT)
+
+	return h3RT
 }
 
 type stickyRoundTripper struct {
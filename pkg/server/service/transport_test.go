@@ -7,6 +7,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"io"
 	"math/big"
 	"net"
 	"net/http"
@@ -526,6 +527,59 @@ func TestEmptyCipherSuites(t *testing.T) {
 	assert.ErrorContains(t, err, "remote error: tls: handshake failure")
 }
 
+func TestCurvePreferences(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.TLS.CurveID.String()))
+	}))
+
+	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
+	require.NoError(t, err)
+
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+	srv.StartTLS()
+
+	transportManager := NewTransportManager(nil)
+
+	dynamicConf := map[string]*dynamic.ServersTransport{
+		"test": {
+			ServerName:       "example.com",
+			RootCAs:          []types.FileOrContent{types.FileOrContent(LocalhostCert)},
+			CurvePreferences: []string{"X25519MLKEM768"},
+		},
+		"invalid": {
+			ServerName:       "example.com",
+			RootCAs:          []types.FileOrContent{types.FileOrContent(LocalhostCert)},
+			MaxVersion:       "VersionTLS12",
+			CurvePreferences: []string{"X25519MLKEM768"},
+		},
+	}
+
+	transportManager.Update(dynamicConf)
+
+	tlsConfig, err := transportManager.GetTLSConfig("test")
+	require.NoError(t, err)
+	assert.Equal(t, []tls.CurveID{tls.X25519MLKEM768}, tlsConfig.CurvePreferences)
+
+	tr, err := transportManager.GetRoundTripper("test")
+	require.NoError(t, err)
+	client := http.Client{Transport: tr}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "X25519MLKEM768", string(body))
+
+	// The hybrid curves cannot be negotiated with TLS 1.2, the default TLS configuration is used.
+	tlsConfig, err = transportManager.GetTLSConfig("invalid")
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+}
+
 func TestMTLS(t *testing.T) {
 	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(http.StatusOK)
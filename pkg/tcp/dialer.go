@@ -63,6 +63,28 @@ func (d tcpDialer) DialContext(ctx context.Context, network, addr string, client
 
 	if d.proxyProtocol != nil && clientConn != nil && d.proxyProtocol.Version > 0 && d.proxyProtocol.Version < 3 {
 		header := proxyproto.HeaderProxyFromAddrs(byte(d.proxyProtocol.Version), clientConn.RemoteAddr(), clientConn.LocalAddr())
+
+		if d.proxyProtocol.Version == 2 && len(d.proxyProtocol.TLVs) > 0 {
+			// The handshake with the client is bounded as the dial, the context being usually not cancelable.
+			handshakeCtx := ctx
+			if d.dialer.Timeout > 0 {
+				var cancel context.CancelFunc
+				handshakeCtx, cancel = context.WithTimeout(ctx, d.dialer.Timeout)
+				defer cancel()
+			}
+
+			tlvs, err := buildProxyProtocolTLVs(handshakeCtx, clientConn, d.proxyProtocol.TLVs)
+			if err != nil {
+				_ = conn.Close()
+				return nil, fmt.Errorf("building PROXY Protocol TLVs: %w", err)
+			}
+
+			if err := header.SetTLVs(tlvs); err != nil {
+				_ = conn.Close()
+				return nil, fmt.Errorf("setting PROXY Protocol TLVs: %w", err)
+			}
+		}
+
 		if _, err := header.WriteTo(conn); err != nil {
 			_ = conn.Close()
 			return nil, fmt.Errorf("writing PROXY Protocol header: %w", err)
@@ -160,6 +182,16 @@ func (d *DialerManager) Build(config *dynamic.TCPServersLoadBalancer, isTLS bool
 		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
 	}
 
+	if proxyProtocol != nil && len(proxyProtocol.TLVs) > 0 {
+		if proxyProtocol.Version != 2 {
+			return nil, errors.New("proxyProtocol TLVs are only supported with version 2")
+		}
+
+		if err := ValidateOutgoingProxyProtocolTLVs(proxyProtocol.TLVs); err != nil {
+			return nil, err
+		}
+	}
+
 	var tlsConfig *tls.Config
 	if st.TLS != nil {
 		if st.TLS.Spiffe != nil {
@@ -175,7 +207,7 @@ func (d *DialerManager) Build(config *dynamic.TCPServersLoadBalancer, isTLS bool
 			tlsConfig = tlsconfig.MTLSClientConfig(d.spiffeX509Source, d.spiffeX509Source, authorizer)
 		}
 
-		if st.TLS.InsecureSkipVerify || len(st.TLS.RootCAs) > 0 || len(st.TLS.ServerName) > 0 || len(st.TLS.Certificates) > 0 || st.TLS.PeerCertURI != "" {
+		if st.TLS.InsecureSkipVerify || len(st.TLS.RootCAs) > 0 || len(st.TLS.ServerName) > 0 || len(st.TLS.Certificates) > 0 || st.TLS.PeerCertURI != "" || len(st.TLS.CurvePreferences) > 0 {
 			if tlsConfig != nil {
 				return nil, errors.New("TLS and SPIFFE configuration cannot be defined at the same time")
 			}
@@ -187,6 +219,15 @@ func (d *DialerManager) Build(config *dynamic.TCPServersLoadBalancer, isTLS bool
 				Certificates:       st.TLS.Certificates.GetCertificates(),
 			}
 
+			if len(st.TLS.CurvePreferences) > 0 {
+				curvePreferences, err := traefiktls.ParseCurvePreferences(st.TLS.CurvePreferences)
+				if err != nil {
+					return nil, err
+				}
+
+				tlsConfig.CurvePreferences = curvePreferences
+			}
+
 			if st.TLS.PeerCertURI != "" {
 				tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
 					return traefiktls.VerifyPeerCertificate(st.TLS.PeerCertURI, tlsConfig, rawCerts)
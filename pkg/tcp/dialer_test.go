@@ -227,6 +227,58 @@ func TestTLS(t *testing.T) {
 	assert.Equal(t, "PONG", buffer.String())
 }
 
+func TestTLSCurvePreferences(t *testing.T) {
+	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
+	require.NoError(t, err)
+
+	backendListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer backendListener.Close()
+
+	tlsListener := tls.NewListener(backendListener, &tls.Config{Certificates: []tls.Certificate{cert}})
+	defer tlsListener.Close()
+
+	go fakeServer(t, tlsListener)
+
+	_, port, err := net.SplitHostPort(tlsListener.Addr().String())
+	require.NoError(t, err)
+
+	dialerManager := NewDialerManager(nil)
+
+	dynamicConf := map[string]*dynamic.TCPServersTransport{
+		"test": {
+			TLS: &dynamic.TLSClientConfig{
+				ServerName:       "example.com",
+				RootCAs:          []types.FileOrContent{types.FileOrContent(LocalhostCert)},
+				CurvePreferences: []string{"X25519MLKEM768"},
+			},
+		},
+		"invalid": {
+			TLS: &dynamic.TLSClientConfig{
+				ServerName:       "example.com",
+				CurvePreferences: []string{"foo"},
+			},
+		},
+	}
+
+	dialerManager.Update(dynamicConf)
+
+	_, err = dialerManager.Build(&dynamic.TCPServersLoadBalancer{ServersTransport: "invalid"}, true)
+	require.Error(t, err)
+
+	dialer, err := dialerManager.Build(&dynamic.TCPServersLoadBalancer{ServersTransport: "test"}, true)
+	require.NoError(t, err)
+
+	conn, err := dialer.Dial("tcp", ":"+port, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	tlsConn := conn.(*tls.Conn)
+	require.NoError(t, tlsConn.Handshake())
+
+	assert.Equal(t, tls.X25519MLKEM768, tlsConn.ConnectionState().CurveID)
+}
+
 func TestTLSWithInsecureSkipVerify(t *testing.T) {
 	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
 	require.NoError(t, err)
@@ -696,6 +748,95 @@ func TestProxyProtocolWithTLS(t *testing.T) {
 	}
 }
 
+func TestProxyProtocolTLVs(t *testing.T) {
+	backendListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	var tlvs []proxyproto.TLV
+	proxyBackendListener := proxyproto.Listener{
+		Listener: backendListener,
+		ValidateHeader: func(h *proxyproto.Header) error {
+			var tlvErr error
+			tlvs, tlvErr = h.TLVs()
+			return tlvErr
+		},
+	}
+	defer proxyBackendListener.Close()
+
+	go fakeServer(t, &proxyBackendListener)
+
+	_, port, err := net.SplitHostPort(backendListener.Addr().String())
+	require.NoError(t, err)
+
+	dialerManager := NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{
+		"test": {
+			ProxyProtocol: &dynamic.ProxyProtocol{
+				Version: 2,
+				TLVs:    []string{TLVAuthority, TLVALPN},
+			},
+		},
+	})
+
+	dialer, err := dialerManager.Build(&dynamic.TCPServersLoadBalancer{ServersTransport: "test"}, false)
+	require.NoError(t, err)
+
+	clientConn := &fakeSNIClientConn{
+		fakeClientConn: fakeClientConn{
+			localAddr:  &net.TCPAddr{IP: net.ParseIP("2.2.2.2"), Port: 12345},
+			remoteAddr: &net.TCPAddr{IP: net.ParseIP("1.1.1.1"), Port: 12345},
+		},
+		serverName: "foo.example.com",
+	}
+
+	conn, err := dialer.Dial("tcp", ":"+port, clientConn)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "PONG", string(buf[:n]))
+
+	// The ALPN TLV is not sent, as the client connection is not a TLS connection.
+	require.Len(t, tlvs, 1)
+	assert.Equal(t, proxyproto.PP2_TYPE_AUTHORITY, tlvs[0].Type)
+	assert.Equal(t, "foo.example.com", string(tlvs[0].Value))
+}
+
+func TestProxyProtocolTLVs_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		proxyProtocol *dynamic.ProxyProtocol
+	}{
+		{
+			desc:          "TLVs with PROXY protocol v1",
+			proxyProtocol: &dynamic.ProxyProtocol{Version: 1, TLVs: []string{TLVAuthority}},
+		},
+		{
+			desc:          "unsupported TLV",
+			proxyProtocol: &dynamic.ProxyProtocol{Version: 2, TLVs: []string{TLVAWSVPCEndpointID}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dialerManager := NewDialerManager(nil)
+			dialerManager.Update(map[string]*dynamic.TCPServersTransport{
+				"test": {ProxyProtocol: test.proxyProtocol},
+			})
+
+			_, err := dialerManager.Build(&dynamic.TCPServersLoadBalancer{ServersTransport: "test"}, false)
+			require.Error(t, err)
+		})
+	}
+}
+
 func TestProxyProtocolDisabled(t *testing.T) {
 	backendListener, err := net.Listen("tcp", ":0")
 	require.NoError(t, err)
@@ -754,6 +895,16 @@ func (f fakeClientConn) RemoteAddr() net.Addr {
 	return f.remoteAddr
 }
 
+type fakeSNIClientConn struct {
+	fakeClientConn
+
+	serverName string
+}
+
+func (f fakeSNIClientConn) ServerName() string {
+	return f.serverName
+}
+
 // fakeSpiffePKI simulates a SPIFFE aware PKI and allows generating multiple valid SVIDs.
 type fakeSpiffePKI struct {
 	caPrivateKey *rsa.PrivateKey
@@ -0,0 +1,341 @@
+package tcp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// statusUpdater is implemented by the handlers which can propagate their status to their parent.
+type statusUpdater interface {
+	RegisterStatusUpdater(fn func(up bool)) error
+}
+
+// Mirroring is a Handler that duplicates the client to server byte stream of the connections towards mirror handlers.
+// The data sent back by the mirrors is discarded.
+type Mirroring struct {
+	handler        Handler
+	mirrorHandlers []*mirrorHandler
+
+	maxBufferSize    int64
+	wantsHealthCheck bool
+
+	lock  sync.Mutex
+	total uint64
+}
+
+// NewMirroring returns a new instance of *Mirroring.
+// maxBufferSize is the maximum amount of data buffered for a mirror which does not keep up with the client,
+// beyond which the mirroring of the connection to this mirror is stopped.
+func NewMirroring(handler Handler, maxBufferSize int64, wantsHealthCheck bool) *Mirroring {
+	return &Mirroring{
+		handler:          handler,
+		maxBufferSize:    maxBufferSize,
+		wantsHealthCheck: wantsHealthCheck,
+	}
+}
+
+type mirrorHandler struct {
+	Handler
+
+	percent int
+
+	lock  sync.Mutex
+	count uint64
+}
+
+// ServeTCP forwards the connection to the main handler, and a copy of the client stream to the active mirrors.
+func (m *Mirroring) ServeTCP(conn WriteCloser) {
+	mirrors := m.getActiveMirrors()
+	if len(mirrors) == 0 {
+		m.handler.ServeTCP(conn)
+		return
+	}
+
+	mConn := &mirroredConn{WriteCloser: conn}
+	for _, mirror := range mirrors {
+		mc := newMirrorConn(conn, m.maxBufferSize)
+		mConn.mirrors = append(mConn.mirrors, mc)
+
+		go mirror.ServeTCP(mc)
+	}
+
+	// The mirrors are stopped once the main handler is done with the connection,
+	// even when it did not read it until the end.
+	defer mConn.closeMirrors()
+
+	m.handler.ServeTCP(mConn)
+}
+
+// AddMirror adds a handler to mirror the connections to.
+func (m *Mirroring) AddMirror(handler Handler, percent int) error {
+	if percent < 0 || percent > 100 {
+		return errors.New("percent must be between 0 and 100")
+	}
+	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent})
+	return nil
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of handler of the Mirroring changes.
+// Not thread safe.
+func (m *Mirroring) RegisterStatusUpdater(fn func(up bool)) error {
+	if !m.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this mirroring service")
+	}
+
+	updater, ok := m.handler.(statusUpdater)
+	if !ok {
+		return fmt.Errorf("service of mirroring %T not a healthcheck.StatusUpdater", m.handler)
+	}
+
+	if err := updater.RegisterStatusUpdater(fn); err != nil {
+		return fmt.Errorf("cannot register service of mirroring as updater: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Mirroring) inc() uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.total++
+	return m.total
+}
+
+func (m *Mirroring) getActiveMirrors() []Handler {
+	total := m.inc()
+
+	var mirrors []Handler
+	for _, handler := range m.mirrorHandlers {
+		handler.lock.Lock()
+		if handler.count*100 < total*uint64(handler.percent) {
+			handler.count++
+			handler.lock.Unlock()
+			mirrors = append(mirrors, handler)
+		} else {
+			handler.lock.Unlock()
+		}
+	}
+	return mirrors
+}
+
+// mirroredConn is the client connection given to the main handler,
+// which copies the data read from the client to the mirrors.
+type mirroredConn struct {
+	WriteCloser
+
+	mirrors []*mirrorConn
+}
+
+// NetConn returns the underlying connection.
+func (c *mirroredConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *mirroredConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		for _, mirror := range c.mirrors {
+			mirror.feed(p[:n])
+		}
+	}
+
+	if err != nil {
+		c.closeMirrors()
+	}
+
+	return n, err
+}
+
+func (c *mirroredConn) Close() error {
+	c.closeMirrors()
+	return c.WriteCloser.Close()
+}
+
+// SetServiceAddr forwards the address of the main service to the underlying connection,
+// so that the mirrors are not recorded.
+func (c *mirroredConn) SetServiceAddr(addr string) {
+	if recorder, ok := c.WriteCloser.(connInfoRecorder); ok {
+		recorder.SetServiceAddr(addr)
+	}
+}
+
+// SetCloseReason forwards the close reason of the main service to the underlying connection.
+func (c *mirroredConn) SetCloseReason(reason string) {
+	if recorder, ok := c.WriteCloser.(connInfoRecorder); ok {
+		recorder.SetCloseReason(reason)
+	}
+}
+
+func (c *mirroredConn) closeMirrors() {
+	for _, mirror := range c.mirrors {
+		mirror.closeStream()
+	}
+}
+
+// mirrorConn is the connection given to a mirror handler.
+// It replays the data read from the client, and discards the data written by the mirror.
+// The data is buffered up to maxBufferSize, so that a slow mirror never slows down the client connection.
+type mirrorConn struct {
+	clientConn    net.Conn
+	maxBufferSize int64
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    bytes.Buffer
+	eof    bool
+	closed bool
+
+	deadlineExceeded bool
+	deadlineTimer    *time.Timer
+}
+
+func newMirrorConn(clientConn net.Conn, maxBufferSize int64) *mirrorConn {
+	c := &mirrorConn{
+		clientConn:    clientConn,
+		maxBufferSize: maxBufferSize,
+	}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// feed appends data to the stream replayed to the mirror, without ever blocking.
+// When the mirror is too slow to consume the buffered data, the stream is ended.
+func (c *mirrorConn) feed(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.eof || c.closed {
+		return
+	}
+
+	if c.maxBufferSize >= 0 && int64(c.buf.Len()+len(p)) > c.maxBufferSize {
+		log.Debug().
+			Str("remoteAddr", c.clientConn.RemoteAddr().String()).
+			Msgf("Stopping TCP mirroring, buffered data exceeds %d bytes", c.maxBufferSize)
+
+		c.eof = true
+		c.buf.Reset()
+		c.cond.Broadcast()
+		return
+	}
+
+	c.buf.Write(p)
+	c.cond.Broadcast()
+}
+
+// closeStream ends the stream replayed to the mirror, once the buffered data has been consumed.
+func (c *mirrorConn) closeStream() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.eof = true
+	c.cond.Broadcast()
+}
+
+func (c *mirrorConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.buf.Len() == 0 && !c.eof && !c.closed && !c.deadlineExceeded {
+		c.cond.Wait()
+	}
+
+	switch {
+	case c.closed:
+		return 0, net.ErrClosed
+	case c.buf.Len() > 0:
+		return c.buf.Read(p)
+	case c.eof:
+		return 0, io.EOF
+	default:
+		return 0, os.ErrDeadlineExceeded
+	}
+}
+
+// Write discards the data sent by the mirror.
+func (c *mirrorConn) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (c *mirrorConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	c.buf.Reset()
+	if c.deadlineTimer != nil {
+		c.deadlineTimer.Stop()
+	}
+	c.cond.Broadcast()
+
+	return nil
+}
+
+func (c *mirrorConn) CloseWrite() error {
+	return nil
+}
+
+func (c *mirrorConn) LocalAddr() net.Addr {
+	return c.clientConn.LocalAddr()
+}
+
+func (c *mirrorConn) RemoteAddr() net.Addr {
+	return c.clientConn.RemoteAddr()
+}
+
+func (c *mirrorConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *mirrorConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.deadlineTimer != nil {
+		c.deadlineTimer.Stop()
+		c.deadlineTimer = nil
+	}
+	c.deadlineExceeded = false
+
+	if t.IsZero() {
+		return nil
+	}
+
+	d := time.Until(t)
+	if d <= 0 {
+		c.deadlineExceeded = true
+		c.cond.Broadcast()
+		return nil
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		// The deadline has been changed in the meantime.
+		if c.deadlineTimer != timer {
+			return
+		}
+
+		c.deadlineExceeded = true
+		c.cond.Broadcast()
+	})
+	c.deadlineTimer = timer
+
+	return nil
+}
+
+func (c *mirrorConn) SetWriteDeadline(_ time.Time) error {
+	return nil
+}
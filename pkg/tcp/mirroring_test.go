@@ -0,0 +1,244 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirroring_percent(t *testing.T) {
+	var mainCount, mirror1Count, mirror2Count atomic.Int32
+
+	var wg sync.WaitGroup
+
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {
+		mainCount.Add(1)
+	}), -1, false)
+
+	err := mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {
+		defer wg.Done()
+		mirror1Count.Add(1)
+	}), 100)
+	require.NoError(t, err)
+
+	err = mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {
+		defer wg.Done()
+		mirror2Count.Add(1)
+	}), 50)
+	require.NoError(t, err)
+
+	// Each connection is sent to the first mirror, and every other connection to the second mirror.
+	wg.Add(15)
+	for range 10 {
+		clientConn, _ := newPipeConn(t)
+		mirroring.ServeTCP(clientConn)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(10), mainCount.Load())
+	assert.Equal(t, int32(10), mirror1Count.Load())
+	assert.Equal(t, int32(5), mirror2Count.Load())
+}
+
+func TestMirroring_invalidPercent(t *testing.T) {
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {}), -1, false)
+
+	for _, percent := range []int{-1, 101} {
+		err := mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {}), percent)
+		assert.Error(t, err)
+	}
+}
+
+func TestMirroring_stream(t *testing.T) {
+	mirrored := make(chan []byte, 1)
+
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {
+		_, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+
+		_, err = conn.Write([]byte("main"))
+		assert.NoError(t, err)
+
+		_ = conn.Close()
+	}), -1, false)
+
+	err := mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {
+		data, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+
+		// The response of the mirror is discarded.
+		_, err = conn.Write([]byte("mirror"))
+		assert.NoError(t, err)
+
+		mirrored <- data
+	}), 100)
+	require.NoError(t, err)
+
+	clientConn, peerConn := newPipeConn(t)
+
+	go func() {
+		_, err := peerConn.Write([]byte("foo"))
+		assert.NoError(t, err)
+		_, err = peerConn.Write([]byte("bar"))
+		assert.NoError(t, err)
+		_ = peerConn.CloseWrite()
+	}()
+
+	response := make(chan []byte, 1)
+	go func() {
+		data, err := io.ReadAll(peerConn)
+		assert.NoError(t, err)
+		response <- data
+	}()
+
+	mirroring.ServeTCP(clientConn)
+
+	select {
+	case data := <-mirrored:
+		assert.Equal(t, "foobar", string(data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the mirrored data")
+	}
+
+	select {
+	case data := <-response:
+		assert.Equal(t, "main", string(data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the response")
+	}
+}
+
+func TestMirroring_slowMirror(t *testing.T) {
+	release := make(chan struct{})
+	mirrored := make(chan []byte, 1)
+
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {
+		data, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+		assert.Equal(t, "foobarfoobar", string(data))
+	}), 8, false)
+
+	err := mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {
+		<-release
+
+		data, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+
+		mirrored <- data
+	}), 100)
+	require.NoError(t, err)
+
+	clientConn, peerConn := newPipeConn(t)
+
+	go func() {
+		for range 2 {
+			_, err := peerConn.Write([]byte("foo"))
+			assert.NoError(t, err)
+			_, err = peerConn.Write([]byte("bar"))
+			assert.NoError(t, err)
+		}
+		_ = peerConn.CloseWrite()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mirroring.ServeTCP(clientConn)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the main connection has been blocked by the mirror")
+	}
+
+	close(release)
+
+	select {
+	case data := <-mirrored:
+		// The mirroring has been stopped when the buffered data exceeded the limit,
+		// the mirror only gets the end of the stream.
+		assert.Empty(t, data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the mirrored data")
+	}
+}
+
+func TestMirrorConn_readDeadline(t *testing.T) {
+	clientConn, _ := newPipeConn(t)
+
+	conn := newMirrorConn(clientConn, -1)
+
+	err := conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = conn.Read(make([]byte, 1))
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	// Resetting the deadline allows to read the data again.
+	err = conn.SetReadDeadline(time.Time{})
+	require.NoError(t, err)
+
+	conn.feed([]byte("foo"))
+
+	buf := make([]byte, 3)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(buf[:n]))
+}
+
+// pipeConn is an in-memory full duplex connection, which supports half-closing.
+type pipeConn struct {
+	net.Conn
+	w *io.PipeWriter
+	r *io.PipeReader
+}
+
+func newPipeConn(t *testing.T) (*pipeConn, *pipeConn) {
+	t.Helper()
+
+	clientR, peerW := io.Pipe()
+	peerR, clientW := io.Pipe()
+
+	client := &pipeConn{Conn: &net.TCPConn{}, r: clientR, w: clientW}
+	peer := &pipeConn{Conn: &net.TCPConn{}, r: peerR, w: peerW}
+
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = peer.Close()
+	})
+
+	return client, peer
+}
+
+func (c *pipeConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *pipeConn) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+func (c *pipeConn) CloseWrite() error {
+	return c.w.Close()
+}
+
+func (c *pipeConn) Close() error {
+	_ = c.r.Close()
+	return c.w.Close()
+}
+
+func (c *pipeConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 12345}
+}
+
+func (c *pipeConn) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80}
+}
@@ -10,6 +10,13 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// connInfoRecorder is implemented by connections keeping track of how they are proxied,
+// e.g. for access logging purposes.
+type connInfoRecorder interface {
+	SetServiceAddr(addr string)
+	SetCloseReason(reason string)
+}
+
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
 	address string
@@ -34,9 +41,17 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
+	recorder, _ := conn.(connInfoRecorder)
+	if recorder != nil {
+		recorder.SetServiceAddr(p.address)
+	}
+
 	connBackend, err := p.dialBackend(conn)
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")
+		if recorder != nil {
+			recorder.SetCloseReason("error while dialing backend: " + err.Error())
+		}
 		return
 	}
 
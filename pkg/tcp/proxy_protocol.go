@@ -0,0 +1,265 @@
+package tcp
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+)
+
+// PROXY protocol v2 TLV names, as used in routing rules, headers and ServersTransport configurations.
+const (
+	TLVALPN               = "alpn"
+	TLVAuthority          = "authority"
+	TLVUniqueID           = "unique_id"
+	TLVSSL                = "ssl"
+	TLVSSLVersion         = "ssl.version"
+	TLVSSLCipher          = "ssl.cipher"
+	TLVSSLClientCN        = "ssl.cn"
+	TLVSSLVerified        = "ssl.verified"
+	TLVAWSVPCEndpointID   = "aws.vpce_id"
+	TLVAzureLinkID        = "azure.link_id"
+	TLVGCPPSCConnectionID = "gcp.psc_connection_id"
+)
+
+const (
+	customTLVPrefix        = "0x"
+	maxCustomTLVNameLength = len(customTLVPrefix) + 2
+)
+
+// outgoingTLVs are the TLVs that can be sent to the backends in a PROXY protocol v2 header.
+var outgoingTLVs = map[string]struct{}{
+	TLVALPN:      {},
+	TLVAuthority: {},
+	TLVSSL:       {},
+}
+
+// ValidateProxyProtocolTLVName checks that the given name identifies a known TLV,
+// or a raw TLV type in its hexadecimal form (e.g. 0xE0).
+func ValidateProxyProtocolTLVName(name string) error {
+	switch name {
+	case TLVALPN, TLVAuthority, TLVUniqueID, TLVSSLVersion, TLVSSLCipher, TLVSSLClientCN, TLVSSLVerified,
+		TLVAWSVPCEndpointID, TLVAzureLinkID, TLVGCPPSCConnectionID:
+		return nil
+	}
+
+	_, err := parseCustomTLVType(name)
+	return err
+}
+
+// ValidateOutgoingProxyProtocolTLVs checks that the given TLVs can be sent to the backends.
+func ValidateOutgoingProxyProtocolTLVs(names []string) error {
+	for _, name := range names {
+		if _, ok := outgoingTLVs[name]; !ok {
+			return fmt.Errorf("unsupported PROXY protocol TLV %q, supported values are %q, %q and %q", name, TLVAuthority, TLVALPN, TLVSSL)
+		}
+	}
+
+	return nil
+}
+
+// ProxyProtocolTLVs returns the TLVs received in the PROXY protocol v2 header of the given connection, if any.
+// The connection wrappers exposing a NetConn method are walked through to find the PROXY protocol connection.
+func ProxyProtocolTLVs(conn any) []proxyproto.TLV {
+	ppConn, ok := findConn[*proxyproto.Conn](conn)
+	if !ok {
+		return nil
+	}
+
+	header := ppConn.ProxyHeader()
+	if header == nil {
+		return nil
+	}
+
+	tlvs, err := header.TLVs()
+	if err != nil {
+		return nil
+	}
+
+	return tlvs
+}
+
+// LookupProxyProtocolTLV returns the value of the TLV identified by name.
+// Raw TLV types (e.g. 0xE0) are returned as is, while known TLVs are decoded to their string representation.
+func LookupProxyProtocolTLV(tlvs []proxyproto.TLV, name string) (string, bool) {
+	switch name {
+	case TLVALPN:
+		return findRawTLV(tlvs, proxyproto.PP2_TYPE_ALPN)
+	case TLVAuthority:
+		return findRawTLV(tlvs, proxyproto.PP2_TYPE_AUTHORITY)
+	case TLVUniqueID:
+		value, ok := findRawTLV(tlvs, proxyproto.PP2_TYPE_UNIQUE_ID)
+		return hex.EncodeToString([]byte(value)), ok
+	case TLVSSLVersion, TLVSSLCipher, TLVSSLClientCN, TLVSSLVerified:
+		ssl, ok := tlvparse.FindSSL(tlvs)
+		if !ok {
+			return "", false
+		}
+
+		switch name {
+		case TLVSSLVersion:
+			return ssl.SSLVersion()
+		case TLVSSLCipher:
+			return ssl.SSLCipher()
+		case TLVSSLClientCN:
+			return ssl.ClientCN()
+		default:
+			return strconv.FormatBool(ssl.ClientCertConn() && ssl.Verified()), true
+		}
+	case TLVAWSVPCEndpointID:
+		value := tlvparse.FindAWSVPCEndpointID(tlvs)
+		return value, value != ""
+	case TLVAzureLinkID:
+		value, ok := tlvparse.FindAzurePrivateEndpointLinkID(tlvs)
+		return strconv.FormatUint(uint64(value), 10), ok
+	case TLVGCPPSCConnectionID:
+		value, ok := tlvparse.ExtractPSCConnectionID(tlvs)
+		return strconv.FormatUint(value, 10), ok
+	}
+
+	tlvType, err := parseCustomTLVType(name)
+	if err != nil {
+		return "", false
+	}
+
+	return findRawTLV(tlvs, tlvType)
+}
+
+// buildProxyProtocolTLVs builds the TLVs to send to a backend, from the client connection information.
+// The given context bounds the TLS handshake with the client, if it is not completed yet.
+func buildProxyProtocolTLVs(ctx context.Context, clientConn ClientConn, names []string) ([]proxyproto.TLV, error) {
+	var state *tls.ConnectionState
+	if tlsConn, ok := findConn[*tls.Conn](clientConn); ok {
+		// The backend connection is dialed before any data is read from the client,
+		// so the handshake has to be completed to know about the TLS connection.
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, fmt.Errorf("TLS handshake with the client: %w", err)
+		}
+
+		s := tlsConn.ConnectionState()
+		state = &s
+	}
+
+	var tlvs []proxyproto.TLV
+	for _, name := range names {
+		switch name {
+		case TLVAuthority:
+			serverName := serverNameOf(clientConn, state)
+			if serverName == "" {
+				continue
+			}
+
+			tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte(serverName)})
+
+		case TLVALPN:
+			if state == nil || state.NegotiatedProtocol == "" {
+				continue
+			}
+
+			tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte(state.NegotiatedProtocol)})
+
+		case TLVSSL:
+			if state == nil {
+				continue
+			}
+
+			tlv, err := sslTLV(state)
+			if err != nil {
+				return nil, fmt.Errorf("building SSL TLV: %w", err)
+			}
+
+			tlvs = append(tlvs, tlv)
+		}
+	}
+
+	return tlvs, nil
+}
+
+func sslTLV(state *tls.ConnectionState) (proxyproto.TLV, error) {
+	ssl := tlvparse.PP2SSL{
+		Client: tlvparse.PP2_BITFIELD_CLIENT_SSL,
+		// Verify is non-zero when no verified client certificate was presented.
+		Verify: 1,
+		TLV: []proxyproto.TLV{
+			{Type: proxyproto.PP2_SUBTYPE_SSL_VERSION, Value: []byte(traefiktls.GetVersion(state))},
+			{Type: proxyproto.PP2_SUBTYPE_SSL_CIPHER, Value: []byte(traefiktls.GetCipherName(state))},
+		},
+	}
+
+	if len(state.PeerCertificates) > 0 {
+		ssl.Client |= tlvparse.PP2_BITFIELD_CLIENT_CERT_CONN
+
+		if len(state.VerifiedChains) > 0 {
+			ssl.Verify = 0
+		}
+
+		if cn := state.PeerCertificates[0].Subject.CommonName; cn != "" {
+			ssl.TLV = append(ssl.TLV, proxyproto.TLV{Type: proxyproto.PP2_SUBTYPE_SSL_CN, Value: []byte(cn)})
+		}
+	}
+
+	return ssl.Marshal()
+}
+
+func serverNameOf(clientConn ClientConn, state *tls.ConnectionState) string {
+	if state != nil {
+		return state.ServerName
+	}
+
+	// TLS passthrough connections expose the server name read from the ClientHello.
+	if conn, ok := findConn[interface{ ServerName() string }](clientConn); ok {
+		return conn.ServerName()
+	}
+
+	return ""
+}
+
+func findRawTLV(tlvs []proxyproto.TLV, tlvType proxyproto.PP2Type) (string, bool) {
+	for _, tlv := range tlvs {
+		if tlv.Type == tlvType {
+			return string(tlv.Value), true
+		}
+	}
+
+	return "", false
+}
+
+func parseCustomTLVType(name string) (proxyproto.PP2Type, error) {
+	if !strings.HasPrefix(strings.ToLower(name), customTLVPrefix) || len(name) > maxCustomTLVNameLength {
+		return 0, fmt.Errorf("unknown PROXY protocol TLV %q", name)
+	}
+
+	value, err := strconv.ParseUint(name[len(customTLVPrefix):], 16, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid PROXY protocol TLV type %q: %w", name, err)
+	}
+
+	return proxyproto.PP2Type(value), nil
+}
+
+// findConn walks through the connection wrappers exposing a NetConn method,
+// and returns the first connection of type T.
+func findConn[T any](conn any) (T, bool) {
+	for conn != nil {
+		if c, ok := conn.(T); ok {
+			return c, true
+		}
+
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			break
+		}
+
+		conn = wrapper.NetConn()
+	}
+
+	var zero T
+	return zero, false
+}
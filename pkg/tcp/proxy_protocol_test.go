@@ -0,0 +1,123 @@
+package tcp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProxyProtocolTLVName(t *testing.T) {
+	testCases := []struct {
+		name      string
+		expectErr bool
+	}{
+		{name: TLVAuthority},
+		{name: TLVSSLClientCN},
+		{name: TLVAWSVPCEndpointID},
+		{name: "0xE0"},
+		{name: "0xe0"},
+		{name: "0x1"},
+		{name: "", expectErr: true},
+		{name: "ssl", expectErr: true},
+		{name: "foo", expectErr: true},
+		{name: "0x", expectErr: true},
+		{name: "0x100", expectErr: true},
+		{name: "0xZZ", expectErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateProxyProtocolTLVName(test.name)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLookupProxyProtocolTLV(t *testing.T) {
+	ssl, err := sslTLV(&tls.ConnectionState{
+		Version:          tls.VersionTLS13,
+		CipherSuite:      tls.TLS_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "client"}}},
+		VerifiedChains:   [][]*x509.Certificate{{}},
+	})
+	require.NoError(t, err)
+
+	tlvs := []proxyproto.TLV{
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.example.com")},
+		{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte("h2")},
+		{Type: proxyproto.PP2_TYPE_UNIQUE_ID, Value: []byte{0xca, 0xfe}},
+		{Type: 0xE0, Value: []byte("custom")},
+		ssl,
+	}
+
+	testCases := []struct {
+		name          string
+		expectedValue string
+		expectedOk    bool
+	}{
+		{name: TLVAuthority, expectedValue: "foo.example.com", expectedOk: true},
+		{name: TLVALPN, expectedValue: "h2", expectedOk: true},
+		{name: TLVUniqueID, expectedValue: "cafe", expectedOk: true},
+		{name: TLVSSLVersion, expectedValue: "1.3", expectedOk: true},
+		{name: TLVSSLCipher, expectedValue: "TLS_AES_128_GCM_SHA256", expectedOk: true},
+		{name: TLVSSLClientCN, expectedValue: "client", expectedOk: true},
+		{name: TLVSSLVerified, expectedValue: "true", expectedOk: true},
+		{name: "0xE0", expectedValue: "custom", expectedOk: true},
+		{name: "0xE1"},
+		{name: TLVAWSVPCEndpointID},
+		{name: "foo"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, ok := LookupProxyProtocolTLV(tlvs, test.name)
+			assert.Equal(t, test.expectedOk, ok)
+			if test.expectedOk {
+				assert.Equal(t, test.expectedValue, value)
+			}
+		})
+	}
+}
+
+func TestBuildProxyProtocolTLVs_handshakeTimeout(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = serverConn.Close()
+		_ = clientConn.Close()
+	})
+
+	// The client never starts the TLS handshake.
+	tlsConn := tls.Server(serverConn, &tls.Config{})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := buildProxyProtocolTLVs(ctx, tlsConn, []string{TLVALPN})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the TLS handshake with the client is not bounded")
+	}
+}
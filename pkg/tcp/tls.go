@@ -14,3 +14,8 @@ type TLSHandler struct {
 func (t *TLSHandler) ServeTCP(conn WriteCloser) {
 	t.Next.ServeTCP(tls.Server(conn, t.Config))
 }
+
+// GetTLSConn returns the TLS connection which is, or is wrapped by, the given connection, if any.
+func GetTLSConn(conn WriteCloser) (*tls.Conn, bool) {
+	return findConn[*tls.Conn](conn)
+}
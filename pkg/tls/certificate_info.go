@@ -0,0 +1,177 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/x509"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// CertificateSourceDefault is the source of the default certificates configured in the TLS stores.
+	CertificateSourceDefault = "default"
+	// CertificateSourceGenerated is the source of the default certificates generated by Traefik.
+	CertificateSourceGenerated = "generated"
+)
+
+// OCSP staple statuses.
+const (
+	OCSPStatusGood    = "good"
+	OCSPStatusRevoked = "revoked"
+	OCSPStatusUnknown = "unknown"
+	// OCSPStatusPending is the status of the certificates for which no staple has been obtained yet.
+	OCSPStatusPending = "pending"
+)
+
+// CertificateInfo describes a certificate of a TLS store.
+type CertificateInfo struct {
+	Store string
+	// Source is the name of the provider of the certificate,
+	// or CertificateSourceDefault and CertificateSourceGenerated for the store default certificates.
+	Source string
+	// Default is whether the certificate is the default certificate of the store.
+	Default bool
+	Leaf    *x509.Certificate
+	// OCSP is the OCSP staple of the certificate, nil when the certificate is not stapled.
+	OCSP *OCSPStapleInfo
+}
+
+// OCSPStapleInfo describes the OCSP staple of a certificate.
+type OCSPStapleInfo struct {
+	Status     string
+	ThisUpdate time.Time
+	NextUpdate time.Time
+}
+
+// MatchDomain returns whether the certificate is valid for the given domain.
+func (c CertificateInfo) MatchDomain(domain string) bool {
+	if ip := net.ParseIP(domain); ip != nil {
+		for _, certIP := range c.Leaf.IPAddresses {
+			if certIP.Equal(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, certDomain := range c.Leaf.DNSNames {
+		if matchDomain(domain, certDomain) {
+			return true
+		}
+	}
+
+	return len(c.Leaf.DNSNames) == 0 && c.Leaf.Subject.CommonName != "" && matchDomain(domain, c.Leaf.Subject.CommonName)
+}
+
+// GetCertificates returns the certificates of all the TLS stores, including their default certificates.
+// The temporary certificates of the ACME TLS-ALPN-01 challenges are excluded.
+func (m *Manager) GetCertificates() []CertificateInfo {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var certificates []CertificateInfo
+	for storeName, store := range m.stores {
+		if storeName == tlsalpn01.ACMETLS1Protocol || store == nil {
+			continue
+		}
+
+		// The same certificate is referenced once per domain set,
+		// and the default certificate can be one of the store certificates (defaultGeneratedCert).
+		indexes := make(map[*CertificateData]int)
+
+		if store.DynamicCerts != nil && store.DynamicCerts.Get() != nil {
+			for _, cert := range store.DynamicCerts.Get().(map[string]*CertificateData) {
+				if _, ok := indexes[cert]; ok {
+					continue
+				}
+
+				if info, ok := m.certificateInfo(storeName, cert); ok {
+					indexes[cert] = len(certificates)
+					certificates = append(certificates, info)
+				}
+			}
+		}
+
+		if store.DefaultCertificate != nil {
+			if i, ok := indexes[store.DefaultCertificate]; ok {
+				certificates[i].Default = true
+				continue
+			}
+
+			if info, ok := m.certificateInfo(storeName, store.DefaultCertificate); ok {
+				info.Default = true
+				certificates = append(certificates, info)
+			}
+		}
+	}
+
+	sort.Slice(certificates, func(i, j int) bool {
+		if certificates[i].Store != certificates[j].Store {
+			return certificates[i].Store < certificates[j].Store
+		}
+		if certificates[i].Default != certificates[j].Default {
+			return certificates[i].Default
+		}
+		return bytes.Compare(certificates[i].Leaf.Raw, certificates[j].Leaf.Raw) < 0
+	})
+
+	return certificates
+}
+
+func (m *Manager) certificateInfo(storeName string, cert *CertificateData) (CertificateInfo, bool) {
+	if cert.Certificate == nil || len(cert.Certificate.Certificate) == 0 {
+		return CertificateInfo{}, false
+	}
+
+	leaf := cert.Certificate.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(cert.Certificate.Certificate[0])
+		if err != nil {
+			return CertificateInfo{}, false
+		}
+	}
+
+	info := CertificateInfo{
+		Store:  storeName,
+		Source: cert.Source,
+		Leaf:   leaf,
+	}
+
+	if m.ocspStapler == nil || cert.Hash == "" {
+		return info, true
+	}
+
+	staple, ok := m.ocspStapler.GetStaple(cert.Hash)
+	if !ok {
+		return info, true
+	}
+
+	info.OCSP = &OCSPStapleInfo{Status: OCSPStatusPending}
+	if staple == nil {
+		return info, true
+	}
+
+	resp, err := ocsp.ParseResponse(staple, nil)
+	if err != nil {
+		return info, true
+	}
+
+	info.OCSP.ThisUpdate = resp.ThisUpdate
+	info.OCSP.NextUpdate = resp.NextUpdate
+
+	switch resp.Status {
+	case ocsp.Good:
+		info.OCSP.Status = OCSPStatusGood
+	case ocsp.Revoked:
+		info.OCSP.Status = OCSPStatusRevoked
+	default:
+		info.OCSP.Status = OCSPStatusUnknown
+	}
+
+	return info, true
+}
@@ -17,6 +17,9 @@ import (
 type CertificateData struct {
 	Hash        string
 	Certificate *tls.Certificate
+	// Source is the name of the provider of the certificate,
+	// or CertificateSourceDefault and CertificateSourceGenerated for the store default certificates.
+	Source string
 }
 
 // CertificateStore store for dynamic certificates.
@@ -0,0 +1,54 @@
+package tls
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+)
+
+// hybridCurveIDs are the hybrid post-quantum key exchanges,
+// which are only negotiated with TLS 1.3.
+var hybridCurveIDs = map[tls.CurveID]struct{}{
+	tls.X25519MLKEM768: {},
+}
+
+// ParseCurvePreferences converts the given curve names to the crypto/tls curve identifiers.
+func ParseCurvePreferences(curves []string) ([]tls.CurveID, error) {
+	curveIDs := make([]tls.CurveID, 0, len(curves))
+	for _, curve := range curves {
+		curveID, exists := CurveIDs[curve]
+		if !exists {
+			return nil, fmt.Errorf("invalid CurveID in curvePreferences: %s", curve)
+		}
+
+		curveIDs = append(curveIDs, curveID)
+	}
+
+	return curveIDs, nil
+}
+
+// ValidateCurvePreferences checks that the curve preferences can be negotiated with the given maximum TLS version.
+// A maxVersion of zero means that the maximum version supported by crypto/tls is used.
+func ValidateCurvePreferences(curveIDs []tls.CurveID, maxVersion uint16) error {
+	if len(curveIDs) == 0 || maxVersion == 0 || maxVersion >= tls.VersionTLS13 {
+		return nil
+	}
+
+	for _, curveID := range curveIDs {
+		if _, hybrid := hybridCurveIDs[curveID]; !hybrid {
+			return nil
+		}
+	}
+
+	return errors.New("the hybrid post-quantum curves require TLS 1.3, but the maximum TLS version is lower")
+}
+
+// GetCurveName returns the name of the key exchange group negotiated by the connection.
+// Available CurveIDs defined at https://pkg.go.dev/crypto/tls/#CurveID
+func GetCurveName(connState *tls.ConnectionState) string {
+	if connState.CurveID == 0 {
+		return "unknown"
+	}
+
+	return connState.CurveID.String()
+}
@@ -0,0 +1,71 @@
+package tls
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurvePreferences(t *testing.T) {
+	curveIDs, err := ParseCurvePreferences([]string{"x25519mlkem768", "X25519", "secp256r1"})
+	require.NoError(t, err)
+	assert.Equal(t, []tls.CurveID{tls.X25519MLKEM768, tls.X25519, tls.CurveP256}, curveIDs)
+
+	_, err = ParseCurvePreferences([]string{"X25519", "foo"})
+	require.Error(t, err)
+}
+
+func TestValidateCurvePreferences(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		curveIDs    []tls.CurveID
+		maxVersion  uint16
+		expectedErr bool
+	}{
+		{
+			desc:       "default curves",
+			maxVersion: tls.VersionTLS12,
+		},
+		{
+			desc:     "hybrid curve without max version",
+			curveIDs: []tls.CurveID{tls.X25519MLKEM768},
+		},
+		{
+			desc:       "hybrid curve with TLS 1.3",
+			curveIDs:   []tls.CurveID{tls.X25519MLKEM768},
+			maxVersion: tls.VersionTLS13,
+		},
+		{
+			desc:       "hybrid and classical curves with TLS 1.2",
+			curveIDs:   []tls.CurveID{tls.X25519MLKEM768, tls.X25519},
+			maxVersion: tls.VersionTLS12,
+		},
+		{
+			desc:        "hybrid curve with TLS 1.2",
+			curveIDs:    []tls.CurveID{tls.X25519MLKEM768},
+			maxVersion:  tls.VersionTLS12,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateCurvePreferences(test.curveIDs, test.maxVersion)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetCurveName(t *testing.T) {
+	assert.Equal(t, "X25519MLKEM768", GetCurveName(&tls.ConnectionState{CurveID: tls.X25519MLKEM768}))
+	assert.Equal(t, "unknown", GetCurveName(&tls.ConnectionState{}))
+}
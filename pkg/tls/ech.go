@@ -0,0 +1,300 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/types"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+const (
+	defaultECHGracePeriod = 24 * time.Hour
+
+	// echConfigVersion is the version of the ECHConfig structure supported by crypto/tls.
+	echConfigVersion = 0xfe0d
+	// echKEMX25519 is the identifier of the DHKEM(X25519, HKDF-SHA256) HPKE KEM,
+	// which is the only KEM supported by crypto/tls for ECH.
+	echKEMX25519 = 0x0020
+)
+
+// ECHConfigList is the ECHConfigList published for TLS options.
+type ECHConfigList struct {
+	// PublicNames are the public names of the ECH configurations, which the clients use as outer SNI.
+	PublicNames []string
+	// ConfigList is the ECHConfigList holding the ECH configurations, to publish in the DNS HTTPS records.
+	ConfigList []byte
+}
+
+func (e *ECH) validate() error {
+	if len(e.Keys) == 0 {
+		return errors.New("at least one key must be defined")
+	}
+
+	for _, key := range e.Keys {
+		if _, err := readECHKey(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// echKey is an ECH private key, with its marshalled ECHConfig.
+type echKey struct {
+	config     []byte
+	privateKey []byte
+	publicName string
+}
+
+// readECHKey reads an ECH key from a PEM file holding a PKCS #8 private key,
+// and an ECHCONFIG block with the ECHConfigList of the key.
+func readECHKey(key types.FileOrContent) (echKey, error) {
+	data, err := key.Read()
+	if err != nil {
+		return echKey{}, fmt.Errorf("reading ECH key: %w", err)
+	}
+
+	var (
+		privateKey *ecdh.PrivateKey
+		configList []byte
+	)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		switch block.Type {
+		case "PRIVATE KEY":
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return echKey{}, fmt.Errorf("parsing ECH private key: %w", err)
+			}
+
+			var ok bool
+			privateKey, ok = parsed.(*ecdh.PrivateKey)
+			if !ok || privateKey.Curve() != ecdh.X25519() {
+				return echKey{}, errors.New("ECH private key is not an X25519 key")
+			}
+
+		case "ECHCONFIG":
+			configList = block.Bytes
+		}
+	}
+
+	if privateKey == nil {
+		return echKey{}, errors.New("no ECH private key found")
+	}
+	if configList == nil {
+		return echKey{}, errors.New("no ECHCONFIG found")
+	}
+
+	configs, err := splitECHConfigList(configList)
+	if err != nil {
+		return echKey{}, err
+	}
+	if len(configs) != 1 {
+		return echKey{}, fmt.Errorf("the ECHConfigList must hold exactly one ECHConfig, got %d", len(configs))
+	}
+
+	publicKey, publicName, err := parseECHConfig(configs[0])
+	if err != nil {
+		return echKey{}, err
+	}
+
+	if !bytes.Equal(publicKey, privateKey.PublicKey().Bytes()) {
+		return echKey{}, errors.New("the ECHConfig public key does not match the ECH private key")
+	}
+
+	return echKey{
+		config:     configs[0],
+		privateKey: privateKey.Bytes(),
+		publicName: publicName,
+	}, nil
+}
+
+// splitECHConfigList returns the marshalled ECHConfigs of an ECHConfigList.
+func splitECHConfigList(data []byte) ([][]byte, error) {
+	s := cryptobyte.String(data)
+
+	var list cryptobyte.String
+	if !s.ReadUint16LengthPrefixed(&list) || !s.Empty() {
+		return nil, errors.New("malformed ECHConfigList")
+	}
+
+	var configs [][]byte
+	for !list.Empty() {
+		raw := []byte(list)
+
+		var (
+			version  uint16
+			contents cryptobyte.String
+		)
+		if !list.ReadUint16(&version) || !list.ReadUint16LengthPrefixed(&contents) {
+			return nil, errors.New("malformed ECHConfigList")
+		}
+
+		configs = append(configs, raw[:4+len(contents)])
+	}
+
+	return configs, nil
+}
+
+// parseECHConfig returns the public key and the public name of a marshalled ECHConfig.
+func parseECHConfig(config []byte) ([]byte, string, error) {
+	s := cryptobyte.String(config)
+
+	var (
+		version  uint16
+		contents cryptobyte.String
+	)
+	if !s.ReadUint16(&version) || !s.ReadUint16LengthPrefixed(&contents) {
+		return nil, "", errors.New("malformed ECHConfig")
+	}
+
+	if version != echConfigVersion {
+		return nil, "", fmt.Errorf("unsupported ECHConfig version %#04x", version)
+	}
+
+	var (
+		configID      uint8
+		kemID         uint16
+		publicKey     cryptobyte.String
+		cipherSuites  cryptobyte.String
+		maxNameLength uint8
+		publicName    cryptobyte.String
+	)
+	if !contents.ReadUint8(&configID) ||
+		!contents.ReadUint16(&kemID) ||
+		!contents.ReadUint16LengthPrefixed(&publicKey) ||
+		!contents.ReadUint16LengthPrefixed(&cipherSuites) ||
+		!contents.ReadUint8(&maxNameLength) ||
+		!contents.ReadUint8LengthPrefixed(&publicName) {
+		return nil, "", errors.New("malformed ECHConfig")
+	}
+
+	if kemID != echKEMX25519 {
+		return nil, "", fmt.Errorf("unsupported ECHConfig KEM %#04x", kemID)
+	}
+
+	return publicKey, string(publicName), nil
+}
+
+// echKeySet holds the ECH keys of TLS options.
+// The configured keys are published and accepted,
+// whereas the keys removed from the configuration are only accepted until the end of the grace period,
+// to give time to the DNS records to be updated.
+type echKeySet struct {
+	keys        []echKey
+	retired     []retiredECHKey
+	gracePeriod time.Duration
+}
+
+type retiredECHKey struct {
+	echKey
+
+	expiresAt time.Time
+}
+
+// newECHKeySet creates the key set of the given ECH configuration, which can be nil,
+// retiring the keys of the previous key set which are not configured anymore.
+// It returns nil when there is no key to accept.
+func newECHKeySet(config *ECH, previous *echKeySet, now time.Time) (*echKeySet, error) {
+	set := &echKeySet{gracePeriod: defaultECHGracePeriod}
+
+	if config != nil {
+		if config.GracePeriod > 0 {
+			set.gracePeriod = time.Duration(config.GracePeriod)
+		}
+
+		for _, key := range config.Keys {
+			k, err := readECHKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			set.keys = append(set.keys, k)
+		}
+	} else if previous != nil {
+		set.gracePeriod = previous.gracePeriod
+	}
+
+	if previous != nil {
+		for _, key := range previous.keys {
+			if !set.contains(key) {
+				set.retired = append(set.retired, retiredECHKey{echKey: key, expiresAt: now.Add(set.gracePeriod)})
+			}
+		}
+
+		for _, key := range previous.retired {
+			if key.expiresAt.After(now) && !set.contains(key.echKey) {
+				set.retired = append(set.retired, key)
+			}
+		}
+	}
+
+	if len(set.keys) == 0 && len(set.retired) == 0 {
+		return nil, nil
+	}
+
+	return set, nil
+}
+
+func (s *echKeySet) contains(key echKey) bool {
+	return slices.ContainsFunc(s.keys, func(k echKey) bool {
+		return bytes.Equal(k.config, key.config)
+	})
+}
+
+// encryptedClientHelloKeys returns the keys accepted at the given time.
+// Only the configured keys are sent as retry configurations to the clients whose ECH is rejected.
+func (s *echKeySet) encryptedClientHelloKeys(now time.Time) []tls.EncryptedClientHelloKey {
+	keys := make([]tls.EncryptedClientHelloKey, 0, len(s.keys)+len(s.retired))
+	for _, key := range s.keys {
+		keys = append(keys, tls.EncryptedClientHelloKey{
+			Config:      key.config,
+			PrivateKey:  key.privateKey,
+			SendAsRetry: true,
+		})
+	}
+
+	for _, key := range s.retired {
+		if key.expiresAt.After(now) {
+			keys = append(keys, tls.EncryptedClientHelloKey{
+				Config:     key.config,
+				PrivateKey: key.privateKey,
+			})
+		}
+	}
+
+	return keys
+}
+
+// configList returns the ECHConfigList of the configured keys.
+func (s *echKeySet) configList() ECHConfigList {
+	var list ECHConfigList
+
+	b := cryptobyte.NewBuilder(nil)
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		for _, key := range s.keys {
+			b.AddBytes(key.config)
+
+			if !slices.Contains(list.PublicNames, key.publicName) {
+				list.PublicNames = append(list.PublicNames, key.publicName)
+			}
+		}
+	})
+
+	list.ConfigList = b.BytesOrPanic()
+
+	return list
+}
@@ -0,0 +1,306 @@
+package tls
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/types"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+func TestReadECHKey(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	config := marshalECHConfig(1, echKEMX25519, key.PublicKey().Bytes(), "public.example.com")
+
+	testCases := []struct {
+		desc      string
+		pem       []byte
+		expectErr string
+	}{
+		{
+			desc: "valid key",
+			pem:  append(encodePKCS8(t, key), encodeECHConfigList(config)...),
+		},
+		{
+			desc:      "no private key",
+			pem:       encodeECHConfigList(config),
+			expectErr: "no ECH private key found",
+		},
+		{
+			desc:      "no ECHCONFIG",
+			pem:       encodePKCS8(t, key),
+			expectErr: "no ECHCONFIG found",
+		},
+		{
+			desc:      "not an X25519 key",
+			pem:       append(encodePKCS8(t, ecdsaKey), encodeECHConfigList(config)...),
+			expectErr: "ECH private key is not an X25519 key",
+		},
+		{
+			desc:      "public key mismatch",
+			pem:       append(encodePKCS8(t, otherKey), encodeECHConfigList(config)...),
+			expectErr: "the ECHConfig public key does not match the ECH private key",
+		},
+		{
+			desc:      "several configs",
+			pem:       append(encodePKCS8(t, key), encodeECHConfigList(config, config)...),
+			expectErr: "the ECHConfigList must hold exactly one ECHConfig, got 2",
+		},
+		{
+			desc:      "unsupported KEM",
+			pem:       append(encodePKCS8(t, key), encodeECHConfigList(marshalECHConfig(1, 0x0010, key.PublicKey().Bytes(), "public.example.com"))...),
+			expectErr: "unsupported ECHConfig KEM 0x0010",
+		},
+		{
+			desc:      "malformed ECHConfigList",
+			pem:       append(encodePKCS8(t, key), pem.EncodeToMemory(&pem.Block{Type: "ECHCONFIG", Bytes: []byte{0x00, 0x05, 0xfe}})...),
+			expectErr: "malformed ECHConfigList",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			echKey, err := readECHKey(types.FileOrContent(test.pem))
+			if test.expectErr != "" {
+				require.EqualError(t, err, test.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, config, echKey.config)
+			assert.Equal(t, key.Bytes(), echKey.privateKey)
+			assert.Equal(t, "public.example.com", echKey.publicName)
+		})
+	}
+}
+
+func TestNewECHKeySet_rotation(t *testing.T) {
+	key1 := generateECHKey(t, 1, "public.example.com")
+	key2 := generateECHKey(t, 2, "public.example.com")
+
+	now := time.Now()
+
+	set, err := newECHKeySet(&ECH{Keys: []types.FileOrContent{key1}}, nil, now)
+	require.NoError(t, err)
+	require.Len(t, set.keys, 1)
+	assert.Empty(t, set.retired)
+
+	config1 := set.keys[0].config
+
+	// The removed key is retired, and still accepted during the grace period.
+	set, err = newECHKeySet(&ECH{Keys: []types.FileOrContent{key2}, GracePeriod: 42}, set, now)
+	require.NoError(t, err)
+	require.Len(t, set.keys, 1)
+	require.Len(t, set.retired, 1)
+	assert.Equal(t, config1, set.retired[0].config)
+	assert.Equal(t, now.Add(42), set.retired[0].expiresAt)
+
+	config2 := set.keys[0].config
+
+	keys := set.encryptedClientHelloKeys(now)
+	require.Len(t, keys, 2)
+	assert.Equal(t, config2, keys[0].Config)
+	assert.True(t, keys[0].SendAsRetry)
+	assert.Equal(t, config1, keys[1].Config)
+	assert.False(t, keys[1].SendAsRetry)
+
+	keys = set.encryptedClientHelloKeys(now.Add(42))
+	require.Len(t, keys, 1)
+	assert.Equal(t, config2, keys[0].Config)
+
+	// Only the configured keys are published.
+	configList := set.configList()
+	assert.Equal(t, []string{"public.example.com"}, configList.PublicNames)
+	assert.Equal(t, encodeConfigList(config2), configList.ConfigList)
+
+	// A retired key added back is not retired anymore.
+	readded, err := newECHKeySet(&ECH{Keys: []types.FileOrContent{key1, key2}}, set, now)
+	require.NoError(t, err)
+	assert.Len(t, readded.keys, 2)
+	assert.Empty(t, readded.retired)
+
+	// Without ECH configuration, the keys are retired with the previous grace period.
+	set, err = newECHKeySet(nil, set, now.Add(10))
+	require.NoError(t, err)
+	assert.Empty(t, set.keys)
+	require.Len(t, set.retired, 2)
+	assert.Equal(t, config2, set.retired[0].config)
+	assert.Equal(t, now.Add(52), set.retired[0].expiresAt)
+	assert.Equal(t, config1, set.retired[1].config)
+	assert.Equal(t, now.Add(42), set.retired[1].expiresAt)
+
+	// The key set is dropped once all the retired keys have expired.
+	set, err = newECHKeySet(nil, set, now.Add(52))
+	require.NoError(t, err)
+	assert.Nil(t, set)
+}
+
+func TestManager_Get_ECH(t *testing.T) {
+	echKey := generateECHKey(t, 1, "public.example.com")
+
+	configs := map[string]Options{
+		"ech": {
+			MinVersion: "VersionTLS13",
+			ECH:        &ECH{Keys: []types.FileOrContent{echKey}},
+		},
+		"invalid": {
+			ECH: &ECH{Keys: []types.FileOrContent{"foo"}},
+		},
+		"default": {},
+	}
+
+	certs := []*CertAndStores{{
+		Certificate: Certificate{CertFile: localhostCert, KeyFile: localhostKey},
+	}}
+
+	tlsManager := NewManager(nil)
+	tlsManager.UpdateConfigs(t.Context(), nil, configs, certs)
+
+	_, err := tlsManager.Get(DefaultTLSStoreName, "invalid")
+	require.Error(t, err)
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, "default")
+	require.NoError(t, err)
+	assert.Nil(t, config.GetEncryptedClientHelloKeys)
+
+	config, err = tlsManager.Get(DefaultTLSStoreName, "ech")
+	require.NoError(t, err)
+	require.NotNil(t, config.GetEncryptedClientHelloKeys)
+
+	assert.Len(t, tlsManager.GetECHKeys(), 1)
+
+	configLists := tlsManager.GetECHConfigLists()
+	require.Len(t, configLists, 1)
+	require.Contains(t, configLists, "ech")
+	assert.Equal(t, []string{"public.example.com"}, configLists["ech"].PublicNames)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM([]byte(localhostCert)))
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = serverConn.Close()
+		_ = clientConn.Close()
+	})
+
+	go func() {
+		_ = tls.Server(serverConn, config).Handshake()
+	}()
+
+	client := tls.Client(clientConn, &tls.Config{
+		ServerName:                     "example.com",
+		RootCAs:                        roots,
+		MinVersion:                     tls.VersionTLS13,
+		EncryptedClientHelloConfigList: configLists["ech"].ConfigList,
+	})
+	require.NoError(t, client.Handshake())
+
+	assert.True(t, client.ConnectionState().ECHAccepted)
+}
+
+func TestManager_UpdateConfigs_removedECHOptions(t *testing.T) {
+	echKey := generateECHKey(t, 1, "public.example.com")
+
+	tlsManager := NewManager(nil)
+	tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{
+		"ech": {ECH: &ECH{Keys: []types.FileOrContent{echKey}, GracePeriod: ptypes.Duration(time.Hour)}},
+	}, nil)
+
+	require.Len(t, tlsManager.GetECHKeys(), 1)
+
+	// The keys of the removed TLS options are still accepted during their grace period, but not published anymore.
+	tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{"default": {}}, nil)
+
+	keys := tlsManager.GetECHKeys()
+	require.Len(t, keys, 1)
+	assert.False(t, keys[0].SendAsRetry)
+	assert.Empty(t, tlsManager.GetECHConfigLists())
+
+	// The key set is dropped once the grace period has expired.
+	tlsManager.echKeySets["ech"].retired[0].expiresAt = time.Now()
+	tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{"default": {}}, nil)
+
+	assert.Empty(t, tlsManager.GetECHKeys())
+	assert.Empty(t, tlsManager.echKeySets)
+}
+
+// generateECHKey generates an X25519 ECH key, and returns it as a PEM file holding the private key and its ECHConfigList.
+func generateECHKey(t *testing.T, configID uint8, publicName string) types.FileOrContent {
+	t.Helper()
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	config := marshalECHConfig(configID, echKEMX25519, key.PublicKey().Bytes(), publicName)
+
+	return types.FileOrContent(append(encodePKCS8(t, key), encodeECHConfigList(config)...))
+}
+
+func marshalECHConfig(configID uint8, kemID uint16, publicKey []byte, publicName string) []byte {
+	b := cryptobyte.NewBuilder(nil)
+	b.AddUint16(echConfigVersion)
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddUint8(configID)
+		b.AddUint16(kemID)
+		b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes(publicKey)
+		})
+		b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+			// HKDF-SHA256 and AES-128-GCM.
+			b.AddUint16(0x0001)
+			b.AddUint16(0x0001)
+		})
+		b.AddUint8(0)
+		b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddBytes([]byte(publicName))
+		})
+		b.AddUint16(0)
+	})
+
+	return b.BytesOrPanic()
+}
+
+func encodeConfigList(configs ...[]byte) []byte {
+	b := cryptobyte.NewBuilder(nil)
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		for _, config := range configs {
+			b.AddBytes(config)
+		}
+	})
+
+	return b.BytesOrPanic()
+}
+
+func encodeECHConfigList(configs ...[]byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "ECHCONFIG", Bytes: encodeConfigList(configs...)})
+}
+
+func encodePKCS8(t *testing.T, key any) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
@@ -155,39 +155,12 @@ func (o *ocspStapler) updateStaple(ctx context.Context, entry *ocspEntry) error
 	}
 
 	for _, responder := range entry.responders {
-		logger := log.With().Str("responder", responder).Logger()
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, responder, bytes.NewReader(ocspReq))
-		if err != nil {
-			return fmt.Errorf("creating OCSP request: %w", err)
-		}
-
-		req.Header.Set("Content-Type", "application/ocsp-request")
-
-		res, err := o.client.Do(req)
+		ocspResBytes, ocspRes, err := requestOCSP(ctx, o.client, responder, ocspReq, entry.leaf, entry.issuer)
 		if err != nil && ctx.Err() != nil {
 			return ctx.Err()
 		}
 		if err != nil {
-			logger.Debug().Err(err).Msg("Unable to obtain OCSP response")
-			continue
-		}
-		defer res.Body.Close()
-
-		if res.StatusCode/100 != 2 {
-			logger.Debug().Msgf("Unable to obtain OCSP response due to status code: %d", res.StatusCode)
-			continue
-		}
-
-		ocspResBytes, err := io.ReadAll(res.Body)
-		if err != nil {
-			logger.Debug().Err(err).Msg("Unable to read OCSP response bytes")
-			continue
-		}
-
-		ocspRes, err := ocsp.ParseResponseForCert(ocspResBytes, entry.leaf, entry.issuer)
-		if err != nil {
-			logger.Debug().Err(err).Msg("Unable to parse OCSP response")
+			log.Debug().Err(err).Str("responder", responder).Msg("Unable to obtain OCSP response")
 			continue
 		}
 
@@ -206,3 +179,35 @@ func (o *ocspStapler) updateStaple(ctx context.Context, entry *ocspEntry) error
 
 	return errors.New("no OCSP staple obtained from any responders")
 }
+
+// requestOCSP sends the given OCSP request to the given responder, and returns the raw and parsed OCSP response.
+func requestOCSP(ctx context.Context, client *http.Client, responder string, ocspReq []byte, leaf, issuer *x509.Certificate) ([]byte, *ocsp.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responder, bytes.NewReader(ocspReq))
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating OCSP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return nil, nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	ocspResBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading OCSP response bytes: %w", err)
+	}
+
+	ocspRes, err := ocsp.ParseResponseForCert(ocspResBytes, leaf, issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing OCSP response: %w", err)
+	}
+
+	return ocspResBytes, ocspRes, nil
+}
@@ -0,0 +1,409 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	defaultCRLRefreshInterval = time.Hour
+	defaultOCSPCacheDuration  = time.Hour
+
+	// crlRetryInterval is the interval between two attempts to load a CRL which failed to load.
+	crlRetryInterval = time.Minute
+	// ocspFailureCacheDuration is the duration during which the OCSP responders are not queried again
+	// for a certificate whose status could not be obtained.
+	ocspFailureCacheDuration = time.Minute
+	// revocationCheckTimeout is the timeout of the OCSP requests.
+	// With the HardFail policy, the requests are made during the TLS handshakes, which they delay up to this timeout.
+	revocationCheckTimeout = 5 * time.Second
+	// crlFetchTimeout is the timeout of the requests fetching the CRLs.
+	crlFetchTimeout = 30 * time.Second
+)
+
+type revocationStatus int
+
+const (
+	revocationStatusUnknown revocationStatus = iota
+	revocationStatusGood
+	revocationStatusRevoked
+)
+
+func (r *Revocation) validate() error {
+	if len(r.CRLs) == 0 && !r.OCSP {
+		return errors.New("at least one CRL or the OCSP checks must be defined")
+	}
+
+	switch r.FailurePolicy {
+	case "", SoftFail, HardFail:
+	default:
+		return fmt.Errorf("unknown failure policy %q", r.FailurePolicy)
+	}
+
+	for _, location := range r.CRLs {
+		if !strings.Contains(location, "://") {
+			continue
+		}
+
+		crlURL, err := url.Parse(location)
+		if err != nil {
+			return fmt.Errorf("parsing CRL URL: %w", err)
+		}
+
+		if crlURL.Scheme != "http" && crlURL.Scheme != "https" {
+			return fmt.Errorf("unsupported CRL URL scheme %q", crlURL.Scheme)
+		}
+	}
+
+	return nil
+}
+
+// revocationChecker checks the revocation status of the client certificates against CRLs and/or OCSP responders.
+// It is kept across the configuration updates, as long as its configuration does not change, to keep its caches.
+type revocationChecker struct {
+	config        Revocation
+	client        *http.Client
+	crls          []*crlSource
+	ocspResponses *cache.Cache
+
+	// ocspPending holds the hashes of the certificates whose OCSP status is being obtained in the background.
+	ocspPending sync.Map
+}
+
+func newRevocationChecker(config Revocation) *revocationChecker {
+	refreshInterval := time.Duration(config.CRLRefreshInterval)
+	if refreshInterval <= 0 {
+		refreshInterval = defaultCRLRefreshInterval
+	}
+
+	checker := &revocationChecker{
+		config:        config,
+		client:        &http.Client{Timeout: revocationCheckTimeout},
+		ocspResponses: cache.New(defaultOCSPCacheDuration, 5*time.Minute),
+	}
+
+	crlClient := &http.Client{Timeout: crlFetchTimeout}
+
+	for _, location := range config.CRLs {
+		source := &crlSource{
+			location:        location,
+			client:          crlClient,
+			refreshInterval: refreshInterval,
+		}
+
+		// The CRLs are loaded in the background, not to delay the configuration update.
+		source.refreshing.Store(true)
+		safe.Go(source.refresh)
+
+		checker.crls = append(checker.crls, source)
+	}
+
+	return checker
+}
+
+// verifyConnection rejects the connections whose verified client certificate, or one of its intermediate certificates, is revoked,
+// or whose revocation status cannot be determined when the failure policy is HardFail.
+// All the verified chains are checked, so that a revoked intermediate certificate is rejected whichever chain it belongs to.
+// The certificates directly trusted, i.e. the last ones of the chains, are not checked.
+func (c *revocationChecker) verifyConnection(cs tls.ConnectionState) error {
+	checked := make(map[string]struct{})
+
+	for _, chain := range cs.VerifiedChains {
+		for i := range len(chain) - 1 {
+			key := hashRawCert(chain[i].Raw)
+			if _, ok := checked[key]; ok {
+				continue
+			}
+			checked[key] = struct{}{}
+
+			kind := "client certificate"
+			if i > 0 {
+				kind = "intermediate certificate"
+			}
+
+			if err := c.verifyCertificate(kind, chain[i], chain[i+1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyCertificate returns an error if the given certificate is revoked,
+// or if its revocation status cannot be determined when the failure policy is HardFail.
+func (c *revocationChecker) verifyCertificate(kind string, cert, issuer *x509.Certificate) error {
+	switch c.status(cert, issuer) {
+	case revocationStatusRevoked:
+		return fmt.Errorf("%s %q (serial %s) is revoked", kind, cert.Subject.CommonName, cert.SerialNumber)
+
+	case revocationStatusUnknown:
+		if c.config.FailurePolicy == HardFail {
+			return fmt.Errorf("unable to determine the revocation status of the %s %q (serial %s)", kind, cert.Subject.CommonName, cert.SerialNumber)
+		}
+
+		log.Debug().Msgf("Unable to determine the revocation status of the %s %q (serial %s), accepting it", kind, cert.Subject.CommonName, cert.SerialNumber)
+	}
+
+	return nil
+}
+
+// status returns the revocation status of the given certificate.
+// The OCSP responders are only queried when the status cannot be determined from the CRLs.
+func (c *revocationChecker) status(leaf, issuer *x509.Certificate) revocationStatus {
+	status := revocationStatusUnknown
+
+	for _, source := range c.crls {
+		switch source.status(leaf, issuer) {
+		case revocationStatusRevoked:
+			return revocationStatusRevoked
+		case revocationStatusGood:
+			status = revocationStatusGood
+		}
+	}
+
+	if status == revocationStatusGood || !c.config.OCSP {
+		return status
+	}
+
+	return c.ocspStatus(leaf, issuer)
+}
+
+// ocspStatus returns the revocation status of the given certificate obtained from the OCSP responders it lists.
+// With the HardFail policy, the responders are queried during the TLS handshake when the status is not cached.
+// Otherwise, they are queried in the background, not to delay the handshake, and the status is unknown until then.
+func (c *revocationChecker) ocspStatus(leaf, issuer *x509.Certificate) revocationStatus {
+	if len(leaf.OCSPServer) == 0 {
+		return revocationStatusUnknown
+	}
+
+	key := hashRawCert(leaf.Raw)
+	if item, ok := c.ocspResponses.Get(key); ok {
+		return item.(revocationStatus)
+	}
+
+	if c.config.FailurePolicy == HardFail {
+		return c.fetchOCSPStatus(key, leaf, issuer)
+	}
+
+	if _, pending := c.ocspPending.LoadOrStore(key, struct{}{}); !pending {
+		safe.Go(func() {
+			defer c.ocspPending.Delete(key)
+
+			c.fetchOCSPStatus(key, leaf, issuer)
+		})
+	}
+
+	return revocationStatusUnknown
+}
+
+// fetchOCSPStatus queries the OCSP responders listed in the given certificate, and caches the obtained revocation status under the given key.
+func (c *revocationChecker) fetchOCSPStatus(key string, leaf, issuer *x509.Certificate) revocationStatus {
+	ocspReq, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		log.Debug().Err(err).Msgf("Unable to create OCSP request for the client certificate %q", leaf.Subject.CommonName)
+		return revocationStatusUnknown
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), revocationCheckTimeout)
+	defer cancel()
+
+	for _, responder := range leaf.OCSPServer {
+		_, ocspRes, err := requestOCSP(ctx, c.client, responder, ocspReq, leaf, issuer)
+		if err != nil {
+			log.Debug().Err(err).Str("responder", responder).Msg("Unable to obtain OCSP response for the client certificate")
+			continue
+		}
+
+		status := revocationStatusUnknown
+		switch ocspRes.Status {
+		case ocsp.Good:
+			status = revocationStatusGood
+		case ocsp.Revoked:
+			status = revocationStatusRevoked
+		}
+
+		cacheDuration := time.Duration(c.config.OCSPCacheDuration)
+		if cacheDuration <= 0 {
+			cacheDuration = defaultOCSPCacheDuration
+		}
+
+		// As per RFC 6960, the nextUpdate field is optional.
+		if !ocspRes.NextUpdate.IsZero() {
+			cacheDuration = max(time.Until(ocspRes.NextUpdate), ocspFailureCacheDuration)
+		}
+
+		c.ocspResponses.Set(key, status, cacheDuration)
+
+		return status
+	}
+
+	c.ocspResponses.Set(key, revocationStatusUnknown, ocspFailureCacheDuration)
+
+	return revocationStatusUnknown
+}
+
+// crlSource holds a CRL file or URL, and the revocation lists loaded from it.
+type crlSource struct {
+	location        string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu          sync.RWMutex
+	lists       []*revocationList
+	nextRefresh time.Time
+	refreshing  atomic.Bool
+}
+
+type revocationList struct {
+	list    *x509.RevocationList
+	revoked map[string]struct{}
+
+	// issuers holds the hashes of the certificates of the issuers whose signature of the list has been checked.
+	issuers sync.Map
+}
+
+// status returns the revocation status of the given certificate according to the revocation lists issued by its issuer.
+func (s *crlSource) status(leaf, issuer *x509.Certificate) revocationStatus {
+	s.mu.RLock()
+	lists := s.lists
+	nextRefresh := s.nextRefresh
+	s.mu.RUnlock()
+
+	if time.Now().After(nextRefresh) && s.refreshing.CompareAndSwap(false, true) {
+		safe.Go(s.refresh)
+	}
+
+	for _, list := range lists {
+		if !bytes.Equal(list.list.RawIssuer, leaf.RawIssuer) {
+			continue
+		}
+
+		issuerKey := hashRawCert(issuer.Raw)
+		if _, ok := list.issuers.Load(issuerKey); !ok {
+			if err := list.list.CheckSignatureFrom(issuer); err != nil {
+				continue
+			}
+
+			list.issuers.Store(issuerKey, struct{}{})
+		}
+
+		// An outdated list cannot tell whether the certificate has been revoked since.
+		if !list.list.NextUpdate.IsZero() && time.Now().After(list.list.NextUpdate) {
+			continue
+		}
+
+		if _, ok := list.revoked[leaf.SerialNumber.String()]; ok {
+			return revocationStatusRevoked
+		}
+
+		return revocationStatusGood
+	}
+
+	return revocationStatusUnknown
+}
+
+// refresh loads the revocation lists from the CRL location.
+// In case of failure, the previously loaded lists are kept.
+func (s *crlSource) refresh() {
+	defer s.refreshing.Store(false)
+
+	lists, err := s.load()
+	if err != nil {
+		log.Error().Err(err).Str("crl", s.location).Msg("Unable to load certificate revocation list")
+
+		s.mu.Lock()
+		s.nextRefresh = time.Now().Add(crlRetryInterval)
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.mu.Lock()
+	s.lists = lists
+	s.nextRefresh = time.Now().Add(s.refreshInterval)
+	s.mu.Unlock()
+}
+
+func (s *crlSource) load() ([]*revocationList, error) {
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var ders [][]byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type == "X509 CRL" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+
+	// The content is not PEM encoded.
+	if len(ders) == 0 {
+		ders = append(ders, data)
+	}
+
+	var lists []*revocationList
+	for _, der := range ders {
+		list, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return nil, fmt.Errorf("parsing CRL: %w", err)
+		}
+
+		revoked := make(map[string]struct{}, len(list.RevokedCertificateEntries))
+		for _, entry := range list.RevokedCertificateEntries {
+			revoked[entry.SerialNumber.String()] = struct{}{}
+		}
+
+		lists = append(lists, &revocationList{list: list, revoked: revoked})
+	}
+
+	return lists, nil
+}
+
+func (s *crlSource) read() ([]byte, error) {
+	if !strings.HasPrefix(s.location, "http://") && !strings.HasPrefix(s.location, "https://") {
+		return os.ReadFile(s.location)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.location, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating CRL request: %w", err)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching CRL: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("fetching CRL: unexpected status code: %d", res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
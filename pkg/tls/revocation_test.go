@@ -0,0 +1,429 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/types"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestRevocation_validate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		revocation  Revocation
+		expectedErr bool
+	}{
+		{
+			desc:        "no CRLs nor OCSP",
+			expectedErr: true,
+		},
+		{
+			desc:       "CRL file",
+			revocation: Revocation{CRLs: []string{"/etc/traefik/ca.crl"}},
+		},
+		{
+			desc:       "CRL URL",
+			revocation: Revocation{CRLs: []string{"https://ca.example.com/ca.crl"}, FailurePolicy: HardFail},
+		},
+		{
+			desc:        "unsupported CRL URL scheme",
+			revocation:  Revocation{CRLs: []string{"ldap://ca.example.com/ca.crl"}},
+			expectedErr: true,
+		},
+		{
+			desc:       "OCSP",
+			revocation: Revocation{OCSP: true, FailurePolicy: SoftFail},
+		},
+		{
+			desc:        "unknown failure policy",
+			revocation:  Revocation{OCSP: true, FailurePolicy: "Unknown"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.revocation.validate()
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRevocationChecker_CRL(t *testing.T) {
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+
+	goodCert := ca.issue(t, 1, "")
+	revokedCert := ca.issue(t, 2, "")
+	otherCert := otherCA.issue(t, 1, "")
+
+	// The intermediate CA has two certificates, the second one being revoked, and thus the client certificate two chains.
+	intermediateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	goodIntermediate := ca.intermediate(t, 3, intermediateKey)
+	revokedIntermediate := ca.intermediate(t, 4, intermediateKey)
+	intermediateCert := revokedIntermediate.issue(t, 1, "")
+
+	crlPath := filepath.Join(t.TempDir(), "ca.crl")
+	err = os.WriteFile(crlPath, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: ca.crl(t, 2, 4)}), 0o600)
+	require.NoError(t, err)
+
+	crlDER := ca.crl(t, 2, 4)
+	crlServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write(crlDER)
+	}))
+	t.Cleanup(crlServer.Close)
+
+	testCases := []struct {
+		desc          string
+		crl           string
+		failurePolicy string
+		chains        [][]*x509.Certificate
+		expectedErr   bool
+	}{
+		{
+			desc:   "not revoked certificate",
+			crl:    crlPath,
+			chains: [][]*x509.Certificate{{goodCert, ca.cert}},
+		},
+		{
+			desc:        "revoked certificate",
+			crl:         crlPath,
+			chains:      [][]*x509.Certificate{{revokedCert, ca.cert}},
+			expectedErr: true,
+		},
+		{
+			desc:        "revoked certificate with CRL URL",
+			crl:         crlServer.URL,
+			chains:      [][]*x509.Certificate{{revokedCert, ca.cert}},
+			expectedErr: true,
+		},
+		{
+			desc:   "no CRL for the issuer",
+			crl:    crlPath,
+			chains: [][]*x509.Certificate{{otherCert, otherCA.cert}},
+		},
+		{
+			desc:          "no CRL for the issuer with HardFail",
+			crl:           crlPath,
+			failurePolicy: HardFail,
+			chains:        [][]*x509.Certificate{{otherCert, otherCA.cert}},
+			expectedErr:   true,
+		},
+		{
+			desc:   "not revoked intermediate certificate",
+			crl:    crlPath,
+			chains: [][]*x509.Certificate{{intermediateCert, goodIntermediate.cert, ca.cert}},
+		},
+		{
+			desc:        "revoked intermediate certificate",
+			crl:         crlPath,
+			chains:      [][]*x509.Certificate{{intermediateCert, revokedIntermediate.cert, ca.cert}},
+			expectedErr: true,
+		},
+		{
+			desc: "revoked intermediate certificate of another chain",
+			crl:  crlPath,
+			chains: [][]*x509.Certificate{
+				{intermediateCert, goodIntermediate.cert, ca.cert},
+				{intermediateCert, revokedIntermediate.cert, ca.cert},
+			},
+			expectedErr: true,
+		},
+		{
+			desc:          "directly trusted certificate",
+			crl:           crlPath,
+			failurePolicy: HardFail,
+			chains:        [][]*x509.Certificate{{otherCert}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker := newRevocationChecker(Revocation{CRLs: []string{test.crl}, FailurePolicy: test.failurePolicy})
+			waitForCRLs(t, checker)
+
+			err := checker.verifyConnection(tls.ConnectionState{VerifiedChains: test.chains})
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRevocationChecker_OCSP(t *testing.T) {
+	ca := newTestCA(t)
+
+	var requests atomic.Int32
+	responder := newOCSPResponder(t, ca, &requests)
+
+	checker := newRevocationChecker(Revocation{OCSP: true, FailurePolicy: HardFail})
+
+	goodCert := ca.issue(t, 1, responder.URL)
+	err := checker.verifyConnection(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{goodCert, ca.cert}}})
+	require.NoError(t, err)
+
+	revokedCert := ca.issue(t, 2, responder.URL)
+	err = checker.verifyConnection(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{revokedCert, ca.cert}}})
+	require.Error(t, err)
+
+	// The responses are cached.
+	err = checker.verifyConnection(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{goodCert, ca.cert}}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// The certificates without OCSP responder cannot be checked.
+	err = checker.verifyConnection(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{ca.issue(t, 3, ""), ca.cert}}})
+	require.Error(t, err)
+}
+
+func TestRevocationChecker_OCSP_softFail(t *testing.T) {
+	ca := newTestCA(t)
+
+	var requests atomic.Int32
+	responder := newOCSPResponder(t, ca, &requests)
+
+	checker := newRevocationChecker(Revocation{OCSP: true, FailurePolicy: SoftFail})
+
+	// The responder is queried in the background, and the certificate accepted meanwhile.
+	revokedCert := ca.issue(t, 2, responder.URL)
+	err := checker.verifyConnection(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{revokedCert, ca.cert}}})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		err := checker.verifyConnection(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{revokedCert, ca.cert}}})
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+// newOCSPResponder returns an OCSP responder of the given CA, for which the certificate with serial 2 is revoked.
+func newOCSPResponder(t *testing.T, ca *testCA, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	responder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		ocspReq, err := ocsp.ParseRequest(body)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		template := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: ocspReq.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Hour),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		if ocspReq.SerialNumber.Int64() == 2 {
+			template.Status = ocsp.Revoked
+			template.RevokedAt = time.Now().Add(-time.Hour)
+		}
+
+		res, err := ocsp.CreateResponse(ca.cert, ca.cert, template, ca.key)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write(res)
+	}))
+	t.Cleanup(responder.Close)
+
+	return responder
+}
+
+func TestManager_Get_revocation(t *testing.T) {
+	ca := newTestCA(t)
+
+	caPEM := types.FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+
+	configs := map[string]Options{
+		"revocation": {
+			ClientAuth: ClientAuth{
+				CAFiles:        []types.FileOrContent{caPEM},
+				ClientAuthType: RequireAndVerifyClientCert,
+				Revocation:     &Revocation{OCSP: true},
+			},
+		},
+		"invalid": {
+			ClientAuth: ClientAuth{
+				ClientAuthType: RequireAnyClientCert,
+				Revocation:     &Revocation{OCSP: true},
+			},
+		},
+		"default": {},
+	}
+
+	tlsManager := NewManager(nil)
+	tlsManager.UpdateConfigs(t.Context(), nil, configs, nil)
+
+	checker := tlsManager.revocationCheckers["revocation"]
+	require.NotNil(t, checker)
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, "revocation")
+	require.NoError(t, err)
+	assert.NotNil(t, config.VerifyConnection)
+
+	config, err = tlsManager.Get(DefaultTLSStoreName, "default")
+	require.NoError(t, err)
+	assert.Nil(t, config.VerifyConnection)
+
+	_, err = tlsManager.Get(DefaultTLSStoreName, "invalid")
+	require.Error(t, err)
+
+	// The checker is kept when its configuration does not change.
+	tlsManager.UpdateConfigs(t.Context(), nil, configs, nil)
+	assert.Same(t, checker, tlsManager.revocationCheckers["revocation"])
+}
+
+func waitForCRLs(t *testing.T, checker *revocationChecker) {
+	t.Helper()
+
+	for _, source := range checker.crls {
+		require.Eventually(t, func() bool {
+			source.mu.RLock()
+			defer source.mu.RUnlock()
+
+			return len(source.lists) > 0
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+func (c *testCA) issue(t *testing.T, serial int64, ocspServer string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if ocspServer != "" {
+		template.OCSPServer = []string{ocspServer}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, key.Public(), c.key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func (c *testCA) intermediate(t *testing.T, serial int64, key crypto.Signer) *testCA {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "Test intermediate CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, key.Public(), c.key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+func (c *testCA) crl(t *testing.T, revokedSerials ...int64) []byte {
+	t.Helper()
+
+	template := &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: time.Now().Add(time.Hour),
+	}
+	for _, serial := range revokedSerials {
+		template.RevokedCertificateEntries = append(template.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: time.Now().Add(-time.Hour),
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, template, c.cert, c.key)
+	require.NoError(t, err)
+
+	return der
+}
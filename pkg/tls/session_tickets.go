@@ -0,0 +1,214 @@
+package tls
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+const (
+	sessionTicketKeySize = 32
+
+	defaultSessionTicketRetainedKeys = 2
+
+	// sessionTicketKeyIDSize is the size of the key identifier prefixing the tickets,
+	// which allows to find the decryption key without trying all of them.
+	sessionTicketKeyIDSize = 8
+)
+
+// sessionTicketKeyLabel is the label of the derivation of the rotated session ticket keys.
+var sessionTicketKeyLabel = []byte("traefik session ticket key")
+
+// sessionTicketKeys encrypts and decrypts the session tickets with the configured keys,
+// so that the replicas sharing them can resume the sessions of each other.
+type sessionTicketKeys struct {
+	secrets          [][]byte
+	rotationInterval time.Duration
+	retainedKeys     int
+}
+
+func newSessionTicketKeys(config *SessionTickets) (*sessionTicketKeys, error) {
+	if len(config.Keys) == 0 {
+		return nil, errors.New("at least one key must be defined")
+	}
+
+	if config.RotationInterval < 0 {
+		return nil, errors.New("the rotation interval must be positive")
+	}
+
+	if config.RetainedKeys < 0 {
+		return nil, errors.New("the number of retained keys must be positive")
+	}
+
+	keys := &sessionTicketKeys{
+		rotationInterval: time.Duration(config.RotationInterval),
+		retainedKeys:     defaultSessionTicketRetainedKeys,
+	}
+
+	if config.RetainedKeys > 0 {
+		keys.retainedKeys = config.RetainedKeys
+	}
+
+	for i, key := range config.Keys {
+		secret, err := readSessionTicketKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("reading session ticket key %d: %w", i, err)
+		}
+
+		keys.secrets = append(keys.secrets, secret)
+	}
+
+	return keys, nil
+}
+
+// readSessionTicketKey reads a base64 encoded 32 bytes session ticket key.
+func readSessionTicketKey(key types.FileOrContent) ([]byte, error) {
+	data, err := key.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding key: %w", err)
+	}
+
+	if len(secret) != sessionTicketKeySize {
+		return nil, fmt.Errorf("the key must be %d bytes long, got %d", sessionTicketKeySize, len(secret))
+	}
+
+	return secret, nil
+}
+
+// encryptionKey returns the key encrypting the tickets at the given time.
+func (k *sessionTicketKeys) encryptionKey(now time.Time) []byte {
+	if k.rotationInterval == 0 {
+		return k.secrets[0]
+	}
+
+	return deriveSessionTicketKey(k.secrets[0], k.period(now))
+}
+
+// decryptionKeys returns the keys decrypting the tickets at the given time.
+// With rotation, the keys of the retained previous periods are accepted,
+// as well as the key of the next period, to tolerate clock skews between the replicas.
+func (k *sessionTicketKeys) decryptionKeys(now time.Time) [][]byte {
+	if k.rotationInterval == 0 {
+		return k.secrets
+	}
+
+	current := k.period(now)
+
+	var keys [][]byte
+	for _, secret := range k.secrets {
+		for period := current + 1; period >= current-int64(k.retainedKeys) && period >= 0; period-- {
+			keys = append(keys, deriveSessionTicketKey(secret, period))
+		}
+	}
+
+	return keys
+}
+
+func (k *sessionTicketKeys) period(now time.Time) int64 {
+	return now.UnixNano() / int64(k.rotationInterval)
+}
+
+func deriveSessionTicketKey(secret []byte, period int64) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(sessionTicketKeyLabel)
+	_ = binary.Write(mac, binary.BigEndian, period)
+
+	return mac.Sum(nil)
+}
+
+func sessionTicketKeyID(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:sessionTicketKeyIDSize]
+}
+
+// wrapSession encrypts the session state with the current encryption key.
+// The ticket is made of the key identifier, the nonce, and the sealed session state.
+func (k *sessionTicketKeys) wrapSession(_ tls.ConnectionState, state *tls.SessionState) ([]byte, error) {
+	plaintext, err := state.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	key := k.encryptionKey(time.Now())
+
+	aead, err := newSessionTicketAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := make([]byte, sessionTicketKeyIDSize+aead.NonceSize(), sessionTicketKeyIDSize+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	copy(ticket, sessionTicketKeyID(key))
+
+	nonce := ticket[sessionTicketKeyIDSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(ticket, nonce, plaintext, ticket[:sessionTicketKeyIDSize]), nil
+}
+
+// unwrapSession decrypts a ticket with the key it has been encrypted with.
+// The tickets which cannot be decrypted are ignored, which falls back to a full handshake.
+func (k *sessionTicketKeys) unwrapSession(identity []byte, _ tls.ConnectionState) (*tls.SessionState, error) {
+	if len(identity) < sessionTicketKeyIDSize {
+		return nil, nil
+	}
+
+	keyID := identity[:sessionTicketKeyIDSize]
+
+	for _, key := range k.decryptionKeys(time.Now()) {
+		if !hmac.Equal(keyID, sessionTicketKeyID(key)) {
+			continue
+		}
+
+		aead, err := newSessionTicketAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(identity) < sessionTicketKeyIDSize+aead.NonceSize() {
+			return nil, nil
+		}
+
+		nonce := identity[sessionTicketKeyIDSize : sessionTicketKeyIDSize+aead.NonceSize()]
+
+		plaintext, err := aead.Open(nil, nonce, identity[sessionTicketKeyIDSize+aead.NonceSize():], keyID)
+		if err != nil {
+			return nil, nil
+		}
+
+		state, err := tls.ParseSessionState(plaintext)
+		if err != nil {
+			return nil, nil
+		}
+
+		return state, nil
+	}
+
+	return nil, nil
+}
+
+func newSessionTicketAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
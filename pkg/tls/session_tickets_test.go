@@ -0,0 +1,236 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+var (
+	sessionTicketKey1 = types.FileOrContent(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", 32))))
+	sessionTicketKey2 = types.FileOrContent(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", 32))))
+)
+
+func TestNewSessionTicketKeys(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    SessionTickets
+		expectErr string
+	}{
+		{
+			desc:   "valid keys",
+			config: SessionTickets{Keys: []types.FileOrContent{sessionTicketKey1, sessionTicketKey2 + "\n"}},
+		},
+		{
+			desc:      "no key",
+			config:    SessionTickets{},
+			expectErr: "at least one key must be defined",
+		},
+		{
+			desc:      "not base64",
+			config:    SessionTickets{Keys: []types.FileOrContent{"!!!"}},
+			expectErr: "reading session ticket key 0: decoding key: illegal base64 data at input byte 0",
+		},
+		{
+			desc:      "too short",
+			config:    SessionTickets{Keys: []types.FileOrContent{sessionTicketKey1, "Zm9v"}},
+			expectErr: "reading session ticket key 1: the key must be 32 bytes long, got 3",
+		},
+		{
+			desc:      "negative rotation interval",
+			config:    SessionTickets{Keys: []types.FileOrContent{sessionTicketKey1}, RotationInterval: -1},
+			expectErr: "the rotation interval must be positive",
+		},
+		{
+			desc:      "negative retained keys",
+			config:    SessionTickets{Keys: []types.FileOrContent{sessionTicketKey1}, RetainedKeys: -1},
+			expectErr: "the number of retained keys must be positive",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newSessionTicketKeys(&test.config)
+			if test.expectErr != "" {
+				require.EqualError(t, err, test.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSessionTicketKeys_rotation(t *testing.T) {
+	keys, err := newSessionTicketKeys(&SessionTickets{
+		Keys:             []types.FileOrContent{sessionTicketKey1, sessionTicketKey2},
+		RotationInterval: ptypes.Duration(time.Hour),
+		RetainedKeys:     1,
+	})
+	require.NoError(t, err)
+
+	now := time.Unix(10*3600, 0)
+
+	current := keys.encryptionKey(now)
+	assert.Equal(t, current, keys.encryptionKey(now.Add(59*time.Minute)))
+
+	next := keys.encryptionKey(now.Add(time.Hour))
+	assert.NotEqual(t, current, next)
+
+	previous := keys.encryptionKey(now.Add(-time.Hour))
+	old := keys.encryptionKey(now.Add(-2 * time.Hour))
+
+	decryptionKeys := keys.decryptionKeys(now)
+	// The next, current, and retained previous keys, derived from each configured key.
+	assert.Len(t, decryptionKeys, 6)
+	assert.Contains(t, decryptionKeys, current)
+	assert.Contains(t, decryptionKeys, next)
+	assert.Contains(t, decryptionKeys, previous)
+	assert.NotContains(t, decryptionKeys, old)
+
+	// Another replica sharing the keys derives the same keys.
+	replica, err := newSessionTicketKeys(&SessionTickets{
+		Keys:             []types.FileOrContent{sessionTicketKey1},
+		RotationInterval: ptypes.Duration(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, current, replica.encryptionKey(now))
+}
+
+func TestManager_Get_SessionTickets(t *testing.T) {
+	configs := map[string]Options{
+		"default": {
+			SessionTickets: &SessionTickets{Keys: []types.FileOrContent{sessionTicketKey1}},
+		},
+		"invalid": {
+			DisableSessionTickets: true,
+			SessionTickets:        &SessionTickets{Keys: []types.FileOrContent{sessionTicketKey1}},
+		},
+	}
+
+	certs := []*CertAndStores{{
+		Certificate: Certificate{CertFile: localhostCert, KeyFile: localhostKey},
+	}}
+
+	// Two replicas sharing the session ticket keys.
+	counter := &handshakesCounter{}
+
+	replica1 := NewManager(nil)
+	replica1.SetHandshakesCounter(counter)
+	replica1.UpdateConfigs(t.Context(), nil, configs, certs)
+
+	replica2 := NewManager(nil)
+	replica2.SetHandshakesCounter(counter)
+	replica2.UpdateConfigs(t.Context(), nil, configs, certs)
+
+	_, err := replica1.Get(DefaultTLSStoreName, "invalid")
+	require.Error(t, err)
+
+	config1, err := replica1.Get(DefaultTLSStoreName, "default")
+	require.NoError(t, err)
+
+	config2, err := replica2.Get(DefaultTLSStoreName, "default")
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM([]byte(localhostCert)))
+
+	for _, maxVersion := range []uint16{tls.VersionTLS12, tls.VersionTLS13} {
+		clientConfig := &tls.Config{
+			ServerName:         "example.com",
+			RootCAs:            roots,
+			MaxVersion:         maxVersion,
+			ClientSessionCache: tls.NewLRUClientSessionCache(1),
+		}
+
+		assert.False(t, handshake(t, config1, clientConfig).DidResume)
+		// The session is resumed by the other replica.
+		assert.True(t, handshake(t, config2, clientConfig).DidResume)
+	}
+
+	expected := map[string]float64{
+		"default/full/X25519":            1,
+		"default/resumed/X25519":         1,
+		"default/full/X25519MLKEM768":    1,
+		"default/resumed/X25519MLKEM768": 1,
+	}
+	assert.Equal(t, expected, counter.values)
+}
+
+// handshake performs a TLS handshake, and returns the client connection state.
+// The server connection is read after the handshake, so that the TLS 1.3 session tickets are sent to the client.
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) tls.ConnectionState {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = serverConn.Close()
+		_ = clientConn.Close()
+	})
+
+	go func() {
+		server := tls.Server(serverConn, serverConfig)
+		if server.Handshake() != nil {
+			return
+		}
+		_, _ = server.Write([]byte("ok"))
+	}()
+
+	client := tls.Client(clientConn, clientConfig)
+	require.NoError(t, client.Handshake())
+
+	// Reading processes the session ticket sent after the handshake in TLS 1.3.
+	buf := make([]byte, 2)
+	_, err := client.Read(buf)
+	require.NoError(t, err)
+
+	return client.ConnectionState()
+}
+
+// handshakesCounter is a metrics.Counter collecting the values by labels.
+type handshakesCounter struct {
+	mu     sync.Mutex
+	labels []string
+	values map[string]float64
+	parent *handshakesCounter
+}
+
+func (c *handshakesCounter) With(labelValues ...string) metrics.Counter {
+	root := c
+	if c.parent != nil {
+		root = c.parent
+	}
+
+	return &handshakesCounter{parent: root, labels: append(append([]string{}, c.labels...), labelValues...)}
+}
+
+func (c *handshakesCounter) Add(delta float64) {
+	root := c.parent
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	if root.values == nil {
+		root.values = make(map[string]float64)
+	}
+
+	var values []string
+	for i := 1; i < len(c.labels); i += 2 {
+		values = append(values, c.labels[i])
+	}
+
+	root.values[strings.Join(values, "/")] += delta
+}
@@ -1,6 +1,9 @@
 package tls
 
-import "github.com/traefik/traefik/v3/pkg/types"
+import (
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
 
 const certificateHeader = "-----BEGIN CERTIFICATE-----\n"
 
@@ -28,6 +31,13 @@ const (
 	RequireAndVerifyClientCert = "RequireAndVerifyClientCert"
 )
 
+const (
+	// SoftFail indicates that a client certificate whose revocation status cannot be determined is accepted.
+	SoftFail = "SoftFail"
+	// HardFail indicates that a client certificate whose revocation status cannot be determined is rejected.
+	HardFail = "HardFail"
+)
+
 // +k8s:deepcopy-gen=true
 
 // ClientAuth defines the parameters of the client authentication part of the TLS connection, if any.
@@ -36,6 +46,49 @@ type ClientAuth struct {
 	// ClientAuthType defines the client authentication type to apply.
 	// The available values are: "NoClientCert", "RequestClientCert", "VerifyClientCertIfGiven" and "RequireAndVerifyClientCert".
 	ClientAuthType string `json:"clientAuthType,omitempty" toml:"clientAuthType,omitempty" yaml:"clientAuthType,omitempty" export:"true"`
+	// Revocation defines the revocation checks of the verified client certificates.
+	Revocation *Revocation `json:"revocation,omitempty" toml:"revocation,omitempty" yaml:"revocation,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Revocation defines the revocation checks of the client certificates, against CRLs and/or OCSP responders.
+type Revocation struct {
+	// CRLs are the paths or the HTTP(S) URLs of the certificate revocation lists, in PEM or DER format.
+	CRLs []string `json:"crls,omitempty" toml:"crls,omitempty" yaml:"crls,omitempty"`
+	// CRLRefreshInterval defines the interval between two refreshes of the CRLs.
+	CRLRefreshInterval ptypes.Duration `json:"crlRefreshInterval,omitempty" toml:"crlRefreshInterval,omitempty" yaml:"crlRefreshInterval,omitempty" export:"true"`
+	// OCSP enables the checks against the OCSP responders listed in the client certificates.
+	OCSP bool `json:"ocsp,omitempty" toml:"ocsp,omitempty" yaml:"ocsp,omitempty" export:"true"`
+	// OCSPCacheDuration defines how long the OCSP responses without next update time are cached.
+	OCSPCacheDuration ptypes.Duration `json:"ocspCacheDuration,omitempty" toml:"ocspCacheDuration,omitempty" yaml:"ocspCacheDuration,omitempty" export:"true"`
+	// FailurePolicy defines whether a client certificate whose revocation status cannot be determined is accepted.
+	// The available values are: "SoftFail" (default) and "HardFail".
+	FailurePolicy string `json:"failurePolicy,omitempty" toml:"failurePolicy,omitempty" yaml:"failurePolicy,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SessionTickets holds the session ticket keys configuration.
+type SessionTickets struct {
+	// Keys are the files (paths or contents) holding each a base64 encoded 32 bytes key.
+	// The first key encrypts the session tickets, and all the keys decrypt them.
+	Keys []types.FileOrContent `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty" loggable:"false"`
+	// RotationInterval defines the interval at which new ticket keys are derived from the configured keys.
+	// The derivation only depends on the time, so the replicas sharing the keys rotate them together.
+	RotationInterval ptypes.Duration `json:"rotationInterval,omitempty" toml:"rotationInterval,omitempty" yaml:"rotationInterval,omitempty" export:"true"`
+	// RetainedKeys defines how many previous rotated keys are still accepted to decrypt the session tickets.
+	RetainedKeys int `json:"retainedKeys,omitempty" toml:"retainedKeys,omitempty" yaml:"retainedKeys,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ECH defines the Encrypted Client Hello keys.
+type ECH struct {
+	// Keys are the PEM files (paths or contents) holding each an X25519 private key, and the ECHConfigList of the key.
+	Keys []types.FileOrContent `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty" loggable:"false"`
+	// GracePeriod defines how long the keys removed from Keys are still accepted to decrypt the ClientHello messages.
+	GracePeriod ptypes.Duration `json:"gracePeriod,omitempty" toml:"gracePeriod,omitempty" yaml:"gracePeriod,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -50,6 +103,10 @@ type Options struct {
 	SniStrict             bool       `json:"sniStrict,omitempty" toml:"sniStrict,omitempty" yaml:"sniStrict,omitempty" export:"true"`
 	ALPNProtocols         []string   `json:"alpnProtocols,omitempty" toml:"alpnProtocols,omitempty" yaml:"alpnProtocols,omitempty" export:"true"`
 	DisableSessionTickets bool       `json:"disableSessionTickets,omitempty" toml:"disableSessionTickets,omitempty" yaml:"disableSessionTickets,omitempty" export:"true"`
+	// SessionTickets defines the session ticket keys, shared by the replicas to resume the sessions of each other.
+	SessionTickets *SessionTickets `json:"sessionTickets,omitempty" toml:"sessionTickets,omitempty" yaml:"sessionTickets,omitempty" export:"true"`
+	// ECH defines the Encrypted Client Hello configuration.
+	ECH *ECH `json:"ech,omitempty" toml:"ech,omitempty" yaml:"ech,omitempty" export:"true"`
 
 	// Deprecated: https://github.com/golang/go/issues/45430
 	PreferServerCipherSuites *bool `json:"preferServerCipherSuites,omitempty" toml:"preferServerCipherSuites,omitempty" yaml:"preferServerCipherSuites,omitempty" export:"true"`
@@ -87,4 +144,8 @@ type CertAndStores struct {
 	Certificate `yaml:",inline" export:"true"`
 
 	Stores []string `json:"stores,omitempty" toml:"stores,omitempty" yaml:"stores,omitempty" export:"true"`
+
+	// Provider is the name of the provider of the certificate.
+	// It is set when merging the configurations, and is only used to report the certificate source.
+	Provider string `json:"-" toml:"-" yaml:"-" label:"-" file:"-" kv:"-"`
 }
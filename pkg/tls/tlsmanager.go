@@ -7,13 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"net"
+	"reflect"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/observability/logs"
 	"github.com/traefik/traefik/v3/pkg/tls/generate"
@@ -50,6 +54,14 @@ type OCSPConfig struct {
 	ResponderOverrides map[string]string `description:"Defines a map of OCSP responders to replace for querying OCSP servers." json:"responderOverrides,omitempty" toml:"responderOverrides,omitempty" yaml:"responderOverrides,omitempty"`
 }
 
+// OnDemandIssuer issues certificates during the TLS handshakes for which no certificate is available.
+type OnDemandIssuer interface {
+	// IssueOnDemand starts the issuance of a certificate for the given domain in the given store, if it is allowed.
+	// It returns whether the issuer handles the domain, in which case the next issuers are not called.
+	// It must not block, as it is called during the TLS handshake.
+	IssueOnDemand(storeName, domain string) bool
+}
+
 // Manager is the TLS option/store/configuration factory.
 type Manager struct {
 	lock         sync.RWMutex
@@ -58,6 +70,17 @@ type Manager struct {
 	configs      map[string]Options
 	certs        []*CertAndStores
 
+	onDemandIssuers []OnDemandIssuer
+
+	// revocationCheckers holds the revocation checkers of the client certificates, by TLS options name.
+	revocationCheckers map[string]*revocationChecker
+
+	// echKeySets holds the ECH keys, by TLS options name.
+	echKeySets map[string]*echKeySet
+
+	// handshakesCounter counts the TLS handshakes, by TLS options name and handshake type (full or resumed).
+	handshakesCounter metrics.Counter
+
 	// As of today, the TLS manager contains and is responsible for creating/starting the OCSP ocspStapler.
 	// It would likely have been a Configuration listener but this implies that certs are re-parsed.
 	// But this would probably have impact on resource consumption.
@@ -80,6 +103,14 @@ func NewManager(ocspConfig *OCSPConfig) *Manager {
 	return manager
 }
 
+// AddOnDemandIssuer adds an issuer of certificates for the TLS handshakes for which no certificate is available.
+func (m *Manager) AddOnDemandIssuer(issuer OnDemandIssuer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.onDemandIssuers = append(m.onDemandIssuers, issuer)
+}
+
 func (m *Manager) Run(ctx context.Context) {
 	if m.ocspStapler != nil {
 		m.ocspStapler.Run(ctx)
@@ -100,6 +131,49 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 		}
 	}
 
+	revocationCheckers := make(map[string]*revocationChecker)
+	for optionName, option := range m.configs {
+		revocation := option.ClientAuth.Revocation
+		if revocation == nil {
+			continue
+		}
+
+		// The checker is kept when its configuration does not change, to keep the loaded CRLs and the cached OCSP responses.
+		if checker, ok := m.revocationCheckers[optionName]; ok && reflect.DeepEqual(checker.config, *revocation) {
+			revocationCheckers[optionName] = checker
+			continue
+		}
+
+		revocationCheckers[optionName] = newRevocationChecker(*revocation)
+	}
+	m.revocationCheckers = revocationCheckers
+
+	now := time.Now()
+	echKeySets := make(map[string]*echKeySet)
+	for optionName, option := range m.configs {
+		keySet, err := newECHKeySet(option.ECH, m.echKeySets[optionName], now)
+		if err != nil {
+			// The error is reported when building the TLS config of the options.
+			continue
+		}
+
+		if keySet != nil {
+			echKeySets[optionName] = keySet
+		}
+	}
+
+	// The keys of the removed TLS options are still accepted until the end of their grace period.
+	for optionName, previous := range m.echKeySets {
+		if _, ok := m.configs[optionName]; ok {
+			continue
+		}
+
+		if keySet, _ := newECHKeySet(nil, previous, now); keySet != nil {
+			echKeySets[optionName] = keySet
+		}
+	}
+	m.echKeySets = echKeySets
+
 	m.storesConfig = stores
 	m.certs = certs
 
@@ -160,6 +234,7 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 		certData := &CertificateData{
 			Certificate: &cert,
 			Hash:        certHash,
+			Source:      conf.Provider,
 		}
 
 		for _, store := range conf.Stores {
@@ -238,6 +313,20 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		return nil, fmt.Errorf("building TLS config: %w", err)
 	}
 
+	if checker, ok := m.revocationCheckers[configName]; ok {
+		tlsConfig.VerifyConnection = checker.verifyConnection
+	}
+
+	if m.handshakesCounter != nil {
+		tlsConfig.VerifyConnection = countHandshakes(m.handshakesCounter.With("options", configName), tlsConfig.VerifyConnection)
+	}
+
+	if keySet, ok := m.echKeySets[configName]; ok {
+		tlsConfig.GetEncryptedClientHelloKeys = func(_ *tls.ClientHelloInfo) ([]tls.EncryptedClientHelloKey, error) {
+			return keySet.encryptedClientHelloKeys(time.Now()), nil
+		}
+	}
+
 	store := m.getStore(storeName)
 	if store == nil {
 		err = fmt.Errorf("TLS store %s not found", storeName)
@@ -247,6 +336,8 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		err = fmt.Errorf("ACME TLS store %s not found", tlsalpn01.ACMETLS1Protocol)
 	}
 
+	onDemandIssuers := slices.Clone(m.onDemandIssuers)
+
 	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		domainToCheck := types.CanonicalDomain(clientHello.ServerName)
 
@@ -272,6 +363,15 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 			return bestCertificate, nil
 		}
 
+		// IP addresses are not eligible to on-demand certificates.
+		if store != nil && domainToCheck != "" && net.ParseIP(domainToCheck) == nil {
+			for _, issuer := range onDemandIssuers {
+				if issuer.IssueOnDemand(storeName, domainToCheck) {
+					break
+				}
+			}
+		}
+
 		if sniStrict {
 			log.Debug().Msgf("TLS: strict SNI enabled - No certificate found for domain: %q, closing connection", domainToCheck)
 			// Same comment as above, as in the isACMETLS case.
@@ -333,6 +433,82 @@ func (m *Manager) GetServerCertificates() []*x509.Certificate {
 	return certificates
 }
 
+// GetECHKeys returns the ECH keys of all the TLS options.
+// They are used to decrypt the ClientHello messages, to route the connections on the inner SNI.
+func (m *Manager) GetECHKeys() []tls.EncryptedClientHelloKey {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	now := time.Now()
+
+	var keys []tls.EncryptedClientHelloKey
+	for _, keySet := range m.echKeySets {
+		keys = append(keys, keySet.encryptedClientHelloKeys(now)...)
+	}
+
+	return keys
+}
+
+// GetECHConfigLists returns the ECHConfigLists to publish in the DNS HTTPS records, by TLS options name.
+func (m *Manager) GetECHConfigLists() map[string]ECHConfigList {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	configLists := make(map[string]ECHConfigList)
+	for optionName, keySet := range m.echKeySets {
+		if len(keySet.keys) == 0 {
+			continue
+		}
+
+		configLists[optionName] = keySet.configList()
+	}
+
+	return configLists
+}
+
+// GetOptions returns the TLS options, by name.
+func (m *Manager) GetOptions() map[string]Options {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	options := make(map[string]Options, len(m.configs))
+	for name, config := range m.configs {
+		options[name] = config
+	}
+
+	return options
+}
+
+// SetHandshakesCounter sets the counter of the TLS handshakes,
+// which is given the TLS options name and the handshake type (full or resumed) as labels.
+func (m *Manager) SetHandshakesCounter(counter metrics.Counter) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.handshakesCounter = counter
+}
+
+// countHandshakes wraps the VerifyConnection callback, which is called on both the full and the resumed handshakes,
+// to count the successfully verified handshakes by negotiated key exchange group.
+func countHandshakes(counter metrics.Counter, verifyConnection func(tls.ConnectionState) error) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if verifyConnection != nil {
+			if err := verifyConnection(cs); err != nil {
+				return err
+			}
+		}
+
+		handshakeType := "full"
+		if cs.DidResume {
+			handshakeType = "resumed"
+		}
+
+		counter.With("type", handshakeType, "group", GetCurveName(&cs)).Add(1)
+
+		return nil
+	}
+}
+
 // GetStore gets the certificate store of a given name.
 func (m *Manager) GetStore(storeName string) *CertificateStore {
 	m.lock.RLock()
@@ -367,6 +543,7 @@ func (m *Manager) getDefaultCertificate(ctx context.Context, tlsStore Store, st
 
 	defaultCertificate := &CertificateData{
 		Certificate: defaultCert,
+		Source:      CertificateSourceGenerated,
 	}
 
 	if tlsStore.DefaultGeneratedCert != nil && tlsStore.DefaultGeneratedCert.Domain != nil && tlsStore.DefaultGeneratedCert.Resolver != "" {
@@ -423,9 +600,16 @@ func (m *Manager) buildDefaultCertificate(defaultCertificate *Certificate) (*Cer
 	return &CertificateData{
 		Certificate: &cert,
 		Hash:        certHash,
+		Source:      CertificateSourceDefault,
 	}, nil
 }
 
+// ValidateOptions checks that a TLS configuration can be built from the given TLS options.
+func ValidateOptions(tlsOption Options) error {
+	_, err := buildTLSConfig(tlsOption)
+	return err
+}
+
 // creates a TLS config that allows terminating HTTPS for multiple domains using SNI.
 func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 	conf := &tls.Config{
@@ -475,6 +659,36 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 		}
 	}
 
+	if tlsOption.ClientAuth.Revocation != nil {
+		if conf.ClientCAs == nil {
+			return nil, errors.New("invalid revocation checks: CAFiles is required")
+		}
+
+		if err := tlsOption.ClientAuth.Revocation.validate(); err != nil {
+			return nil, fmt.Errorf("invalid revocation checks: %w", err)
+		}
+	}
+
+	if tlsOption.SessionTickets != nil {
+		if tlsOption.DisableSessionTickets {
+			return nil, errors.New("session ticket keys cannot be defined when the session tickets are disabled")
+		}
+
+		keys, err := newSessionTicketKeys(tlsOption.SessionTickets)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session tickets configuration: %w", err)
+		}
+
+		conf.WrapSession = keys.wrapSession
+		conf.UnwrapSession = keys.unwrapSession
+	}
+
+	if tlsOption.ECH != nil {
+		if err := tlsOption.ECH.validate(); err != nil {
+			return nil, fmt.Errorf("invalid ECH configuration: %w", err)
+		}
+	}
+
 	// Set the minimum TLS version if set in the config
 	if minConst, exists := MinVersion[tlsOption.MinVersion]; exists {
 		conf.MinVersion = minConst
@@ -501,16 +715,16 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 
 	// Set the list of CurvePreferences/CurveIDs if set in the config
 	if tlsOption.CurvePreferences != nil {
-		conf.CurvePreferences = make([]tls.CurveID, 0)
-		// if our list of CurvePreferences/CurveIDs is defined in the config, we can re-initialize the list as empty
-		for _, curve := range tlsOption.CurvePreferences {
-			if curveID, exists := CurveIDs[curve]; exists {
-				conf.CurvePreferences = append(conf.CurvePreferences, curveID)
-			} else {
-				// CurveID listed in the toml does not exist in our listed
-				return nil, fmt.Errorf("invalid CurveID in curvePreferences: %s", curve)
-			}
+		curveIDs, err := ParseCurvePreferences(tlsOption.CurvePreferences)
+		if err != nil {
+			return nil, err
 		}
+
+		if err := ValidateCurvePreferences(curveIDs, conf.MaxVersion); err != nil {
+			return nil, fmt.Errorf("invalid curvePreferences: %w", err)
+		}
+
+		conf.CurvePreferences = curveIDs
 	}
 
 	return conf, nil
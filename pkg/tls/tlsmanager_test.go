@@ -130,6 +130,8 @@ func TestManager_Get(t *testing.T) {
 		"foo":     {MinVersion: "VersionTLS12"},
 		"bar":     {MinVersion: "VersionTLS11"},
 		"invalid": {CurvePreferences: []string{"42"}},
+		"pq":      {MinVersion: "VersionTLS12", CurvePreferences: []string{"X25519MLKEM768", "X25519"}},
+		"pqonly":  {MaxVersion: "VersionTLS12", CurvePreferences: []string{"X25519MLKEM768"}},
 	}
 
 	testCases := []struct {
@@ -163,6 +165,16 @@ func TestManager_Get(t *testing.T) {
 			tlsOptionsName: "invalid",
 			expectedError:  true,
 		},
+		{
+			desc:               "Get a tls config with a hybrid post-quantum curve",
+			tlsOptionsName:     "pq",
+			expectedMinVersion: uint16(tls.VersionTLS12),
+		},
+		{
+			desc:           "Get a tls config with only hybrid post-quantum curves and TLS 1.2",
+			tlsOptionsName: "pqonly",
+			expectedError:  true,
+		},
 	}
 
 	tlsManager := NewManager(nil)
@@ -455,3 +467,88 @@ func TestManager_Get_DefaultValues(t *testing.T) {
 		tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
 	}, config.CipherSuites)
 }
+
+func TestManager_Get_OnDemand(t *testing.T) {
+	dynamicConfigs := []*CertAndStores{{
+		Certificate: Certificate{
+			CertFile: localhostCert,
+			KeyFile:  localhostKey,
+		},
+	}}
+
+	testCases := []struct {
+		desc            string
+		serverName      string
+		expectedDomains []string
+	}{
+		{
+			desc:       "certificate available",
+			serverName: "example.com",
+		},
+		{
+			desc:            "no certificate available",
+			serverName:      "Foo.Example.ORG",
+			expectedDomains: []string{"foo.example.org"},
+		},
+		{
+			desc:       "IP address",
+			serverName: "10.0.0.1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			issuer := &onDemandIssuerMock{}
+
+			tlsManager := NewManager(nil)
+			tlsManager.AddOnDemandIssuer(issuer)
+			tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{"default": DefaultTLSOptions}, dynamicConfigs)
+
+			config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+			require.NoError(t, err)
+
+			certificate, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: test.serverName})
+			require.NoError(t, err)
+			// The default certificate is served while the certificate is issued.
+			require.NotNil(t, certificate)
+
+			assert.Equal(t, test.expectedDomains, issuer.domains)
+		})
+	}
+}
+
+func TestManager_Get_OnDemand_firstIssuer(t *testing.T) {
+	declining := &onDemandIssuerMock{declines: true}
+	first := &onDemandIssuerMock{}
+	second := &onDemandIssuerMock{}
+
+	tlsManager := NewManager(nil)
+	tlsManager.AddOnDemandIssuer(declining)
+	tlsManager.AddOnDemandIssuer(first)
+	tlsManager.AddOnDemandIssuer(second)
+	tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{"default": DefaultTLSOptions}, nil)
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	_, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.example.org"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"foo.example.org"}, declining.domains)
+	assert.Equal(t, []string{"foo.example.org"}, first.domains)
+	assert.Empty(t, second.domains)
+}
+
+type onDemandIssuerMock struct {
+	declines bool
+	domains  []string
+}
+
+func (m *onDemandIssuerMock) IssueOnDemand(storeName, domain string) bool {
+	if storeName == DefaultTLSStoreName {
+		m.domains = append(m.domains, domain)
+	}
+	return !m.declines
+}
@@ -63,6 +63,11 @@ func (in *ClientAuth) DeepCopyInto(out *ClientAuth) {
 		*out = make([]types.FileOrContent, len(*in))
 		copy(*out, *in)
 	}
+	if in.Revocation != nil {
+		in, out := &in.Revocation, &out.Revocation
+		*out = new(Revocation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -76,6 +81,27 @@ func (in *ClientAuth) DeepCopy() *ClientAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ECH) DeepCopyInto(out *ECH) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]types.FileOrContent, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ECH.
+func (in *ECH) DeepCopy() *ECH {
+	if in == nil {
+		return nil
+	}
+	out := new(ECH)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GeneratedCert) DeepCopyInto(out *GeneratedCert) {
 	*out = *in
@@ -116,6 +142,16 @@ func (in *Options) DeepCopyInto(out *Options) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SessionTickets != nil {
+		in, out := &in.SessionTickets, &out.SessionTickets
+		*out = new(SessionTickets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ECH != nil {
+		in, out := &in.ECH, &out.ECH
+		*out = new(ECH)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PreferServerCipherSuites != nil {
 		in, out := &in.PreferServerCipherSuites, &out.PreferServerCipherSuites
 		*out = new(bool)
@@ -134,6 +170,48 @@ func (in *Options) DeepCopy() *Options {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Revocation) DeepCopyInto(out *Revocation) {
+	*out = *in
+	if in.CRLs != nil {
+		in, out := &in.CRLs, &out.CRLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Revocation.
+func (in *Revocation) DeepCopy() *Revocation {
+	if in == nil {
+		return nil
+	}
+	out := new(Revocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionTickets) DeepCopyInto(out *SessionTickets) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]types.FileOrContent, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionTickets.
+func (in *SessionTickets) DeepCopy() *SessionTickets {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionTickets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Store) DeepCopyInto(out *Store) {
 	*out = *in
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,6 +16,13 @@ const maxDatagramSize = 65535
 
 const closeRetryInterval = 500 * time.Millisecond
 
+// Session close reasons.
+const (
+	closeReasonTimeout  = "idle timeout"
+	closeReasonClient   = "client closed"
+	closeReasonShutdown = "entrypoint closed"
+)
+
 var errClosedListener = errors.New("udp: listener closed")
 
 // Listener augments a session-oriented Listener over a UDP PacketConn.
@@ -134,6 +142,7 @@ func (l *Listener) close() error {
 	defer l.mu.Unlock()
 	err := l.pConn.Close()
 	for k, v := range l.conns {
+		v.SetCloseReason(closeReasonShutdown)
 		v.close()
 		delete(l.conns, k)
 	}
@@ -219,6 +228,63 @@ type Conn struct {
 	timeout  time.Duration // for timeouts
 	doneOnce sync.Once
 	doneCh   chan struct{}
+
+	bytesRead    atomic.Int64 // the number of bytes received from the client
+	bytesWritten atomic.Int64 // the number of bytes sent to the client
+
+	muSession   sync.Mutex
+	serviceAddr string // the address of the server the session is forwarded to
+	closeReason string // why the session ended
+}
+
+// RemoteAddr returns the remote network address of the client.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.rAddr
+}
+
+// BytesRead returns the number of bytes received from the client so far.
+func (c *Conn) BytesRead() int64 {
+	return c.bytesRead.Load()
+}
+
+// BytesWritten returns the number of bytes sent to the client so far.
+func (c *Conn) BytesWritten() int64 {
+	return c.bytesWritten.Load()
+}
+
+// SetServiceAddr records the address of the server the session is forwarded to.
+func (c *Conn) SetServiceAddr(addr string) {
+	c.muSession.Lock()
+	defer c.muSession.Unlock()
+
+	c.serviceAddr = addr
+}
+
+// ServiceAddr returns the address of the server the session is forwarded to, if any.
+func (c *Conn) ServiceAddr() string {
+	c.muSession.Lock()
+	defer c.muSession.Unlock()
+
+	return c.serviceAddr
+}
+
+// SetCloseReason records why the session is ending.
+// Only the first reason is kept, as the following ones are consequences of it.
+func (c *Conn) SetCloseReason(reason string) {
+	c.muSession.Lock()
+	defer c.muSession.Unlock()
+
+	if c.closeReason == "" {
+		c.closeReason = reason
+	}
+}
+
+// CloseReason returns why the session ended, if known.
+func (c *Conn) CloseReason() string {
+	c.muSession.Lock()
+	defer c.muSession.Unlock()
+
+	return c.closeReason
 }
 
 // Read reads up to len(p) bytes into p from the connection.
@@ -228,6 +294,7 @@ func (c *Conn) Read(p []byte) (int, error) {
 	select {
 	case c.readCh <- p:
 		n := <-c.sizeCh
+		c.bytesRead.Add(int64(n))
 		c.muActivity.Lock()
 		c.lastActivity = time.Now()
 		c.muActivity.Unlock()
@@ -246,7 +313,14 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	c.lastActivity = time.Now()
 	c.muActivity.Unlock()
 
-	return c.listener.pConn.WriteTo(p, c.rAddr)
+	n, err = c.listener.pConn.WriteTo(p, c.rAddr)
+	c.bytesWritten.Add(int64(n))
+
+	if err != nil {
+		c.SetCloseReason("client error: " + err.Error())
+	}
+
+	return n, err
 }
 
 // Close releases resources related to the Conn.
@@ -277,6 +351,7 @@ func (c *Conn) readLoop() {
 				deadline := c.lastActivity.Add(c.timeout)
 				c.muActivity.RUnlock()
 				if time.Now().After(deadline) {
+					c.SetCloseReason(closeReasonTimeout)
 					c.Close()
 					return
 				}
@@ -297,6 +372,7 @@ func (c *Conn) readLoop() {
 			deadline := c.lastActivity.Add(c.timeout)
 			c.muActivity.RUnlock()
 			if time.Now().After(deadline) {
+				c.SetCloseReason(closeReasonTimeout)
 				c.Close()
 				return
 			}
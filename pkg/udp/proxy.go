@@ -25,9 +25,12 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
+	conn.SetServiceAddr(p.target)
+
 	connBackend, err := net.Dial("udp", p.target)
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")
+		conn.SetCloseReason("error while dialing backend: " + err.Error())
 		return
 	}
 
@@ -35,8 +38,8 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	defer connBackend.Close()
 
 	errChan := make(chan error)
-	go connCopy(conn, connBackend, errChan)
-	go connCopy(connBackend, conn, errChan)
+	go connCopy(conn, connBackend, errChan, conn)
+	go connCopy(connBackend, conn, errChan, conn)
 
 	err = <-errChan
 	if err != nil {
@@ -46,12 +49,23 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	<-errChan
 }
 
-func connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) {
+// connCopy copies the datagrams from src to dst, until either fails or the session is closed.
+// The reason of the end of the copy is recorded on the session, before dst is closed.
+func connCopy(dst io.WriteCloser, src io.Reader, errCh chan error, session *Conn) {
 	// The buffer is initialized to the maximum UDP datagram size,
 	// to make sure that the whole UDP datagram is read or written atomically (no data is discarded).
 	buffer := make([]byte, maxDatagramSize)
 
 	_, err := io.CopyBuffer(dst, src, buffer)
+
+	// The errors writing to the client are recorded by the session itself, thus the other ones come from the backend.
+	// A copy without error ends because the session is closed, as a UDP backend never ends the stream.
+	if err != nil {
+		session.SetCloseReason("backend error: " + err.Error())
+	} else {
+		session.SetCloseReason(closeReasonClient)
+	}
+
 	errCh <- err
 
 	if err := dst.Close(); err != nil {